@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/destroy"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	libvirttypes "github.com/openshift/installer/pkg/types/libvirt"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+)
+
+var (
+	adoptOpts struct {
+		platform   string
+		infraID    string
+		region     string
+		cloud      string
+		libvirtURI string
+	}
+)
+
+func newAdoptCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "adopt CLUSTER_NAME",
+		Short: "Recover the metadata.json of a cluster from its resources, so it can be destroyed",
+		Long: `Recovers the metadata.json of a cluster whose asset directory was lost,
+from the tags the installer put on the cluster's resources, and writes it to
+the asset directory. 'openshift-install destroy cluster' then destroys the
+cluster as if it had been created there.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runAdoptCmd,
+	}
+	cmd.Flags().StringVar(&adoptOpts.platform, "platform", "", "platform of the cluster: aws, openstack or libvirt")
+	cmd.Flags().StringVar(&adoptOpts.infraID, "infra-id", "", "infrastructure ID of the cluster, which prefixes the names of its resources; defaults to the cluster name")
+	cmd.Flags().StringVar(&adoptOpts.region, "region", "", "region of the cluster on AWS and OpenStack")
+	cmd.Flags().StringVar(&adoptOpts.cloud, "cloud", "", "cloud of the cluster in clouds.yaml on OpenStack")
+	cmd.Flags().StringVar(&adoptOpts.libvirtURI, "libvirt-uri", "", "URI of the libvirt daemon of the cluster on libvirt")
+	return cmd
+}
+
+func runAdoptCmd(_ *cobra.Command, args []string) error {
+	cleanup, err := setupFileHook(rootOpts.dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to setup logging hook")
+	}
+	defer cleanup()
+
+	path := filepath.Join(rootOpts.dir, "metadata.json")
+	if _, err := os.Stat(path); err == nil {
+		return errors.Errorf("%s already exists; 'openshift-install destroy cluster' can destroy the cluster with it", path)
+	}
+
+	metadata := &types.ClusterMetadata{
+		ClusterName: args[0],
+		InfraID:     adoptOpts.infraID,
+	}
+	switch adoptOpts.platform {
+	case "aws":
+		metadata.AWS = &awstypes.Metadata{Region: adoptOpts.region}
+	case "openstack":
+		metadata.OpenStack = &openstacktypes.Metadata{Region: adoptOpts.region, Cloud: adoptOpts.cloud}
+	case "libvirt":
+		metadata.Libvirt = &libvirttypes.Metadata{URI: adoptOpts.libvirtURI}
+	default:
+		return errors.Errorf("--platform must be aws, openstack or libvirt, not %q", adoptOpts.platform)
+	}
+
+	if err := destroy.Adopt(logrus.WithField("component", "destroy"), metadata); err != nil {
+		return errors.Wrapf(err, "failed to adopt cluster %q", metadata.ClusterName)
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the metadata")
+	}
+	if err := os.MkdirAll(rootOpts.dir, 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrap(err, "failed to write the metadata")
+	}
+	logrus.Infof("Adopted cluster %q; run 'openshift-install destroy cluster' to destroy it", metadata.ClusterName)
+	return nil
+}
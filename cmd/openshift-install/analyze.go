@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/analyze"
+)
+
+var (
+	analyzeOpts struct {
+		bootstrap string
+	}
+)
+
+// bootstrapUnits are the units of the bootstrap node whose journal is
+// analyzed.
+var bootstrapUnits = []string{"bootkube.service", "openshift.service", "kubelet.service", "crio.service"}
+
+func newAnalyzeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analyze [BUNDLE]",
+		Short: "Diagnoses a failed install from its logs",
+		Long: `Diagnoses a failed install: which cluster operators are degraded or
+unavailable, and which known failure signatures the logs match, with the
+lines matching them.
+
+The logs are read from BUNDLE, a directory or tar archive of gathered logs
+and resource dumps (e.g. 'oc get clusteroperators -o yaml'), from the
+journal of the bootstrap node with --bootstrap, or else from the asset
+directory's .openshift_install.log.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runAnalyzeCmd,
+	}
+	cmd.Flags().StringVar(&analyzeOpts.bootstrap, "bootstrap", "", "also analyze the journal of the bootstrap node at this [user@]address (the user defaults to core), read over SSH")
+	return cmd
+}
+
+func runAnalyzeCmd(_ *cobra.Command, args []string) error {
+	var sources []analyze.Source
+	if len(args) > 0 {
+		bundle, err := analyze.ReadBundle(args[0])
+		if err != nil {
+			return errors.Wrap(err, "failed to read the log bundle")
+		}
+		sources = append(sources, bundle...)
+	}
+
+	if analyzeOpts.bootstrap != "" {
+		journal, err := bootstrapJournal(analyzeOpts.bootstrap)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, analyze.Source{Name: "bootstrap-journal", Data: journal})
+	}
+
+	if len(sources) == 0 {
+		path := filepath.Join(rootOpts.dir, ".openshift_install.log")
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrap(err, "no log bundle or bootstrap node given, and failed to read the installer's log")
+		}
+		sources = append(sources, analyze.Source{Name: ".openshift_install.log", Data: data})
+	}
+
+	return analyze.Analyze(sources).Write(os.Stdout)
+}
+
+// bootstrapJournal returns the journal of the bootstrap node's install
+// units, read over SSH.
+func bootstrapJournal(address string) ([]byte, error) {
+	if !strings.Contains(address, "@") {
+		address = "core@" + address
+	}
+	args := []string{"-o", "BatchMode=yes", "-o", "StrictHostKeyChecking=no", "-o", "UserKnownHostsFile=/dev/null", address, "journalctl", "--boot", "--no-pager"}
+	for _, unit := range bootstrapUnits {
+		args = append(args, "--unit", unit)
+	}
+
+	logrus.Debugf("Reading the journal of %s", address)
+	cmd := exec.Command("ssh", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the journal of %s: %s", address, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/manifests"
+)
+
+const (
+	// outputFormatDirectory writes the usual directory layout.
+	outputFormatDirectory = "dir"
+	// outputFormatBundle writes a multi-document YAML bundle to stdout.
+	outputFormatBundle = "bundle"
+	// outputFormatBundleJSON writes a JSON List bundle to stdout.
+	outputFormatBundleJSON = "bundle-json"
+)
+
+var documentSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// bundleDocument is a manifest in a bundle.
+type bundleDocument struct {
+	source string
+	data   []byte
+}
+
+// bundleDocuments splits the manifests in files into documents, in the
+// order they are created in the cluster: by directory, then by filename.
+func bundleDocuments(files []*asset.File) ([]bundleDocument, error) {
+	sorted := make([]*asset.File, 0, len(files))
+	for _, file := range files {
+		if manifests.IsKustomization(file.Filename) {
+			continue
+		}
+		switch filepath.Ext(file.Filename) {
+		case ".json", ".yaml", ".yml":
+			sorted = append(sorted, file)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Filename < sorted[j].Filename
+	})
+
+	documents := []bundleDocument{}
+	for _, file := range sorted {
+		for _, part := range documentSeparator.Split(string(file.Data), -1) {
+			if len(bytes.TrimSpace([]byte(part))) == 0 {
+				continue
+			}
+			data, err := yaml.YAMLToJSON([]byte(part))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse %s", file.Filename)
+			}
+			if string(data) == "null" {
+				// Only comments.
+				continue
+			}
+			documents = append(documents, bundleDocument{source: file.Filename, data: data})
+		}
+	}
+	return documents, nil
+}
+
+// writeBundle writes the manifests in files to w as a single bundle in the
+// given format.
+func writeBundle(w io.Writer, format string, files []*asset.File) error {
+	documents, err := bundleDocuments(files)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case outputFormatBundle:
+		for _, document := range documents {
+			data, err := yaml.JSONToYAML(document.data)
+			if err != nil {
+				return errors.Wrapf(err, "failed to convert %s", document.source)
+			}
+			if _, err := fmt.Fprintf(w, "---\n# Source: %s\n%s", document.source, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	case outputFormatBundleJSON:
+		items := make([]json.RawMessage, 0, len(documents))
+		for _, document := range documents {
+			items = append(items, document.data)
+		}
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items":      items,
+		}, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal bundle")
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	default:
+		return errors.Errorf("unsupported bundle format %q", format)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
+	"github.com/openshift/installer/pkg/asset/kubeconfig"
+	"github.com/openshift/installer/pkg/asset/tls"
+)
+
+var (
+	certificatesOpts struct {
+		expiringWithin time.Duration
+		regenerate     bool
+	}
+)
+
+// certificateStatus is a certificate found in the state file.
+type certificateStatus struct {
+	name     string
+	notAfter time.Time
+	isCA     bool
+}
+
+func newCertificatesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "certificates",
+		Short: "Reports the expiry of the certificates in the asset directory, and regenerates expired ones",
+		Long: `Reports the expiry of the certificates in the state of the asset directory,
+so a directory with certificates past (or near) expiry can be caught before it
+is used to create a cluster.
+
+With --regenerate, expired and expiring leaf certificates are generated again,
+along with the assets embedding them (e.g. the Ignition configs and the admin
+kubeconfig), which are written to the asset directory.  Certificate authorities
+are not regenerated; an asset directory with an expired one has to be created
+afresh.`,
+		Args: cobra.NoArgs,
+		RunE: runCertificatesCmd,
+	}
+	cmd.Flags().DurationVar(&certificatesOpts.expiringWithin, "expiring-within", 10*time.Minute, "treat certificates expiring within this duration as expired")
+	cmd.Flags().BoolVar(&certificatesOpts.regenerate, "regenerate", false, "regenerate expired leaf certificates and the assets which depend on them")
+	return cmd
+}
+
+func runCertificatesCmd(cmd *cobra.Command, args []string) error {
+	assetStore, err := asset.NewStore(rootOpts.dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create asset store")
+	}
+
+	regenerated := append([]asset.WritableAsset{}, ignitionConfigsTarget.assets...)
+	regenerated = append(regenerated, &kubeconfig.Admin{})
+	targets := make([]asset.Asset, 0, len(regenerated)+1)
+	for _, a := range regenerated {
+		targets = append(targets, a)
+	}
+	// The Terraform variables embed the Ignition configs, so they are
+	// invalidated too, but left for create cluster to generate.
+	targets = append(targets, &cluster.TerraformVariables{})
+
+	expiry := time.Now().Add(certificatesOpts.expiringWithin)
+	statuses := map[string]certificateStatus{}
+	var parseErr error
+	stale := func(a asset.Asset) bool {
+		certKey, ok := a.(tls.CertKeyInterface)
+		if !ok {
+			return false
+		}
+		cert, err := tls.PemToCertificate(certKey.Cert())
+		if err != nil {
+			parseErr = errors.Wrapf(err, "failed to parse the certificate of %q", a.Name())
+			return false
+		}
+		statuses[a.Name()] = certificateStatus{name: a.Name(), notAfter: cert.NotAfter, isCA: cert.IsCA}
+		return certificatesOpts.regenerate && !cert.IsCA && cert.NotAfter.Before(expiry)
+	}
+	removed, err := assetStore.Invalidate(stale, targets...)
+	if err != nil {
+		return errors.Wrap(err, "failed to inspect the state")
+	}
+	if parseErr != nil {
+		return parseErr
+	}
+	if len(statuses) == 0 {
+		logrus.Warnf("No certificates found in the state of %s", rootOpts.dir)
+		return nil
+	}
+	if err := writeCertificateStatuses(statuses, expiry); err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		if status.isCA && status.notAfter.Before(expiry) {
+			logrus.Warnf("%s expires at %s and cannot be regenerated; create a new asset directory", status.name, status.notAfter.Format(time.RFC3339))
+		}
+	}
+	if !certificatesOpts.regenerate {
+		return nil
+	}
+
+	fetch := []asset.WritableAsset{}
+	for _, a := range regenerated {
+		for _, r := range removed {
+			if r.Name() == a.Name() {
+				fetch = append(fetch, a)
+				break
+			}
+		}
+	}
+	if len(fetch) == 0 {
+		logrus.Info("No certificates needed regenerating")
+		return nil
+	}
+	return runTargetCmd(fetch...)(cmd, args)
+}
+
+// writeCertificateStatuses prints a table of the certificates, soonest
+// expiring first.
+func writeCertificateStatuses(statuses map[string]certificateStatus, expiry time.Time) error {
+	sorted := make([]certificateStatus, 0, len(statuses))
+	for _, status := range statuses {
+		sorted = append(sorted, status)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].notAfter.Equal(sorted[j].notAfter) {
+			return sorted[i].notAfter.Before(sorted[j].notAfter)
+		}
+		return sorted[i].name < sorted[j].name
+	})
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CERTIFICATE\tEXPIRES\tSTATUS")
+	for _, status := range sorted {
+		state := "valid"
+		switch {
+		case status.notAfter.Before(now):
+			state = "expired"
+		case status.notAfter.Before(expiry):
+			state = "expiring"
+		}
+		if status.isCA {
+			state += " (CA)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", status.name, status.notAfter.Format(time.RFC3339), state)
+	}
+	return w.Flush()
+}
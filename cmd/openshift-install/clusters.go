@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/validate"
+)
+
+var (
+	clustersOpts struct {
+		parallel int
+	}
+
+	// forwardedClusterFlags are the flags of the root and create commands
+	// which 'create clusters' passes on to each cluster's installer, as
+	// given.  --metrics-file is passed on per cluster.
+	forwardedClusterFlags = []string{
+		"vault-path",
+		"vault-auth-method",
+		"metrics-pushgateway",
+		"release-image",
+		"pull-secret-file",
+		"allow-manifest-hooks",
+		"keep-bootstrap",
+		"api-timeout",
+		"bootstrap-timeout",
+		"install-timeout",
+		"oidc-kubeconfig",
+	}
+
+	// unsupportedClusterFlags are the flags of the create command which
+	// make no sense for several clusters.
+	unsupportedClusterFlags = []string{
+		"install-config",
+		"install-config-header",
+		"output-archive",
+		"progress",
+	}
+)
+
+// clusterCreation is the state of a cluster being created by
+// 'create clusters'.
+type clusterCreation struct {
+	name     string
+	config   string
+	phase    string
+	err      error
+	duration time.Duration
+}
+
+func newCreateClustersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clusters CONFIG_DIR",
+		Short: "Create an OpenShift cluster from each install config in a directory",
+		Long: `Creates an OpenShift cluster from each install config (*.yaml or *.yml)
+in CONFIG_DIR, concurrently.
+
+Each cluster is created by a separate 'openshift-install create cluster',
+as with --cluster, in <dir>/clusters/<name>, where <name> is the install
+config's file name without its extension. The clusters share the OS image
+cache. Their phases are logged as they start and end, and a summary of the
+clusters is printed once they are all created or failed. The standard error
+of each cluster's installer is written to .openshift_install_stderr.log in
+the cluster's directory.
+
+The flags of 'create cluster' are passed on to each cluster's installer.
+--metrics-file is written per cluster, with the cluster's name appended to
+the file name (e.g. metrics-<name>.json), and the metrics of the clusters
+pushed to --metrics-pushgateway are grouped by cluster.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runCreateClustersCmd,
+	}
+	cmd.Flags().IntVar(&clustersOpts.parallel, "parallel", 0, "how many clusters to create at once; all of them by default")
+	addClusterFlags(cmd.Flags())
+	return cmd
+}
+
+func runCreateClustersCmd(cmd *cobra.Command, args []string) error {
+	if rootOpts.cluster != "" {
+		return errors.New("--cluster cannot be used with create clusters, which names each cluster after its install config")
+	}
+	forwarded, err := forwardedClusterArgs(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	cleanup, err := setupFileHook(rootOpts.dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to setup logging hook")
+	}
+	defer cleanup()
+
+	clusters, err := clusterConfigs(args[0])
+	if err != nil {
+		return err
+	}
+	if len(clusters) == 0 {
+		return errors.Errorf("no install configs found in %s", args[0])
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.Wrap(err, "failed to find the installer's executable")
+	}
+
+	parallel := clustersOpts.parallel
+	if parallel <= 0 || parallel > len(clusters) {
+		parallel = len(clusters)
+	}
+	logrus.Infof("Creating %d clusters, %d at a time", len(clusters), parallel)
+
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for _, c := range clusters {
+		wg.Add(1)
+		go func(c *clusterCreation) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			start := time.Now()
+			c.err = c.create(executable, forwarded)
+			c.duration = time.Since(start)
+			if c.err != nil {
+				logrus.Errorf("%s: failed: %v", c.name, c.err)
+			} else {
+				logrus.Infof("%s: created", c.name)
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	failed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tSTATUS\tPHASE\tDURATION\tERROR")
+	for _, c := range clusters {
+		status, detail := "created", ""
+		if c.err != nil {
+			status, detail = "failed", c.err.Error()
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.name, status, c.phase, c.duration.Round(time.Second), detail)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d clusters failed", failed, len(clusters))
+	}
+	return nil
+}
+
+// clusterConfigs returns a cluster for each install config in dir, named
+// after the config's file name.
+func clusterConfigs(dir string) ([]*clusterCreation, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the install configs")
+	}
+	var clusters []*clusterCreation
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if !file.Mode().IsRegular() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		name := strings.TrimSuffix(file.Name(), ext)
+		if err := validate.DomainName(name); err != nil {
+			return nil, errors.Wrapf(err, "invalid cluster name from %s", file.Name())
+		}
+
+		path := filepath.Join(dir, file.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the install config")
+		}
+		config := &types.InstallConfig{}
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", file.Name())
+		}
+		// An install config without a name is completed with the cluster's.
+		if config.ObjectMeta.Name != "" && config.ObjectMeta.Name != name {
+			return nil, errors.Errorf("%s is for cluster %q, but its metadata.name is %q; rename the file or change its metadata.name", file.Name(), name, config.ObjectMeta.Name)
+		}
+		clusters = append(clusters, &clusterCreation{name: name, config: path})
+	}
+	return clusters, nil
+}
+
+// forwardedClusterArgs returns the arguments of 'create cluster' for the
+// flags set on the command line of 'create clusters', or an error if one
+// of them cannot be passed on.
+func forwardedClusterArgs(flags *pflag.FlagSet) ([]string, error) {
+	for _, name := range unsupportedClusterFlags {
+		if flags.Changed(name) {
+			return nil, errors.Errorf("--%s cannot be used with create clusters", name)
+		}
+	}
+	if _, err := parsePhaseHooks(createOpts.hooks); err != nil {
+		return nil, err
+	}
+
+	var args []string
+	for _, name := range forwardedClusterFlags {
+		if flag := flags.Lookup(name); flag != nil && flag.Changed {
+			args = append(args, fmt.Sprintf("--%s=%s", name, flag.Value))
+		}
+	}
+	for _, hook := range createOpts.hooks {
+		args = append(args, "--hook", hook)
+	}
+	return args, nil
+}
+
+// clusterMetricsFile returns the metrics file of the named cluster: file
+// with the name appended to its base name.
+func clusterMetricsFile(file, name string) string {
+	ext := filepath.Ext(file)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(file, ext), name, ext)
+}
+
+// create runs 'openshift-install create cluster' for the cluster, with the
+// forwarded arguments, following its phases through the event stream.
+func (c *clusterCreation) create(executable string, forwarded []string) error {
+	args := []string{
+		"--dir", rootOpts.dir,
+		"--cluster", c.name,
+		"--log-level", rootOpts.logLevel,
+		"--events", "fd:3",
+		"create", "cluster",
+		"--install-config", c.config,
+	}
+	if rootOpts.metricsFile != "" {
+		args = append(args, "--metrics-file", clusterMetricsFile(rootOpts.metricsFile, c.name))
+	}
+	args = append(args, forwarded...)
+
+	dir := filepath.Join(rootOpts.dir, "clusters", c.name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrap(err, "failed to create the cluster directory")
+	}
+	logFile, err := os.OpenFile(filepath.Join(dir, ".openshift_install_stderr.log"), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the installer's log file")
+	}
+	defer logFile.Close()
+
+	eventsReader, eventsWriter, err := os.Pipe()
+	if err != nil {
+		return errors.Wrap(err, "failed to create the event stream")
+	}
+	defer eventsReader.Close()
+
+	stderr := &lastLineWriter{}
+	cmd := exec.Command(executable, args...)
+	cmd.Stderr = io.MultiWriter(logFile, stderr)
+	cmd.ExtraFiles = []*os.File{eventsWriter}
+	err = cmd.Start()
+	eventsWriter.Close()
+	if err != nil {
+		return errors.Wrap(err, "failed to run the installer")
+	}
+
+	var failed *event
+	scanner := bufio.NewScanner(eventsReader)
+	for scanner.Scan() {
+		var ev event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			logrus.Debugf("%s: failed to parse event: %v", c.name, err)
+			continue
+		}
+		c.phase = ev.Phase
+		logrus.Infof("%s: %s %s", c.name, ev.Phase, ev.Event)
+		if ev.Event == eventFailed {
+			failed = &ev
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if failed != nil && failed.Error != "" {
+			return errors.New(failed.Error)
+		}
+		if line := stderr.Line(); line != "" {
+			return errors.New(line)
+		}
+		return err
+	}
+	return nil
+}
+
+// lastLineWriter keeps the last non-empty line written to it.
+type lastLineWriter struct {
+	partial []byte
+	last    string
+}
+
+func (w *lastLineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		if line := strings.TrimSpace(string(w.partial[:i])); line != "" {
+			w.last = line
+		}
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Line returns the last non-empty line, including an unterminated one.
+func (w *lastLineWriter) Line() string {
+	if line := strings.TrimSpace(string(w.partial)); line != "" {
+		return line
+	}
+	return w.last
+}
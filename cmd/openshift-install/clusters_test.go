@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterConfigs(t *testing.T) {
+	cases := []struct {
+		name          string
+		files         map[string]string
+		expected      map[string]string
+		expectedError string
+	}{
+		{
+			name: "named after files",
+			files: map[string]string{
+				"east.yaml":  "metadata:\n  name: east\n",
+				"west.yml":   "baseDomain: example.com\n",
+				"README.md":  "# Clusters\n",
+				"notes.yaml": "metadata:\n  name: notes\n",
+			},
+			expected: map[string]string{
+				"east":  "east.yaml",
+				"west":  "west.yml",
+				"notes": "notes.yaml",
+			},
+		},
+		{
+			name: "metadata.name mismatch",
+			files: map[string]string{
+				"east.yaml": "metadata:\n  name: west\n",
+			},
+			expectedError: `^east\.yaml is for cluster "east", but its metadata\.name is "west"; rename the file or change its metadata\.name$`,
+		},
+		{
+			name: "invalid cluster name",
+			files: map[string]string{
+				"East_1.yaml": "metadata:\n  name: East_1\n",
+			},
+			expectedError: `^invalid cluster name from East_1\.yaml: `,
+		},
+		{
+			name: "invalid YAML",
+			files: map[string]string{
+				"east.yaml": "metadata: [\n",
+			},
+			expectedError: `^failed to parse east\.yaml: `,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "openshift-install-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			for name, data := range tc.files {
+				if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			clusters, err := clusterConfigs(dir)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			actual := map[string]string{}
+			for _, c := range clusters {
+				actual[c.name] = filepath.Base(c.config)
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
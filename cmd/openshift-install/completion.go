@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func newCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion SHELL",
+		Short: "Outputs shell completion code for the specified shell (bash, zsh or fish)",
+		Long: `Outputs shell completion code for the specified shell (bash, zsh or fish).
+
+For example, to load completion for the current bash session:
+
+  source <(openshift-install completion bash)
+`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"bash", "fish", "zsh"},
+		RunE:      runCompletionCmd,
+	}
+}
+
+func runCompletionCmd(cmd *cobra.Command, args []string) error {
+	root := cmd.Root()
+	switch args[0] {
+	case "bash":
+		return root.GenBashCompletion(os.Stdout)
+	case "zsh":
+		return root.GenZshCompletion(os.Stdout)
+	case "fish":
+		return genFishCompletion(root, os.Stdout)
+	default:
+		return errors.Errorf("unsupported shell %q", args[0])
+	}
+}
+
+// genFishCompletion writes fish completions for the command tree. The
+// vendored cobra only generates bash and zsh completions.
+func genFishCompletion(root *cobra.Command, w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "# fish completion for %s\n", root.Name()); err != nil {
+		return err
+	}
+	return writeFishCommand(root, root, w)
+}
+
+func writeFishCommand(root, cmd *cobra.Command, w io.Writer) error {
+	condition := fmt.Sprintf("__fish_seen_subcommand_from %s", cmd.Name())
+	if cmd == root {
+		condition = "__fish_use_subcommand"
+	}
+
+	var err error
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Hidden {
+			return
+		}
+		line := fmt.Sprintf("complete -c %s -l %s -d %s", root.Name(), flag.Name, fishQuote(flag.Usage))
+		if cmd != root {
+			line += fmt.Sprintf(" -n %s", fishQuote(condition))
+		}
+		if flag.Shorthand != "" {
+			line += fmt.Sprintf(" -s %s", flag.Shorthand)
+		}
+		if _, dirs := flag.Annotations[cobra.BashCompSubdirsInDir]; dirs {
+			line += " -r -f -a '(__fish_complete_directories)'"
+		} else if flag.Value.Type() != "bool" {
+			line += " -r"
+		}
+		_, err = fmt.Fprintln(w, line)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, child := range cmd.Commands() {
+		if !child.IsAvailableCommand() {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "complete -c %s -f -n %s -a %s -d %s\n", root.Name(), fishQuote(condition), child.Name(), fishQuote(child.Short)); err != nil {
+			return err
+		}
+		if err := writeFishCommand(root, child, w); err != nil {
+			return err
+		}
+	}
+
+	for _, arg := range cmd.ValidArgs {
+		if _, err := fmt.Fprintf(w, "complete -c %s -f -n %s -a %s\n", root.Name(), fishQuote(condition), arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fishQuote single-quotes s for fish.
+func fishQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return "'" + strings.Replace(s, "'", `\'`, -1) + "'"
+}
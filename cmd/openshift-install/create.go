@@ -3,15 +3,19 @@ package main
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
+	"net/http"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/crypto/ssh/terminal"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -30,6 +34,33 @@ import (
 	"github.com/openshift/installer/pkg/asset/manifests"
 	"github.com/openshift/installer/pkg/asset/templates"
 	destroybootstrap "github.com/openshift/installer/pkg/destroy/bootstrap"
+	"github.com/openshift/installer/pkg/encryption"
+	"github.com/openshift/installer/pkg/failure"
+)
+
+var (
+	createOpts struct {
+		releaseImage   string
+		pullSecretFile string
+		progress       bool
+		outputFormat   string
+		outputArchive  string
+		keepBootstrap  bool
+		ignitionRoles  []string
+		oidcKubeconfig string
+
+		allowManifestHooks bool
+
+		apiTimeout       time.Duration
+		bootstrapTimeout time.Duration
+		installTimeout   time.Duration
+
+		hooks      []string
+		phaseHooks map[string][]string
+
+		installConfig        string
+		installConfigHeaders []string
+	}
 )
 
 type target struct {
@@ -63,6 +94,16 @@ var (
 		assets: []asset.WritableAsset{&manifests.Manifests{}, &manifests.Openshift{}},
 	}
 
+	hiveManifestsTarget = target{
+		name: "Hive Manifests",
+		command: &cobra.Command{
+			Use:   "hive-manifests",
+			Short: "Generates a Hive ClusterDeployment and ClusterImageSet equivalent to the install config",
+			Long:  "",
+		},
+		assets: []asset.WritableAsset{&manifests.Hive{}},
+	}
+
 	manifestTemplatesTarget = target{
 		name: "Manifest templates",
 		command: &cobra.Command{
@@ -92,28 +133,41 @@ var (
 			// FIXME: add longer descriptions for our commands with examples for better UX.
 			// Long:  "",
 			PostRunE: func(_ *cobra.Command, _ []string) error {
+				defer progress.Stop()
 				ctx := context.Background()
-				config, err := clientcmd.BuildConfigFromFlags("", filepath.Join(rootOpts.dir, "auth", "kubeconfig"))
+				data, err := kubeconfig.ReadAdmin(rootOpts.dir)
+				if err != nil {
+					return errors.Wrap(err, "loading kubeconfig")
+				}
+				config, err := clientcmd.RESTConfigFromKubeConfig(data)
 				if err != nil {
 					return errors.Wrap(err, "loading kubeconfig")
 				}
+				if err := useKubeconfigProxy(config, data); err != nil {
+					return errors.Wrap(err, "loading kubeconfig")
+				}
 
 				err = destroyBootstrap(ctx, config, rootOpts.dir)
 				if err != nil {
 					return err
 				}
+				setPhase(phaseOperators)
+				stopWatching := make(chan struct{})
+				watchFailingOperators(config, stopWatching)
 				consoleURL, err := waitForConsole(ctx, config, rootOpts.dir)
+				close(stopWatching)
 				if err != nil {
 					return err
 				}
 
+				progress.Stop()
 				return logComplete(rootOpts.dir, consoleURL)
 			},
 		},
-		assets: []asset.WritableAsset{&cluster.TerraformVariables{}, &kubeconfig.Admin{}, &cluster.Cluster{}},
+		assets: []asset.WritableAsset{&cluster.TerraformVariables{}, &kubeconfig.Admin{}, &kubeconfig.OIDC{}, &cluster.Cluster{}},
 	}
 
-	targets = []target{installConfigTarget, manifestTemplatesTarget, manifestsTarget, ignitionConfigsTarget, clusterTarget}
+	targets = []target{installConfigTarget, manifestTemplatesTarget, manifestsTarget, hiveManifestsTarget, ignitionConfigsTarget, clusterTarget}
 )
 
 func newCreateCmd() *cobra.Command {
@@ -125,28 +179,182 @@ func newCreateCmd() *cobra.Command {
 		},
 	}
 
+	cmd.PersistentFlags().StringVar(&createOpts.releaseImage, "release-image", "", "digest-pinned release image to install when generating the install config")
+	cmd.PersistentFlags().StringVar(&createOpts.pullSecretFile, "pull-secret-file", "", "pull secret to use when generating the install config: a file like ~/.docker/config.json or a podman auth file, whose registry auths are used")
+	cmd.PersistentFlags().StringVarP(&createOpts.installConfig, "install-config", "f", "", "install config to use instead of generating one: a path, an https:// URL, or - for stdin")
+	cmd.PersistentFlags().StringArrayVar(&createOpts.installConfigHeaders, "install-config-header", nil, "header (e.g. \"Authorization: Bearer ...\") sent when fetching --install-config from a URL; may be repeated")
+	cmd.PersistentFlags().StringVar(&createOpts.outputArchive, "output-archive", "", "write the generated assets to this tar.gz instead of the asset directory (create cluster writes both, as it needs the assets on disk)")
+	cmd.PersistentFlags().BoolVar(&createOpts.allowManifestHooks, "allow-manifest-hooks", false, "run the commands listed in the install config's manifestHooks when generating the manifests")
+	cmd.PersistentFlags().BoolVar(&createOpts.progress, "progress", false, "show a progress display instead of info logs when run in a terminal")
+
+	addClusterFlags(clusterTarget.command.Flags())
+	ignitionConfigsTarget.command.Flags().StringSliceVar(&createOpts.ignitionRoles, "role", nil, "generate only the Ignition configs of these roles (bootstrap, master or worker), e.g. to regenerate worker.ign for scaling out; may be repeated")
+	manifestsTarget.command.Flags().StringVar(&createOpts.outputFormat, "output-format", outputFormatDirectory, fmt.Sprintf("how to write the manifests: %q for the directory layout, or %q or %q to print a single YAML or JSON bundle to stdout", outputFormatDirectory, outputFormatBundle, outputFormatBundleJSON))
+
 	for _, t := range targets {
 		t.command.RunE = runTargetCmd(t.assets...)
 		cmd.AddCommand(t.command)
 	}
+	ignitionConfigsTarget.command.RunE = func(cmd *cobra.Command, args []string) error {
+		assets, err := ignitionConfigAssets(createOpts.ignitionRoles)
+		if err != nil {
+			return err
+		}
+		return runTargetCmd(assets...)(cmd, args)
+	}
+	cmd.AddCommand(newCreateClustersCmd())
 
 	return cmd
 }
 
+// addClusterFlags adds the flags of 'create cluster', which 'create clusters'
+// forwards to each cluster's installer, to flags.
+func addClusterFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&createOpts.keepBootstrap, "keep-bootstrap", false, "keep the bootstrap machine after bootstrapping completes, for debugging; remove it later with 'openshift-install destroy bootstrap'")
+	flags.DurationVar(&createOpts.apiTimeout, "api-timeout", 30*time.Minute, "how long to wait for the Kubernetes API to come up")
+	flags.DurationVar(&createOpts.bootstrapTimeout, "bootstrap-timeout", 30*time.Minute, "how long to wait for bootstrapping to complete once the Kubernetes API is up")
+	flags.DurationVar(&createOpts.installTimeout, "install-timeout", 10*time.Minute, "how long to wait for the operators to settle and the console to be available after bootstrapping")
+	flags.StringVar(&createOpts.oidcKubeconfig, "oidc-kubeconfig", "", "also write auth/kubeconfig-oidc, which logs users in with this OpenID identity provider of the install config through 'kubectl oidc-login'")
+	flags.StringArrayVar(&createOpts.hooks, "hook", nil, fmt.Sprintf("command run by sh -c at a phase of the install, as <phase>=<command>, with $KUBECONFIG and $OPENSHIFT_INSTALL_METADATA set once they exist; the phases are %s; may be repeated", strings.Join(hookPhases, ", ")))
+}
+
+// ignitionConfigAssets returns the Ignition config assets of the given
+// roles, or all of them if there are none.
+func ignitionConfigAssets(roles []string) ([]asset.WritableAsset, error) {
+	if len(roles) == 0 {
+		return ignitionConfigsTarget.assets, nil
+	}
+	byRole := map[string]asset.WritableAsset{
+		"bootstrap": &bootstrap.Bootstrap{},
+		"master":    &machine.Master{},
+		"worker":    &machine.Worker{},
+	}
+	assets := make([]asset.WritableAsset, 0, len(roles))
+	for _, role := range roles {
+		a, ok := byRole[role]
+		if !ok {
+			return nil, errors.Errorf("unsupported role %q: must be bootstrap, master or worker", role)
+		}
+		if a != nil {
+			assets = append(assets, a)
+			byRole[role] = nil
+		}
+	}
+	return assets, nil
+}
+
+// dependsOnInstallConfig returns true if any of the targets is, or depends
+// on, the install config, which consumes the secrets of the secrets backend.
+func dependsOnInstallConfig(targets []asset.WritableAsset) bool {
+	seen := map[reflect.Type]bool{}
+	var visit func(a asset.Asset) bool
+	visit = func(a asset.Asset) bool {
+		t := reflect.TypeOf(a)
+		if seen[t] {
+			return false
+		}
+		seen[t] = true
+		if _, ok := a.(*installconfig.InstallConfig); ok {
+			return true
+		}
+		for _, dep := range a.Dependencies() {
+			if visit(dep) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, a := range targets {
+		if visit(a) {
+			return true
+		}
+	}
+	return false
+}
+
 func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args []string) error {
-	return func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) (err error) {
 		cleanup, err := setupFileHook(rootOpts.dir)
 		if err != nil {
 			return errors.Wrap(err, "failed to setup logging hook")
 		}
 		defer cleanup()
+		startPhase(progressPhaseNames[phaseAssets])
+
+		if createOpts.progress && terminal.IsTerminal(int(os.Stderr.Fd())) {
+			progress = startProgress(os.Stderr, stderrHook)
+			defer func() {
+				// Commands which wait on the cluster afterwards stop the
+				// display themselves.
+				if err != nil || cmd.PostRunE == nil {
+					progress.Stop()
+				}
+			}()
+		}
+
+		installconfig.SetReleaseImage(createOpts.releaseImage)
+
+		if createOpts.pullSecretFile != "" {
+			path := createOpts.pullSecretFile
+			if strings.HasPrefix(path, "~/") {
+				path = filepath.Join(os.Getenv("HOME"), path[2:])
+			}
+			installconfig.SetPullSecretFile(path)
+		}
+
+		if createOpts.allowManifestHooks {
+			if err := os.Setenv(manifests.AllowManifestHooksEnvVar, "true"); err != nil {
+				return errors.Wrap(err, "failed to allow the manifest hooks")
+			}
+		}
+
+		if createOpts.oidcKubeconfig != "" {
+			if err := os.Setenv(kubeconfig.OIDCIdentityProviderEnvVar, createOpts.oidcKubeconfig); err != nil {
+				return errors.Wrap(err, "failed to set the OIDC identity provider")
+			}
+		}
+
+		if dependsOnInstallConfig(targets) {
+			if err := installconfig.ExportSecrets(); err != nil {
+				return errors.Wrap(err, "failed to read secrets")
+			}
+		}
+
+		if createOpts.installConfig != "" {
+			if err := writeInstallConfig(rootOpts.dir, createOpts.installConfig, createOpts.installConfigHeaders); err != nil {
+				return err
+			}
+		}
+
+		createOpts.phaseHooks, err = parsePhaseHooks(createOpts.hooks)
+		if err != nil {
+			return err
+		}
+
+		bundle := createOpts.outputFormat != "" && createOpts.outputFormat != outputFormatDirectory
+		if bundle && createOpts.outputFormat != outputFormatBundle && createOpts.outputFormat != outputFormatBundleJSON {
+			return errors.Errorf("unsupported output format %q", createOpts.outputFormat)
+		}
+		if bundle && createOpts.outputArchive != "" {
+			return errors.New("--output-format and --output-archive cannot be combined")
+		}
+		// Commands which wait on the cluster afterwards read their assets
+		// from disk.
+		persist := createOpts.outputArchive == "" || cmd.PostRunE != nil
 
 		assetStore, err := asset.NewStore(rootOpts.dir)
 		if err != nil {
 			return errors.Wrapf(err, "failed to create asset store")
 		}
 
+		var bundleFiles []*asset.File
 		for _, a := range targets {
+			if _, ok := a.(*cluster.Cluster); ok {
+				if err := runAfterManifestsHooks(assetStore, rootOpts.dir); err != nil {
+					return err
+				}
+				setPhase(phaseInfrastructure)
+			}
 			err := assetStore.Fetch(a)
 			if err != nil {
 				if exitError, ok := errors.Cause(err).(*exec.ExitError); ok && len(exitError.Stderr) > 0 {
@@ -155,6 +363,21 @@ func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args
 				err = errors.Wrapf(err, "failed to fetch %s", a.Name())
 			}
 
+			if bundle {
+				if err != nil {
+					return err
+				}
+				bundleFiles = append(bundleFiles, a.Files()...)
+				continue
+			}
+
+			if !persist {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
 			if err2 := asset.PersistToFile(a, rootOpts.dir); err2 != nil {
 				err2 = errors.Wrapf(err2, "failed to write asset (%s) to disk", a.Name())
 				if err != nil {
@@ -167,6 +390,22 @@ func runTargetCmd(targets ...asset.WritableAsset) func(cmd *cobra.Command, args
 			if err != nil {
 				return err
 			}
+
+			if _, ok := a.(*cluster.Cluster); ok {
+				if err := runPhaseHooks(phaseAfterInfrastructure, rootOpts.dir); err != nil {
+					return err
+				}
+			}
+		}
+
+		if bundle {
+			return writeBundle(os.Stdout, createOpts.outputFormat, bundleFiles)
+		}
+		if createOpts.outputArchive != "" {
+			if err := asset.PersistToArchive(createOpts.outputArchive, targets...); err != nil {
+				return errors.Wrapf(err, "failed to write %s", createOpts.outputArchive)
+			}
+			logrus.Infof("Wrote the assets to %s", createOpts.outputArchive)
 		}
 		return nil
 	}
@@ -187,8 +426,9 @@ func destroyBootstrap(ctx context.Context, config *rest.Config, directory string
 	}
 
 	discovery := client.Discovery()
+	setPhase(phaseBootstrap)
 
-	apiTimeout := 30 * time.Minute
+	apiTimeout := createOpts.apiTimeout
 	logrus.Infof("Waiting %v for the Kubernetes API...", apiTimeout)
 	apiContext, cancel := context.WithTimeout(ctx, apiTimeout)
 	defer cancel()
@@ -217,10 +457,13 @@ func destroyBootstrap(ctx context.Context, config *rest.Config, directory string
 			}
 		}
 	}, 2*time.Second, apiContext.Done())
+	if apiContext.Err() == context.DeadlineExceeded {
+		return failure.New(failure.APITimeout, "bootstrap", "check that the bootstrap and master machines are running and that the API load balancer reaches them; see docs/user/troubleshooting.md", errors.Wrap(apiContext.Err(), "waiting for the Kubernetes API"))
+	}
 
 	events := client.CoreV1().Events("kube-system")
 
-	eventTimeout := 30 * time.Minute
+	eventTimeout := createOpts.bootstrapTimeout
 	logrus.Infof("Waiting %v for the bootstrap-complete event...", eventTimeout)
 	eventContext, cancel := context.WithTimeout(ctx, eventTimeout)
 	defer cancel()
@@ -264,13 +507,40 @@ func destroyBootstrap(ctx context.Context, config *rest.Config, directory string
 		},
 	)
 	if err != nil {
-		return errors.Wrap(err, "waiting for bootstrap-complete")
+		return failure.New(failure.BootstrapTimeout, "bootstrap", "SSH to the bootstrap machine and check 'journalctl -u bootkube.service'; slow environments may need a longer --bootstrap-timeout", errors.Wrap(err, "waiting for bootstrap-complete"))
+	}
+	if err := runPhaseHooks(phaseAfterBootstrapComplete, directory); err != nil {
+		return err
 	}
 
+	if createOpts.keepBootstrap {
+		logrus.Info("Keeping the bootstrap resources; run 'openshift-install destroy bootstrap' to destroy them")
+		return nil
+	}
+	if err := runPhaseHooks(phaseBeforeBootstrapDestroy, directory); err != nil {
+		return err
+	}
 	logrus.Info("Destroying the bootstrap resources...")
 	return destroybootstrap.Destroy(rootOpts.dir)
 }
 
+// useKubeconfigProxy makes the REST config reach the API through the
+// kubeconfig's proxy-url, which the vendored client-go does not know of.
+func useKubeconfigProxy(config *rest.Config, kubeconfigData []byte) error {
+	proxyURL, err := kubeconfig.ProxyURL(kubeconfigData)
+	if err != nil || proxyURL == nil {
+		return err
+	}
+	logrus.Debugf("Reaching the API through the proxy at %s", proxyURL.Host)
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if t, ok := rt.(*http.Transport); ok && t != http.DefaultTransport {
+			t.Proxy = http.ProxyURL(proxyURL)
+		}
+		return rt
+	}
+	return nil
+}
+
 // waitForconsole returns the console URL from the route 'console' in namespace openshift-console
 func waitForConsole(ctx context.Context, config *rest.Config, directory string) (string, error) {
 	url := ""
@@ -282,7 +552,7 @@ func waitForConsole(ctx context.Context, config *rest.Config, directory string)
 		return "", errors.Wrap(err, "creating a route client")
 	}
 
-	consoleRouteTimeout := 10 * time.Minute
+	consoleRouteTimeout := createOpts.installTimeout
 	logrus.Infof("Waiting %v for the openshift-console route to be created...", consoleRouteTimeout)
 	consoleRouteContext, cancel := context.WithTimeout(ctx, consoleRouteTimeout)
 	defer cancel()
@@ -320,7 +590,7 @@ func waitForConsole(ctx context.Context, config *rest.Config, directory string)
 		return "", errors.Wrap(err, "waiting for console route to be created")
 	}
 	if url == "" {
-		return url, errors.Wrap(err, "could not obtain openshift-console URL from route")
+		return url, failure.New(failure.InstallTimeout, "operators", "check for failing operators with 'oc get clusteroperators'; slow environments may need a longer --install-timeout", errors.Wrap(consoleRouteContext.Err(), "could not obtain openshift-console URL from route"))
 	}
 	return url, nil
 }
@@ -331,14 +601,18 @@ func logComplete(directory, consoleURL string) error {
 	if err != nil {
 		return err
 	}
-	kubeconfig := filepath.Join(absDir, "auth", "kubeconfig")
+	kubeconfigPath := kubeconfig.AdminPath(absDir)
 	pwFile := filepath.Join(absDir, "auth", "kubeadmin-password")
-	pw, err := ioutil.ReadFile(pwFile)
+	pw, err := encryption.ReadFile(pwFile)
 	if err != nil {
 		return err
 	}
 	logrus.Info("Install complete!")
-	logrus.Infof("Run 'export KUBECONFIG=%s' to manage the cluster with 'oc', the OpenShift CLI.", kubeconfig)
+	if encryption.Enabled() {
+		logrus.Infof("The auth files are encrypted; run 'openshift-install decrypt %s' for the kubeconfig to manage the cluster with 'oc', the OpenShift CLI.", kubeconfigPath)
+	} else {
+		logrus.Infof("Run 'export KUBECONFIG=%s' to manage the cluster with 'oc', the OpenShift CLI.", kubeconfigPath)
+	}
 	logrus.Infof("The cluster is ready when 'oc login -u kubeadmin -p %s' succeeds (wait a few minutes).", pw)
 	logrus.Infof("Access the OpenShift web-console here: %s", consoleURL)
 	logrus.Infof("Login to the console with user: kubeadmin, password: %s", pw)
@@ -1,15 +1,22 @@
 package main
 
 import (
+	"path/filepath"
+	"time"
+
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/cluster"
 	"github.com/openshift/installer/pkg/destroy"
 	"github.com/openshift/installer/pkg/destroy/bootstrap"
 	_ "github.com/openshift/installer/pkg/destroy/libvirt"
 	_ "github.com/openshift/installer/pkg/destroy/openstack"
+	"github.com/openshift/installer/pkg/failure"
+	"github.com/openshift/installer/pkg/types"
 )
 
 func newDestroyCmd() *cobra.Command {
@@ -26,12 +33,30 @@ func newDestroyCmd() *cobra.Command {
 	return cmd
 }
 
+var (
+	destroyOpts struct {
+		metadata      string
+		force         bool
+		timeout       time.Duration
+		retries       int
+		backoff       time.Duration
+		backoffFactor float64
+	}
+)
+
 func newDestroyClusterCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "cluster",
 		Short: "Destroy an OpenShift cluster",
 		RunE:  runDestroyCmd,
 	}
+	cmd.Flags().StringVar(&destroyOpts.metadata, "metadata", "", "metadata.json of the cluster to destroy, e.g. copied from its asset directory; defaults to the one in the asset directory")
+	cmd.Flags().BoolVar(&destroyOpts.force, "force", false, "remove what keeps resources from being deleted, e.g. empty versioned buckets, disassociate elastic IPs and detach network interfaces")
+	cmd.Flags().DurationVar(&destroyOpts.timeout, "timeout", 0, "give up destroying the cluster after this long, listing the resources which remain; 0 waits for as long as it takes")
+	cmd.Flags().IntVar(&destroyOpts.retries, "retries", destroy.DefaultBackoff.Steps, "number of times deleting each kind of resource is attempted")
+	cmd.Flags().DurationVar(&destroyOpts.backoff, "backoff", destroy.DefaultBackoff.Duration, "delay before retrying to delete a kind of resource the first time")
+	cmd.Flags().Float64Var(&destroyOpts.backoffFactor, "backoff-factor", destroy.DefaultBackoff.Factor, "factor by which the delay between retries grows")
+	return cmd
 }
 
 func runDestroyCmd(cmd *cobra.Command, args []string) error {
@@ -40,12 +65,45 @@ func runDestroyCmd(cmd *cobra.Command, args []string) error {
 		return errors.Wrap(err, "failed to setup logging hook")
 	}
 	defer cleanup()
+	startPhase(phaseDestroy)
 
-	destroyer, err := destroy.New(logrus.StandardLogger(), rootOpts.dir)
+	if destroyOpts.retries < 1 {
+		return errors.New("--retries must be positive")
+	}
+	opts := destroy.Options{
+		Force:   destroyOpts.force,
+		Timeout: destroyOpts.timeout,
+		Backoff: wait.Backoff{
+			Duration: destroyOpts.backoff,
+			Factor:   destroyOpts.backoffFactor,
+			Steps:    destroyOpts.retries,
+		},
+	}
+	var metadata *types.ClusterMetadata
+	if destroyOpts.metadata != "" {
+		metadata, err = cluster.LoadMetadataFile(destroyOpts.metadata)
+	} else {
+		metadata, err = cluster.LoadMetadata(rootOpts.dir)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed while preparing to destroy cluster")
+	}
+	destroyer, err := destroy.NewFromMetadata(logrus.WithField("component", "destroy"), metadata, opts)
 	if err != nil {
 		return errors.Wrap(err, "Failed while preparing to destroy cluster")
 	}
-	if err := destroyer.Run(); err != nil {
+
+	recorder := destroy.NewRecorder(metadata)
+	logrus.AddHook(recorder)
+	err = destroyer.Run()
+	reportPath := filepath.Join(rootOpts.dir, destroy.ReportFileName)
+	if err2 := recorder.End(err).Write(reportPath); err2 != nil {
+		logrus.Warnf("Failed to write the destroy report to %s: %v", reportPath, err2)
+	}
+	if err != nil {
+		if _, ok := errors.Cause(err).(*destroy.RemainingResourcesError); ok {
+			err = failure.New(failure.DestroyIncomplete, "destroy", "delete what blocks the remaining resources, or retry with --force, then rerun 'openshift-install destroy cluster'", err)
+		}
 		return errors.Wrap(err, "Failed to destroy cluster")
 	}
 
@@ -66,6 +124,7 @@ func newDestroyBootstrapCmd() *cobra.Command {
 		Use:   "bootstrap",
 		Short: "Destroy the bootstrap resources",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			startPhase(phaseDestroy)
 			return bootstrap.Destroy(rootOpts.dir)
 		},
 	}
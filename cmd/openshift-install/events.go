@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/failure"
+	"github.com/openshift/installer/pkg/redact"
+)
+
+const (
+	eventStarted   = "started"
+	eventCompleted = "completed"
+	eventFailed    = "failed"
+
+	// phaseDestroy is the phase of the destroy commands.
+	phaseDestroy = "destroy"
+)
+
+// events is the phase event stream of the running command, or nil when
+// none was requested with --events. All its methods are no-ops on nil.
+var events *eventStream
+
+// event is a line of the event stream.
+type event struct {
+	Time            time.Time `json:"time"`
+	Command         string    `json:"command"`
+	Phase           string    `json:"phase"`
+	Event           string    `json:"event"`
+	DurationSeconds float64   `json:"durationSeconds,omitempty"`
+	Code            string    `json:"code,omitempty"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// eventStream writes JSON lines marking when the phases of a command
+// start, complete and fail, for CI to track their timing without parsing
+// the logs.
+type eventStream struct {
+	lock       sync.Mutex
+	file       *os.File
+	command    string
+	phase      string
+	phaseStart time.Time
+}
+
+// openEventStream opens the event stream of command on target: a file,
+// which is appended to, or fd:<N> for an open file descriptor.
+func openEventStream(target string, command string) (*eventStream, error) {
+	var file *os.File
+	if strings.HasPrefix(target, "fd:") {
+		fd, err := strconv.Atoi(strings.TrimPrefix(target, "fd:"))
+		if err != nil || fd < 0 {
+			return nil, errors.Errorf("invalid file descriptor %q", target)
+		}
+		file = os.NewFile(uintptr(fd), target)
+	} else {
+		var err error
+		file, err = os.OpenFile(target, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &eventStream{file: file, command: command}, nil
+}
+
+// StartPhase completes the current phase, if any, and starts phase.
+func (e *eventStream) StartPhase(phase string) {
+	if e == nil {
+		return
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if phase == e.phase {
+		return
+	}
+	now := time.Now()
+	if e.phase != "" {
+		e.write(event{Time: now, Phase: e.phase, Event: eventCompleted, DurationSeconds: now.Sub(e.phaseStart).Seconds()})
+	}
+	e.phase, e.phaseStart = phase, now
+	e.write(event{Time: now, Phase: phase, Event: eventStarted})
+}
+
+// End completes or, with an error, fails the current phase, and closes the
+// stream.
+func (e *eventStream) End(err error) {
+	if e == nil {
+		return
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.phase != "" {
+		now := time.Now()
+		ev := event{Time: now, Phase: e.phase, Event: eventCompleted, DurationSeconds: now.Sub(e.phaseStart).Seconds()}
+		if err != nil {
+			ev.Event, ev.Error = eventFailed, redact.String(err.Error())
+			if f := failure.Find(err); f != nil {
+				ev.Code = string(f.Code)
+			}
+		}
+		e.write(ev)
+		e.phase = ""
+	}
+	e.file.Close()
+}
+
+// write writes an event. The caller must hold the lock.
+func (e *eventStream) write(ev event) {
+	ev.Time = ev.Time.UTC()
+	ev.Command = e.command
+	data, err := json.Marshal(ev)
+	if err != nil {
+		logrus.Debugf("Failed to marshal event: %v", err)
+		return
+	}
+	if _, err := e.file.Write(append(data, '\n')); err != nil {
+		logrus.Debugf("Failed to write event: %v", err)
+	}
+}
+
+// startPhase moves the event stream and the metrics on to phase.
+func startPhase(phase string) {
+	events.StartPhase(phase)
+	metrics.StartPhase(phase)
+}
+
+// setPhase moves the progress display, the event stream and the metrics on
+// to phase.
+func setPhase(phase progressPhase) {
+	progress.SetPhase(phase)
+	startPhase(progressPhaseNames[phase])
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/kubeconfig"
+	"github.com/openshift/installer/pkg/asset/manifests"
+	"github.com/openshift/installer/pkg/encryption"
+)
+
+const (
+	// phaseAfterManifests is once the manifests are generated, before the
+	// infrastructure is created.
+	phaseAfterManifests = "after-manifests"
+
+	// phaseAfterInfrastructure is once Terraform has created the
+	// infrastructure.
+	phaseAfterInfrastructure = "after-infrastructure"
+
+	// phaseAfterBootstrapComplete is once the bootstrap-complete event is
+	// seen.
+	phaseAfterBootstrapComplete = "after-bootstrap-complete"
+
+	// phaseBeforeBootstrapDestroy is right before the bootstrap resources
+	// are destroyed.
+	phaseBeforeBootstrapDestroy = "before-bootstrap-destroy"
+)
+
+var hookPhases = []string{phaseAfterManifests, phaseAfterInfrastructure, phaseAfterBootstrapComplete, phaseBeforeBootstrapDestroy}
+
+// parsePhaseHooks parses hook specifications such as
+// "after-infrastructure=/usr/local/bin/register-dns --zone example.com"
+// into the commands to run at each phase, in the order they were given.
+// The commands are run by sh -c, so they are quoted as in the shell.
+func parsePhaseHooks(specs []string) (map[string][]string, error) {
+	hooks := map[string][]string{}
+	for _, spec := range specs {
+		eq := strings.Index(spec, "=")
+		if eq < 0 {
+			return nil, errors.Errorf("invalid hook %q: must be <phase>=<command>", spec)
+		}
+
+		phase, command := spec[:eq], strings.TrimSpace(spec[eq+1:])
+		known := false
+		for _, p := range hookPhases {
+			known = known || p == phase
+		}
+		if !known {
+			return nil, errors.Errorf("invalid hook %q: unknown phase %q; must be one of %s", spec, phase, strings.Join(hookPhases, ", "))
+		}
+		if command == "" {
+			return nil, errors.Errorf("invalid hook %q: no command", spec)
+		}
+		hooks[phase] = append(hooks[phase], command)
+	}
+	return hooks, nil
+}
+
+// runPhaseHooks runs the hooks of phase in the asset directory, passing the
+// paths of the kubeconfig and metadata, once they are written, and any
+// extra environment, to them.  An encrypted kubeconfig is passed as a
+// plaintext copy, removed once the hooks are done.  The first failing hook
+// fails the phase.
+func runPhaseHooks(phase string, directory string, env ...string) error {
+	hooks := createOpts.phaseHooks[phase]
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	absDir, err := filepath.Abs(directory)
+	if err != nil {
+		return err
+	}
+	env = append(append(os.Environ(),
+		fmt.Sprintf("OPENSHIFT_INSTALL_PHASE=%s", phase),
+		fmt.Sprintf("OPENSHIFT_INSTALL_ASSET_DIR=%s", absDir),
+	), env...)
+	// Before the infrastructure is created, the files in the asset
+	// directory are left over from earlier installs, if any.
+	afterInfrastructure := phase != phaseAfterManifests
+	if metadata := filepath.Join(absDir, "metadata.json"); afterInfrastructure && fileExists(metadata) {
+		env = append(env, fmt.Sprintf("OPENSHIFT_INSTALL_METADATA=%s", metadata))
+	}
+	if kubeconfigPath := kubeconfig.AdminPath(absDir); afterInfrastructure && fileExists(kubeconfigPath) {
+		path, cleanup, err := encryption.PlaintextFile(kubeconfigPath)
+		if err != nil {
+			return errors.Wrap(err, "failed to read the kubeconfig for the hooks")
+		}
+		defer cleanup()
+		env = append(env, fmt.Sprintf("KUBECONFIG=%s", path))
+	}
+
+	for _, hook := range hooks {
+		logrus.Infof("Running %s hook %s...", phase, hook)
+		cmd := exec.Command("sh", "-c", hook)
+		cmd.Dir = absDir
+		cmd.Env = env
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		stdout, err := cmd.Output()
+		if len(stdout) > 0 {
+			logrus.Debugf("Hook %s output: %s", hook, strings.TrimSpace(string(stdout)))
+		}
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				err = errors.Wrap(err, msg)
+			}
+			return errors.Wrapf(err, "%s hook %s", phase, hook)
+		}
+	}
+	return nil
+}
+
+// fileExists returns true if there is a file at path.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// runAfterManifestsHooks generates the manifests and runs the
+// after-manifests hooks with a copy of them, which create cluster does not
+// write to the asset directory, in the directory named by
+// OPENSHIFT_INSTALL_MANIFESTS_DIR.
+func runAfterManifestsHooks(assetStore asset.Store, directory string) error {
+	if len(createOpts.phaseHooks[phaseAfterManifests]) == 0 {
+		return nil
+	}
+
+	assets := []asset.WritableAsset{&manifests.Manifests{}, &manifests.Openshift{}}
+	for _, a := range assets {
+		if err := assetStore.Fetch(a); err != nil {
+			return errors.Wrapf(err, "failed to fetch %s", a.Name())
+		}
+	}
+
+	manifestsDir, err := ioutil.TempDir("", "openshift-install-manifests-")
+	if err != nil {
+		return errors.Wrap(err, "failed to create the manifests directory")
+	}
+	defer os.RemoveAll(manifestsDir)
+	for _, a := range assets {
+		if err := asset.PersistToFile(a, manifestsDir); err != nil {
+			return errors.Wrapf(err, "failed to write %s", a.Name())
+		}
+	}
+	return runPhaseHooks(phaseAfterManifests, directory, fmt.Sprintf("OPENSHIFT_INSTALL_MANIFESTS_DIR=%s", manifestsDir))
+}
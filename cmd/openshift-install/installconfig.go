@@ -0,0 +1,119 @@
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// installConfigHeaderEnvVar names the environment variable holding an
+// extra header for fetching the install config from a URL, as an
+// alternative to --install-config-header which keeps credentials out of
+// the process list.
+const installConfigHeaderEnvVar = "OPENSHIFT_INSTALL_CONFIG_HEADER"
+
+// maxInstallConfigSize bounds the install config read from stdin or a URL.
+const maxInstallConfigSize = 1 << 20
+
+// writeInstallConfig reads the install config from source, which is "-"
+// for stdin, an https:// URL or a path, and writes it to the asset
+// directory where the Install Config asset loads it from.
+func writeInstallConfig(directory, source string, headers []string) error {
+	target := filepath.Join(directory, installconfig.InstallConfigFilename)
+	if _, err := os.Stat(target); err == nil {
+		return errors.Errorf("%s already exists; remove it to use an install config from %s", target, source)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	data, err := readInstallConfig(source, headers)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the install config from %s", source)
+	}
+	// Reject what is not an install config before writing it, since the
+	// written file has to be removed before the next attempt.
+	if err := yaml.Unmarshal(data, &types.InstallConfig{}); err != nil {
+		return errors.Wrapf(err, "failed to parse the install config from %s", source)
+	}
+
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return errors.Wrap(err, "failed to create the asset directory")
+	}
+	// The install config includes the pull secret.
+	return ioutil.WriteFile(target, data, 0600)
+}
+
+func readInstallConfig(source string, headers []string) ([]byte, error) {
+	switch {
+	case source == "-":
+		return readLimited(os.Stdin)
+	case strings.Contains(source, "://"):
+		return fetchInstallConfig(source, headers)
+	default:
+		return ioutil.ReadFile(source)
+	}
+}
+
+func fetchInstallConfig(source string, headers []string) ([]byte, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" {
+		return nil, errors.Errorf("unsupported scheme %q; the install config includes the pull secret, so only https is supported", u.Scheme)
+	}
+
+	req, err := http.NewRequest("GET", source, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build request")
+	}
+	if header, ok := os.LookupEnv(installConfigHeaderEnvVar); ok {
+		headers = append(headers, header)
+	}
+	for i, header := range headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			// Do not echo the header, which may hold credentials.
+			return nil, errors.Errorf("invalid header %d; must be of the form Name: value", i+1)
+		}
+		req.Header.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+
+	redacted := *u
+	redacted.User = nil
+	logrus.WithField("component", "http").Debugf("Fetching the install config from %s", redacted.String())
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("incorrect HTTP response (%s)", resp.Status)
+	}
+	return readLimited(resp.Body)
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxInstallConfigSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxInstallConfigSize {
+		return nil, errors.Errorf("larger than %d bytes", maxInstallConfigSize)
+	}
+	return data, nil
+}
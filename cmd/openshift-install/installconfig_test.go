@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+)
+
+// dirFetcher fetches asset files from a directory.
+type dirFetcher string
+
+func (d dirFetcher) FetchByName(name string) (*asset.File, error) {
+	data, err := ioutil.ReadFile(filepath.Join(string(d), name))
+	if err != nil {
+		return nil, err
+	}
+	return &asset.File{Filename: name, Data: data}, nil
+}
+
+func (d dirFetcher) FetchByPattern(pattern string) ([]*asset.File, error) {
+	return nil, nil
+}
+
+func TestWriteInstallConfig(t *testing.T) {
+	cases := []struct {
+		name          string
+		data          string
+		expectedError string
+		expectedLoad  string
+	}{
+		{
+			name: "install config",
+			data: `clusterID: 3b9a5bcd-5d4f-4e8e-a5cb-8e9d2f1c7a60
+metadata:
+  name: test
+baseDomain: example.com
+platform:
+  libvirt: {}
+pullSecret: '{"auths":{"quay.io":{"auth":"dXNlcjpwYXNz"}}}'
+sshKey: ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAQa4Iv4kujekvpBNjtL6RaiTHaOyPN0kjDN0mEgRQeX you@example.com
+`,
+		},
+		{
+			name:          "missing file",
+			expectedError: `^failed to read the install config from .*: open .*: no such file or directory$`,
+		},
+		{
+			name:          "invalid YAML",
+			data:          "metadata: [\n",
+			expectedError: `^failed to parse the install config from .*: `,
+		},
+		{
+			name: "invalid install config",
+			data: `clusterID: 3b9a5bcd-5d4f-4e8e-a5cb-8e9d2f1c7a60
+metadata:
+  name: test
+baseDomain: example.com
+platform:
+  libvirt: {}
+pullSecret: '{"auths":{"quay.io":{"auth":"dXNlcjpwYXNz"}}}'
+sshKey: ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDxL
+`,
+			expectedLoad: `^invalid "install-config\.yml" file: sshKey\[0\]: Invalid value: `,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "openshift-install-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			source := filepath.Join(dir, "source.yaml")
+			if tc.data != "" {
+				if err := ioutil.WriteFile(source, []byte(tc.data), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			assetDir := filepath.Join(dir, "assets")
+
+			err = writeInstallConfig(assetDir, source, nil)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				_, err := os.Stat(filepath.Join(assetDir, installconfig.InstallConfigFilename))
+				assert.True(t, os.IsNotExist(err), "the install config was written")
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// The written install config is validated when it is loaded.
+			found, err := (&installconfig.InstallConfig{}).Load(dirFetcher(assetDir))
+			if tc.expectedLoad == "" {
+				assert.NoError(t, err)
+				assert.True(t, found)
+			} else {
+				assert.Regexp(t, tc.expectedLoad, err)
+			}
+		})
+	}
+}
+
+func TestFetchInstallConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "metadata:\n  name: test\n")
+	}))
+	defer server.Close()
+	// fetchInstallConfig's client uses the default transport, which does
+	// not trust the test server's certificate.
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = server.Client().Transport
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	cases := []struct {
+		name          string
+		source        string
+		headers       []string
+		headerEnv     string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "header flag",
+			source:   server.URL,
+			headers:  []string{"Authorization: Bearer token"},
+			expected: "metadata:\n  name: test\n",
+		},
+		{
+			name:      "header environment variable",
+			source:    server.URL,
+			headerEnv: "Authorization: Bearer token",
+			expected:  "metadata:\n  name: test\n",
+		},
+		{
+			name:          "unauthorized",
+			source:        server.URL,
+			expectedError: `^incorrect HTTP response \(401 Unauthorized\)$`,
+		},
+		{
+			name:          "invalid header",
+			source:        server.URL,
+			headers:       []string{"Bearer token"},
+			expectedError: `^invalid header 1; must be of the form Name: value$`,
+		},
+		{
+			name:          "http",
+			source:        "http://example.com/install-config.yaml",
+			expectedError: `^unsupported scheme "http"; the install config includes the pull secret, so only https is supported$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.headerEnv != "" {
+				os.Setenv(installConfigHeaderEnvVar, tc.headerEnv)
+				defer os.Unsetenv(installConfigHeaderEnvVar)
+			}
+			data, err := readInstallConfig(tc.source, tc.headers)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+			assert.Equal(t, tc.expected, string(data))
+		})
+	}
+}
@@ -1,18 +1,29 @@
 package main
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/redact"
 )
 
+// logComponents are the values of the "component" field with which the
+// subsystems tag their log entries, and whose levels can be set apart.
+var logComponents = []string{"assets", "terraform", "destroy", "http"}
+
 type fileHook struct {
 	file      io.Writer
 	formatter logrus.Formatter
 	level     logrus.Level
+
+	// componentLevels override level for the entries of some components.
+	componentLevels map[string]logrus.Level
 }
 
 func newFileHook(file io.Writer, level logrus.Level, formatter logrus.Formatter) *fileHook {
@@ -24,9 +35,16 @@ func newFileHook(file io.Writer, level logrus.Level, formatter logrus.Formatter)
 }
 
 func (h fileHook) Levels() []logrus.Level {
+	maxLevel := h.level
+	for _, level := range h.componentLevels {
+		if level > maxLevel {
+			maxLevel = level
+		}
+	}
+
 	var levels []logrus.Level
 	for _, level := range logrus.AllLevels {
-		if level <= h.level {
+		if level <= maxLevel {
 			levels = append(levels, level)
 		}
 	}
@@ -35,15 +53,67 @@ func (h fileHook) Levels() []logrus.Level {
 }
 
 func (h *fileHook) Fire(entry *logrus.Entry) error {
+	// The hook is registered for its initial levels; skip entries above
+	// the current one.
+	level := h.level
+	if component, ok := entry.Data["component"].(string); ok {
+		if componentLevel, ok := h.componentLevels[component]; ok {
+			level = componentLevel
+		}
+	}
+	if entry.Level > level {
+		return nil
+	}
+
 	line, err := h.formatter.Format(entry)
 	if err != nil {
 		return err
 	}
 
-	_, err = h.file.Write(line)
+	// Debug output includes Terraform plans and error messages which may
+	// embed asset contents, so never write secrets to the logs.
+	_, err = h.file.Write(redact.Bytes(line))
 	return err
 }
 
+// parseLogLevels parses a log level specification such as
+// "info,terraform=debug": a default level, optionally followed by
+// levels for some components.
+func parseLogLevels(spec string) (logrus.Level, map[string]logrus.Level, error) {
+	level := logrus.InfoLevel
+	componentLevels := map[string]logrus.Level{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		eq := strings.Index(part, "=")
+		if eq < 0 {
+			l, err := logrus.ParseLevel(part)
+			if err != nil {
+				return level, nil, err
+			}
+			level = l
+			continue
+		}
+
+		component, value := part[:eq], part[eq+1:]
+		known := false
+		for _, c := range logComponents {
+			known = known || c == component
+		}
+		if !known {
+			return level, nil, fmt.Errorf("unknown component %q; must be one of %s", component, strings.Join(logComponents, ", "))
+		}
+		l, err := logrus.ParseLevel(value)
+		if err != nil {
+			return level, nil, err
+		}
+		componentLevels[component] = l
+	}
+	return level, componentLevels, nil
+}
+
 func setupFileHook(baseDir string) (func(), error) {
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
 		return nil, errors.Wrap(err, "failed to create base directory for logs")
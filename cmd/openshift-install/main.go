@@ -3,18 +3,33 @@ package main
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/failure"
+	"github.com/openshift/installer/pkg/redact"
+	"github.com/openshift/installer/pkg/validate"
 )
 
 var (
 	rootOpts struct {
-		dir      string
-		logLevel string
+		dir             string
+		cluster         string
+		logLevel        string
+		vaultPath       string
+		vaultAuthMethod string
+		events          string
+		metricsFile     string
+		pushgateway     string
 	}
+
+	// stderrHook writes log entries to the terminal.
+	stderrHook *fileHook
 )
 
 func main() {
@@ -25,11 +40,22 @@ func main() {
 		newDestroyCmd(),
 		newVersionCmd(),
 		newGraphCmd(),
+		newCompletionCmd(),
+		newDecryptCmd(),
+		newCertificatesCmd(),
+		newAnalyzeCmd(),
+		newAdoptCmd(),
 	} {
 		rootCmd.AddCommand(subCmd)
 	}
 
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	events.End(err)
+	metrics.End(err)
+	if err != nil {
+		if f := failure.Find(err); f != nil {
+			logrus.Errorf("Failure %s", f.Summary())
+		}
 		logrus.Fatalf("Error executing openshift-install: %v", err)
 	}
 }
@@ -44,7 +70,15 @@ func newRootCmd() *cobra.Command {
 		SilenceUsage:      true,
 	}
 	cmd.PersistentFlags().StringVar(&rootOpts.dir, "dir", ".", "assets directory")
-	cmd.PersistentFlags().StringVar(&rootOpts.logLevel, "log-level", "info", "log level (e.g. \"debug | info | warn | error\")")
+	// Complete --dir with directories.
+	cmd.PersistentFlags().SetAnnotation("dir", cobra.BashCompSubdirsInDir, []string{})
+	cmd.PersistentFlags().StringVar(&rootOpts.cluster, "cluster", "", "name of the cluster whose assets to manage, which the install config must have as its metadata.name; each cluster's assets and state are kept under <dir>/clusters/<name>")
+	cmd.PersistentFlags().StringVar(&rootOpts.logLevel, "log-level", "info", "log level (e.g. \"debug | info | warn | error\"), optionally followed by levels for the assets, terraform, destroy and http components (e.g. \"info,terraform=debug\")")
+	cmd.PersistentFlags().StringVar(&rootOpts.vaultPath, "vault-path", "", "Vault KV secret (e.g. secret/data/openshift) holding the pullSecret, sshKey and cloud credentials, read from $VAULT_ADDR; equivalent to OPENSHIFT_INSTALL_VAULT_PATH")
+	cmd.PersistentFlags().StringVar(&rootOpts.vaultAuthMethod, "vault-auth-method", "", "how to authenticate to Vault: token (the default), approle or kubernetes; equivalent to OPENSHIFT_INSTALL_VAULT_AUTH_METHOD")
+	cmd.PersistentFlags().StringVar(&rootOpts.events, "events", "", "append JSON lines marking when the phases of create and destroy start, complete and fail to this file, or to fd:<N>")
+	cmd.PersistentFlags().StringVar(&rootOpts.metricsFile, "metrics-file", "", "write how long each phase of create and destroy took, and whether the command succeeded, to this JSON file")
+	cmd.PersistentFlags().StringVar(&rootOpts.pushgateway, "metrics-pushgateway", "", "push how long each phase of create and destroy took, and whether the command succeeded, to the Prometheus pushgateway at this URL")
 	return cmd
 }
 
@@ -52,12 +86,12 @@ func runRootCmd(cmd *cobra.Command, args []string) error {
 	logrus.SetOutput(ioutil.Discard)
 	logrus.SetLevel(logrus.TraceLevel)
 
-	level, err := logrus.ParseLevel(rootOpts.logLevel)
+	level, componentLevels, err := parseLogLevels(rootOpts.logLevel)
 	if err != nil {
 		return errors.Wrap(err, "invalid log-level")
 	}
 
-	logrus.AddHook(newFileHook(os.Stderr, level, &logrus.TextFormatter{
+	stderrHook = newFileHook(os.Stderr, level, &logrus.TextFormatter{
 		// Setting ForceColors is necessary because logrus.TextFormatter determines
 		// whether or not to enable colors by looking at the output of the logger.
 		// In this case, the output is ioutil.Discard, which is not a terminal.
@@ -66,7 +100,43 @@ func runRootCmd(cmd *cobra.Command, args []string) error {
 		ForceColors:            terminal.IsTerminal(int(os.Stderr.Fd())),
 		DisableTimestamp:       true,
 		DisableLevelTruncation: true,
-	}))
+	})
+	stderrHook.componentLevels = componentLevels
+	logrus.AddHook(stderrHook)
+	redact.RegisterEnvironment()
 
+	if rootOpts.events != "" {
+		events, err = openEventStream(rootOpts.events, cmd.CommandPath())
+		if err != nil {
+			return errors.Wrap(err, "failed to open the event stream")
+		}
+	}
+
+	if rootOpts.cluster != "" {
+		if err := validate.DomainName(rootOpts.cluster); err != nil {
+			return errors.Wrap(err, "invalid cluster")
+		}
+		// Namespace everything generated for the cluster, including the
+		// asset state, Terraform state and logs, so several clusters can
+		// share a directory.
+		rootOpts.dir = filepath.Join(rootOpts.dir, "clusters", rootOpts.cluster)
+		installconfig.SetClusterName(rootOpts.cluster)
+	}
+
+	if rootOpts.metricsFile != "" || rootOpts.pushgateway != "" {
+		metrics = newInstallMetrics(rootOpts.metricsFile, rootOpts.pushgateway, cmd.CommandPath(), rootOpts.dir)
+	}
+
+	for envVar, value := range map[string]string{
+		"OPENSHIFT_INSTALL_VAULT_PATH":        rootOpts.vaultPath,
+		"OPENSHIFT_INSTALL_VAULT_AUTH_METHOD": rootOpts.vaultAuthMethod,
+	} {
+		if value == "" {
+			continue
+		}
+		if err := os.Setenv(envVar, value); err != nil {
+			return errors.Wrapf(err, "failed to set %s", envVar)
+		}
+	}
 	return nil
 }
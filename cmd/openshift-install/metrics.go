@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/failure"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// metrics records the phase durations of the running command, or is nil
+// when neither --metrics-file nor --metrics-pushgateway was given. All its
+// methods are no-ops on nil.
+var metrics *installMetrics
+
+// phaseMetric is the duration of a phase.
+type phaseMetric struct {
+	Name            string  `json:"name"`
+	DurationSeconds float64 `json:"durationSeconds"`
+}
+
+// metricsReport is what metrics.json holds.
+type metricsReport struct {
+	Command         string        `json:"command"`
+	ClusterName     string        `json:"clusterName,omitempty"`
+	InfraID         string        `json:"infraID,omitempty"`
+	Platform        string        `json:"platform,omitempty"`
+	Start           time.Time     `json:"start"`
+	DurationSeconds float64       `json:"durationSeconds"`
+	Success         bool          `json:"success"`
+	FailedPhase     string        `json:"failedPhase,omitempty"`
+	FailureCode     string        `json:"failureCode,omitempty"`
+	Phases          []phaseMetric `json:"phases"`
+}
+
+// installMetrics records how long the phases of a command take, for
+// tracking install performance across a fleet, and writes them to a file
+// or pushes them to a Prometheus pushgateway when the command ends.
+type installMetrics struct {
+	lock        sync.Mutex
+	file        string
+	pushgateway string
+	directory   string
+	report      metricsReport
+	phase       string
+	phaseStart  time.Time
+}
+
+func newInstallMetrics(file string, pushgateway string, command string, directory string) *installMetrics {
+	m := &installMetrics{
+		file:        file,
+		pushgateway: strings.TrimSuffix(pushgateway, "/"),
+		directory:   directory,
+		report:      metricsReport{Command: command, Start: time.Now().UTC(), Phases: []phaseMetric{}},
+	}
+	// Destroying the cluster removes its metadata, so it is read now.
+	m.readMetadata()
+	return m
+}
+
+// StartPhase completes the current phase, if any, and starts phase.
+func (m *installMetrics) StartPhase(phase string) {
+	if m == nil {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if phase == m.phase {
+		return
+	}
+	now := time.Now()
+	m.endPhase(now)
+	m.phase, m.phaseStart = phase, now
+}
+
+// endPhase records the duration of the current phase. The caller must hold
+// the lock.
+func (m *installMetrics) endPhase(now time.Time) {
+	if m.phase == "" {
+		return
+	}
+	m.report.Phases = append(m.report.Phases, phaseMetric{Name: m.phase, DurationSeconds: now.Sub(m.phaseStart).Seconds()})
+}
+
+// End completes the current phase and writes or pushes the metrics.
+// Failing to do so is logged, but does not fail the command.
+func (m *installMetrics) End(err error) {
+	if m == nil {
+		return
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	now := time.Now()
+	m.endPhase(now)
+	m.report.DurationSeconds = now.Sub(m.report.Start).Seconds()
+	m.report.Success = err == nil
+	if err != nil {
+		m.report.FailedPhase = m.phase
+		if f := failure.Find(err); f != nil {
+			m.report.FailureCode = string(f.Code)
+		}
+	}
+	m.phase = ""
+	// Creating the cluster writes its metadata.
+	m.readMetadata()
+
+	if m.file != "" {
+		data, err := json.MarshalIndent(m.report, "", "  ")
+		if err == nil {
+			err = ioutil.WriteFile(m.file, append(data, '\n'), 0644)
+		}
+		if err != nil {
+			logrus.Warnf("Failed to write the metrics to %s: %v", m.file, err)
+		}
+	}
+	if m.pushgateway != "" {
+		if err := m.push(); err != nil {
+			logrus.Warnf("Failed to push the metrics to %s: %v", m.pushgateway, err)
+		}
+	}
+}
+
+// readMetadata reads the cluster's name, infrastructure ID and platform
+// from the asset directory's metadata.json, if there is one.
+func (m *installMetrics) readMetadata() {
+	data, err := ioutil.ReadFile(filepath.Join(m.directory, "metadata.json"))
+	if err != nil {
+		return
+	}
+	var metadata types.ClusterMetadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return
+	}
+	m.report.ClusterName = metadata.ClusterName
+	m.report.InfraID = metadata.InfraID
+	switch {
+	case metadata.AWS != nil:
+		m.report.Platform = "aws"
+	case metadata.OpenStack != nil:
+		m.report.Platform = "openstack"
+	case metadata.Libvirt != nil:
+		m.report.Platform = "libvirt"
+	}
+}
+
+// push replaces the metrics of the command's group in the pushgateway:
+// job openshift-install, and the cluster, if known.
+func (m *installMetrics) push() error {
+	groupingKey := "/metrics/job/openshift-install"
+	if m.report.ClusterName != "" {
+		groupingKey += "/cluster/" + url.PathEscape(m.report.ClusterName)
+	}
+	req, err := http.NewRequest("PUT", m.pushgateway+groupingKey, bytes.NewReader(m.report.exposition()))
+	if err != nil {
+		return errors.Wrap(err, "failed to build request")
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	logrus.WithField("component", "http").Debugf("Pushing the metrics to %s", req.URL)
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return errors.Errorf("incorrect HTTP response (%s)", resp.Status)
+	}
+	return nil
+}
+
+// exposition returns the metrics in the Prometheus text format.
+func (r *metricsReport) exposition() []byte {
+	labels := fmt.Sprintf(`command=%q`, r.Command)
+	if r.Platform != "" {
+		labels += fmt.Sprintf(`,platform=%q`, r.Platform)
+	}
+	success := 0
+	if r.Success {
+		success = 1
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "# HELP openshift_install_phase_duration_seconds How long each phase of the command took.")
+	fmt.Fprintln(&buf, "# TYPE openshift_install_phase_duration_seconds gauge")
+	for _, phase := range r.Phases {
+		fmt.Fprintf(&buf, "openshift_install_phase_duration_seconds{%s,phase=%q} %g\n", labels, phase.Name, phase.DurationSeconds)
+	}
+	fmt.Fprintln(&buf, "# HELP openshift_install_duration_seconds How long the command took.")
+	fmt.Fprintln(&buf, "# TYPE openshift_install_duration_seconds gauge")
+	fmt.Fprintf(&buf, "openshift_install_duration_seconds{%s} %g\n", labels, r.DurationSeconds)
+	fmt.Fprintln(&buf, "# HELP openshift_install_success Whether the command succeeded.")
+	fmt.Fprintln(&buf, "# TYPE openshift_install_success gauge")
+	fmt.Fprintf(&buf, "openshift_install_success{%s} %d\n", labels, success)
+	fmt.Fprintln(&buf, "# HELP openshift_install_start_timestamp_seconds When the command started.")
+	fmt.Fprintln(&buf, "# TYPE openshift_install_start_timestamp_seconds gauge")
+	fmt.Fprintf(&buf, "openshift_install_start_timestamp_seconds{%s} %d\n", labels, r.Start.Unix())
+	return buf.Bytes()
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// progressPhase is a stage of cluster creation.
+type progressPhase int
+
+const (
+	phaseAssets progressPhase = iota
+	phaseInfrastructure
+	phaseBootstrap
+	phaseOperators
+)
+
+var progressPhaseNames = []string{"assets", "infrastructure", "bootstrap", "operators"}
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// progress is the progress display for the running command, or nil when
+// plain logs are shown. All its methods are no-ops on nil.
+var progress *progressDisplay
+
+// progressDisplay shows the current phase of cluster creation on a single,
+// continuously redrawn terminal line. Warnings and errors are still logged
+// above it; other log entries only go to the log file.
+type progressDisplay struct {
+	out  io.Writer
+	hook *fileHook
+	// originalLevel, originalComponentLevels and originalFile are
+	// restored to hook on stop.
+	originalLevel           logrus.Level
+	originalComponentLevels map[string]logrus.Level
+	originalFile            io.Writer
+
+	lock       sync.Mutex
+	start      time.Time
+	phase      progressPhase
+	phaseStart time.Time
+	status     string
+	frame      int
+	done       chan struct{}
+	stopped    bool
+}
+
+// startProgress starts the progress display on out, taking over hook's
+// output.
+func startProgress(out io.Writer, hook *fileHook) *progressDisplay {
+	now := time.Now()
+	p := &progressDisplay{
+		out:                     out,
+		hook:                    hook,
+		originalLevel:           hook.level,
+		originalComponentLevels: hook.componentLevels,
+		originalFile:            hook.file,
+		start:                   now,
+		phaseStart:              now,
+		done:                    make(chan struct{}),
+	}
+	hook.file = p
+	hook.componentLevels = nil
+	if hook.level > logrus.WarnLevel {
+		hook.level = logrus.WarnLevel
+	}
+
+	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-ticker.C:
+				p.lock.Lock()
+				p.frame++
+				p.draw()
+				p.lock.Unlock()
+			}
+		}
+	}()
+	return p
+}
+
+// Write writes log output above the progress line.
+func (p *progressDisplay) Write(data []byte) (int, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.clear()
+	n, err := p.out.Write(data)
+	p.draw()
+	return n, err
+}
+
+// SetPhase marks the current phase complete and moves on to phase.
+func (p *progressDisplay) SetPhase(phase progressPhase) {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if phase == p.phase || p.stopped {
+		return
+	}
+	p.clear()
+	fmt.Fprintf(p.out, "done %s (%s)\n", progressPhaseNames[p.phase], since(p.phaseStart))
+	p.phase = phase
+	p.phaseStart = time.Now()
+	p.status = ""
+	p.draw()
+}
+
+// SetStatus sets a message describing what the current phase is waiting
+// on, e.g. a failing operator. An empty status clears it.
+func (p *progressDisplay) SetStatus(status string) {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.status = status
+}
+
+// Stop removes the progress line and restores plain logging.
+func (p *progressDisplay) Stop() {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.stopped {
+		return
+	}
+	p.stopped = true
+	close(p.done)
+	p.clear()
+	p.hook.file = p.originalFile
+	p.hook.level = p.originalLevel
+	p.hook.componentLevels = p.originalComponentLevels
+}
+
+// clear erases the progress line. The caller must hold the lock.
+func (p *progressDisplay) clear() {
+	if !p.stopped {
+		fmt.Fprint(p.out, "\r\033[K")
+	}
+}
+
+// draw redraws the progress line. The caller must hold the lock.
+func (p *progressDisplay) draw() {
+	if p.stopped {
+		return
+	}
+	line := fmt.Sprintf("%s [%d/%d] %s %s (total %s)",
+		spinnerFrames[p.frame%len(spinnerFrames)],
+		int(p.phase)+1,
+		len(progressPhaseNames),
+		progressPhaseNames[p.phase],
+		since(p.phaseStart),
+		since(p.start),
+	)
+	if p.status != "" {
+		line += ": " + p.status
+	}
+	fmt.Fprint(p.out, "\r\033[K"+line)
+}
+
+func since(t time.Time) time.Duration {
+	return time.Since(t).Round(time.Second)
+}
+
+// watchFailingOperators reports the first failing cluster operator as the
+// progress status until stop is closed.
+func watchFailingOperators(config *rest.Config, stop <-chan struct{}) {
+	if progress == nil {
+		return
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		logrus.Debugf("Not watching cluster operators: %v", err)
+		return
+	}
+	go wait.Until(func() {
+		data, err := client.CoreV1().RESTClient().Get().AbsPath("/apis/config.openshift.io/v1/clusteroperators").DoRaw()
+		if err != nil {
+			logrus.Debugf("Failed to list cluster operators: %v", err)
+			return
+		}
+		var operators configv1.ClusterOperatorList
+		if err := json.Unmarshal(data, &operators); err != nil {
+			logrus.Debugf("Failed to parse cluster operators: %v", err)
+			return
+		}
+		progress.SetStatus(failingOperator(operators.Items))
+	}, 10*time.Second, stop)
+}
+
+// failingOperator describes the first failing operator, or returns an empty
+// string if none are failing.
+func failingOperator(operators []configv1.ClusterOperator) string {
+	for _, operator := range operators {
+		for _, condition := range operator.Status.Conditions {
+			if condition.Type == configv1.OperatorFailing && condition.Status == configv1.ConditionTrue {
+				message := strings.SplitN(condition.Message, "\n", 2)[0]
+				return fmt.Sprintf("%s failing: %s", operator.Name, message)
+			}
+		}
+	}
+	return ""
+}
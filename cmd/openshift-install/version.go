@@ -1,33 +1,62 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/installer/pkg/rhcos"
 	"github.com/openshift/installer/pkg/terraform"
+	"github.com/openshift/installer/pkg/types"
 )
 
 var (
 	version = "was not built correctly" // set in hack/build.sh
+
+	versionOpts struct {
+		output string
+	}
 )
 
+// versionInfo is everything needed to reproduce an install with this
+// binary.
+type versionInfo struct {
+	Version   string `json:"version"`
+	Terraform string `json:"terraform"`
+	// Providers maps each platform to the version constraints of the
+	// Terraform provider plugins it uses.
+	Providers    map[string]map[string]string `json:"providers"`
+	RHCOSChannel string                       `json:"rhcosChannel"`
+	// RHCOSBuild is empty if the latest build could not be fetched.
+	RHCOSBuild   string `json:"rhcosBuild,omitempty"`
+	ReleaseImage string `json:"releaseImage"`
+}
+
 func newVersionCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print version information",
 		Long:  "",
 		RunE:  runVersionCmd,
 	}
+	cmd.Flags().StringVarP(&versionOpts.output, "output", "o", "", "output format (e.g. \"json\"); prints human-readable text if empty")
+	return cmd
 }
 
 func runVersionCmd(cmd *cobra.Command, args []string) error {
-	fmt.Printf("%s %s\n", os.Args[0], version)
+	if versionOpts.output != "" && versionOpts.output != "json" {
+		return errors.Errorf("unsupported output format %q", versionOpts.output)
+	}
+
 	terraformVersion, err := terraform.Version()
 	if err != nil {
 		exitError, ok := err.(*exec.ExitError)
@@ -36,6 +65,63 @@ func runVersionCmd(cmd *cobra.Command, args []string) error {
 		}
 		return errors.Wrap(err, "Failed to calculate Terraform version")
 	}
-	fmt.Println(terraformVersion)
+
+	info := versionInfo{
+		Version:      version,
+		Terraform:    terraformVersion,
+		Providers:    map[string]map[string]string{},
+		RHCOSChannel: rhcos.DefaultChannel,
+		ReleaseImage: types.DefaultReleaseImage,
+	}
+	for _, platform := range types.PlatformNames {
+		providers, err := terraform.ProviderVersions(platform)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the %s Terraform provider versions", platform)
+		}
+		info.Providers[platform] = providers
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+	info.RHCOSBuild, err = rhcos.LatestBuild(ctx, rhcos.DefaultChannel)
+	if err != nil {
+		logrus.Warnf("Failed to fetch the latest RHCOS build: %v", err)
+	}
+
+	if versionOpts.output == "json" {
+		data, err := json.MarshalIndent(info, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal version information")
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%s %s\n", os.Args[0], info.Version)
+	fmt.Println(info.Terraform)
+	for _, platform := range types.PlatformNames {
+		for _, provider := range sortedKeys(info.Providers[platform]) {
+			constraint := info.Providers[platform][provider]
+			if constraint == "" {
+				constraint = "any version"
+			}
+			fmt.Printf("%s provider %s: %s\n", platform, provider, constraint)
+		}
+	}
+	rhcosBuild := info.RHCOSBuild
+	if rhcosBuild == "" {
+		rhcosBuild = "unknown"
+	}
+	fmt.Printf("RHCOS %s build: %s\n", info.RHCOSChannel, rhcosBuild)
+	fmt.Printf("release image: %s\n", info.ReleaseImage)
 	return nil
 }
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
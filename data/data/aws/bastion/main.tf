@@ -0,0 +1,62 @@
+data "ignition_user" "core" {
+  name                = "core"
+  ssh_authorized_keys = ["${var.ssh_keys}"]
+}
+
+data "ignition_config" "bastion" {
+  users = ["${data.ignition_user.core.id}"]
+}
+
+resource "aws_security_group" "bastion" {
+  count  = "${var.enabled ? 1 : 0}"
+  vpc_id = "${var.vpc_id}"
+
+  tags = "${merge(map(
+      "Name", "${var.infra_id}_bastion_sg",
+    ), var.tags)}"
+}
+
+resource "aws_security_group_rule" "bastion_ingress_ssh" {
+  count             = "${var.enabled ? 1 : 0}"
+  type              = "ingress"
+  security_group_id = "${aws_security_group.bastion.id}"
+
+  protocol    = "tcp"
+  cidr_blocks = ["${var.allowed_cidrs}"]
+  from_port   = 22
+  to_port     = 22
+}
+
+resource "aws_security_group_rule" "bastion_egress" {
+  count             = "${var.enabled ? 1 : 0}"
+  type              = "egress"
+  security_group_id = "${aws_security_group.bastion.id}"
+
+  protocol    = "-1"
+  cidr_blocks = ["0.0.0.0/0"]
+  from_port   = 0
+  to_port     = 0
+}
+
+resource "aws_instance" "bastion" {
+  count = "${var.enabled ? 1 : 0}"
+  ami   = "${var.ami}"
+
+  instance_type               = "${var.instance_type}"
+  subnet_id                   = "${var.subnet_id}"
+  user_data                   = "${data.ignition_config.bastion.rendered}"
+  vpc_security_group_ids      = ["${aws_security_group.bastion.id}"]
+  associate_public_ip_address = true
+
+  lifecycle {
+    # Ignore changes in the AMI which force recreation of the resource. This
+    # avoids accidental deletion of nodes whenever a new OS release comes out.
+    ignore_changes = ["ami"]
+  }
+
+  tags = "${merge(map(
+    "kubernetes.io/cluster/${var.cluster_name}", "owned",
+  ), var.tags)}"
+
+  volume_tags = "${var.tags}"
+}
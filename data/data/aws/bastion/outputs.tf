@@ -0,0 +1,7 @@
+output "instance_id" {
+  value = "${element(concat(aws_instance.bastion.*.id, list("")), 0)}"
+}
+
+output "public_ip" {
+  value = "${element(concat(aws_instance.bastion.*.public_ip, list("")), 0)}"
+}
@@ -0,0 +1,53 @@
+variable "allowed_cidrs" {
+  type        = "list"
+  default     = ["0.0.0.0/0"]
+  description = "The networks SSH connections to the bastion are allowed from."
+}
+
+variable "ami" {
+  type        = "string"
+  description = "The AMI ID for the bastion."
+}
+
+variable "cluster_name" {
+  type        = "string"
+  description = "The name of the cluster."
+}
+
+variable "infra_id" {
+  type        = "string"
+  description = "The infra ID, which prefixes the names of the cluster's resources."
+}
+
+variable "enabled" {
+  default     = false
+  description = "If set to true, the bastion is created."
+}
+
+variable "instance_type" {
+  type        = "string"
+  default     = "t3.micro"
+  description = "The instance type of the bastion."
+}
+
+variable "ssh_keys" {
+  type        = "list"
+  default     = []
+  description = "The SSH public keys authorized for the core user."
+}
+
+variable "subnet_id" {
+  type        = "string"
+  description = "The public subnet ID for the bastion."
+}
+
+variable "tags" {
+  type        = "map"
+  default     = {}
+  description = "AWS tags to be applied to created resources."
+}
+
+variable "vpc_id" {
+  type        = "string"
+  description = "The VPC ID for the bastion's security group."
+}
@@ -1,3 +1,10 @@
+data "aws_partition" "current" {}
+
+locals {
+  // EC2 is a service of amazonaws.com.cn in the China partition.
+  ec2_principal = "${data.aws_partition.current.partition == "aws-cn" ? "ec2.amazonaws.com.cn" : "ec2.amazonaws.com"}"
+}
+
 resource "aws_s3_bucket" "ignition" {
   acl = "private"
 
@@ -23,6 +30,59 @@ resource "aws_s3_bucket_object" "ignition" {
   }
 }
 
+# The bootstrap Ignition config holds the cluster's keys, so the object is
+# never public: the bootstrap node fetches it with its instance profile,
+# which may only read that object.
+resource "aws_s3_bucket_policy" "ignition" {
+  bucket = "${aws_s3_bucket.ignition.id}"
+
+  policy = <<EOF
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Sid": "DenyInsecureTransport",
+      "Effect": "Deny",
+      "Principal": "*",
+      "Action": "s3:*",
+      "Resource": [
+        "${aws_s3_bucket.ignition.arn}",
+        "${aws_s3_bucket.ignition.arn}/*"
+      ],
+      "Condition": {
+        "Bool": {
+          "aws:SecureTransport": "false"
+        }
+      }
+    },
+    {
+      "Sid": "DenyPublicACLs",
+      "Effect": "Deny",
+      "Principal": "*",
+      "Action": [
+        "s3:PutBucketAcl",
+        "s3:PutObject",
+        "s3:PutObjectAcl"
+      ],
+      "Resource": [
+        "${aws_s3_bucket.ignition.arn}",
+        "${aws_s3_bucket.ignition.arn}/*"
+      ],
+      "Condition": {
+        "StringEquals": {
+          "s3:x-amz-acl": [
+            "authenticated-read",
+            "public-read",
+            "public-read-write"
+          ]
+        }
+      }
+    }
+  ]
+}
+EOF
+}
+
 data "ignition_config" "redirect" {
   replace {
     source = "s3://${aws_s3_bucket.ignition.id}/bootstrap.ign"
@@ -30,7 +90,7 @@ data "ignition_config" "redirect" {
 }
 
 resource "aws_iam_instance_profile" "bootstrap" {
-  name = "${var.cluster_name}-bootstrap-profile"
+  name = "${var.infra_id}-bootstrap-profile"
 
   role = "${var.iam_role == "" ?
     join("|", aws_iam_role.bootstrap.*.name) :
@@ -45,7 +105,7 @@ data "aws_iam_role" "bootstrap" {
 
 resource "aws_iam_role" "bootstrap" {
   count = "${var.iam_role == "" ? 1 : 0}"
-  name  = "${var.cluster_name}-bootstrap-role"
+  name  = "${var.infra_id}-bootstrap-role"
   path  = "/"
 
   assume_role_policy = <<EOF
@@ -55,7 +115,7 @@ resource "aws_iam_role" "bootstrap" {
         {
             "Action": "sts:AssumeRole",
             "Principal": {
-                "Service": "ec2.amazonaws.com"
+                "Service": "${local.ec2_principal}"
             },
             "Effect": "Allow",
             "Sid": ""
@@ -67,7 +127,7 @@ EOF
 
 resource "aws_iam_role_policy" "bootstrap" {
   count = "${var.iam_role == "" ? 1 : 0}"
-  name  = "${var.cluster_name}-bootstrap-policy"
+  name  = "${var.infra_id}-bootstrap-policy"
   role  = "${aws_iam_role.bootstrap.id}"
 
   policy = <<EOF
@@ -93,7 +153,7 @@ resource "aws_iam_role_policy" "bootstrap" {
       "Action" : [
         "s3:GetObject"
       ],
-      "Resource": "arn:aws:s3:::*",
+      "Resource": "${aws_s3_bucket.ignition.arn}/${aws_s3_bucket_object.ignition.key}",
       "Effect": "Allow"
     }
   ]
@@ -136,3 +196,10 @@ resource "aws_lb_target_group_attachment" "bootstrap" {
   target_group_arn = "${var.target_group_arns[count.index]}"
   target_id        = "${aws_instance.bootstrap.private_ip}"
 }
+
+resource "aws_elb_attachment" "bootstrap" {
+  count = "${var.elb_ids_length}"
+
+  elb      = "${var.elb_ids[count.index]}"
+  instance = "${aws_instance.bootstrap.id}"
+}
@@ -13,6 +13,22 @@ variable "cluster_name" {
   description = "The name of the cluster."
 }
 
+variable "infra_id" {
+  type        = "string"
+  description = "The infra ID, which prefixes the names of the cluster's resources."
+}
+
+variable "elb_ids" {
+  type        = "list"
+  default     = []
+  description = "The list of classic load balancer IDs for the instances."
+}
+
+variable "elb_ids_length" {
+  default     = 0
+  description = "The length of the 'elb_ids' variable, to work around https://github.com/hashicorp/terraform/issues/12570."
+}
+
 variable "iam_role" {
   type        = "string"
   default     = ""
@@ -1,9 +1,14 @@
+data "aws_partition" "current" {}
+
 locals {
-  arn = "aws"
+  arn = "${data.aws_partition.current.partition}"
+
+  // EC2 is a service of amazonaws.com.cn in the China partition.
+  ec2_principal = "${data.aws_partition.current.partition == "aws-cn" ? "ec2.amazonaws.com.cn" : "ec2.amazonaws.com"}"
 }
 
 resource "aws_iam_instance_profile" "worker" {
-  name = "${var.cluster_name}-worker-profile"
+  name = "${var.infra_id}-worker-profile"
 
   role = "${var.worker_iam_role == "" ?
     join("|", aws_iam_role.worker_role.*.name) :
@@ -18,7 +23,7 @@ data "aws_iam_role" "worker_role" {
 
 resource "aws_iam_role" "worker_role" {
   count = "${var.worker_iam_role == "" ? 1 : 0}"
-  name  = "${var.cluster_name}-worker-role"
+  name  = "${var.infra_id}-worker-role"
   path  = "/"
 
   assume_role_policy = <<EOF
@@ -28,7 +33,7 @@ resource "aws_iam_role" "worker_role" {
         {
             "Action": "sts:AssumeRole",
             "Principal": {
-                "Service": "ec2.amazonaws.com"
+                "Service": "${local.ec2_principal}"
             },
             "Effect": "Allow",
             "Sid": ""
@@ -40,7 +45,7 @@ EOF
 
 resource "aws_iam_role_policy" "worker_policy" {
   count = "${var.worker_iam_role == "" ? 1 : 0}"
-  name  = "${var.cluster_name}_worker_policy"
+  name  = "${var.infra_id}_worker_policy"
   role  = "${aws_iam_role.worker_role.id}"
 
   policy = <<EOF
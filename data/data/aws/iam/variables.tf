@@ -1,4 +1,4 @@
-variable "cluster_name" {
+variable "infra_id" {
   type = "string"
 }
 
@@ -1,6 +1,7 @@
 locals {
   private_endpoints = "${var.aws_endpoints == "public" ? false : true}"
   public_endpoints  = "${var.aws_endpoints == "private" ? false : true}"
+  public_api        = "${local.public_endpoints && !var.aws_api_lb_internal}"
   private_zone_id   = "${var.aws_external_private_zone != "" ? var.aws_external_private_zone : join("", aws_route53_zone.int.*.zone_id)}"
 }
 
@@ -12,6 +13,16 @@ provider "aws" {
     role_arn     = "${var.aws_installer_role == "" ? "" : "${var.aws_installer_role}"}"
     session_name = "OPENSHIFT_INSTALLER_${var.cluster_name}"
   }
+
+  # Empty endpoints use the provider's defaults.
+  endpoints {
+    ec2 = "${lookup(var.aws_service_endpoints, "ec2", "")}"
+    elb = "${lookup(var.aws_service_endpoints, "elasticloadbalancing", "")}"
+    iam = "${lookup(var.aws_service_endpoints, "iam", "")}"
+    r53 = "${lookup(var.aws_service_endpoints, "route53", "")}"
+    s3  = "${lookup(var.aws_service_endpoints, "s3", "")}"
+    sts = "${lookup(var.aws_service_endpoints, "sts", "")}"
+  }
 }
 
 module "bootstrap" {
@@ -22,15 +33,39 @@ module "bootstrap" {
   cluster_name                = "${var.cluster_name}"
   iam_role                    = "${var.aws_master_iam_role_name}"
   ignition                    = "${var.ignition_bootstrap}"
+  infra_id                    = "${var.cluster_infra_id}"
+  instance_type               = "${var.aws_bootstrap_ec2_type}"
   subnet_id                   = "${module.vpc.master_subnet_ids[0]}"
   target_group_arns           = "${module.vpc.aws_lb_target_group_arns}"
   target_group_arns_length    = "${module.vpc.aws_lb_target_group_arns_length}"
+  elb_ids                     = "${module.vpc.aws_elb_api_ids}"
+  elb_ids_length              = "${module.vpc.aws_elb_api_ids_length}"
   vpc_security_group_ids      = ["${concat(var.aws_master_extra_sg_ids, list(module.vpc.master_sg_id))}"]
 
   tags = "${merge(map(
-      "Name", "${var.cluster_name}-bootstrap",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "Name", "${var.cluster_infra_id}-bootstrap",
+      "${var.aws_tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.aws_tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
+    ), var.aws_extra_tags)}"
+}
+
+module "bastion" {
+  source = "./bastion"
+
+  allowed_cidrs = "${var.aws_bastion_allowed_cidrs}"
+  ami           = "${var.aws_ec2_ami_override}"
+  cluster_name  = "${var.cluster_name}"
+  enabled       = "${var.aws_bastion}"
+  infra_id      = "${var.cluster_infra_id}"
+  instance_type = "${var.aws_bastion_ec2_type}"
+  ssh_keys      = "${var.aws_bastion_ssh_keys}"
+  subnet_id     = "${module.vpc.master_subnet_ids[0]}"
+  vpc_id        = "${module.vpc.vpc_id}"
+
+  tags = "${merge(map(
+      "Name", "${var.cluster_infra_id}-bastion",
+      "${var.aws_tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.aws_tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.aws_extra_tags)}"
 }
 
@@ -41,17 +76,23 @@ module "masters" {
   cluster_id               = "${var.cluster_id}"
   cluster_name             = "${var.cluster_name}"
   ec2_type                 = "${var.aws_master_ec2_type}"
+  elb_ids                  = "${module.vpc.aws_elb_api_ids}"
+  elb_ids_length           = "${module.vpc.aws_elb_api_ids_length}"
   extra_tags               = "${var.aws_extra_tags}"
+  infra_id                 = "${var.cluster_infra_id}"
   instance_count           = "${var.master_count}"
   master_iam_role          = "${var.aws_master_iam_role_name}"
   master_sg_ids            = "${concat(var.aws_master_extra_sg_ids, list(module.vpc.master_sg_id))}"
+  placement_group          = "${var.aws_master_placement_group}"
   public_endpoints         = "${local.public_endpoints}"
   root_volume_iops         = "${var.aws_master_root_volume_iops}"
   root_volume_size         = "${var.aws_master_root_volume_size}"
   root_volume_type         = "${var.aws_master_root_volume_type}"
   subnet_ids               = "${module.vpc.master_subnet_ids}"
+  tag_key_prefix           = "${var.aws_tag_key_prefix}"
   target_group_arns        = "${module.vpc.aws_lb_target_group_arns}"
   target_group_arns_length = "${module.vpc.aws_lb_target_group_arns_length}"
+  tenancy                  = "${var.aws_master_tenancy}"
   ec2_ami                  = "${var.aws_ec2_ami_override}"
   user_data_ign            = "${var.ignition_master}"
 }
@@ -59,7 +100,7 @@ module "masters" {
 module "iam" {
   source = "./iam"
 
-  cluster_name    = "${var.cluster_name}"
+  infra_id        = "${var.cluster_infra_id}"
   worker_iam_role = "${var.aws_worker_iam_role_name}"
 }
 
@@ -75,10 +116,12 @@ module "dns" {
   elb_alias_enabled        = true
   master_count             = "${var.master_count}"
   private_zone_id          = "${local.private_zone_id}"
+  public_zone_id           = "${var.aws_external_public_zone}"
+  public_records           = "${!var.aws_private_zone_only}"
   external_vpc_id          = "${module.vpc.vpc_id}"
   extra_tags               = "${var.aws_extra_tags}"
   private_endpoints        = "${local.private_endpoints}"
-  public_endpoints         = "${local.public_endpoints}"
+  public_endpoints         = "${local.public_api}"
 }
 
 module "vpc" {
@@ -89,17 +132,30 @@ module "vpc" {
   cluster_id      = "${var.cluster_id}"
   cluster_name    = "${var.cluster_name}"
   external_vpc_id = "${var.aws_external_vpc_id}"
+  infra_id        = "${var.cluster_infra_id}"
 
   external_master_subnet_ids = "${compact(var.aws_external_master_subnet_ids)}"
   external_worker_subnet_ids = "${compact(var.aws_external_worker_subnet_ids)}"
   extra_tags                 = "${var.aws_extra_tags}"
+  mcs_port                   = "${var.machine_config_server_port}"
 
   // empty map subnet_configs will have the vpc module creating subnets in all availabile AZs
   new_master_subnet_configs = "${var.aws_master_custom_subnets}"
   new_worker_subnet_configs = "${var.aws_worker_custom_subnets}"
+  secondary_cidr_blocks     = "${var.aws_vpc_secondary_cidr_blocks}"
+  tag_key_prefix            = "${var.aws_tag_key_prefix}"
 
   private_master_endpoints = "${local.private_endpoints}"
-  public_master_endpoints  = "${local.public_endpoints}"
+  public_master_endpoints  = "${local.public_api}"
+
+  api_lb_type               = "${var.aws_api_lb_type}"
+  api_lb_idle_timeout       = "${var.aws_api_lb_idle_timeout}"
+  api_health_check_interval = "${var.aws_api_lb_api_health_check_interval}"
+  api_healthy_threshold     = "${var.aws_api_lb_api_healthy_threshold}"
+  api_unhealthy_threshold   = "${var.aws_api_lb_api_unhealthy_threshold}"
+  mcs_health_check_interval = "${var.aws_api_lb_mcs_health_check_interval}"
+  mcs_healthy_threshold     = "${var.aws_api_lb_mcs_healthy_threshold}"
+  mcs_unhealthy_threshold   = "${var.aws_api_lb_mcs_unhealthy_threshold}"
 }
 
 resource "aws_route53_record" "etcd_a_nodes" {
@@ -128,7 +184,15 @@ resource "aws_route53_zone" "int" {
   tags = "${merge(map(
       "Name", "${var.cluster_name}_int",
       "KubernetesCluster", "${var.cluster_name}",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.aws_tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.aws_tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.aws_extra_tags)}"
 }
+
+output "bastion_instance_id" {
+  value = "${module.bastion.instance_id}"
+}
+
+output "bastion_public_ip" {
+  value = "${module.bastion.public_ip}"
+}
@@ -1,9 +1,14 @@
+data "aws_partition" "current" {}
+
 locals {
-  arn = "aws"
+  arn = "${data.aws_partition.current.partition}"
+
+  // EC2 is a service of amazonaws.com.cn in the China partition.
+  ec2_principal = "${data.aws_partition.current.partition == "aws-cn" ? "ec2.amazonaws.com.cn" : "ec2.amazonaws.com"}"
 }
 
 resource "aws_iam_instance_profile" "master" {
-  name = "${var.cluster_name}-master-profile"
+  name = "${var.infra_id}-master-profile"
 
   role = "${var.master_iam_role == "" ?
     join("|", aws_iam_role.master_role.*.name) :
@@ -18,7 +23,7 @@ data "aws_iam_role" "master_role" {
 
 resource "aws_iam_role" "master_role" {
   count = "${var.master_iam_role == "" ? 1 : 0}"
-  name  = "${var.cluster_name}-master-role"
+  name  = "${var.infra_id}-master-role"
   path  = "/"
 
   assume_role_policy = <<EOF
@@ -28,7 +33,7 @@ resource "aws_iam_role" "master_role" {
         {
             "Action": "sts:AssumeRole",
             "Principal": {
-                "Service": "ec2.amazonaws.com"
+                "Service": "${local.ec2_principal}"
             },
             "Effect": "Allow",
             "Sid": ""
@@ -40,7 +45,7 @@ EOF
 
 resource "aws_iam_role_policy" "master_policy" {
   count = "${var.master_iam_role == "" ? 1 : 0}"
-  name  = "${var.cluster_name}_master_policy"
+  name  = "${var.infra_id}_master_policy"
   role  = "${aws_iam_role.master_role.id}"
 
   policy = <<EOF
@@ -82,6 +87,8 @@ resource "aws_instance" "master" {
   instance_type        = "${var.ec2_type}"
   subnet_id            = "${element(var.subnet_ids, count.index)}"
   user_data            = "${var.user_data_ign}"
+  tenancy              = "${var.tenancy}"
+  placement_group      = "${var.placement_group}"
 
   vpc_security_group_ids      = ["${var.master_sg_ids}"]
   associate_public_ip_address = "${var.public_endpoints}"
@@ -94,10 +101,10 @@ resource "aws_instance" "master" {
   }
 
   tags = "${merge(map(
-      "Name", "${var.cluster_name}-master-${count.index}",
+      "Name", "${var.infra_id}-master-${count.index}",
       "kubernetes.io/cluster/${var.cluster_name}", "owned",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}",
       "clusterid", "${var.cluster_name}"
     ), var.extra_tags)}"
 
@@ -108,10 +115,10 @@ resource "aws_instance" "master" {
   }
 
   volume_tags = "${merge(map(
-    "Name", "${var.cluster_name}-master-${count.index}-vol",
+    "Name", "${var.infra_id}-master-${count.index}-vol",
     "kubernetes.io/cluster/${var.cluster_name}", "owned",
-    "tectonicClusterID", "${var.cluster_id}",
-    "openshiftClusterID", "${var.cluster_id}"
+    "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+    "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
   ), var.extra_tags)}"
 }
 
@@ -121,3 +128,10 @@ resource "aws_lb_target_group_attachment" "master" {
   target_group_arn = "${var.target_group_arns[count.index % var.target_group_arns_length]}"
   target_id        = "${aws_instance.master.*.private_ip[count.index / var.target_group_arns_length]}"
 }
+
+resource "aws_elb_attachment" "master" {
+  count = "${var.instance_count * var.elb_ids_length}"
+
+  elb      = "${var.elb_ids[count.index % var.elb_ids_length]}"
+  instance = "${aws_instance.master.*.id[count.index / var.elb_ids_length]}"
+}
@@ -7,10 +7,20 @@ variable "cluster_id" {
   type = "string"
 }
 
+variable "tag_key_prefix" {
+  type        = "string"
+  default     = ""
+  description = "The prefix of the keys of the tags identifying the cluster (e.g. example.com/)."
+}
+
 variable "cluster_name" {
   type = "string"
 }
 
+variable "infra_id" {
+  type = "string"
+}
+
 variable "dns_server_ip" {
   type    = "string"
   default = ""
@@ -20,6 +30,17 @@ variable "ec2_type" {
   type = "string"
 }
 
+variable "elb_ids" {
+  type        = "list"
+  default     = []
+  description = "The list of classic load balancer IDs for the instances."
+}
+
+variable "elb_ids_length" {
+  default     = 0
+  description = "The length of the 'elb_ids' variable, to work around https://github.com/hashicorp/terraform/issues/12570."
+}
+
 variable "extra_tags" {
   description = "Extra AWS tags to be applied to created resources."
   type        = "map"
@@ -89,3 +110,15 @@ variable "target_group_arns_length" {
 variable "user_data_ign" {
   type = "string"
 }
+
+variable "placement_group" {
+  type        = "string"
+  default     = ""
+  description = "The name of an existing placement group for the master instances."
+}
+
+variable "tenancy" {
+  type        = "string"
+  default     = "default"
+  description = "The tenancy of the master instances: default, dedicated or host."
+}
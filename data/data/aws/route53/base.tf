@@ -1,14 +1,15 @@
 locals {
-  public_endpoints_count  = "${var.public_endpoints ? 1 : 0}"
+  public_endpoints_count  = "${var.public_endpoints && var.public_records ? 1 : 0}"
   private_endpoints_count = "${var.private_endpoints ? 1 : 0}"
 }
 
 data "aws_route53_zone" "base" {
-  name = "${var.base_domain}"
+  count = "${var.public_records && var.public_zone_id == "" ? 1 : 0}"
+  name  = "${var.base_domain}"
 }
 
 locals {
-  public_zone_id = "${join("", data.aws_route53_zone.base.*.zone_id)}"
+  public_zone_id = "${var.public_zone_id != "" ? var.public_zone_id : join("", data.aws_route53_zone.base.*.zone_id)}"
 
   zone_id = "${var.private_endpoints ? var.private_zone_id : local.public_zone_id}"
 }
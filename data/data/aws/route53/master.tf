@@ -1,6 +1,6 @@
 resource "aws_route53_record" "master_nodes" {
-  count   = "${var.elb_alias_enabled ? 0 : var.master_count}"
-  zone_id = "${data.aws_route53_zone.base.zone_id}"
+  count   = "${var.elb_alias_enabled || !var.public_records ? 0 : var.master_count}"
+  zone_id = "${local.public_zone_id}"
   name    = "${var.cluster_name}-master-${count.index}"
   type    = "A"
   ttl     = "60"
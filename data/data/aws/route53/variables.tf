@@ -90,6 +90,17 @@ variable "private_zone_id" {
   type        = "string"
 }
 
+variable "public_records" {
+  description = "If set to false, the public zone is not looked up and no public records are created."
+  default     = true
+}
+
+variable "public_zone_id" {
+  description = "(optional) Route53 Public Zone ID. If empty, the zone is looked up by the base domain."
+  type        = "string"
+  default     = ""
+}
+
 variable "api_external_lb_dns_name" {
   description = "External API's LB DNS name"
   type        = "string"
@@ -1,6 +1,6 @@
 resource "aws_route53_record" "worker_nodes" {
-  count   = "${var.elb_alias_enabled ? 0 : var.worker_count}"
-  zone_id = "${data.aws_route53_zone.base.zone_id}"
+  count   = "${var.elb_alias_enabled || !var.public_records ? 0 : var.worker_count}"
+  zone_id = "${local.public_zone_id}"
   name    = "${var.cluster_name}-worker-${count.index}"
   type    = "A"
   ttl     = "60"
@@ -9,8 +9,8 @@ resource "aws_route53_record" "worker_nodes" {
 
 resource "aws_route53_record" "worker_nodes_public" {
   // hack: worker_public_ips_enabled is a workaround for https://github.com/hashicorp/terraform/issues/10857
-  count   = "${var.worker_public_ips_enabled ? var.worker_count : 0}"
-  zone_id = "${data.aws_route53_zone.base.zone_id}"
+  count   = "${var.worker_public_ips_enabled && var.public_records ? var.worker_count : 0}"
+  zone_id = "${local.public_zone_id}"
   name    = "${var.cluster_name}-worker-${count.index}-public"
   type    = "A"
   ttl     = "60"
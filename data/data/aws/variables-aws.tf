@@ -15,6 +15,12 @@ variable "aws_master_ec2_type" {
   default = "t3.medium"
 }
 
+variable "aws_bootstrap_ec2_type" {
+  type        = "string"
+  description = "Instance size for the bootstrap node. Example: `t3.medium`."
+  default     = "t3.medium"
+}
+
 variable "aws_ec2_ami_override" {
   type        = "string"
   description = "(optional) AMI override for all nodes. Example: `ami-foobar123`."
@@ -41,6 +47,16 @@ This should not overlap with any other networks, such as a private datacenter co
 EOF
 }
 
+variable "aws_vpc_secondary_cidr_blocks" {
+  type    = "list"
+  default = []
+
+  description = <<EOF
+(optional) Additional blocks of IP addresses associated with the VPC, from the install config's machine networks after the first.
+The worker subnets are created in the first of them.
+EOF
+}
+
 variable "aws_external_vpc_id" {
   type = "string"
 
@@ -62,6 +78,68 @@ If set to "public", then only create public-facing ingress resources (ELB, A-rec
 EOF
 }
 
+variable "aws_api_lb_type" {
+  type    = "string"
+  default = "network"
+
+  description = <<EOF
+(optional) The type of the API load balancers: "network" or "classic".
+EOF
+}
+
+variable "aws_api_lb_internal" {
+  default = false
+
+  description = <<EOF
+(optional) If set to true, only the internal API load balancer is created.
+EOF
+}
+
+variable "aws_api_lb_idle_timeout" {
+  type    = "string"
+  default = "3600"
+
+  description = <<EOF
+(optional) The idle timeout in seconds of classic API load balancers.
+EOF
+}
+
+variable "aws_api_lb_api_health_check_interval" {
+  type        = "string"
+  default     = "10"
+  description = "(optional) The interval in seconds of the API listener's health check."
+}
+
+variable "aws_api_lb_api_healthy_threshold" {
+  type        = "string"
+  default     = "3"
+  description = "(optional) The healthy threshold of the API listener's health check."
+}
+
+variable "aws_api_lb_api_unhealthy_threshold" {
+  type        = "string"
+  default     = "3"
+  description = "(optional) The unhealthy threshold of the API listener's health check."
+}
+
+variable "aws_api_lb_mcs_health_check_interval" {
+  type        = "string"
+  default     = "10"
+  description = "(optional) The interval in seconds of the machine-config server listener's health check."
+}
+
+variable "aws_api_lb_mcs_healthy_threshold" {
+  type        = "string"
+  default     = "3"
+  description = "(optional) The healthy threshold of the machine-config server listener's health check."
+}
+
+variable "aws_api_lb_mcs_unhealthy_threshold" {
+  type        = "string"
+  default     = "3"
+  description = "(optional) The unhealthy threshold of the machine-config server listener's health check."
+}
+
 variable "aws_external_private_zone" {
   default = ""
 
@@ -74,6 +152,26 @@ Example: `"Z1ILINNUJGTAO1"`
 EOF
 }
 
+variable "aws_external_public_zone" {
+  default = ""
+
+  description = <<EOF
+(optional) If set, the given Route53 zone ID will be used as the public zone instead of looking it up by the base domain.
+This zone is never created or deleted by the installer.
+
+Example: `"Z1ILINNUJGTAO1"`
+EOF
+}
+
+variable "aws_private_zone_only" {
+  default = false
+
+  description = <<EOF
+(optional) If set, only the private zone is used for cluster DNS records.
+The public zone is not looked up and no public records are created.
+EOF
+}
+
 variable "aws_external_master_subnet_ids" {
   type = "list"
 
@@ -100,6 +198,18 @@ EOF
   default = []
 }
 
+variable "aws_service_endpoints" {
+  type = "map"
+
+  description = <<EOF
+(optional) Endpoint URLs of AWS services, keyed by their AWS SDK endpoint IDs, overriding the defaults.
+
+Example: `{ "ec2" = "https://ec2.us-gov-west-1.amazonaws.com" }`
+EOF
+
+  default = {}
+}
+
 variable "aws_extra_tags" {
   type = "map"
 
@@ -112,6 +222,16 @@ EOF
   default = {}
 }
 
+variable "aws_tag_key_prefix" {
+  type = "string"
+
+  description = <<EOF
+(optional) The prefix of the keys of the tectonicClusterID and openshiftClusterID tags (e.g. example.com/).
+EOF
+
+  default = ""
+}
+
 variable "aws_master_root_volume_type" {
   type        = "string"
   default     = "gp2"
@@ -189,6 +309,24 @@ Example:
 EOF
 }
 
+variable "aws_master_tenancy" {
+  type    = "string"
+  default = "default"
+
+  description = <<EOF
+(optional) The tenancy of the master instances: default, dedicated or host.
+EOF
+}
+
+variable "aws_master_placement_group" {
+  type    = "string"
+  default = ""
+
+  description = <<EOF
+(optional) The name of an existing placement group in which to launch the master instances.
+EOF
+}
+
 variable "aws_worker_iam_role_name" {
   type    = "string"
   default = ""
@@ -202,3 +340,38 @@ Example:
  * Role Name = openshift-installer
 EOF
 }
+
+variable "aws_bastion" {
+  default = false
+
+  description = <<EOF
+(optional) If set to true, an SSH bastion host is created in a public subnet, with the cluster SSH keys.
+EOF
+}
+
+variable "aws_bastion_ec2_type" {
+  type    = "string"
+  default = "t3.micro"
+
+  description = <<EOF
+(optional) The instance type of the bastion host.
+EOF
+}
+
+variable "aws_bastion_allowed_cidrs" {
+  type    = "list"
+  default = ["0.0.0.0/0"]
+
+  description = <<EOF
+(optional) The networks SSH connections to the bastion host are allowed from.
+EOF
+}
+
+variable "aws_bastion_ssh_keys" {
+  type    = "list"
+  default = []
+
+  description = <<EOF
+(optional) The SSH public keys authorized on the bastion host.
+EOF
+}
@@ -1,7 +1,12 @@
+locals {
+  network_lb = "${var.api_lb_type == "network"}"
+  classic_lb = "${var.api_lb_type == "classic"}"
+}
+
 resource "aws_lb" "api_internal" {
-  count = "${var.private_master_endpoints ? 1 : 0}"
+  count = "${var.private_master_endpoints && local.network_lb ? 1 : 0}"
 
-  name                             = "${var.cluster_name}-int"
+  name                             = "${var.infra_id}-int"
   load_balancer_type               = "network"
   subnets                          = ["${local.master_subnet_ids}"]
   internal                         = true
@@ -10,15 +15,15 @@ resource "aws_lb" "api_internal" {
 
   tags = "${merge(map(
       "kubernetes.io/cluster/${var.cluster_name}", "owned",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.extra_tags)}"
 }
 
 resource "aws_lb" "api_external" {
-  count = "${var.public_master_endpoints ? 1 : 0}"
+  count = "${var.public_master_endpoints && local.network_lb ? 1 : 0}"
 
-  name                             = "${var.cluster_name}-ext"
+  name                             = "${var.infra_id}-ext"
   load_balancer_type               = "network"
   subnets                          = ["${local.master_subnet_ids}"]
   internal                         = false
@@ -27,15 +32,15 @@ resource "aws_lb" "api_external" {
 
   tags = "${merge(map(
       "kubernetes.io/cluster/${var.cluster_name}", "owned",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.extra_tags)}"
 }
 
 resource "aws_lb_target_group" "api_internal" {
-  count = "${var.private_master_endpoints ? 1 : 0}"
+  count = "${var.private_master_endpoints && local.network_lb ? 1 : 0}"
 
-  name     = "${var.cluster_name}-api-int"
+  name     = "${var.infra_id}-api-int"
   protocol = "TCP"
   port     = 6443
   vpc_id   = "${local.vpc_id}"
@@ -44,23 +49,23 @@ resource "aws_lb_target_group" "api_internal" {
 
   tags = "${merge(map(
       "kubernetes.io/cluster/${var.cluster_name}", "owned",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.extra_tags)}"
 
   health_check {
-    healthy_threshold   = 3
-    unhealthy_threshold = 3
-    interval            = 10
+    healthy_threshold   = "${var.api_healthy_threshold}"
+    unhealthy_threshold = "${var.api_unhealthy_threshold}"
+    interval            = "${var.api_health_check_interval}"
     port                = 6443
     protocol            = "TCP"
   }
 }
 
 resource "aws_lb_target_group" "api_external" {
-  count = "${var.public_master_endpoints ? 1 : 0}"
+  count = "${var.public_master_endpoints && local.network_lb ? 1 : 0}"
 
-  name     = "${var.cluster_name}-api-ext"
+  name     = "${var.infra_id}-api-ext"
   protocol = "TCP"
   port     = 6443
   vpc_id   = "${local.vpc_id}"
@@ -69,21 +74,23 @@ resource "aws_lb_target_group" "api_external" {
 
   tags = "${merge(map(
       "kubernetes.io/cluster/${var.cluster_name}", "owned",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.extra_tags)}"
 
   health_check {
-    healthy_threshold   = 3
-    unhealthy_threshold = 3
-    interval            = 10
+    healthy_threshold   = "${var.api_healthy_threshold}"
+    unhealthy_threshold = "${var.api_unhealthy_threshold}"
+    interval            = "${var.api_health_check_interval}"
     port                = 6443
     protocol            = "TCP"
   }
 }
 
 resource "aws_lb_target_group" "services" {
-  name     = "${var.cluster_name}-services"
+  count = "${var.private_master_endpoints && local.network_lb ? 1 : 0}"
+
+  name     = "${var.infra_id}-services"
   protocol = "TCP"
   port     = 49500
   vpc_id   = "${local.vpc_id}"
@@ -92,21 +99,21 @@ resource "aws_lb_target_group" "services" {
 
   tags = "${merge(map(
       "kubernetes.io/cluster/${var.cluster_name}", "owned",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.extra_tags)}"
 
   health_check {
-    healthy_threshold   = 3
-    unhealthy_threshold = 3
-    interval            = 10
+    healthy_threshold   = "${var.mcs_healthy_threshold}"
+    unhealthy_threshold = "${var.mcs_unhealthy_threshold}"
+    interval            = "${var.mcs_health_check_interval}"
     port                = 49500
     protocol            = "TCP"
   }
 }
 
 resource "aws_lb_listener" "api_internal_api" {
-  count = "${var.private_master_endpoints ? 1 : 0}"
+  count = "${var.private_master_endpoints && local.network_lb ? 1 : 0}"
 
   load_balancer_arn = "${aws_lb.api_internal.arn}"
   protocol          = "TCP"
@@ -119,11 +126,11 @@ resource "aws_lb_listener" "api_internal_api" {
 }
 
 resource "aws_lb_listener" "api_internal_services" {
-  count = "${var.private_master_endpoints ? 1 : 0}"
+  count = "${var.private_master_endpoints && local.network_lb ? 1 : 0}"
 
   load_balancer_arn = "${aws_lb.api_internal.arn}"
   protocol          = "TCP"
-  port              = "49500"
+  port              = "${var.mcs_port}"
 
   default_action {
     target_group_arn = "${aws_lb_target_group.services.arn}"
@@ -132,7 +139,7 @@ resource "aws_lb_listener" "api_internal_services" {
 }
 
 resource "aws_lb_listener" "api_external_api" {
-  count = "${var.public_master_endpoints ? 1 : 0}"
+  count = "${var.public_master_endpoints && local.network_lb ? 1 : 0}"
 
   load_balancer_arn = "${aws_lb.api_external.arn}"
   protocol          = "TCP"
@@ -143,3 +150,74 @@ resource "aws_lb_listener" "api_external_api" {
     type             = "forward"
   }
 }
+
+resource "aws_elb" "api_internal" {
+  count = "${var.private_master_endpoints && local.classic_lb ? 1 : 0}"
+
+  name                      = "${var.infra_id}-int"
+  subnets                   = ["${local.master_subnet_ids}"]
+  internal                  = true
+  security_groups           = ["${aws_security_group.api.id}"]
+  cross_zone_load_balancing = true
+  idle_timeout              = "${var.api_lb_idle_timeout}"
+
+  listener {
+    instance_port     = 6443
+    instance_protocol = "tcp"
+    lb_port           = 6443
+    lb_protocol       = "tcp"
+  }
+
+  listener {
+    instance_port     = 49500
+    instance_protocol = "tcp"
+    lb_port           = "${var.mcs_port}"
+    lb_protocol       = "tcp"
+  }
+
+  health_check {
+    healthy_threshold   = "${var.api_healthy_threshold}"
+    unhealthy_threshold = "${var.api_unhealthy_threshold}"
+    interval            = "${var.api_health_check_interval}"
+    timeout             = 3
+    target              = "TCP:6443"
+  }
+
+  tags = "${merge(map(
+      "kubernetes.io/cluster/${var.cluster_name}", "owned",
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
+    ), var.extra_tags)}"
+}
+
+resource "aws_elb" "api_external" {
+  count = "${var.public_master_endpoints && local.classic_lb ? 1 : 0}"
+
+  name                      = "${var.infra_id}-ext"
+  subnets                   = ["${local.master_subnet_ids}"]
+  internal                  = false
+  security_groups           = ["${aws_security_group.api.id}"]
+  cross_zone_load_balancing = true
+  idle_timeout              = "${var.api_lb_idle_timeout}"
+
+  listener {
+    instance_port     = 6443
+    instance_protocol = "tcp"
+    lb_port           = 6443
+    lb_protocol       = "tcp"
+  }
+
+  health_check {
+    healthy_threshold   = "${var.api_healthy_threshold}"
+    unhealthy_threshold = "${var.api_unhealthy_threshold}"
+    interval            = "${var.api_health_check_interval}"
+    timeout             = 3
+    target              = "TCP:6443"
+  }
+
+  tags = "${merge(map(
+      "kubernetes.io/cluster/${var.cluster_name}", "owned",
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
+    ), var.extra_tags)}"
+}
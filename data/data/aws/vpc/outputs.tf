@@ -31,21 +31,29 @@ output "aws_lb_target_group_arns" {
 }
 
 output "aws_lb_target_group_arns_length" {
-  value = "${(var.private_master_endpoints ? 2 : 0) + (var.public_master_endpoints ? 1 : 0)}"
+  value = "${local.network_lb ? (var.private_master_endpoints ? 2 : 0) + (var.public_master_endpoints ? 1 : 0) : 0}"
+}
+
+output "aws_elb_api_ids" {
+  value = "${concat(aws_elb.api_internal.*.id, aws_elb.api_external.*.id)}"
+}
+
+output "aws_elb_api_ids_length" {
+  value = "${local.classic_lb ? (var.private_master_endpoints ? 1 : 0) + (var.public_master_endpoints ? 1 : 0) : 0}"
 }
 
 output "aws_lb_api_external_dns_name" {
-  value = "${element(concat(aws_lb.api_external.*.dns_name, list("")), 0)}"
+  value = "${element(concat(aws_lb.api_external.*.dns_name, aws_elb.api_external.*.dns_name, list("")), 0)}"
 }
 
 output "aws_lb_api_external_zone_id" {
-  value = "${element(concat(aws_lb.api_external.*.zone_id, list("")), 0)}"
+  value = "${element(concat(aws_lb.api_external.*.zone_id, aws_elb.api_external.*.zone_id, list("")), 0)}"
 }
 
 output "aws_lb_api_internal_dns_name" {
-  value = "${element(concat(aws_lb.api_internal.*.dns_name, list("")), 0)}"
+  value = "${element(concat(aws_lb.api_internal.*.dns_name, aws_elb.api_internal.*.dns_name, list("")), 0)}"
 }
 
 output "aws_lb_api_internal_zone_id" {
-  value = "${element(concat(aws_lb.api_internal.*.zone_id, list("")), 0)}"
+  value = "${element(concat(aws_lb.api_internal.*.zone_id, aws_elb.api_internal.*.zone_id, list("")), 0)}"
 }
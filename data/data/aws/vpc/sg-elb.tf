@@ -2,10 +2,10 @@ resource "aws_security_group" "api" {
   vpc_id = "${data.aws_vpc.cluster_vpc.id}"
 
   tags = "${merge(map(
-      "Name", "${var.cluster_name}_api_sg",
+      "Name", "${var.infra_id}_api_sg",
       "kubernetes.io/cluster/${var.cluster_name}", "owned",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.extra_tags)}"
 }
 
@@ -35,18 +35,18 @@ resource "aws_security_group_rule" "mcs_ingress" {
 
   protocol    = "tcp"
   cidr_blocks = ["0.0.0.0/0"]
-  from_port   = 49500
-  to_port     = 49500
+  from_port   = "${var.mcs_port}"
+  to_port     = "${var.mcs_port}"
 }
 
 resource "aws_security_group" "console" {
   vpc_id = "${data.aws_vpc.cluster_vpc.id}"
 
   tags = "${merge(map(
-      "Name", "${var.cluster_name}_console_sg",
+      "Name", "${var.infra_id}_console_sg",
       "kubernetes.io/cluster/${var.cluster_name}", "owned",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.extra_tags)}"
 }
 
@@ -2,10 +2,10 @@ resource "aws_security_group" "etcd" {
   vpc_id = "${data.aws_vpc.cluster_vpc.id}"
 
   tags = "${merge(map(
-      "Name", "${var.cluster_name}_etcd_sg",
+      "Name", "${var.infra_id}_etcd_sg",
       "kubernetes.io/cluster/${var.cluster_name}", "owned",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.extra_tags)}"
 }
 
@@ -2,10 +2,10 @@ resource "aws_security_group" "master" {
   vpc_id = "${data.aws_vpc.cluster_vpc.id}"
 
   tags = "${merge(map(
-      "Name", "${var.cluster_name}_master_sg",
+      "Name", "${var.infra_id}_master_sg",
       "kubernetes.io/cluster/${var.cluster_name}", "owned",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.extra_tags)}"
 }
 
@@ -34,7 +34,7 @@ resource "aws_security_group_rule" "master_ingress_icmp" {
   security_group_id = "${aws_security_group.master.id}"
 
   protocol    = "icmp"
-  cidr_blocks = ["${data.aws_vpc.cluster_vpc.cidr_block}"]
+  cidr_blocks = ["${local.machine_cidrs}"]
   from_port   = 0
   to_port     = 0
 }
@@ -54,7 +54,7 @@ resource "aws_security_group_rule" "master_ingress_http" {
   security_group_id = "${aws_security_group.master.id}"
 
   protocol    = "tcp"
-  cidr_blocks = ["${data.aws_vpc.cluster_vpc.cidr_block}"]
+  cidr_blocks = ["${local.machine_cidrs}"]
   from_port   = 80
   to_port     = 80
 }
@@ -64,7 +64,7 @@ resource "aws_security_group_rule" "master_ingress_https" {
   security_group_id = "${aws_security_group.master.id}"
 
   protocol    = "tcp"
-  cidr_blocks = ["${data.aws_vpc.cluster_vpc.cidr_block}"]
+  cidr_blocks = ["${local.machine_cidrs}"]
   from_port   = 6443
   to_port     = 6445
 }
@@ -2,10 +2,22 @@ variable "cidr_block" {
   type = "string"
 }
 
+variable "secondary_cidr_blocks" {
+  description = "Additional blocks of IP addresses associated with a new VPC."
+  type        = "list"
+  default     = []
+}
+
 variable "cluster_id" {
   type = "string"
 }
 
+variable "tag_key_prefix" {
+  type        = "string"
+  default     = ""
+  description = "The prefix of the keys of the tags identifying the cluster (e.g. example.com/)."
+}
+
 variable "base_domain" {
   type = "string"
 }
@@ -14,6 +26,10 @@ variable "cluster_name" {
   type = "string"
 }
 
+variable "infra_id" {
+  type = "string"
+}
+
 variable "external_vpc_id" {
   type = "string"
 }
@@ -32,6 +48,12 @@ variable "extra_tags" {
   default     = {}
 }
 
+variable "mcs_port" {
+  description = "The port on which the load balancer serves the machine-config server."
+  type        = "string"
+  default     = "49500"
+}
+
 variable "new_master_subnet_configs" {
   description = "{az_name = new_subnet_cidr}: Empty map means create new subnets in all availability zones in region with generated cidrs"
   type        = "map"
@@ -51,3 +73,45 @@ variable "public_master_endpoints" {
   description = "If set to true, public-facing ingress resources are created."
   default     = true
 }
+
+variable "api_lb_type" {
+  description = "The type of the API load balancers: network or classic."
+  type        = "string"
+  default     = "network"
+}
+
+variable "api_lb_idle_timeout" {
+  description = "The idle timeout in seconds of classic API load balancers."
+  type        = "string"
+  default     = "3600"
+}
+
+variable "api_health_check_interval" {
+  type    = "string"
+  default = "10"
+}
+
+variable "api_healthy_threshold" {
+  type    = "string"
+  default = "3"
+}
+
+variable "api_unhealthy_threshold" {
+  type    = "string"
+  default = "3"
+}
+
+variable "mcs_health_check_interval" {
+  type    = "string"
+  default = "10"
+}
+
+variable "mcs_healthy_threshold" {
+  type    = "string"
+  default = "3"
+}
+
+variable "mcs_unhealthy_threshold" {
+  type    = "string"
+  default = "3"
+}
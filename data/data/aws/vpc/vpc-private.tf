@@ -3,10 +3,10 @@ resource "aws_route_table" "private_routes" {
   vpc_id = "${data.aws_vpc.cluster_vpc.id}"
 
   tags = "${merge(map(
-      "Name","${var.cluster_name}-private-${local.new_worker_subnet_azs[count.index]}",
+      "Name","${var.infra_id}-private-${local.new_worker_subnet_azs[count.index]}",
       "kubernetes.io/cluster/${var.cluster_name}", "shared",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.extra_tags)}"
 }
 
@@ -31,13 +31,15 @@ resource "aws_subnet" "worker_subnet" {
   availability_zone = "${local.new_worker_subnet_azs[count.index]}"
 
   tags = "${merge(map(
-    "Name", "${var.cluster_name}-worker-${local.new_worker_subnet_azs[count.index]}",
+    "Name", "${var.infra_id}-worker-${local.new_worker_subnet_azs[count.index]}",
     "kubernetes.io/cluster/${var.cluster_name}","shared",
     "kubernetes.io/role/internal-elb", "",
-    "tectonicClusterID", "${var.cluster_id}",
-    "openshiftClusterID", "${var.cluster_id}"
+    "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+    "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ),
     var.extra_tags)}"
+
+  depends_on = ["aws_vpc_ipv4_cidr_block_association.secondary"]
 }
 
 resource "aws_route_table_association" "worker_routing" {
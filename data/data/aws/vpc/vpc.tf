@@ -1,6 +1,11 @@
 locals {
-  new_worker_cidr_range = "${cidrsubnet(data.aws_vpc.cluster_vpc.cidr_block,1,1)}"
-  new_master_cidr_range = "${cidrsubnet(data.aws_vpc.cluster_vpc.cidr_block,1,0)}"
+  // With secondary machine networks, the master subnets are carved from
+  // the primary block and the worker subnets from the first secondary one.
+  // Otherwise the primary block is split between them.
+  new_worker_cidr_range = "${length(var.secondary_cidr_blocks) == 0 ? cidrsubnet(data.aws_vpc.cluster_vpc.cidr_block,1,1) : element(concat(var.secondary_cidr_blocks, list("")), 0)}"
+  new_master_cidr_range = "${length(var.secondary_cidr_blocks) == 0 ? cidrsubnet(data.aws_vpc.cluster_vpc.cidr_block,1,0) : data.aws_vpc.cluster_vpc.cidr_block}"
+
+  machine_cidrs = ["${concat(list(data.aws_vpc.cluster_vpc.cidr_block), var.secondary_cidr_blocks)}"]
 }
 
 resource "aws_vpc" "new_vpc" {
@@ -12,7 +17,14 @@ resource "aws_vpc" "new_vpc" {
   tags = "${merge(map(
       "Name", "${var.cluster_name}.${var.base_domain}",
       "kubernetes.io/cluster/${var.cluster_name}", "shared",
-      "tectonicClusterID", "${var.cluster_id}",
-      "openshiftClusterID", "${var.cluster_id}"
+      "${var.tag_key_prefix}tectonicClusterID", "${var.cluster_id}",
+      "${var.tag_key_prefix}openshiftClusterID", "${var.cluster_id}"
     ), var.extra_tags)}"
 }
+
+resource "aws_vpc_ipv4_cidr_block_association" "secondary" {
+  count = "${local.external_vpc_mode ? 0 : length(var.secondary_cidr_blocks)}"
+
+  vpc_id     = "${local.vpc_id}"
+  cidr_block = "${var.secondary_cidr_blocks[count.index]}"
+}
@@ -37,6 +37,11 @@ Note: This field MUST be set manually prior to creating the cluster.
 EOF
 }
 
+variable "cluster_infra_id" {
+  type        = "string"
+  description = "(internal) The infra ID, which prefixes the names of the cluster's AWS resources."
+}
+
 variable "ignition_master" {
   type    = "string"
   default = ""
@@ -55,6 +60,16 @@ variable "ignition_bootstrap" {
 EOF
 }
 
+variable "machine_config_server_port" {
+  type    = "string"
+  default = "49500"
+
+  description = <<EOF
+The port machines connect to when fetching their Ignition configs from the machine-config server.
+The load balancer in front of the masters listens on this port and forwards to the machine-config server.
+EOF
+}
+
 // This variable is generated by OpenShift internally. Do not modify
 variable "cluster_id" {
   type        = "string"
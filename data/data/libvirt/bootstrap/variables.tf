@@ -19,6 +19,12 @@ variable "ignition" {
   description = "The content of the bootstrap ignition file."
 }
 
+variable "memory" {
+  type        = "string"
+  default     = "2048"
+  description = "RAM in MiB allocated to the bootstrap node."
+}
+
 variable "network_id" {
   type        = "string"
   description = "The ID of a network resource containing the bootstrap node's addresses."
@@ -5,8 +5,10 @@ provider "libvirt" {
 module "volume" {
   source = "./volume"
 
-  cluster_name = "${var.cluster_name}"
-  image        = "${var.os_image}"
+  base_volume_name = "${var.libvirt_base_volume_name}"
+  base_volume_pool = "${var.libvirt_base_volume_pool}"
+  cluster_name     = "${var.cluster_name}"
+  image            = "${var.os_image}"
 }
 
 module "bootstrap" {
@@ -16,6 +18,7 @@ module "bootstrap" {
   base_volume_id = "${module.volume.coreos_base_volume_id}"
   cluster_name   = "${var.cluster_name}"
   ignition       = "${var.ignition_bootstrap}"
+  memory         = "${var.libvirt_bootstrap_memory}"
   network_id     = "${libvirt_network.net.id}"
 }
 
@@ -56,6 +59,15 @@ resource "libvirt_network" "net" {
     ))}"]
   }]
 
+  # dnsmasq host records cannot be wildcards, so resolve the ingress domain
+  # (and all of its sub-domains) with an address option instead.
+  dnsmasq_options {
+    options = [{
+      option_name  = "address"
+      option_value = "/apps.${var.cluster_name}.${var.base_domain}/${var.libvirt_ingress_ip}"
+    }]
+  }
+
   autostart = true
 }
 
@@ -28,6 +28,29 @@ variable "libvirt_bootstrap_ip" {
   description = "the desired bootstrap ip"
 }
 
+variable "libvirt_bootstrap_memory" {
+  type        = "string"
+  default     = "2048"
+  description = "RAM in MiB allocated to the bootstrap node"
+}
+
+variable "libvirt_base_volume_name" {
+  type        = "string"
+  default     = ""
+  description = "The name of a cached OS image volume, shared by clusters, which backs the base volume instead of os_image"
+}
+
+variable "libvirt_base_volume_pool" {
+  type        = "string"
+  default     = ""
+  description = "The storage pool of libvirt_base_volume_name"
+}
+
+variable "libvirt_ingress_ip" {
+  type        = "string"
+  description = "the IP which the wildcard apps domain resolves to"
+}
+
 variable "libvirt_master_ips" {
   type        = "list"
   description = "the list of desired master ips. Must match master_count"
@@ -1,4 +1,8 @@
 resource "libvirt_volume" "coreos_base" {
   name   = "${var.cluster_name}-base"
-  source = "${var.image}"
+  source = "${var.base_volume_name == "" ? var.image : ""}"
+
+  // A thin volume backed by the shared cached image, when there is one.
+  base_volume_name = "${var.base_volume_name}"
+  base_volume_pool = "${var.base_volume_pool}"
 }
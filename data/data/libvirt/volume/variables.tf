@@ -1,3 +1,15 @@
+variable "base_volume_name" {
+  type        = "string"
+  default     = ""
+  description = "The name of a shared volume which backs the base volume instead of image."
+}
+
+variable "base_volume_pool" {
+  type        = "string"
+  default     = ""
+  description = "The storage pool of base_volume_name."
+}
+
 variable "cluster_name" {
   type        = "string"
   description = "The name of the cluster."
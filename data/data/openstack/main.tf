@@ -31,7 +31,7 @@ module "bootstrap" {
   cluster_name      = "${var.cluster_name}"
   cluster_id        = "${var.cluster_id}"
   image_name        = "${var.openstack_base_image}"
-  flavor_name       = "${var.openstack_master_flavor_name}"
+  flavor_name       = "${var.openstack_bootstrap_flavor_name != "" ? var.openstack_bootstrap_flavor_name : var.openstack_master_flavor_name}"
   ignition          = "${var.ignition_bootstrap}"
   bootstrap_port_id = "${module.topology.bootstrap_port_id}"
 }
@@ -39,14 +39,17 @@ module "bootstrap" {
 module "masters" {
   source = "./masters"
 
-  base_image     = "${var.openstack_base_image}"
-  cluster_id     = "${var.cluster_id}"
-  cluster_name   = "${var.cluster_name}"
-  flavor_name    = "${var.openstack_master_flavor_name}"
-  instance_count = "${var.master_count}"
-  master_sg_ids  = "${concat(var.openstack_master_extra_sg_ids, list(module.topology.master_sg_id))}"
-  subnet_ids     = "${module.topology.master_subnet_ids}"
-  user_data_ign  = "${var.ignition_master}"
+  availability_zones = "${var.openstack_master_availability_zones}"
+  base_image         = "${var.openstack_base_image}"
+  cluster_id         = "${var.cluster_id}"
+  cluster_name       = "${var.cluster_name}"
+  flavor_name        = "${var.openstack_master_flavor_name}"
+  instance_count     = "${var.master_count}"
+  master_sg_ids      = "${concat(var.openstack_master_extra_sg_ids, list(module.topology.master_sg_id))}"
+  root_volume_size   = "${var.openstack_master_root_volume_size}"
+  root_volume_type   = "${var.openstack_master_root_volume_type}"
+  subnet_ids         = "${module.topology.master_subnet_ids}"
+  user_data_ign      = "${var.ignition_master}"
 }
 
 # TODO(shadower) add a dns module here
@@ -54,11 +57,14 @@ module "masters" {
 module "topology" {
   source = "./topology"
 
+  allocate_floating_ips      = "${var.openstack_allocate_floating_ips}"
+  api_floating_ip            = "${var.openstack_api_floating_ip}"
   cidr_block                 = "${var.openstack_network_cidr_block}"
   cluster_id                 = "${var.cluster_id}"
   cluster_name               = "${var.cluster_name}"
   external_master_subnet_ids = "${compact(var.openstack_external_master_subnet_ids)}"
   external_network           = "${var.openstack_external_network}"
+  ingress_floating_ip        = "${var.openstack_ingress_floating_ip}"
   masters_count              = "${var.master_count}"
 }
 
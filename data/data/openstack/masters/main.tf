@@ -7,14 +7,54 @@ data "openstack_compute_flavor_v2" "masters_flavor" {
   name = "${var.flavor_name}"
 }
 
+locals {
+  // element() fails on an empty list, so pad the zones with "", which lets
+  // Nova pick the zone when none are given.
+  zones = "${concat(var.availability_zones, list(""))}"
+}
+
 resource "openstack_compute_instance_v2" "master_conf" {
   name  = "${var.cluster_name}-master-${count.index}"
-  count = "${var.instance_count}"
+  count = "${var.root_volume_size == 0 ? var.instance_count : 0}"
+
+  availability_zone = "${element(local.zones, count.index % max(1, length(var.availability_zones)))}"
+  flavor_id         = "${data.openstack_compute_flavor_v2.masters_flavor.id}"
+  image_id          = "${data.openstack_images_image_v2.masters_img.id}"
+  security_groups   = ["${var.master_sg_ids}"]
+  user_data         = "${var.user_data_ign}"
+
+  network = {
+    port = "${var.subnet_ids[count.index]}"
+  }
 
-  flavor_id       = "${data.openstack_compute_flavor_v2.masters_flavor.id}"
-  image_id        = "${data.openstack_images_image_v2.masters_img.id}"
-  security_groups = ["${var.master_sg_ids}"]
-  user_data       = "${var.user_data_ign}"
+  metadata {
+    Name               = "${var.cluster_name}-master"
+    owned              = "kubernetes.io/cluster/${var.cluster_name}"
+    tectonicClusterID  = "${var.cluster_id}"
+    openshiftClusterID = "${var.cluster_id}"
+  }
+}
+
+// Terraform 0.11 cannot make the block_device conditional, so masters
+// booting from a Cinder volume are a separate resource.
+resource "openstack_compute_instance_v2" "master_conf_volume" {
+  name  = "${var.cluster_name}-master-${count.index}"
+  count = "${var.root_volume_size == 0 ? 0 : var.instance_count}"
+
+  availability_zone = "${element(local.zones, count.index % max(1, length(var.availability_zones)))}"
+  flavor_id         = "${data.openstack_compute_flavor_v2.masters_flavor.id}"
+  security_groups   = ["${var.master_sg_ids}"]
+  user_data         = "${var.user_data_ign}"
+
+  block_device {
+    uuid                  = "${data.openstack_images_image_v2.masters_img.id}"
+    source_type           = "image"
+    destination_type      = "volume"
+    volume_size           = "${var.root_volume_size}"
+    volume_type           = "${var.root_volume_type}"
+    boot_index            = 0
+    delete_on_termination = true
+  }
 
   network = {
     port = "${var.subnet_ids[count.index]}"
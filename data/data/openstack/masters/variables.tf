@@ -1,3 +1,9 @@
+variable "availability_zones" {
+  type        = "list"
+  default     = []
+  description = "The availability zones across which the masters are spread."
+}
+
 variable "base_image" {
   type = "string"
 }
@@ -24,6 +30,17 @@ variable "master_sg_ids" {
   description = "The security group IDs to be applied to the master nodes."
 }
 
+variable "root_volume_size" {
+  type        = "string"
+  default     = "0"
+  description = "The size in GiB of the volume the masters boot from. If 0, they boot from the flavor's disk."
+}
+
+variable "root_volume_type" {
+  type    = "string"
+  default = ""
+}
+
 variable "subnet_ids" {
   type = "list"
 }
@@ -0,0 +1,27 @@
+// Allocated floating IPs are tagged so that destroy releases them, while
+// given ones are left alone.
+resource "openstack_networking_floatingip_v2" "api" {
+  count = "${var.allocate_floating_ips && var.api_floating_ip == "" ? 1 : 0}"
+  pool  = "${var.external_network}"
+  tags  = ["tectonicClusterID=${var.cluster_id}", "openshiftClusterID=${var.cluster_id}"]
+}
+
+resource "openstack_networking_floatingip_v2" "ingress" {
+  count = "${var.allocate_floating_ips && var.ingress_floating_ip == "" ? 1 : 0}"
+  pool  = "${var.external_network}"
+  tags  = ["tectonicClusterID=${var.cluster_id}", "openshiftClusterID=${var.cluster_id}"]
+}
+
+locals {
+  api_floating_ip     = "${var.api_floating_ip != "" ? var.api_floating_ip : join("", openstack_networking_floatingip_v2.api.*.address)}"
+  ingress_floating_ip = "${var.ingress_floating_ip != "" ? var.ingress_floating_ip : join("", openstack_networking_floatingip_v2.ingress.*.address)}"
+}
+
+resource "openstack_networking_floatingip_associate_v2" "api" {
+  count       = "${var.api_floating_ip != "" || var.allocate_floating_ips ? 1 : 0}"
+  floating_ip = "${local.api_floating_ip}"
+  port_id     = "${openstack_networking_port_v2.masters.0.id}"
+
+  // The port must be reachable from the external network.
+  depends_on = ["openstack_networking_router_interface_v2.masters_router_interface"]
+}
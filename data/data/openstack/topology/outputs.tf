@@ -1,3 +1,11 @@
+output "api_floating_ip" {
+  value = "${local.api_floating_ip}"
+}
+
+output "ingress_floating_ip" {
+  value = "${local.ingress_floating_ip}"
+}
+
 output "bootstrap_port_id" {
   value = "${openstack_networking_port_v2.bootstrap_port.id}"
 }
@@ -1,3 +1,14 @@
+variable "allocate_floating_ips" {
+  description = "Whether to allocate the API and ingress floating IPs which are not given."
+  default     = false
+}
+
+variable "api_floating_ip" {
+  description = "An existing floating IP to associate with the first master."
+  type        = "string"
+  default     = ""
+}
+
 variable "cidr_block" {
   type = "string"
 }
@@ -20,6 +31,12 @@ variable "external_network" {
   default     = ""
 }
 
+variable "ingress_floating_ip" {
+  description = "An existing floating IP reserved for the ingress router."
+  type        = "string"
+  default     = ""
+}
+
 variable "masters_count" {
   type = "string"
 }
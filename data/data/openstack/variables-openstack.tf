@@ -212,6 +212,23 @@ Floating IP access to the deployed nodes.
 EOF
 }
 
+variable "openstack_api_floating_ip" {
+  type        = "string"
+  default     = ""
+  description = "(optional) An existing floating IP to associate with the first master for the API."
+}
+
+variable "openstack_ingress_floating_ip" {
+  type        = "string"
+  default     = ""
+  description = "(optional) An existing floating IP reserved for the ingress router."
+}
+
+variable "openstack_allocate_floating_ips" {
+  default     = false
+  description = "(optional) Whether to allocate the API and ingress floating IPs which are not given from openstack_external_network."
+}
+
 variable "openstack_extra_tags" {
   type    = "map"
   default = {}
@@ -234,12 +251,36 @@ Example: `["sg-51530134", "sg-b253d7cc"]`
 EOF
 }
 
+variable "openstack_bootstrap_flavor_name" {
+  type        = "string"
+  default     = ""
+  description = "(optional) Instance size for the bootstrap node. Defaults to the master flavor. Example: `m1.medium`."
+}
+
 variable "openstack_master_flavor_name" {
   type        = "string"
   default     = "m1.medium"
   description = "Instance size for the master node(s). Example: `m1.medium`."
 }
 
+variable "openstack_master_root_volume_size" {
+  type        = "string"
+  default     = "0"
+  description = "The size in GiB of the Cinder volume the masters boot from. If 0, they boot from the flavor's ephemeral disk."
+}
+
+variable "openstack_master_root_volume_type" {
+  type        = "string"
+  default     = ""
+  description = "The Cinder volume type of the masters' root volumes. If empty, the cloud's default type is used."
+}
+
+variable "openstack_master_availability_zones" {
+  type        = "list"
+  default     = []
+  description = "The Nova availability zones across which the masters are spread. If empty, Nova picks the zones."
+}
+
 variable "openstack_region" {
   type        = "string"
   description = "The target OpenStack region for the cluster."
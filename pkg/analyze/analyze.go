@@ -0,0 +1,199 @@
+// Package analyze diagnoses failed installs from their logs and resource
+// dumps: which cluster operators are degraded, and which known failure
+// signatures the logs match, with the lines matching them.
+package analyze
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	"github.com/ghodss/yaml"
+)
+
+const (
+	// maxExcerpts is how many matching lines are kept per signature.
+	maxExcerpts = 3
+
+	// maxLineLength is the length excerpts are truncated to.
+	maxLineLength = 300
+)
+
+// Source is a log or resource dump to analyze.
+type Source struct {
+	// Name identifies the source, e.g. its path in a log bundle.
+	Name string
+
+	// Data is the source's content.
+	Data []byte
+}
+
+// OperatorStatus is a cluster operator which is degraded or unavailable.
+type OperatorStatus struct {
+	// Name is the name of the cluster operator.
+	Name string
+
+	// Condition is the failing condition, e.g. Degraded=True.
+	Condition string
+
+	// Reason is the condition's reason.
+	Reason string
+
+	// Message is the condition's message.
+	Message string
+}
+
+// Excerpt is a line matching a failure signature.
+type Excerpt struct {
+	// Source is the name of the source the line is from.
+	Source string
+
+	// Line is the line number, starting at 1.
+	Line int
+
+	// Text is the line, truncated to maxLineLength.
+	Text string
+}
+
+// Finding is a failure signature matched by the sources.
+type Finding struct {
+	// Signature is the matched failure signature.
+	Signature *Signature
+
+	// Matches is how many lines matched.
+	Matches int
+
+	// Excerpts are the first matching lines.
+	Excerpts []Excerpt
+}
+
+// Report is the diagnosis of a failed install.
+type Report struct {
+	// Operators are the degraded or unavailable cluster operators.
+	Operators []OperatorStatus
+
+	// Findings are the matched failure signatures, in the order of
+	// signatures.
+	Findings []*Finding
+}
+
+// Analyze diagnoses a failed install from its sources.
+func Analyze(sources []Source) *Report {
+	report := &Report{}
+	findings := map[*Signature]*Finding{}
+	operators := map[string]OperatorStatus{}
+
+	for _, source := range sources {
+		if isBinary(source.Data) {
+			continue
+		}
+		switch filepath.Ext(source.Name) {
+		case ".json", ".yaml", ".yml":
+			for _, operator := range failingOperators(source.Data) {
+				operators[operator.Name+"/"+operator.Condition] = operator
+			}
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(source.Data))
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for lineNumber := 1; scanner.Scan(); lineNumber++ {
+			line := scanner.Text()
+			for _, signature := range signatures {
+				if !signature.Pattern.MatchString(line) {
+					continue
+				}
+				finding, ok := findings[signature]
+				if !ok {
+					finding = &Finding{Signature: signature}
+					findings[signature] = finding
+				}
+				finding.Matches++
+				if len(finding.Excerpts) < maxExcerpts {
+					if len(line) > maxLineLength {
+						line = line[:maxLineLength] + "..."
+					}
+					finding.Excerpts = append(finding.Excerpts, Excerpt{Source: source.Name, Line: lineNumber, Text: line})
+				}
+			}
+		}
+	}
+
+	for _, signature := range signatures {
+		if finding, ok := findings[signature]; ok {
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+	for _, operator := range operators {
+		report.Operators = append(report.Operators, operator)
+	}
+	sort.Slice(report.Operators, func(i, j int) bool {
+		if report.Operators[i].Name != report.Operators[j].Name {
+			return report.Operators[i].Name < report.Operators[j].Name
+		}
+		return report.Operators[i].Condition < report.Operators[j].Condition
+	})
+	return report
+}
+
+// isBinary returns true if data looks like binary rather than text.
+func isBinary(data []byte) bool {
+	if len(data) > 512 {
+		data = data[:512]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// object holds the fields of a resource needed to find failing cluster
+// operators, in a dump of a single resource or a List.
+type object struct {
+	Kind     string            `json:"kind"`
+	Items    []json.RawMessage `json:"items"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Status struct {
+		Conditions []struct {
+			Type    string `json:"type"`
+			Status  string `json:"status"`
+			Reason  string `json:"reason"`
+			Message string `json:"message"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// failingOperators returns the degraded or unavailable cluster operators
+// in a resource dump, e.g. of 'oc get clusteroperators -o json'.
+func failingOperators(data []byte) []OperatorStatus {
+	data, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil
+	}
+	var obj object
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil
+	}
+
+	var operators []OperatorStatus
+	switch obj.Kind {
+	case "List", "ClusterOperatorList":
+		for _, item := range obj.Items {
+			operators = append(operators, failingOperators(item)...)
+		}
+	case "ClusterOperator":
+		for _, condition := range obj.Status.Conditions {
+			failing := (condition.Type == "Degraded" || condition.Type == "Failing") && condition.Status == "True"
+			failing = failing || (condition.Type == "Available" && condition.Status == "False")
+			if failing {
+				operators = append(operators, OperatorStatus{
+					Name:      obj.Metadata.Name,
+					Condition: condition.Type + "=" + condition.Status,
+					Reason:    condition.Reason,
+					Message:   condition.Message,
+				})
+			}
+		}
+	}
+	return operators
+}
@@ -0,0 +1,80 @@
+package analyze
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	sources := []Source{
+		{
+			Name: "bootstrap/journal.log",
+			Data: []byte(`Apr 01 10:00:00 bootstrap bootkube.sh[1234]: Starting etcd
+Apr 01 10:00:01 bootstrap bootkube.sh[1234]: Error: error pulling image "quay.io/openshift-release-dev/ocp-release@sha256:abc": unauthorized: authentication required
+Apr 01 10:00:02 bootstrap bootkube.sh[1234]: Error: error pulling image "quay.io/openshift-release-dev/ocp-release@sha256:abc": unauthorized: authentication required
+`),
+		},
+		{
+			Name: "resources/clusteroperators.yaml",
+			Data: []byte(`apiVersion: v1
+kind: List
+items:
+- apiVersion: config.openshift.io/v1
+  kind: ClusterOperator
+  metadata:
+    name: ingress
+  status:
+    conditions:
+    - type: Available
+      status: "False"
+      reason: NoIngressController
+      message: no default ingress controller
+- apiVersion: config.openshift.io/v1
+  kind: ClusterOperator
+  metadata:
+    name: kube-apiserver
+  status:
+    conditions:
+    - type: Available
+      status: "True"
+    - type: Degraded
+      status: "False"
+`),
+		},
+		{
+			Name: "bootstrap/core.dump",
+			Data: []byte("\x00no space left on device"),
+		},
+	}
+
+	report := Analyze(sources)
+	assert.Equal(t, []OperatorStatus{{
+		Name:      "ingress",
+		Condition: "Available=False",
+		Reason:    "NoIngressController",
+		Message:   "no default ingress controller",
+	}}, report.Operators)
+
+	var names []string
+	for _, finding := range report.Findings {
+		names = append(names, finding.Signature.Name)
+	}
+	assert.Equal(t, []string{"PullSecret", "ImagePull"}, names)
+	assert.Equal(t, 2, report.Findings[0].Matches)
+	assert.Equal(t, Excerpt{
+		Source: "bootstrap/journal.log",
+		Line:   2,
+		Text:   `Apr 01 10:00:01 bootstrap bootkube.sh[1234]: Error: error pulling image "quay.io/openshift-release-dev/ocp-release@sha256:abc": unauthorized: authentication required`,
+	}, report.Findings[0].Excerpts[0])
+
+	var out bytes.Buffer
+	assert.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "Failing cluster operators:\n  ingress: Available=False NoIngressController: no default ingress controller\n")
+	assert.Contains(t, out.String(), "\nPullSecret (2 matching lines): The registry rejected the pull secret;")
+
+	out.Reset()
+	assert.NoError(t, Analyze(nil).Write(&out))
+	assert.Equal(t, "No degraded operators or known failure signatures found.\n", out.String())
+}
@@ -0,0 +1,90 @@
+package analyze
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxSourceSize is the size above which files of a log bundle are skipped.
+const maxSourceSize = 256 * 1024 * 1024
+
+// ReadBundle returns the files of a gathered log bundle: a directory, or a
+// tar archive, optionally gzipped.
+func ReadBundle(path string) ([]Source, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return readDirectory(path)
+	}
+	return readArchive(path)
+}
+
+func readDirectory(dir string) ([]Source, error) {
+	var sources []Source
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || info.Size() > maxSourceSize {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, Source{Name: name, Data: data})
+		return nil
+	})
+	return sources, errors.Wrapf(err, "failed to read %s", dir)
+}
+
+func readArchive(path string) ([]Source, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") || strings.HasSuffix(path, ".tgz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decompress %s", path)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var sources []Source
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return sources, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", path)
+		}
+		if header.Typeflag != tar.TypeReg || header.Size > maxSourceSize {
+			continue
+		}
+		data, err := ioutil.ReadAll(archive)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s from %s", header.Name, path)
+		}
+		sources = append(sources, Source{Name: header.Name, Data: data})
+	}
+}
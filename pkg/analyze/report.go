@@ -0,0 +1,42 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+)
+
+// Write writes the report for people to read.
+func (r *Report) Write(w io.Writer) error {
+	if len(r.Operators) == 0 && len(r.Findings) == 0 {
+		_, err := fmt.Fprintln(w, "No degraded operators or known failure signatures found.")
+		return err
+	}
+
+	if len(r.Operators) > 0 {
+		if _, err := fmt.Fprintln(w, "Failing cluster operators:"); err != nil {
+			return err
+		}
+		for _, operator := range r.Operators {
+			if _, err := fmt.Fprintf(w, "  %s: %s %s: %s\n", operator.Name, operator.Condition, operator.Reason, operator.Message); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, finding := range r.Findings {
+		if i > 0 || len(r.Operators) > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s (%d matching lines): %s\n", finding.Signature.Name, finding.Matches, finding.Signature.Hint); err != nil {
+			return err
+		}
+		for _, excerpt := range finding.Excerpts {
+			if _, err := fmt.Fprintf(w, "  %s:%d: %s\n", excerpt.Source, excerpt.Line, excerpt.Text); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package analyze
+
+import (
+	"regexp"
+)
+
+// Signature is a known cause of failed installs, recognized by the log
+// lines it leaves.
+type Signature struct {
+	// Name identifies the signature.
+	Name string
+
+	// Pattern matches the log lines left by the failure.
+	Pattern *regexp.Regexp
+
+	// Hint suggests how to remedy the failure.
+	Hint string
+}
+
+// signatures are the known failure signatures, roughly in the order their
+// failures would happen during an install.
+var signatures = []*Signature{
+	{
+		Name:    "CloudQuota",
+		Pattern: regexp.MustCompile(`(VcpuLimitExceeded|InstanceLimitExceeded|AddressLimitExceeded|VpcLimitExceeded|NatGatewayLimitExceeded|QuotaExceeded|Quota exceeded)`),
+		Hint:    "A cloud quota is exhausted; raise it, or free resources, and destroy and re-create the cluster.",
+	},
+	{
+		Name:    "PullSecret",
+		Pattern: regexp.MustCompile(`(unauthorized: authentication required|unauthorized: access to the requested resource is not authorized|error: unauthorized|401 Unauthorized)`),
+		Hint:    "The registry rejected the pull secret; get a current one from https://try.openshift.com and check it covers the release image's registry.",
+	},
+	{
+		Name:    "ImagePull",
+		Pattern: regexp.MustCompile(`(Error pulling image|error pulling image|ErrImagePull|ImagePullBackOff|manifest unknown|failed to pull image)`),
+		Hint:    "Images could not be pulled; check the nodes reach the registry (directly or through the proxy) and the release image exists.",
+	},
+	{
+		Name:    "IgnitionFetch",
+		Pattern: regexp.MustCompile(`(GET https?://[^ ]*:22623/config/[^ ]*: (dial tcp|Get)|GET error: .*22623)`),
+		Hint:    "Nodes could not fetch their Ignition configs from the machine config server on port 22623; check the API load balancer and security groups let them reach the bootstrap node.",
+	},
+	{
+		Name:    "Certificates",
+		Pattern: regexp.MustCompile(`x509: certificate has expired or is not yet valid`),
+		Hint:    "Certificates were used outside their validity; check the clocks of the nodes and, since the bootstrap certificates expire after 24 hours, re-create the cluster from fresh assets rather than reusing old ones.",
+	},
+	{
+		Name:    "EtcdDNS",
+		Pattern: regexp.MustCompile(`lookup etcd-\d+\.[^ ]*( on [^ ]*)?: no such host`),
+		Hint:    "The etcd DNS records of the masters cannot be resolved; check the cluster's private DNS zone and the masters' resolvers.",
+	},
+	{
+		Name:    "EtcdUnavailable",
+		Pattern: regexp.MustCompile(`(etcdserver: request timed out|etcd cluster is unavailable or misconfigured|failed to reach any etcd|etcdserver: leader changed)`),
+		Hint:    "etcd is unavailable or unstable; check the etcd-member pods on the masters and that their disks are fast enough.",
+	},
+	{
+		Name:    "DiskFull",
+		Pattern: regexp.MustCompile(`no space left on device`),
+		Hint:    "A node ran out of disk space; give the machines larger root volumes.",
+	},
+	{
+		Name:    "OutOfMemory",
+		Pattern: regexp.MustCompile(`(Out of memory: Kill(ed)? process|oom-kill|OOMKilled)`),
+		Hint:    "A node ran out of memory; give the bootstrap node or the masters a larger machine type.",
+	},
+	{
+		Name:    "APIUnreachable",
+		Pattern: regexp.MustCompile(`(dial tcp [^ ]*:6443: (connect: connection refused|i/o timeout)|Unable to connect to the server)`),
+		Hint:    "The Kubernetes API could not be reached; check the API load balancer and DNS records, and the bootkube service on the bootstrap node.",
+	},
+}
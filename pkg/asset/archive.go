@@ -0,0 +1,101 @@
+package asset
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/reproducible"
+)
+
+// PersistToArchive writes all of the files of the specified assets into a
+// gzipped tarball at the specified path. Files holding credentials are only
+// readable by their owner.
+func PersistToArchive(archivePath string, assets ...WritableAsset) (err error) {
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return errors.Wrap(err, "failed to create dir")
+	}
+	// Write to a temporary file first, so a failed run does not leave a
+	// truncated archive behind.
+	file, err := ioutil.TempFile(filepath.Dir(archivePath), "."+filepath.Base(archivePath))
+	if err != nil {
+		return errors.Wrap(err, "failed to create archive")
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(file.Name())
+		}
+	}()
+
+	now, err := reproducible.Now()
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	dirs := map[string]bool{}
+	for _, a := range assets {
+		for _, f := range a.Files() {
+			name := filepath.ToSlash(f.Filename)
+			for dir := path.Dir(name); dir != "." && !dirs[dir]; dir = path.Dir(dir) {
+				dirs[dir] = true
+				if err := tw.WriteHeader(&tar.Header{
+					Typeflag: tar.TypeDir,
+					Name:     dir + "/",
+					Mode:     0755,
+					ModTime:  now,
+				}); err != nil {
+					return errors.Wrap(err, "failed to write archive")
+				}
+			}
+			data, err := dataAtRest(f)
+			if err != nil {
+				return err
+			}
+			if err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     name,
+				Mode:     archiveFileMode(name),
+				Size:     int64(len(data)),
+				ModTime:  now,
+			}); err != nil {
+				return errors.Wrap(err, "failed to write archive")
+			}
+			if _, err := tw.Write(data); err != nil {
+				return errors.Wrap(err, "failed to write archive")
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return errors.Wrap(err, "failed to write archive")
+	}
+	if err := gz.Close(); err != nil {
+		return errors.Wrap(err, "failed to write archive")
+	}
+	if err := file.Close(); err != nil {
+		return errors.Wrap(err, "failed to write archive")
+	}
+	return errors.Wrap(os.Rename(file.Name(), archivePath), "failed to write archive")
+}
+
+// archiveFileMode returns the mode of a file in an archive: owner-only
+// for credentials, Ignition configs (which embed keys) and Terraform
+// state, world-readable otherwise.
+func archiveFileMode(name string) int64 {
+	switch {
+	case strings.HasPrefix(name, "auth/"),
+		strings.HasSuffix(name, ".ign"),
+		strings.HasSuffix(name, ".tfstate"):
+		return 0600
+	default:
+		return 0644
+	}
+}
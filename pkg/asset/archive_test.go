@@ -0,0 +1,72 @@
+package asset
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistToArchive(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestPersistToArchive")
+	if err != nil {
+		t.Fatalf("could not create the temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	asset := &writablePersistAsset{
+		FileList: []*File{
+			{Filename: "metadata.json", Data: []byte("{}")},
+			{Filename: "auth/kubeconfig", Data: []byte("kubeconfig")},
+			{Filename: "bootstrap.ign", Data: []byte("ignition")},
+		},
+	}
+	path := filepath.Join(dir, "out", "assets.tar.gz")
+	if err := PersistToArchive(path, asset); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	type entry struct {
+		name string
+		mode int64
+		data string
+	}
+	entries := []entry{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry{name: header.Name, mode: header.Mode, data: string(data)})
+	}
+
+	assert.Equal(t, []entry{
+		{name: "metadata.json", mode: 0644, data: "{}"},
+		{name: "auth/", mode: 0755},
+		{name: "auth/kubeconfig", mode: 0600, data: "kubeconfig"},
+		{name: "bootstrap.ign", mode: 0600, data: "ignition"},
+	}, entries)
+}
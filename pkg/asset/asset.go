@@ -5,11 +5,18 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/encryption"
 )
 
+// logger tags the asset store's log entries, so their level can be set
+// apart.
+var logger = logrus.WithField("component", "assets")
+
 // Asset used to install OpenShift.
 type Asset interface {
 	// Dependencies returns the assets upon which this asset directly depends.
@@ -51,17 +58,31 @@ func PersistToFile(asset WritableAsset, directory string) error {
 		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 			return errors.Wrap(err, "failed to create dir")
 		}
-		if err := ioutil.WriteFile(path, f.Data, 0644); err != nil {
+		data, err := dataAtRest(f)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, data, 0644); err != nil {
 			return errors.Wrap(err, "failed to write file")
 		}
 	}
 	return nil
 }
 
+// dataAtRest returns the contents of the file as written out, encrypting
+// the auth files when encryption is enabled.
+func dataAtRest(f *File) ([]byte, error) {
+	if !strings.HasPrefix(filepath.ToSlash(f.Filename), "auth/") {
+		return f.Data, nil
+	}
+	data, err := encryption.Encrypt(f.Data)
+	return data, errors.Wrapf(err, "failed to encrypt %s", f.Filename)
+}
+
 // deleteAssetFromDisk removes all the files for asset from disk.
 // this is function is not safe for calling concurrently on the same directory.
 func deleteAssetFromDisk(asset WritableAsset, directory string) error {
-	logrus.Debugf("Purging asset %q from disk", asset.Name())
+	logger.Debugf("Purging asset %q from disk", asset.Name())
 	for _, f := range asset.Files() {
 		path := filepath.Join(directory, f.Filename)
 		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
@@ -8,6 +8,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/encryption"
 )
 
 type persistAsset struct{}
@@ -86,6 +88,38 @@ func TestPersistToFile(t *testing.T) {
 	}
 }
 
+func TestPersistToFileEncrypted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestPersistToFileEncrypted")
+	if err != nil {
+		t.Skipf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.Setenv(encryption.PassphraseEnvVar, "passphrase")
+	defer os.Unsetenv(encryption.PassphraseEnvVar)
+
+	asset := &writablePersistAsset{
+		FileList: []*File{
+			{Filename: "auth/kubeconfig", Data: []byte("kubeconfig")},
+			{Filename: "metadata.json", Data: []byte("metadata")},
+		},
+	}
+	err = PersistToFile(asset, dir)
+	assert.NoError(t, err, "unexpected error persisting state to file")
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "auth", "kubeconfig"))
+	assert.NoError(t, err)
+	assert.True(t, encryption.IsEncrypted(data), "auth files are encrypted")
+	data, err = ioutil.ReadFile(filepath.Join(dir, "metadata.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("metadata"), data, "other files are not encrypted")
+
+	fetcher := &fileFetcher{directory: dir}
+	file, err := fetcher.FetchByName("auth/kubeconfig")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("kubeconfig"), file.Data, "auth files are decrypted on load")
+}
+
 func verifyFilesCreated(t *testing.T, dir string, expectedFiles map[string][]byte) {
 	dirContents, err := ioutil.ReadDir(dir)
 	assert.NoError(t, err, "could not read contents of directory %q", dir)
@@ -14,14 +14,15 @@ func Metadata(config *types.InstallConfig) *aws.Metadata {
 		Region: config.Platform.AWS.Region,
 		Identifier: []map[string]string{
 			{
-				"tectonicClusterID": config.ClusterID,
+				config.Platform.AWS.TagKey("tectonicClusterID"): config.ClusterID,
 			},
 			{
-				"openshiftClusterID": config.ClusterID,
+				config.Platform.AWS.TagKey("openshiftClusterID"): config.ClusterID,
 			},
 			{
 				fmt.Sprintf("kubernetes.io/cluster/%s", config.ObjectMeta.Name): "owned",
 			},
 		},
+		ServiceEndpoints: config.Platform.AWS.ServiceEndpoints,
 	}
 }
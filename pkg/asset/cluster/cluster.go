@@ -17,8 +17,10 @@ import (
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/kubeconfig"
 	"github.com/openshift/installer/pkg/asset/password"
+	"github.com/openshift/installer/pkg/failure"
 	"github.com/openshift/installer/pkg/terraform"
 	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
 )
 
 const (
@@ -76,7 +78,9 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 	}
 
 	metadata := &types.ClusterMetadata{
-		ClusterName: installConfig.Config.ObjectMeta.Name,
+		ClusterName:  installConfig.Config.ObjectMeta.Name,
+		InfraID:      installConfig.Config.InfraID,
+		ReleaseImage: installconfig.ReleaseImagePullSpec(installConfig.Config),
 	}
 
 	defer func() {
@@ -114,7 +118,7 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 	logrus.Infof("Creating cluster...")
 	stateFile, err := terraform.Apply(tmpDir, installConfig.Config.Platform.Name())
 	if err != nil {
-		err = errors.Wrap(err, "failed to create cluster")
+		err = failure.New(failure.InfrastructureCreation, "terraform", "look for the failing resource in the Terraform output in .openshift_install.log, often a quota or permission error, and run 'openshift-install destroy cluster' before retrying", errors.Wrap(err, "failed to create cluster"))
 	}
 
 	data, err2 := ioutil.ReadFile(stateFile)
@@ -123,6 +127,9 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 			Filename: terraform.StateFileName,
 			Data:     data,
 		})
+		if metadata.AWS != nil && installConfig.Config.Platform.AWS.Bastion != nil {
+			recordBastion(metadata.AWS, data)
+		}
 	} else {
 		if err == nil {
 			err = err2
@@ -135,6 +142,25 @@ func (c *Cluster) Generate(parents asset.Parents) (err error) {
 	return err
 }
 
+// recordBastion records the instance ID of the SSH bastion host from the
+// Terraform state in the metadata, so it is found when debugging, and logs
+// its address.
+func recordBastion(metadata *awstypes.Metadata, stateData []byte) {
+	outputs, err := terraform.Outputs(stateData)
+	if err != nil {
+		logrus.Errorf("Failed to read the bastion from the tfstate: %v", err)
+		return
+	}
+	id, _ := outputs["bastion_instance_id"].(string)
+	if id == "" {
+		return
+	}
+	metadata.BastionInstanceID = id
+	if ip, _ := outputs["bastion_public_ip"].(string); ip != "" {
+		logrus.Infof("Created the SSH bastion %s, reachable with 'ssh core@%s'", id, ip)
+	}
+}
+
 // Files returns the FileList generated by the asset.
 func (c *Cluster) Files() []*asset.File {
 	return c.FileList
@@ -156,13 +182,19 @@ func (c *Cluster) Load(f asset.FileFetcher) (found bool, err error) {
 
 // LoadMetadata loads the cluster metadata from an asset directory.
 func LoadMetadata(dir string) (cmetadata *types.ClusterMetadata, err error) {
-	raw, err := ioutil.ReadFile(filepath.Join(dir, metadataFileName))
+	return LoadMetadataFile(filepath.Join(dir, metadataFileName))
+}
+
+// LoadMetadataFile loads the cluster metadata from a file, e.g. a
+// metadata.json copied out of the asset directory.
+func LoadMetadataFile(path string) (cmetadata *types.ClusterMetadata, err error) {
+	raw, err := ioutil.ReadFile(path)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to read %s file", metadataFileName)
+		return nil, errors.Wrapf(err, "failed to read %s file", path)
 	}
 
 	if err = json.Unmarshal(raw, &cmetadata); err != nil {
-		return nil, errors.Wrapf(err, "failed to Unmarshal data from %s file to types.ClusterMetadata", metadataFileName)
+		return nil, errors.Wrapf(err, "failed to Unmarshal data from %s file to types.ClusterMetadata", path)
 	}
 
 	return cmetadata, err
@@ -9,6 +9,6 @@ import (
 // Metadata converts an install configuration to libvirt metadata.
 func Metadata(config *types.InstallConfig) *libvirt.Metadata {
 	return &libvirt.Metadata{
-		URI: config.Platform.Libvirt.URI,
+		URI: config.Platform.Libvirt.ConnectionURI(),
 	}
 }
@@ -7,7 +7,9 @@ import (
 	"github.com/openshift/installer/pkg/asset/ignition/bootstrap"
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
 	"github.com/openshift/installer/pkg/tfvars"
+	awstfvars "github.com/openshift/installer/pkg/tfvars/aws"
 	"github.com/pkg/errors"
 )
 
@@ -50,7 +52,20 @@ func (t *TerraformVariables) Generate(parents asset.Parents) error {
 
 	masterIgn := string(master.Files()[0].Data)
 
-	data, err := tfvars.TFVars(installConfig.Config, bootstrapIgn, masterIgn)
+	var hostedZone *awstfvars.HostedZone
+	if platform := installConfig.Config.Platform.AWS; platform != nil && platform.HostedZone != "" {
+		ssn, err := awsconfig.NewSession(platform)
+		if err != nil {
+			return err
+		}
+		zone, err := awsconfig.GetHostedZone(ssn, platform.HostedZone)
+		if err != nil {
+			return errors.Wrap(err, "failed to look up the hosted zone")
+		}
+		hostedZone = &awstfvars.HostedZone{ID: zone.ID, Private: zone.Private}
+	}
+
+	data, err := tfvars.TFVars(installConfig.Config, bootstrapIgn, masterIgn, hostedZone)
 	if err != nil {
 		return errors.Wrap(err, "failed to get Tfvars")
 	}
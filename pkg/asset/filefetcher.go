@@ -1,16 +1,21 @@
 package asset
 
 import (
-	"io/ioutil"
+	"os"
 	"path/filepath"
 	"sort"
+
+	"github.com/openshift/installer/pkg/encryption"
 )
 
-// FileFetcher fetches the asset files from disk.
+// FileFetcher fetches the asset files from disk, decrypting any which were
+// encrypted at rest.
 type FileFetcher interface {
 	// FetchByName returns the file with the given name.
 	FetchByName(string) (*File, error)
-	// FetchByPattern returns the files whose name match the given glob.
+	// FetchByPattern returns the files whose name match the given glob,
+	// sorted by name. Directories are skipped, so assets which write a
+	// variable number of files can reload all of them.
 	FetchByPattern(pattern string) ([]*File, error)
 }
 
@@ -20,14 +25,14 @@ type fileFetcher struct {
 
 // FetchByName returns the file with the given name.
 func (f *fileFetcher) FetchByName(name string) (*File, error) {
-	data, err := ioutil.ReadFile(filepath.Join(f.directory, name))
+	data, err := encryption.ReadFile(filepath.Join(f.directory, name))
 	if err != nil {
 		return nil, err
 	}
 	return &File{Filename: name, Data: data}, nil
 }
 
-// FetchByPattern returns the files whose name match the given regexp.
+// FetchByPattern returns the files whose name match the given glob.
 func (f *fileFetcher) FetchByPattern(pattern string) (files []*File, err error) {
 	matches, err := filepath.Glob(filepath.Join(f.directory, pattern))
 	if err != nil {
@@ -36,7 +41,15 @@ func (f *fileFetcher) FetchByPattern(pattern string) (files []*File, err error)
 
 	files = make([]*File, 0, len(matches))
 	for _, path := range matches {
-		data, err := ioutil.ReadFile(path)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		data, err := encryption.ReadFile(path)
 		if err != nil {
 			return nil, err
 		}
@@ -79,20 +79,21 @@ func TestFetchByPattern(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	files := map[string][]byte{
-		"master-0.ign":   []byte("some data 0"),
-		"master-1.ign":   []byte("some data 1"),
-		"master-2.ign":   []byte("some data 2"),
-		"master-10.ign":  []byte("some data 3"),
-		"master-20.ign":  []byte("some data 4"),
-		"master-00.ign":  []byte("some data 5"),
-		"master-01.ign":  []byte("some data 6"),
-		"amaster-0.ign":  []byte("some data 7"),
-		"master-.ign":    []byte("some data 8"),
-		"master-.igni":   []byte("some data 9"),
-		"master-.ignign": []byte("some data 10"),
-		"manifests/0":    []byte("some data 11"),
-		"manifests/some": []byte("some data 12"),
-		"amanifests/a":   []byte("some data 13"),
+		"master-0.ign":       []byte("some data 0"),
+		"master-1.ign":       []byte("some data 1"),
+		"master-2.ign":       []byte("some data 2"),
+		"master-10.ign":      []byte("some data 3"),
+		"master-20.ign":      []byte("some data 4"),
+		"master-00.ign":      []byte("some data 5"),
+		"master-01.ign":      []byte("some data 6"),
+		"amaster-0.ign":      []byte("some data 7"),
+		"master-.ign":        []byte("some data 8"),
+		"master-.igni":       []byte("some data 9"),
+		"master-.ignign":     []byte("some data 10"),
+		"manifests/0":        []byte("some data 11"),
+		"manifests/some":     []byte("some data 12"),
+		"amanifests/a":       []byte("some data 13"),
+		"manifests/nested/a": []byte("some data 14"),
 	}
 
 	for path, data := range files {
@@ -158,6 +159,19 @@ func TestFetchByPattern(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: filepath.Join("manifests", "*", "*"),
+			expectFiles: []*File{
+				{
+					Filename: "manifests/nested/a",
+					Data:     []byte("some data 14"),
+				},
+			},
+		},
+		{
+			input:       "missing-*",
+			expectFiles: []*File{},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,101 @@
+package asset
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// checkCycles returns an error naming the assets of the first dependency
+// cycle found from asset, which would otherwise recurse forever.
+func checkCycles(asset Asset) error {
+	done := map[reflect.Type]bool{}
+	var path []Asset
+	var visit func(Asset) error
+	visit = func(a Asset) error {
+		t := reflect.TypeOf(a)
+		if done[t] {
+			return nil
+		}
+		for i, p := range path {
+			if reflect.TypeOf(p) == t {
+				names := make([]string, 0, len(path)-i+1)
+				for _, c := range path[i:] {
+					names = append(names, c.Name())
+				}
+				return errors.Errorf("dependency cycle: %s -> %s", strings.Join(names, " -> "), a.Name())
+			}
+		}
+
+		path = append(path, a)
+		for _, d := range a.Dependencies() {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		done[t] = true
+		return nil
+	}
+	return visit(asset)
+}
+
+// checkConflicts returns an error naming the assets if two of the fetched
+// assets claim the same file, unless one depends on the other, in which
+// case it includes the file of its dependency in its own, like Manifests
+// does.
+func (s *StoreImpl) checkConflicts() error {
+	ancestors := map[reflect.Type]map[reflect.Type]bool{}
+	var ancestorsOf func(Asset) map[reflect.Type]bool
+	ancestorsOf = func(a Asset) map[reflect.Type]bool {
+		t := reflect.TypeOf(a)
+		if found, ok := ancestors[t]; ok {
+			return found
+		}
+		found := map[reflect.Type]bool{}
+		for _, d := range a.Dependencies() {
+			found[reflect.TypeOf(d)] = true
+			for p := range ancestorsOf(d) {
+				found[p] = true
+			}
+		}
+		ancestors[t] = found
+		return found
+	}
+
+	// Visit the assets in a stable order, so the same conflict is
+	// reported every time.
+	types := make([]reflect.Type, 0, len(s.assets))
+	for t, state := range s.assets {
+		if state.source == unfetched || state.asset == nil {
+			continue
+		}
+		if _, ok := state.asset.(WritableAsset); ok {
+			types = append(types, t)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].String() < types[j].String() })
+
+	owners := map[string]WritableAsset{}
+	for _, t := range types {
+		a := s.assets[t].asset.(WritableAsset)
+		for _, f := range a.Files() {
+			owner, ok := owners[f.Filename]
+			if !ok {
+				owners[f.Filename] = a
+				continue
+			}
+			ownerType := reflect.TypeOf(owner)
+			if ownerType == t || ancestorsOf(a)[ownerType] {
+				continue
+			}
+			if ancestorsOf(owner)[t] {
+				continue
+			}
+			return errors.Errorf("assets %q and %q both write %s", owner.Name(), a.Name(), f.Filename)
+		}
+	}
+	return nil
+}
@@ -10,13 +10,13 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/coreos/ignition/config/util"
 	igntypes "github.com/coreos/ignition/config/v2_2/types"
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/data"
 	"github.com/openshift/installer/pkg/asset"
@@ -30,7 +30,6 @@ import (
 
 const (
 	rootDir              = "/opt/openshift"
-	defaultReleaseImage  = "registry.svc.ci.openshift.org/openshift/origin-release:v4.0"
 	bootstrapIgnFilename = "bootstrap.ign"
 	etcdCertSignerImage  = "quay.io/coreos/kube-etcd-signer-server:678cc8e6841e2121ebfdb6e2db568fce290b67d6"
 	etcdctlImage         = "quay.io/coreos/etcd:v3.2.14"
@@ -46,6 +45,7 @@ type bootstrapTemplateData struct {
 	PullSecret            string
 	ReleaseImage          string
 	AdminKubeConfigBase64 string
+	ServiceNodePortRange  string
 }
 
 // Bootstrap is an asset that generates the ignition config for bootstrap nodes.
@@ -105,10 +105,11 @@ func (a *Bootstrap) Generate(dependencies asset.Parents) error {
 		return err
 	}
 	a.addParentFiles(dependencies)
+	a.addProxyFiles(installConfig.Config)
 
 	a.Config.Passwd.Users = append(
 		a.Config.Passwd.Users,
-		igntypes.PasswdUser{Name: "core", SSHAuthorizedKeys: []igntypes.SSHAuthorizedKey{igntypes.SSHAuthorizedKey(installConfig.Config.SSHKey)}},
+		igntypes.PasswdUser{Name: "core", SSHAuthorizedKeys: ignition.SSHAuthorizedKeys(installConfig.Config.SSHKey)},
 	)
 
 	data, err := json.Marshal(a.Config)
@@ -143,19 +144,14 @@ func (a *Bootstrap) getTemplateData(installConfig *types.InstallConfig, adminKub
 		etcdEndpoints[i] = fmt.Sprintf("https://%s-etcd-%d.%s:2379", installConfig.ObjectMeta.Name, i, installConfig.BaseDomain)
 	}
 
-	releaseImage := defaultReleaseImage
-	if ri, ok := os.LookupEnv("OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE"); ok && ri != "" {
-		logrus.Warn("Found override for ReleaseImage. Please be warned, this is not advised")
-		releaseImage = ri
-	}
-
 	return &bootstrapTemplateData{
 		EtcdCertSignerImage:   etcdCertSignerImage,
 		EtcdctlImage:          etcdctlImage,
 		PullSecret:            installConfig.PullSecret,
-		ReleaseImage:          releaseImage,
+		ReleaseImage:          installconfig.ReleaseImagePullSpec(installConfig),
 		EtcdCluster:           strings.Join(etcdEndpoints, ","),
 		AdminKubeConfigBase64: base64.StdEncoding.EncodeToString(adminKubeConfig),
+		ServiceNodePortRange:  installConfig.Networking.ServiceNodePortRange,
 	}, nil
 }
 
@@ -177,6 +173,7 @@ func (a *Bootstrap) addStorageFiles(base string, uri string, templateData *boots
 			return err
 		}
 		file.Close()
+		sortFileInfos(children)
 
 		for _, childInfo := range children {
 			name := childInfo.Name()
@@ -233,6 +230,7 @@ func (a *Bootstrap) addSystemdUnits(uri string, templateData *bootstrapTemplateD
 	if err != nil {
 		return err
 	}
+	sortFileInfos(children)
 
 	for _, childInfo := range children {
 		name := childInfo.Name()
@@ -296,14 +294,19 @@ func (a *Bootstrap) addParentFiles(dependencies asset.Parents) {
 		ignition.FileFromBytes("/etc/kubernetes/kubeconfig", 0600, kubeletKubeconfig.Files()[0].Data),
 		ignition.FileFromBytes("/var/lib/kubelet/kubeconfig", 0600, kubeletKubeconfig.Files()[0].Data),
 	)
-	a.Config.Storage.Files = append(
-		a.Config.Storage.Files,
-		ignition.FilesFromAsset(rootDir, 0644, mfsts)...,
-	)
-	a.Config.Storage.Files = append(
-		a.Config.Storage.Files,
-		ignition.FilesFromAsset(rootDir, 0644, openshiftManifests)...,
-	)
+	for _, manifestsAsset := range []asset.WritableAsset{mfsts, openshiftManifests} {
+		for _, f := range manifestsAsset.Files() {
+			// Kustomizations are for GitOps tools; bootkube would try to
+			// create them in the cluster.
+			if manifests.IsKustomization(f.Filename) {
+				continue
+			}
+			a.Config.Storage.Files = append(
+				a.Config.Storage.Files,
+				ignition.FileFromBytes(filepath.Join(rootDir, f.Filename), 0644, f.Data),
+			)
+		}
+	}
 
 	for _, asset := range []asset.WritableAsset{
 		&tls.RootCA{},
@@ -357,3 +360,11 @@ func (a *Bootstrap) Load(f asset.FileFetcher) (found bool, err error) {
 	a.File, a.Config = file, config
 	return true, nil
 }
+
+// sortFileInfos sorts directory entries by name, so the Ignition config is
+// reproducible.
+func sortFileInfos(infos []os.FileInfo) {
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name() < infos[j].Name()
+	})
+}
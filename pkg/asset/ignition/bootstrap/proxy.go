@@ -0,0 +1,101 @@
+package bootstrap
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/coreos/ignition/config/util"
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+
+	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// proxyTrustBundlePath is where the proxy's trust bundle is added to
+	// the host's trust.
+	proxyTrustBundlePath = "/etc/pki/ca-trust/source/anchors/openshift-proxy-ca.crt"
+
+	// proxyEnvironmentPath sets the proxy environment of every systemd
+	// unit, so podman, CRI-O and the kubelet pull through the proxy.
+	proxyEnvironmentPath = "/etc/systemd/system.conf.d/10-openshift-proxy.conf"
+
+	proxyTrustUnit = `[Unit]
+Description=Trust the proxy CA bundle
+DefaultDependencies=no
+Before=crio.service kubelet.service bootkube.service
+After=local-fs.target
+
+[Service]
+Type=oneshot
+ExecStart=/usr/bin/update-ca-trust extract
+RemainAfterExit=yes
+
+[Install]
+WantedBy=multi-user.target
+`
+)
+
+// addProxyFiles configures the bootstrap node to reach the outside through
+// the install config's proxy, trusting its CA bundle.
+func (a *Bootstrap) addProxyFiles(installConfig *types.InstallConfig) {
+	proxy := installConfig.Proxy
+	if proxy == nil {
+		return
+	}
+
+	var env []string
+	if proxy.HTTPProxy != "" {
+		env = append(env, fmt.Sprintf("%q", "HTTP_PROXY="+proxy.HTTPProxy))
+	}
+	if proxy.HTTPSProxy != "" {
+		env = append(env, fmt.Sprintf("%q", "HTTPS_PROXY="+proxy.HTTPSProxy))
+	}
+	env = append(env, fmt.Sprintf("%q", "NO_PROXY="+bootstrapNoProxy(installConfig)))
+	a.Config.Storage.Files = append(
+		a.Config.Storage.Files,
+		ignition.FileFromString(proxyEnvironmentPath, 0644, fmt.Sprintf("[Manager]\nDefaultEnvironment=%s\n", strings.Join(env, " "))),
+	)
+
+	if proxy.TrustBundle != "" {
+		a.Config.Storage.Files = append(
+			a.Config.Storage.Files,
+			ignition.FileFromString(proxyTrustBundlePath, 0644, proxy.TrustBundle),
+		)
+		a.Config.Systemd.Units = append(a.Config.Systemd.Units, igntypes.Unit{
+			Name:     "openshift-proxy-ca-trust.service",
+			Contents: proxyTrustUnit,
+			Enabled:  util.BoolToPtr(true),
+		})
+	}
+}
+
+// bootstrapNoProxy returns the hosts the bootstrap node reaches without the
+// proxy: those of the install config and the cluster's own names and
+// networks.
+func bootstrapNoProxy(installConfig *types.InstallConfig) string {
+	noProxy := []string{"localhost", "127.0.0.1", ".svc", ".cluster.local"}
+	clusterName, baseDomain := installConfig.ObjectMeta.Name, installConfig.BaseDomain
+	noProxy = append(noProxy,
+		fmt.Sprintf(".%s.%s", clusterName, baseDomain),
+		fmt.Sprintf("%s-api.%s", clusterName, baseDomain),
+	)
+	for i := 0; i < installConfig.MasterCount(); i++ {
+		noProxy = append(noProxy, fmt.Sprintf("%s-etcd-%d.%s", clusterName, i, baseDomain))
+	}
+	noProxy = append(noProxy, installConfig.Networking.ServiceCIDR.String())
+	for _, network := range installConfig.Networking.ClusterNetworks {
+		noProxy = append(noProxy, network.CIDR)
+	}
+	if len(installConfig.Networking.ClusterNetworks) == 0 && installConfig.Networking.PodCIDR != nil {
+		noProxy = append(noProxy, installConfig.Networking.PodCIDR.String())
+	}
+	if installConfig.Platform.AWS != nil {
+		// The instance metadata service.
+		noProxy = append(noProxy, "169.254.169.254")
+	}
+	if installConfig.Proxy.NoProxy != "" {
+		noProxy = append(noProxy, installConfig.Proxy.NoProxy)
+	}
+	return strings.Join(noProxy, ",")
+}
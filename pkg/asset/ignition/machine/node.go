@@ -7,12 +7,17 @@ import (
 	ignition "github.com/coreos/ignition/config/v2_2/types"
 	"github.com/vincent-petithory/dataurl"
 
+	assetignition "github.com/openshift/installer/pkg/asset/ignition"
 	"github.com/openshift/installer/pkg/types"
 )
 
 // pointerIgnitionConfig generates a config which references the remote config
 // served by the machine config server.
 func pointerIgnitionConfig(installConfig *types.InstallConfig, rootCA []byte, role string) *ignition.Config {
+	ca := rootCA
+	if installConfig.MachineConfigServer != nil && installConfig.MachineConfigServer.CertificateAuthority != "" {
+		ca = []byte(installConfig.MachineConfigServer.CertificateAuthority)
+	}
 	return &ignition.Config{
 		Ignition: ignition.Ignition{
 			Version: ignition.MaxVersion.String(),
@@ -21,7 +26,7 @@ func pointerIgnitionConfig(installConfig *types.InstallConfig, rootCA []byte, ro
 					Source: func() *url.URL {
 						return &url.URL{
 							Scheme: "https",
-							Host:   fmt.Sprintf("%s-api.%s:49500", installConfig.ObjectMeta.Name, installConfig.BaseDomain),
+							Host:   fmt.Sprintf("%s-api.%s:%d", installConfig.ObjectMeta.Name, installConfig.BaseDomain, installConfig.MachineConfigServerPort()),
 							Path:   fmt.Sprintf("/config/%s", role),
 						}
 					}().String(),
@@ -30,7 +35,7 @@ func pointerIgnitionConfig(installConfig *types.InstallConfig, rootCA []byte, ro
 			Security: ignition.Security{
 				TLS: ignition.TLS{
 					CertificateAuthorities: []ignition.CaReference{{
-						Source: dataurl.EncodeBytes(rootCA),
+						Source: dataurl.EncodeBytes(ca),
 					}},
 				},
 			},
@@ -39,7 +44,7 @@ func pointerIgnitionConfig(installConfig *types.InstallConfig, rootCA []byte, ro
 		Passwd: ignition.Passwd{
 			Users: []ignition.PasswdUser{{
 				Name:              "core",
-				SSHAuthorizedKeys: []ignition.SSHAuthorizedKey{ignition.SSHAuthorizedKey(installConfig.SSHKey)},
+				SSHAuthorizedKeys: assetignition.SSHAuthorizedKeys(installConfig.SSHKey),
 			}},
 		},
 	}
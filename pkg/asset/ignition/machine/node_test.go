@@ -0,0 +1,59 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vincent-petithory/dataurl"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestPointerIgnitionConfig(t *testing.T) {
+	cases := []struct {
+		name                string
+		machineConfigServer *types.MachineConfigServer
+		expectedSource      string
+		expectedCA          string
+	}{
+		{
+			name:           "default",
+			expectedSource: "https://test-cluster-api.test-domain:49500/config/master",
+			expectedCA:     "root-ca",
+		},
+		{
+			name:                "custom port",
+			machineConfigServer: &types.MachineConfigServer{Port: 443},
+			expectedSource:      "https://test-cluster-api.test-domain:443/config/master",
+			expectedCA:          "root-ca",
+		},
+		{
+			name:                "custom certificate authority",
+			machineConfigServer: &types.MachineConfigServer{CertificateAuthority: "lb-ca"},
+			expectedSource:      "https://test-cluster-api.test-domain:49500/config/master",
+			expectedCA:          "lb-ca",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			installConfig := &types.InstallConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				BaseDomain:          "test-domain",
+				MachineConfigServer: tc.machineConfigServer,
+			}
+			config := pointerIgnitionConfig(installConfig, []byte("root-ca"), "master")
+			if assert.Len(t, config.Ignition.Config.Append, 1) {
+				assert.Equal(t, tc.expectedSource, config.Ignition.Config.Append[0].Source)
+			}
+			if assert.Len(t, config.Ignition.Security.TLS.CertificateAuthorities, 1) {
+				ca, err := dataurl.DecodeString(config.Ignition.Security.TLS.CertificateAuthorities[0].Source)
+				if assert.NoError(t, err) {
+					assert.Equal(t, tc.expectedCA, string(ca.Data))
+				}
+			}
+		})
+	}
+}
@@ -39,3 +39,13 @@ func FileFromBytes(path string, mode int, contents []byte) ignition.File {
 		},
 	}
 }
+
+// SSHAuthorizedKeys converts the given public keys into ignition
+// authorized keys.
+func SSHAuthorizedKeys(keys []string) []ignition.SSHAuthorizedKey {
+	authorizedKeys := make([]ignition.SSHAuthorizedKey, 0, len(keys))
+	for _, key := range keys {
+		authorizedKeys = append(authorizedKeys, ignition.SSHAuthorizedKey(key))
+	}
+	return authorizedKeys
+}
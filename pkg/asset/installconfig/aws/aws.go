@@ -161,11 +161,28 @@ func getCredentials() error {
 		return err
 	}
 
+	sessionToken, err := asset.GenerateUserProvidedAsset(
+		"AWS Session Token",
+		&survey.Question{
+			Prompt: &survey.Password{
+				Message: "AWS Session Token",
+				Help:    "The session token for temporary (STS) credentials. Leave this empty for long-lived access keys.",
+			},
+		},
+		"",
+	)
+	if err != nil {
+		return err
+	}
+
 	tmpl, err := template.New("aws-credentials").Parse(`# Created by openshift-install
 # https://docs.aws.amazon.com/cli/latest/userguide/cli-configure-files.html
 [default]
 aws_access_key_id={{.KeyID}}
 aws_secret_access_key={{.SecretKey}}
+{{- if .SessionToken}}
+aws_session_token={{.SessionToken}}
+{{- end}}
 `)
 	if err != nil {
 		return err
@@ -185,7 +202,8 @@ aws_secret_access_key={{.SecretKey}}
 	defer file.Close()
 
 	return tmpl.Execute(file, map[string]string{
-		"KeyID":     keyID,
-		"SecretKey": secretKey,
+		"KeyID":        keyID,
+		"SecretKey":    secretKey,
+		"SessionToken": sessionToken,
 	})
 }
@@ -0,0 +1,33 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/pkg/errors"
+
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+)
+
+// ValidateInfraID checks that the IAM roles named after the infra ID do not
+// exist yet, which they would if another cluster used the same infra ID.
+func ValidateInfraID(platform *awstypes.Platform, infraID string) error {
+	ssn, err := NewSession(platform)
+	if err != nil {
+		return err
+	}
+	client := iam.New(ssn)
+	for _, role := range []string{"bootstrap", "master", "worker"} {
+		name := fmt.Sprintf("%s-%s-role", infraID, role)
+		_, err := client.GetRole(&iam.GetRoleInput{RoleName: aws.String(name)})
+		if err == nil {
+			return errors.Errorf("infra ID %q is already in use by IAM role %q", infraID, name)
+		}
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != iam.ErrCodeNoSuchEntityException {
+			return errors.Wrapf(err, "failed to get IAM role %q", name)
+		}
+	}
+	return nil
+}
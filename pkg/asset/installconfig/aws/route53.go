@@ -0,0 +1,138 @@
+package aws
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/pkg/errors"
+
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+)
+
+// HostedZone describes an existing Route53 hosted zone.
+type HostedZone struct {
+	// ID is the ID of the zone, without the "/hostedzone/" prefix.
+	ID string
+	// Name is the domain of the zone, without the trailing dot.
+	Name string
+	// Private is true for zones only resolvable within their VPCs.
+	Private bool
+	// NameServers are the name servers Route53 assigned to the zone.
+	NameServers []string
+	// VPCs are the IDs of the VPCs associated with a private zone.
+	VPCs []string
+}
+
+// GetHostedZone looks up the Route53 hosted zone with the given ID.
+func GetHostedZone(ssn *session.Session, id string) (*HostedZone, error) {
+	output, err := route53.New(ssn).GetHostedZone(&route53.GetHostedZoneInput{Id: aws.String(id)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get hosted zone %q", id)
+	}
+
+	zone := &HostedZone{
+		ID:   strings.TrimPrefix(aws.StringValue(output.HostedZone.Id), "/hostedzone/"),
+		Name: strings.TrimSuffix(aws.StringValue(output.HostedZone.Name), "."),
+	}
+	if output.HostedZone.Config != nil {
+		zone.Private = aws.BoolValue(output.HostedZone.Config.PrivateZone)
+	}
+	if output.DelegationSet != nil {
+		zone.NameServers = aws.StringValueSlice(output.DelegationSet.NameServers)
+	}
+	for _, vpc := range output.VPCs {
+		zone.VPCs = append(zone.VPCs, aws.StringValue(vpc.VPCId))
+	}
+	return zone, nil
+}
+
+// ValidateHostedZone checks that the hosted zone configured for the platform
+// exists, can hold the records for the base domain and, for public zones, is
+// delegated to the Route53 name servers.
+func ValidateHostedZone(platform *awstypes.Platform, baseDomain string) error {
+	if platform.HostedZone == "" {
+		return nil
+	}
+
+	ssn, err := NewSession(platform)
+	if err != nil {
+		return err
+	}
+	zone, err := GetHostedZone(ssn, platform.HostedZone)
+	if err != nil {
+		return err
+	}
+
+	if err := validateZoneDomain(zone.Name, baseDomain); err != nil {
+		return errors.Wrapf(err, "hosted zone %q", zone.ID)
+	}
+
+	if zone.Private || platform.PrivateZoneOnly {
+		return validatePrivateZone(zone, platform)
+	}
+
+	published, err := net.LookupNS(zone.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to look up the name servers for %q", zone.Name)
+	}
+	nameServers := make([]string, 0, len(published))
+	for _, ns := range published {
+		nameServers = append(nameServers, ns.Host)
+	}
+	return errors.Wrapf(validateDelegation(zone.NameServers, nameServers), "hosted zone %q", zone.ID)
+}
+
+// validateZoneDomain checks that records for the base domain can be created
+// in a zone for the given domain.
+func validateZoneDomain(zoneName, baseDomain string) error {
+	zoneName = normalizeDomain(zoneName)
+	baseDomain = normalizeDomain(baseDomain)
+	if baseDomain != zoneName && !strings.HasSuffix(baseDomain, "."+zoneName) {
+		return errors.Errorf("zone domain %q does not contain the base domain %q", zoneName, baseDomain)
+	}
+	return nil
+}
+
+// validateDelegation checks that the name servers published for a domain are
+// the ones assigned to its hosted zone.
+func validateDelegation(assigned, published []string) error {
+	normalize := func(names []string) []string {
+		normalized := make([]string, 0, len(names))
+		for _, name := range names {
+			normalized = append(normalized, normalizeDomain(name))
+		}
+		sort.Strings(normalized)
+		return normalized
+	}
+
+	assigned, published = normalize(assigned), normalize(published)
+	if strings.Join(assigned, ",") != strings.Join(published, ",") {
+		return errors.Errorf("is not delegated: the domain is served by %v but the zone by %v", published, assigned)
+	}
+	return nil
+}
+
+func normalizeDomain(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// validatePrivateZone checks that a zone holding the cluster's private
+// records is a private zone associated with the cluster's VPC.
+func validatePrivateZone(zone *HostedZone, platform *awstypes.Platform) error {
+	if !zone.Private {
+		return errors.Errorf("hosted zone %q is public, but privateZoneOnly is set", zone.ID)
+	}
+	if platform.VPCID == "" {
+		return errors.Errorf("private hosted zone %q requires an existing vpcID", zone.ID)
+	}
+	for _, vpc := range zone.VPCs {
+		if vpc == platform.VPCID {
+			return nil
+		}
+	}
+	return errors.Errorf("private hosted zone %q is not associated with VPC %q", zone.ID, platform.VPCID)
+}
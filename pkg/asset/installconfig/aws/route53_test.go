@@ -0,0 +1,86 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+)
+
+func TestValidateZoneDomain(t *testing.T) {
+	cases := []struct {
+		zone  string
+		base  string
+		valid bool
+	}{
+		{zone: "example.com", base: "example.com", valid: true},
+		{zone: "example.com.", base: "Example.COM", valid: true},
+		{zone: "example.com", base: "dev.example.com", valid: true},
+		{zone: "dev.example.com", base: "example.com"},
+		{zone: "example.com", base: "notexample.com"},
+	}
+	for _, tc := range cases {
+		err := validateZoneDomain(tc.zone, tc.base)
+		if tc.valid {
+			assert.NoError(t, err, "zone %q, base domain %q", tc.zone, tc.base)
+		} else {
+			assert.Error(t, err, "zone %q, base domain %q", tc.zone, tc.base)
+		}
+	}
+}
+
+func TestValidateDelegation(t *testing.T) {
+	assigned := []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.com"}
+	assert.NoError(t, validateDelegation(assigned, []string{"NS-2.awsdns-02.com.", "ns-1.awsdns-01.org."}))
+	assert.Error(t, validateDelegation(assigned, []string{"ns1.other-provider.net."}))
+	assert.Error(t, validateDelegation(assigned, nil))
+}
+
+func TestValidatePrivateZone(t *testing.T) {
+	cases := []struct {
+		name          string
+		zone          *HostedZone
+		platform      *awstypes.Platform
+		expectedError string
+	}{
+		{
+			name:     "private zone",
+			zone:     &HostedZone{ID: "Z1ILINNUJGTAO1", Private: true, VPCs: []string{"vpc-1", "vpc-2"}},
+			platform: &awstypes.Platform{VPCID: "vpc-2"},
+		},
+		{
+			name:     "private zone only",
+			zone:     &HostedZone{ID: "Z1ILINNUJGTAO1", Private: true, VPCs: []string{"vpc-1"}},
+			platform: &awstypes.Platform{VPCID: "vpc-1", PrivateZoneOnly: true},
+		},
+		{
+			name:          "public zone with private zone only",
+			zone:          &HostedZone{ID: "Z1ILINNUJGTAO1"},
+			platform:      &awstypes.Platform{VPCID: "vpc-1", PrivateZoneOnly: true},
+			expectedError: `^hosted zone "Z1ILINNUJGTAO1" is public, but privateZoneOnly is set$`,
+		},
+		{
+			name:          "private zone without a vpc",
+			zone:          &HostedZone{ID: "Z1ILINNUJGTAO1", Private: true, VPCs: []string{"vpc-1"}},
+			platform:      &awstypes.Platform{PrivateZoneOnly: true},
+			expectedError: `^private hosted zone "Z1ILINNUJGTAO1" requires an existing vpcID$`,
+		},
+		{
+			name:          "private zone of another vpc",
+			zone:          &HostedZone{ID: "Z1ILINNUJGTAO1", Private: true, VPCs: []string{"vpc-1"}},
+			platform:      &awstypes.Platform{VPCID: "vpc-2"},
+			expectedError: `^private hosted zone "Z1ILINNUJGTAO1" is not associated with VPC "vpc-2"$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validatePrivateZone(tc.zone, tc.platform)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/pkg/errors"
+
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+)
+
+// NewSession returns an AWS session for the platform's region which uses
+// its service endpoint overrides.
+func NewSession(platform *awstypes.Platform) (*session.Session, error) {
+	config := aws.Config{
+		EndpointResolver: endpointResolver(platform.ServiceEndpoints),
+	}
+	if platform.Region != "" {
+		config.Region = aws.String(platform.Region)
+	}
+	ssn, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            config,
+	})
+	return ssn, errors.Wrap(err, "failed to create AWS session")
+}
+
+// endpointResolver resolves the overridden services to their endpoints,
+// Route53 in the aws-cn partition to its global endpoint, and everything
+// else with the SDK's defaults.
+func endpointResolver(overrides []awstypes.ServiceEndpoint) endpoints.Resolver {
+	urls := make(map[string]string, len(overrides))
+	for _, endpoint := range overrides {
+		urls[endpoint.Name] = endpoint.URL
+	}
+	return endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		if url, ok := urls[service]; ok {
+			return endpoints.ResolvedEndpoint{
+				URL:           url,
+				SigningRegion: region,
+			}, nil
+		}
+		if service == "route53" && awstypes.Partition(region) == "aws-cn" {
+			return endpoints.ResolvedEndpoint{
+				URL:           awstypes.ChinaRoute53Endpoint,
+				SigningRegion: "cn-northwest-1",
+			}, nil
+		}
+		return endpoints.DefaultResolver().EndpointFor(service, region, opts...)
+	})
+}
@@ -0,0 +1,33 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+)
+
+func TestEndpointResolver(t *testing.T) {
+	resolver := endpointResolver([]awstypes.ServiceEndpoint{
+		{Name: "ec2", URL: "https://ec2.example.com"},
+	})
+
+	endpoint, err := resolver.EndpointFor("ec2", "us-gov-west-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://ec2.example.com", endpoint.URL)
+	assert.Equal(t, "us-gov-west-1", endpoint.SigningRegion)
+
+	endpoint, err = resolver.EndpointFor("iam", "us-gov-west-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://iam.us-gov.amazonaws.com", endpoint.URL)
+
+	endpoint, err = resolver.EndpointFor("route53", "cn-north-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://route53.amazonaws.com.cn", endpoint.URL)
+	assert.Equal(t, "cn-northwest-1", endpoint.SigningRegion)
+
+	endpoint, err = resolver.EndpointFor("iam", "cn-north-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://iam.cn-north-1.amazonaws.com.cn", endpoint.URL)
+}
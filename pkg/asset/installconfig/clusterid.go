@@ -4,6 +4,7 @@ import (
 	"github.com/pborman/uuid"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/reproducible"
 )
 
 type clusterID struct {
@@ -17,8 +18,13 @@ func (a *clusterID) Dependencies() []asset.Asset {
 	return []asset.Asset{}
 }
 
-// Generate generates a new UUID
+// Generate generates a new UUID, or derives one from the seed if there is
+// one.
 func (a *clusterID) Generate(asset.Parents) error {
+	if seed := reproducible.Seed(); seed != "" {
+		a.ClusterID = uuid.NewSHA1(uuid.NameSpace_OID, []byte(seed)).String()
+		return nil
+	}
 	a.ClusterID = uuid.New()
 	return nil
 }
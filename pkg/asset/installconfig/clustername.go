@@ -1,12 +1,33 @@
 package installconfig
 
 import (
+	"github.com/pkg/errors"
 	survey "gopkg.in/AlecAivazis/survey.v1"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/validate"
 )
 
+// clusterNameFlag is the cluster name given with --cluster, if any.
+var clusterNameFlag string
+
+// SetClusterName records the cluster name given with --cluster.  It takes
+// precedence over OPENSHIFT_INSTALL_CLUSTER_NAME, and an install config
+// read from disk must have the same metadata.name.
+func SetClusterName(name string) {
+	clusterNameFlag = name
+}
+
+// checkClusterName rejects an install config read from disk which names a
+// cluster other than the one given with --cluster.
+func checkClusterName(config *types.InstallConfig) error {
+	if clusterNameFlag != "" && config.ObjectMeta.Name != clusterNameFlag {
+		return errors.Errorf("metadata.name %q does not match --cluster %q", config.ObjectMeta.Name, clusterNameFlag)
+	}
+	return nil
+}
+
 type clusterName struct {
 	ClusterName string
 }
@@ -18,8 +39,14 @@ func (a *clusterName) Dependencies() []asset.Asset {
 	return []asset.Asset{}
 }
 
-// Generate queries for the cluster name from the user.
+// Generate queries for the cluster name from the user, unless it was given
+// with --cluster.
 func (a *clusterName) Generate(asset.Parents) error {
+	if clusterNameFlag != "" {
+		a.ClusterName = clusterNameFlag
+		return nil
+	}
+
 	n, err := asset.GenerateUserProvidedAsset(
 		a.Name(),
 		&survey.Question{
@@ -28,7 +55,7 @@ func (a *clusterName) Generate(asset.Parents) error {
 				Help:    "The name of the cluster.  This will be used when generating sub-domains.\n\nFor libvirt, choose a name that is unique enough to be used as a prefix during cluster deletion.  For example, if you use 'demo' as your cluster name, `openshift-install destroy cluster` may destroy all domains, networks, pools, and volumes that begin with 'demo'.",
 			},
 			Validate: survey.ComposeValidators(survey.Required, func(ans interface{}) error {
-				return validate.DomainName(ans.(string))
+				return validate.ClusterName(ans.(string))
 			}),
 		},
 		"OPENSHIFT_INSTALL_CLUSTER_NAME",
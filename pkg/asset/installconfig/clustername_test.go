@@ -0,0 +1,65 @@
+package installconfig
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestClusterNameFlag(t *testing.T) {
+	os.Setenv("OPENSHIFT_INSTALL_CLUSTER_NAME", "from-env")
+	defer os.Unsetenv("OPENSHIFT_INSTALL_CLUSTER_NAME")
+
+	clusterName := &clusterName{}
+	if assert.NoError(t, clusterName.Generate(nil)) {
+		assert.Equal(t, "from-env", clusterName.ClusterName)
+	}
+
+	SetClusterName("from-flag")
+	defer SetClusterName("")
+	if assert.NoError(t, clusterName.Generate(nil)) {
+		assert.Equal(t, "from-flag", clusterName.ClusterName)
+	}
+}
+
+func TestCheckClusterName(t *testing.T) {
+	cases := []struct {
+		name          string
+		flag          string
+		configured    string
+		expectedError string
+	}{
+		{
+			name:       "no flag",
+			configured: "test",
+		},
+		{
+			name:       "same",
+			flag:       "test",
+			configured: "test",
+		},
+		{
+			name:          "mismatch",
+			flag:          "other",
+			configured:    "test",
+			expectedError: `^metadata\.name "test" does not match --cluster "other"$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			SetClusterName(tc.flag)
+			defer SetClusterName("")
+
+			err := checkClusterName(&types.InstallConfig{ObjectMeta: metav1.ObjectMeta{Name: tc.configured}})
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package installconfig
+
+import (
+	"github.com/ghodss/yaml"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// completeInstallConfig queries for the values missing from a
+// user-provided install config, the same way they are queried for when
+// generating one, so templated install configs need only hold the values
+// which are the same for every cluster.  An install config without an
+// sshKey is asked for one; set it to "" for no key.  It returns the
+// fields which were added.
+func completeInstallConfig(config *types.InstallConfig, data []byte) ([]string, error) {
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	var completed []string
+	if config.ClusterID == "" {
+		clusterID := &clusterID{}
+		if err := clusterID.Generate(nil); err != nil {
+			return nil, err
+		}
+		config.ClusterID = clusterID.ClusterID
+		completed = append(completed, "clusterID")
+	}
+	if config.ObjectMeta.Name == "" {
+		clusterName := &clusterName{}
+		if err := clusterName.Generate(nil); err != nil {
+			return nil, err
+		}
+		config.ObjectMeta.Name = clusterName.ClusterName
+		completed = append(completed, "metadata.name")
+	}
+	if config.BaseDomain == "" {
+		baseDomain := &baseDomain{}
+		if err := baseDomain.Generate(nil); err != nil {
+			return nil, err
+		}
+		config.BaseDomain = baseDomain.BaseDomain
+		completed = append(completed, "baseDomain")
+	}
+	if config.Platform.Name() == "" {
+		platform := &platform{}
+		if err := platform.Generate(nil); err != nil {
+			return nil, err
+		}
+		config.Platform = types.Platform(*platform)
+		completed = append(completed, "platform")
+	}
+	if config.PullSecret == "" {
+		pullSecret := &pullSecret{}
+		if err := pullSecret.Generate(nil); err != nil {
+			return nil, err
+		}
+		config.PullSecret = pullSecret.PullSecret
+		completed = append(completed, "pullSecret")
+	}
+	if _, ok := fields["sshKey"]; !ok {
+		sshPublicKey := &sshPublicKey{}
+		if err := sshPublicKey.Generate(nil); err != nil {
+			return nil, err
+		}
+		config.SSHKey = types.ParseSSHKeys(sshPublicKey.Key)
+		completed = append(completed, "sshKey")
+	}
+	releaseImageCompleted, err := checkReleaseImage(config)
+	if err != nil {
+		return nil, err
+	}
+	if releaseImageCompleted {
+		completed = append(completed, "releaseImage")
+	}
+	return completed, nil
+}
@@ -0,0 +1,52 @@
+package installconfig
+
+import (
+	"os"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+type infraID struct {
+	// InfraID is the infra ID requested by the user, if any.
+	InfraID string
+}
+
+var _ asset.Asset = (*infraID)(nil)
+
+// Dependencies returns the cluster name, which is the default prefix.
+func (a *infraID) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&clusterName{},
+	}
+}
+
+// Generate reads the requested infra ID from the environment.  It is either
+// given whole, or as a prefix (defaulting to the cluster name) and a fixed
+// suffix.  Without either, the infra ID defaults to the cluster name.
+func (a *infraID) Generate(parents asset.Parents) error {
+	if id, ok := os.LookupEnv("OPENSHIFT_INSTALL_INFRA_ID"); ok && id != "" {
+		a.InfraID = id
+		return nil
+	}
+
+	prefix := os.Getenv("OPENSHIFT_INSTALL_INFRA_ID_PREFIX")
+	suffix := os.Getenv("OPENSHIFT_INSTALL_INFRA_ID_SUFFIX")
+	if prefix == "" && suffix == "" {
+		return nil
+	}
+	if prefix == "" {
+		clusterName := &clusterName{}
+		parents.Get(clusterName)
+		prefix = clusterName.ClusterName
+	}
+	a.InfraID = prefix
+	if suffix != "" {
+		a.InfraID += "-" + suffix
+	}
+	return nil
+}
+
+// Name returns the human-friendly name of the asset.
+func (a *infraID) Name() string {
+	return "Infra ID"
+}
@@ -3,19 +3,26 @@ package installconfig
 import (
 	"net"
 	"os"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/networkoperator/v1"
 	"github.com/openshift/installer/pkg/asset"
+	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
 	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/redact"
 	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/validation"
 )
 
 const (
-	installConfigFilename = "install-config.yml"
+	// InstallConfigFilename is the name of the install config file in the
+	// asset directory.
+	InstallConfigFilename = "install-config.yml"
 )
 
 var (
@@ -42,6 +49,8 @@ func (a *InstallConfig) Dependencies() []asset.Asset {
 		&clusterName{},
 		&pullSecret{},
 		&platform{},
+		&releaseImage{},
+		&infraID{},
 	}
 }
 
@@ -53,6 +62,8 @@ func (a *InstallConfig) Generate(parents asset.Parents) error {
 	clusterName := &clusterName{}
 	pullSecret := &pullSecret{}
 	platform := &platform{}
+	releaseImage := &releaseImage{}
+	infraID := &infraID{}
 	parents.Get(
 		clusterID,
 		sshPublicKey,
@@ -60,6 +71,8 @@ func (a *InstallConfig) Generate(parents asset.Parents) error {
 		clusterName,
 		pullSecret,
 		platform,
+		releaseImage,
+		infraID,
 	)
 
 	a.Config = &types.InstallConfig{
@@ -67,7 +80,8 @@ func (a *InstallConfig) Generate(parents asset.Parents) error {
 			Name: clusterName.ClusterName,
 		},
 		ClusterID:  clusterID.ClusterID,
-		SSHKey:     sshPublicKey.Key,
+		InfraID:    infraID.InfraID,
+		SSHKey:     types.ParseSSHKeys(sshPublicKey.Key),
 		BaseDomain: baseDomain.BaseDomain,
 		Networking: types.Networking{
 			Type: "OpenshiftSDN",
@@ -82,7 +96,8 @@ func (a *InstallConfig) Generate(parents asset.Parents) error {
 				},
 			},
 		},
-		PullSecret: pullSecret.PullSecret,
+		PullSecret:   pullSecret.PullSecret,
+		ReleaseImage: releaseImage.PullSpec,
 	}
 
 	numberOfMasters := int64(3)
@@ -111,12 +126,19 @@ func (a *InstallConfig) Generate(parents asset.Parents) error {
 		},
 	}
 
+	if err := validateInstallConfig(a.Config); err != nil {
+		return errors.Wrap(err, "invalid install config")
+	}
+	if err := validatePlatformResources(a.Config); err != nil {
+		return errors.Wrap(err, "invalid install config")
+	}
+
 	data, err := yaml.Marshal(a.Config)
 	if err != nil {
 		return errors.Wrap(err, "failed to Marshal InstallConfig")
 	}
 	a.File = &asset.File{
-		Filename: installConfigFilename,
+		Filename: InstallConfigFilename,
 		Data:     data,
 	}
 
@@ -136,6 +158,32 @@ func (a *InstallConfig) Files() []*asset.File {
 	return []*asset.File{}
 }
 
+// validateInstallConfig checks the install config itself, without calling
+// out to the target platform.
+func validateInstallConfig(config *types.InstallConfig) error {
+	// Register the pull secret first, so it is redacted from any errors
+	// which include it.
+	redact.RegisterPullSecret(config.PullSecret)
+
+	return validation.ValidateInstallConfig(config).ToAggregate()
+}
+
+// validatePlatformResources checks the install config against the resources
+// it references on the target platform.  It is only run when the install
+// config is generated, so loading an install config never needs the
+// platform's credentials.
+func validatePlatformResources(config *types.InstallConfig) error {
+	if config.Platform.AWS != nil {
+		if err := awsconfig.ValidateHostedZone(config.Platform.AWS, config.BaseDomain); err != nil {
+			return err
+		}
+		if config.InfraID != "" {
+			return awsconfig.ValidateInfraID(config.Platform.AWS, config.InfraID)
+		}
+	}
+	return nil
+}
+
 func parseCIDR(s string) net.IPNet {
 	_, cidr, _ := net.ParseCIDR(s)
 	return *cidr
@@ -143,7 +191,7 @@ func parseCIDR(s string) net.IPNet {
 
 // Load returns the installconfig from disk.
 func (a *InstallConfig) Load(f asset.FileFetcher) (found bool, err error) {
-	file, err := f.FetchByName(installConfigFilename)
+	file, err := f.FetchByName(InstallConfigFilename)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return false, nil
@@ -156,6 +204,26 @@ func (a *InstallConfig) Load(f asset.FileFetcher) (found bool, err error) {
 		return false, errors.Wrapf(err, "failed to unmarshal")
 	}
 
+	completed, err := completeInstallConfig(config, file.Data)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to complete %q", InstallConfigFilename)
+	}
+	if len(completed) > 0 {
+		logrus.Infof("Completed %s with the missing %s", InstallConfigFilename, strings.Join(completed, ", "))
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to Marshal InstallConfig")
+		}
+		file = &asset.File{Filename: InstallConfigFilename, Data: data}
+	}
+
+	if err := checkClusterName(config); err != nil {
+		return false, errors.Wrapf(err, "invalid %q file", InstallConfigFilename)
+	}
+	if err := validateInstallConfig(config); err != nil {
+		return false, errors.Wrapf(err, "invalid %q file", InstallConfigFilename)
+	}
+
 	a.File, a.Config = file, config
 	return true, nil
 }
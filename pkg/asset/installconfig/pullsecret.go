@@ -1,12 +1,30 @@
 package installconfig
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	survey "gopkg.in/AlecAivazis/survey.v1"
 
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/validate"
 )
 
+// pullSecretFile is the pull secret file passed with --pull-secret-file, if
+// any.
+var pullSecretFile string
+
+// SetPullSecretFile records the pull secret file passed with
+// --pull-secret-file.  It takes precedence over OPENSHIFT_INSTALL_PULL_SECRET
+// and OPENSHIFT_INSTALL_PULL_SECRET_PATH.
+func SetPullSecretFile(path string) {
+	pullSecretFile = path
+}
+
 type pullSecret struct {
 	PullSecret string
 }
@@ -18,9 +36,36 @@ func (a *pullSecret) Dependencies() []asset.Asset {
 	return []asset.Asset{}
 }
 
-// Generate queries for the pull secret from the user.
+// Generate queries for the pull secret from the user.  The pull secret may
+// also be an existing docker or podman auth file (e.g.
+// ~/.docker/config.json), from which the registry auths are extracted.
 func (a *pullSecret) Generate(asset.Parents) error {
-	s, err := asset.GenerateUserProvidedAssetForPath(
+	s, err := a.userProvided()
+	if err != nil {
+		return err
+	}
+	secret, ignored, err := registryAuths(s)
+	if err != nil {
+		return err
+	}
+	for _, registry := range ignored {
+		logrus.Warnf("Ignoring the pull secret entry for %s, which has no credentials (they may be kept by a credential helper)", registry)
+	}
+	a.PullSecret = secret
+	return nil
+}
+
+// userProvided returns the pull secret from --pull-secret-file or, failing
+// that, the environment or the user.
+func (a *pullSecret) userProvided() (string, error) {
+	if pullSecretFile != "" {
+		data, err := ioutil.ReadFile(pullSecretFile)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read the pull secret file")
+		}
+		return string(data), nil
+	}
+	return asset.GenerateUserProvidedAssetForPath(
 		a.Name(),
 		&survey.Question{
 			Prompt: &survey.Input{
@@ -28,17 +73,68 @@ func (a *pullSecret) Generate(asset.Parents) error {
 				Help:    "The container registry pull secret for this cluster, as a single line of JSON (e.g. {\"auths\": {...}}).\n\nYou can get this secret from https://try.openshift.com",
 			},
 			Validate: survey.ComposeValidators(survey.Required, func(ans interface{}) error {
-				return validate.JSON([]byte(ans.(string)))
+				_, _, err := registryAuths(ans.(string))
+				return err
 			}),
 		},
 		"OPENSHIFT_INSTALL_PULL_SECRET",
 		"OPENSHIFT_INSTALL_PULL_SECRET_PATH",
 	)
-	a.PullSecret = s
-	return err
 }
 
 // Name returns the human-friendly name of the asset.
 func (a *pullSecret) Name() string {
 	return "Pull Secret"
 }
+
+// authFile is the part of a docker or podman auth file holding the
+// credentials.
+type authFile struct {
+	Auths map[string]registryAuth `json:"auths"`
+}
+
+type registryAuth struct {
+	Auth     string `json:"auth,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// registryAuths returns a pull secret holding the registry auths of an auth
+// file.  Other settings (e.g. credsStore) are dropped, along with registries
+// whose credentials are kept by a credential helper rather than in the file,
+// which are returned as ignored.
+func registryAuths(data string) (secret string, ignored []string, err error) {
+	var file authFile
+	if err := json.Unmarshal([]byte(data), &file); err != nil {
+		return "", nil, errors.Wrap(err, "invalid pull secret")
+	}
+
+	registries := make([]string, 0, len(file.Auths))
+	for registry := range file.Auths {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+
+	auths := authFile{Auths: map[string]registryAuth{}}
+	for _, registry := range registries {
+		auth := file.Auths[registry]
+		if auth.Auth == "" && auth.Username != "" {
+			auth.Auth = base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+		}
+		if auth.Auth == "" {
+			ignored = append(ignored, registry)
+			continue
+		}
+		auths.Auths[registry] = registryAuth{Auth: auth.Auth, Email: auth.Email}
+	}
+
+	encoded, err := json.Marshal(auths)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := validate.PullSecret(string(encoded)); err != nil {
+		return "", nil, err
+	}
+	return string(encoded), ignored, nil
+}
@@ -0,0 +1,88 @@
+package installconfig
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryAuths(t *testing.T) {
+	cases := []struct {
+		name            string
+		data            string
+		expectedSecret  string
+		expectedIgnored []string
+		expectedError   string
+	}{
+		{
+			name:           "pull secret",
+			data:           `{"auths":{"quay.io":{"auth":"dXNlcjpwYXNz","email":"user@example.com"}}}`,
+			expectedSecret: `{"auths":{"quay.io":{"auth":"dXNlcjpwYXNz","email":"user@example.com"}}}`,
+		},
+		{
+			name:           "username and password",
+			data:           `{"auths":{"registry.example.com":{"username":"user","password":"pass"}}}`,
+			expectedSecret: `{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}`,
+		},
+		{
+			name:            "credential helper",
+			data:            `{"auths":{"quay.io":{"auth":"dXNlcjpwYXNz"},"registry.example.com":{},"docker.io":{}},"credsStore":"secretservice"}`,
+			expectedSecret:  `{"auths":{"quay.io":{"auth":"dXNlcjpwYXNz"}}}`,
+			expectedIgnored: []string{"docker.io", "registry.example.com"},
+		},
+		{
+			name:          "only credential helpers",
+			data:          `{"auths":{"quay.io":{}},"credsStore":"secretservice"}`,
+			expectedError: `^invalid pull secret \(no registry auths\)$`,
+		},
+		{
+			name:          "invalid JSON",
+			data:          `{"auths":`,
+			expectedError: `^invalid pull secret: unexpected end of JSON input$`,
+		},
+		{
+			name:          "auth without password",
+			data:          `{"auths":{"quay.io":{"auth":"dXNlcg=="}}}`,
+			expectedError: `^invalid pull secret \(auth for "quay\.io" is not of the form user:password\)$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			secret, ignored, err := registryAuths(tc.data)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+			assert.Equal(t, tc.expectedSecret, secret)
+			assert.Equal(t, tc.expectedIgnored, ignored)
+		})
+	}
+}
+
+func TestPullSecretFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "openshift-install-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(`{"auths":{"quay.io":{"auth":"ZmxhZzpwYXNz"}}}`); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	os.Setenv("OPENSHIFT_INSTALL_PULL_SECRET", `{"auths":{"quay.io":{"auth":"ZW52OnBhc3M="}}}`)
+	defer os.Unsetenv("OPENSHIFT_INSTALL_PULL_SECRET")
+	SetPullSecretFile(file.Name())
+	defer SetPullSecretFile("")
+
+	pullSecret := &pullSecret{}
+	if assert.NoError(t, pullSecret.Generate(nil)) {
+		assert.Equal(t, `{"auths":{"quay.io":{"auth":"ZmxhZzpwYXNz"}}}`, pullSecret.PullSecret)
+	}
+
+	SetPullSecretFile(file.Name() + ".missing")
+	assert.Regexp(t, "^failed to read the pull secret file: ", pullSecret.Generate(nil))
+}
@@ -0,0 +1,103 @@
+package installconfig
+
+import (
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// releaseImageEnvVar holds the requested release image, which is
+	// recorded in the install config.  --release-image takes precedence.
+	releaseImageEnvVar = "OPENSHIFT_INSTALL_RELEASE_IMAGE"
+
+	// releaseImageOverrideEnvVar is the deprecated way to request a
+	// release image.  As before it was deprecated, it overrides the
+	// default release image without being recorded in the install config,
+	// so it need not be pinned to a digest.
+	releaseImageOverrideEnvVar = "OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE"
+)
+
+var (
+	warnReleaseImageOverride sync.Once
+
+	// releaseImageFlag is the release image requested with
+	// --release-image, if any.
+	releaseImageFlag string
+)
+
+// SetReleaseImage records the release image requested with
+// --release-image.  It takes precedence over
+// OPENSHIFT_INSTALL_RELEASE_IMAGE.
+func SetReleaseImage(pullSpec string) {
+	releaseImageFlag = pullSpec
+}
+
+// requestedReleaseImage returns the release image requested with
+// --release-image or, failing that, OPENSHIFT_INSTALL_RELEASE_IMAGE.
+func requestedReleaseImage() string {
+	if releaseImageFlag != "" {
+		return releaseImageFlag
+	}
+	return os.Getenv(releaseImageEnvVar)
+}
+
+type releaseImage struct {
+	// PullSpec is the release image requested by the user, if any.
+	PullSpec string
+}
+
+var _ asset.Asset = (*releaseImage)(nil)
+
+// Dependencies returns no dependencies.
+func (a *releaseImage) Dependencies() []asset.Asset {
+	return nil
+}
+
+// Generate reads the requested release image from --release-image or the
+// environment.
+func (a *releaseImage) Generate(asset.Parents) error {
+	a.PullSpec = requestedReleaseImage()
+	return nil
+}
+
+// Name returns the human-friendly name of the asset.
+func (a *releaseImage) Name() string {
+	return "Release Image"
+}
+
+// checkReleaseImage reconciles the release image requested with
+// --release-image or OPENSHIFT_INSTALL_RELEASE_IMAGE, if any, with that of an install config read from disk:
+// the install config is completed with it, but must not disagree with it.
+func checkReleaseImage(config *types.InstallConfig) (completed bool, err error) {
+	requested := requestedReleaseImage()
+	switch {
+	case requested == "" || requested == config.ReleaseImage:
+		return false, nil
+	case config.ReleaseImage == "":
+		config.ReleaseImage = requested
+		return true, nil
+	default:
+		return false, errors.Errorf("the requested release image %s conflicts with the install config's releaseImage %s", requested, config.ReleaseImage)
+	}
+}
+
+// ReleaseImagePullSpec returns the pull spec of the release payload to
+// install: the install config's releaseImage, the deprecated
+// OPENSHIFT_INSTALL_RELEASE_IMAGE_OVERRIDE, or the default.
+func ReleaseImagePullSpec(config *types.InstallConfig) string {
+	if config.ReleaseImage == "" {
+		if ri := os.Getenv(releaseImageOverrideEnvVar); ri != "" {
+			warnReleaseImageOverride.Do(func() {
+				logrus.Warnf("%s is deprecated; use --release-image or %s instead", releaseImageOverrideEnvVar, releaseImageEnvVar)
+			})
+			return ri
+		}
+	}
+	return config.ReleaseImagePullSpec()
+}
@@ -0,0 +1,92 @@
+package installconfig
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+const testReleaseImage = "quay.io/openshift-release-dev/ocp-release@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestCheckReleaseImage(t *testing.T) {
+	cases := []struct {
+		name              string
+		requested         string
+		configured        string
+		expectedImage     string
+		expectedCompleted bool
+		expectedError     string
+	}{
+		{
+			name:          "none requested",
+			configured:    testReleaseImage,
+			expectedImage: testReleaseImage,
+		},
+		{
+			name:              "completed",
+			requested:         testReleaseImage,
+			expectedImage:     testReleaseImage,
+			expectedCompleted: true,
+		},
+		{
+			name:          "same",
+			requested:     testReleaseImage,
+			configured:    testReleaseImage,
+			expectedImage: testReleaseImage,
+		},
+		{
+			name:          "conflicting",
+			requested:     "quay.io/openshift-release-dev/ocp-release@sha256:" + "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+			configured:    testReleaseImage,
+			expectedImage: testReleaseImage,
+			expectedError: "the requested release image .* conflicts with the install config's releaseImage .*",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Setenv(releaseImageEnvVar, tc.requested)
+			defer os.Unsetenv(releaseImageEnvVar)
+
+			config := &types.InstallConfig{ReleaseImage: tc.configured}
+			completed, err := checkReleaseImage(config)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+			assert.Equal(t, tc.expectedCompleted, completed)
+			assert.Equal(t, tc.expectedImage, config.ReleaseImage)
+		})
+	}
+}
+
+func TestRequestedReleaseImage(t *testing.T) {
+	flag := "quay.io/openshift-release-dev/ocp-release@sha256:" + "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"
+	os.Setenv(releaseImageEnvVar, testReleaseImage)
+	defer os.Unsetenv(releaseImageEnvVar)
+
+	assert.Equal(t, testReleaseImage, requestedReleaseImage())
+
+	SetReleaseImage(flag)
+	defer SetReleaseImage("")
+	assert.Equal(t, flag, requestedReleaseImage())
+
+	config := &types.InstallConfig{}
+	completed, err := checkReleaseImage(config)
+	assert.NoError(t, err)
+	assert.True(t, completed)
+	assert.Equal(t, flag, config.ReleaseImage)
+}
+
+func TestReleaseImagePullSpecOverride(t *testing.T) {
+	// The deprecated override need not be pinned to a digest.
+	override := "registry.example.com/ocp/release:4.1"
+	os.Setenv(releaseImageOverrideEnvVar, override)
+	defer os.Unsetenv(releaseImageOverrideEnvVar)
+
+	assert.Equal(t, override, ReleaseImagePullSpec(&types.InstallConfig{}))
+	assert.Equal(t, testReleaseImage, ReleaseImagePullSpec(&types.InstallConfig{ReleaseImage: testReleaseImage}))
+}
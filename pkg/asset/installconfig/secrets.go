@@ -0,0 +1,79 @@
+package installconfig
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/redact"
+)
+
+// secretsBackend fetches secrets kept outside of the install config and the
+// asset directory, e.g. in Vault.
+type secretsBackend interface {
+	// Secrets returns the secrets held by the backend, by name.
+	Secrets() (map[string]string, error)
+}
+
+// secretEnvironmentVariables are the environment variables through which
+// the secrets, by their names in a secrets backend, are consumed: the pull
+// secret and SSH keys by the install config assets, and the cloud
+// credentials by the cloud SDKs and Terraform.
+var secretEnvironmentVariables = map[string]string{
+	"pullSecret":         "OPENSHIFT_INSTALL_PULL_SECRET",
+	"sshKey":             "OPENSHIFT_INSTALL_SSH_PUB_KEY",
+	"awsAccessKeyID":     "AWS_ACCESS_KEY_ID",
+	"awsSecretAccessKey": "AWS_SECRET_ACCESS_KEY",
+	"awsSessionToken":    "AWS_SESSION_TOKEN",
+	"openstackPassword":  "OS_PASSWORD",
+}
+
+// newSecretsBackend returns the secrets backend configured in the
+// environment, or nil if there is none.
+func newSecretsBackend() (secretsBackend, error) {
+	path := os.Getenv("OPENSHIFT_INSTALL_VAULT_PATH")
+	if path == "" {
+		return nil, nil
+	}
+	return newVaultBackend(os.Getenv("VAULT_ADDR"), path, os.Getenv("OPENSHIFT_INSTALL_VAULT_AUTH_METHOD"))
+}
+
+// ExportSecrets fetches the secrets from the configured secrets backend, if
+// any, and sets the environment variables through which they are consumed,
+// so they never have to be written to the install config or the asset
+// directory.  Secrets already given in the environment, directly or as a
+// path, take precedence.
+func ExportSecrets() error {
+	backend, err := newSecretsBackend()
+	if err != nil || backend == nil {
+		return err
+	}
+	secrets, err := backend.Secrets()
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch the secrets")
+	}
+
+	for name, envVar := range secretEnvironmentVariables {
+		value, ok := secrets[name]
+		if !ok || value == "" {
+			continue
+		}
+		if _, ok := os.LookupEnv(envVar); ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(envVar + "_PATH"); ok {
+			continue
+		}
+		if name == "pullSecret" {
+			redact.RegisterPullSecret(value)
+		} else {
+			redact.Register(value)
+		}
+		if err := os.Setenv(envVar, value); err != nil {
+			return err
+		}
+		logrus.Debugf("Using the %s from the secrets backend", name)
+	}
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	survey "gopkg.in/AlecAivazis/survey.v1"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/validate"
 )
 
@@ -19,6 +20,7 @@ const (
 )
 
 type sshPublicKey struct {
+	// Key holds the selected public keys in authorized_keys format.
 	Key string
 }
 
@@ -37,7 +39,7 @@ func readSSHKey(path string) (string, error) {
 
 	key := string(keyAsBytes)
 
-	err = validate.SSHPublicKey(key)
+	err = validateSSHKeys(key)
 	if err != nil {
 		return "", err
 	}
@@ -45,11 +47,25 @@ func readSSHKey(path string) (string, error) {
 	return key, nil
 }
 
+// validateSSHKeys checks every key in the given authorized_keys content.
+func validateSSHKeys(content string) error {
+	keys := types.ParseSSHKeys(content)
+	if len(keys) == 0 {
+		return errors.New("no SSH public keys found")
+	}
+	for _, key := range keys {
+		if err := validate.SSHAuthorizedKey(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Generate generates the SSH public key asset.
 func (a *sshPublicKey) Generate(asset.Parents) error {
 	if value, ok := os.LookupEnv("OPENSHIFT_INSTALL_SSH_PUB_KEY"); ok {
 		if value != "" {
-			if err := validate.SSHPublicKey(value); err != nil {
+			if err := validateSSHKeys(value); err != nil {
 				return errors.Wrap(err, "failed to validate public key")
 			}
 		}
@@ -0,0 +1,197 @@
+package installconfig
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// vaultAuthToken authenticates with VAULT_TOKEN, or the token saved
+	// by 'vault login'.
+	vaultAuthToken = "token"
+
+	// vaultAuthAppRole logs in with VAULT_ROLE_ID and VAULT_SECRET_ID.
+	vaultAuthAppRole = "approle"
+
+	// vaultAuthKubernetes logs in as VAULT_ROLE with the pod's service
+	// account token.
+	vaultAuthKubernetes = "kubernetes"
+
+	kubernetesServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// vaultBackend reads the secrets from a secret in Vault's KV secrets
+// engine, version 1 or 2.
+type vaultBackend struct {
+	address    string
+	path       string
+	authMethod string
+	client     *http.Client
+}
+
+var _ secretsBackend = (*vaultBackend)(nil)
+
+func newVaultBackend(address string, path string, authMethod string) (*vaultBackend, error) {
+	if address == "" {
+		return nil, errors.New("VAULT_ADDR must be set to read secrets from Vault")
+	}
+	if authMethod == "" {
+		authMethod = vaultAuthToken
+	}
+	switch authMethod {
+	case vaultAuthToken, vaultAuthAppRole, vaultAuthKubernetes:
+	default:
+		return nil, errors.Errorf("unsupported Vault auth method %q; must be %s, %s or %s", authMethod, vaultAuthToken, vaultAuthAppRole, vaultAuthKubernetes)
+	}
+
+	tlsConfig := &tls.Config{}
+	if caCert := os.Getenv("VAULT_CACERT"); caCert != "" {
+		data, err := ioutil.ReadFile(caCert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read VAULT_CACERT")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.Errorf("no certificates found in %s", caCert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &vaultBackend{
+		address:    strings.TrimSuffix(address, "/"),
+		path:       strings.Trim(path, "/"),
+		authMethod: authMethod,
+		client: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment},
+		},
+	}, nil
+}
+
+// Secrets returns the fields of the secret at the backend's path.
+func (v *vaultBackend) Secrets() (map[string]string, error) {
+	token, err := v.token()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to authenticate to Vault with %s", v.authMethod)
+	}
+
+	logrus.WithField("component", "http").Debugf("Fetching the secrets from %s in Vault", v.path)
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := v.do("GET", v.path, token, nil, &response); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s from Vault", v.path)
+	}
+
+	data := response.Data
+	// Version 2 of the KV secrets engine nests the fields beside the
+	// secret's metadata.
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	secrets := make(map[string]string, len(data))
+	for name, value := range data {
+		if s, ok := value.(string); ok {
+			secrets[name] = s
+		}
+	}
+	return secrets, nil
+}
+
+// token returns a Vault token, logging in with the auth method if needed.
+func (v *vaultBackend) token() (string, error) {
+	var login interface{}
+	switch v.authMethod {
+	case vaultAuthToken:
+		if token := os.Getenv("VAULT_TOKEN"); token != "" {
+			return token, nil
+		}
+		data, err := ioutil.ReadFile(filepath.Join(os.Getenv("HOME"), ".vault-token"))
+		if err != nil {
+			return "", errors.Wrap(err, "VAULT_TOKEN is not set and no token was saved by 'vault login'")
+		}
+		return strings.TrimSpace(string(data)), nil
+	case vaultAuthAppRole:
+		roleID, secretID := os.Getenv("VAULT_ROLE_ID"), os.Getenv("VAULT_SECRET_ID")
+		if roleID == "" || secretID == "" {
+			return "", errors.New("VAULT_ROLE_ID and VAULT_SECRET_ID must be set")
+		}
+		login = map[string]string{"role_id": roleID, "secret_id": secretID}
+	case vaultAuthKubernetes:
+		role := os.Getenv("VAULT_ROLE")
+		if role == "" {
+			return "", errors.New("VAULT_ROLE must be set")
+		}
+		jwt, err := ioutil.ReadFile(kubernetesServiceAccountTokenPath)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read the service account token")
+		}
+		login = map[string]string{"role": role, "jwt": strings.TrimSpace(string(jwt))}
+	}
+
+	var response struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if err := v.do("POST", fmt.Sprintf("auth/%s/login", v.authMethod), "", login, &response); err != nil {
+		return "", err
+	}
+	if response.Auth.ClientToken == "" {
+		return "", errors.New("no token in the login response")
+	}
+	return response.Auth.ClientToken, nil
+}
+
+// do sends a request to Vault's HTTP API and decodes its response into
+// out.
+func (v *vaultBackend) do(method string, path string, token string, in interface{}, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/v1/%s", v.address, path), &body)
+	if err != nil {
+		return errors.Wrap(err, "failed to build request")
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Errors []string `json:"errors"`
+		}
+		json.NewDecoder(resp.Body).Decode(&failure)
+		if len(failure.Errors) > 0 {
+			return errors.Errorf("incorrect HTTP response (%s): %s", resp.Status, strings.Join(failure.Errors, "; "))
+		}
+		return errors.Errorf("incorrect HTTP response (%s)", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,57 @@
+package installconfig
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVaultBackendSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			var login map[string]string
+			json.NewDecoder(r.Body).Decode(&login)
+			if login["role_id"] != "role" || login["secret_id"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errors":["invalid role or secret ID"]}`))
+				return
+			}
+			w.Write([]byte(`{"auth":{"client_token":"s.login"}}`))
+		case "/v1/secret/data/openshift":
+			if r.Header.Get("X-Vault-Token") != "s.login" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"errors":["permission denied"]}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"data":{"pullSecret":"{\"auths\":{}}","sshKey":"ssh-ed25519 AAAA"},"metadata":{"version":3}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	backend, err := newVaultBackend(server.URL, "/secret/data/openshift", vaultAuthAppRole)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	os.Setenv("VAULT_ROLE_ID", "role")
+	os.Setenv("VAULT_SECRET_ID", "wrong")
+	_, err = backend.Secrets()
+	assert.EqualError(t, err, "failed to authenticate to Vault with approle: incorrect HTTP response (400 Bad Request): invalid role or secret ID")
+
+	os.Setenv("VAULT_SECRET_ID", "secret")
+	defer os.Unsetenv("VAULT_ROLE_ID")
+	defer os.Unsetenv("VAULT_SECRET_ID")
+	secrets, err := backend.Secrets()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"pullSecret": `{"auths":{}}`, "sshKey": "ssh-ed25519 AAAA"}, secrets)
+
+	_, err = newVaultBackend(server.URL, "secret/openshift", "ldap")
+	assert.EqualError(t, err, `unsupported Vault auth method "ldap"; must be token, approle or kubernetes`)
+}
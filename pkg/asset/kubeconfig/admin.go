@@ -6,6 +6,7 @@ import (
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/encryption"
 )
 
 var (
@@ -39,6 +40,7 @@ func (k *Admin) Generate(parents asset.Parents) error {
 		rootCA,
 		adminCertKey,
 		installConfig.Config,
+		installConfig.Config.Proxy,
 		"admin",
 		kubeconfigAdminPath,
 	)
@@ -53,3 +55,16 @@ func (k *Admin) Name() string {
 func (k *Admin) Load(f asset.FileFetcher) (found bool, err error) {
 	return k.load(f, kubeconfigAdminPath)
 }
+
+// AdminPath returns the path of the admin kubeconfig in the asset
+// directory dir.  It is encrypted when the auth files are; read it with
+// ReadAdmin.
+func AdminPath(dir string) string {
+	return filepath.Join(dir, kubeconfigAdminPath)
+}
+
+// ReadAdmin reads the admin kubeconfig from the asset directory dir,
+// decrypting it if necessary.
+func ReadAdmin(dir string) ([]byte, error) {
+	return encryption.ReadFile(AdminPath(dir))
+}
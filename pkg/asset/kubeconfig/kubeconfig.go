@@ -2,7 +2,9 @@ package kubeconfig
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
@@ -18,31 +20,60 @@ type kubeconfig struct {
 	File   *asset.File
 }
 
-// generate generates the kubeconfig.
+// generate generates the kubeconfig.  With a proxy, the kubeconfig
+// reaches the API through it, trusting the proxy's CAs.
 func (k *kubeconfig) generate(
 	rootCA tls.CertKeyInterface,
 	clientCertKey tls.CertKeyInterface,
 	installConfig *types.InstallConfig,
+	proxy *types.Proxy,
 	userName string,
 	kubeconfigPath string,
 ) error {
+	return k.generateWithAuthInfo(
+		rootCA,
+		clientcmd.AuthInfo{
+			ClientCertificateData: []byte(clientCertKey.Cert()),
+			ClientKeyData:         []byte(clientCertKey.Key()),
+		},
+		installConfig,
+		proxy,
+		userName,
+		kubeconfigPath,
+	)
+}
+
+// generateWithAuthInfo generates a kubeconfig authenticating the user with
+// authInfo, e.g. through an exec credential plugin.
+func (k *kubeconfig) generateWithAuthInfo(
+	rootCA tls.CertKeyInterface,
+	authInfo clientcmd.AuthInfo,
+	installConfig *types.InstallConfig,
+	proxy *types.Proxy,
+	userName string,
+	kubeconfigPath string,
+) error {
+	server := fmt.Sprintf("https://%s-api.%s:6443", installConfig.ObjectMeta.Name, installConfig.BaseDomain)
+	caData := []byte(rootCA.Cert())
+	proxyURL := apiProxyURL(proxy, server)
+	if proxyURL != "" && proxy.TrustBundle != "" {
+		caData = append(append(caData, '\n'), proxy.TrustBundle...)
+	}
+
 	k.Config = &clientcmd.Config{
 		Clusters: []clientcmd.NamedCluster{
 			{
 				Name: installConfig.ObjectMeta.Name,
 				Cluster: clientcmd.Cluster{
-					Server: fmt.Sprintf("https://%s-api.%s:6443", installConfig.ObjectMeta.Name, installConfig.BaseDomain),
-					CertificateAuthorityData: []byte(rootCA.Cert()),
+					Server: server,
+					CertificateAuthorityData: caData,
 				},
 			},
 		},
 		AuthInfos: []clientcmd.NamedAuthInfo{
 			{
-				Name: userName,
-				AuthInfo: clientcmd.AuthInfo{
-					ClientCertificateData: []byte(clientCertKey.Cert()),
-					ClientKeyData:         []byte(clientCertKey.Key()),
-				},
+				Name:     userName,
+				AuthInfo: authInfo,
 			},
 		},
 		Contexts: []clientcmd.NamedContext{
@@ -61,6 +92,12 @@ func (k *kubeconfig) generate(
 	if err != nil {
 		return errors.Wrap(err, "failed to Marshal kubeconfig")
 	}
+	if proxyURL != "" {
+		data, err = setProxyURL(data, proxyURL)
+		if err != nil {
+			return errors.Wrap(err, "failed to set the proxy URL")
+		}
+	}
 
 	k.File = &asset.File{
 		Filename: kubeconfigPath,
@@ -96,3 +133,85 @@ func (k *kubeconfig) load(f asset.FileFetcher, name string) (found bool, err err
 	k.File, k.Config = file, config
 	return true, nil
 }
+
+// apiProxyURL returns the URL of the proxy through which the server is
+// reached, or "" if it is reached directly.
+func apiProxyURL(proxy *types.Proxy, server string) string {
+	if proxy == nil {
+		return ""
+	}
+	proxyURL := proxy.HTTPSProxy
+	if proxyURL == "" {
+		proxyURL = proxy.HTTPProxy
+	}
+	if proxyURL == "" {
+		return ""
+	}
+
+	u, err := url.Parse(server)
+	if err != nil {
+		return proxyURL
+	}
+	host := u.Hostname()
+	for _, entry := range strings.Split(proxy.NoProxy, ",") {
+		if entry == "*" || entry == host {
+			return ""
+		}
+		if domain := strings.TrimPrefix(entry, "."); domain != "" && strings.HasSuffix(host, "."+domain) {
+			return ""
+		}
+	}
+	return proxyURL
+}
+
+// setProxyURL sets the proxy-url of the kubeconfig's clusters.  The
+// vendored client-go predates the field, so it is added to the marshalled
+// kubeconfig.
+func setProxyURL(data []byte, proxyURL string) ([]byte, error) {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	clusters, _ := config["clusters"].([]interface{})
+	for _, c := range clusters {
+		if cluster, ok := c.(map[string]interface{})["cluster"].(map[string]interface{}); ok {
+			cluster["proxy-url"] = proxyURL
+		}
+	}
+	return yaml.Marshal(config)
+}
+
+// ProxyURL returns the proxy-url of the kubeconfig's current cluster, or
+// nil if it has none.
+func ProxyURL(data []byte) (*url.URL, error) {
+	var config struct {
+		Clusters []struct {
+			Name    string `json:"name"`
+			Cluster struct {
+				ProxyURL string `json:"proxy-url"`
+			} `json:"cluster"`
+		} `json:"clusters"`
+		Contexts []struct {
+			Name    string `json:"name"`
+			Context struct {
+				Cluster string `json:"cluster"`
+			} `json:"context"`
+		} `json:"contexts"`
+		CurrentContext string `json:"current-context"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal kubeconfig")
+	}
+
+	for _, context := range config.Contexts {
+		if context.Name != config.CurrentContext {
+			continue
+		}
+		for _, cluster := range config.Clusters {
+			if cluster.Name == context.Context.Cluster && cluster.Cluster.ProxyURL != "" {
+				return url.Parse(cluster.Cluster.ProxyURL)
+			}
+		}
+	}
+	return nil, nil
+}
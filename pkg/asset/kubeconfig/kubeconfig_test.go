@@ -1,13 +1,17 @@
 package kubeconfig
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
-
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/encryption"
 	"github.com/openshift/installer/pkg/types"
 )
 
@@ -52,6 +56,7 @@ func TestKubeconfigGenerate(t *testing.T) {
 		userName     string
 		filename     string
 		clientCert   tls.CertKeyInterface
+		proxy        *types.Proxy
 		expectedData []byte
 	}{
 		{
@@ -72,6 +77,65 @@ contexts:
 current-context: admin
 preferences: {}
 users:
+- name: admin
+  user:
+    client-certificate-data: VEhJUyBJUyBBRE1JTiBDRVJUIERBVEE=
+    client-key-data: VEhJUyBJUyBBRE1JTiBLRVkgREFUQQ==
+`),
+		},
+		{
+			name:       "admin kubeconfig with proxy",
+			userName:   "admin",
+			filename:   "auth/kubeconfig",
+			clientCert: adminCert,
+			proxy: &types.Proxy{
+				HTTPProxy:   "http://proxy.example.com:3128",
+				HTTPSProxy:  "https://proxy.example.com:3129",
+				NoProxy:     ".internal.example.com",
+				TrustBundle: "THIS IS PROXY CA CERT DATA",
+			},
+			expectedData: []byte(`clusters:
+- cluster:
+    certificate-authority-data: VEhJUyBJUyBST09UIENBIENFUlQgREFUQQpUSElTIElTIFBST1hZIENBIENFUlQgREFUQQ==
+    proxy-url: https://proxy.example.com:3129
+    server: https://test-cluster-name-api.test.example.com:6443
+  name: test-cluster-name
+contexts:
+- context:
+    cluster: test-cluster-name
+    user: admin
+  name: admin
+current-context: admin
+preferences: {}
+users:
+- name: admin
+  user:
+    client-certificate-data: VEhJUyBJUyBBRE1JTiBDRVJUIERBVEE=
+    client-key-data: VEhJUyBJUyBBRE1JTiBLRVkgREFUQQ==
+`),
+		},
+		{
+			name:       "admin kubeconfig with API in noProxy",
+			userName:   "admin",
+			filename:   "auth/kubeconfig",
+			clientCert: adminCert,
+			proxy: &types.Proxy{
+				HTTPProxy: "http://proxy.example.com:3128",
+				NoProxy:   "localhost,.test.example.com",
+			},
+			expectedData: []byte(`clusters:
+- cluster:
+    certificate-authority-data: VEhJUyBJUyBST09UIENBIENFUlQgREFUQQ==
+    server: https://test-cluster-name-api.test.example.com:6443
+  name: test-cluster-name
+contexts:
+- context:
+    cluster: test-cluster-name
+    user: admin
+  name: admin
+current-context: admin
+preferences: {}
+users:
 - name: admin
   user:
     client-certificate-data: VEhJUyBJUyBBRE1JTiBDRVJUIERBVEE=
@@ -107,13 +171,99 @@ users:
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			kc := &kubeconfig{}
-			err := kc.generate(rootCA, tt.clientCert, installConfig, tt.userName, tt.filename)
+			err := kc.generate(rootCA, tt.clientCert, installConfig, tt.proxy, tt.userName, tt.filename)
 			assert.NoError(t, err, "unexpected error generating config")
 			actualFiles := kc.Files()
 			assert.Equal(t, 1, len(actualFiles), "unexpected number of files generated")
 			assert.Equal(t, tt.filename, actualFiles[0].Filename, "unexpected file name generated")
 			assert.Equal(t, tt.expectedData, actualFiles[0].Data, "unexpected config")
+
+			proxyURL, err := ProxyURL(actualFiles[0].Data)
+			assert.NoError(t, err, "unexpected error reading the proxy URL")
+			if proxyURL != nil {
+				assert.Equal(t, tt.proxy.HTTPSProxy, proxyURL.String(), "unexpected proxy URL")
+			}
 		})
 	}
 
 }
+
+func TestOIDCExecConfig(t *testing.T) {
+	openID := &types.OpenIDIdentityProvider{
+		ClientID:     "openshift",
+		ClientSecret: "THIS IS THE CLIENT SECRET",
+		Issuer:       "https://sso.example.com",
+		CAConfigMap:  "sso-ca",
+		ExtraScopes:  []string{"email", "groups"},
+	}
+	configMaps := []types.ConfigMap{{Name: "sso-ca", Data: map[string]string{"ca.crt": "THIS IS SSO CA CERT DATA"}}}
+
+	exec := oidcExecConfig(openID, configMaps)
+	assert.Equal(t, "kubectl", exec.Command)
+	assert.Equal(t, []string{
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=https://sso.example.com",
+		"--oidc-client-id=openshift",
+		"--oidc-extra-scope=email",
+		"--oidc-extra-scope=groups",
+		"--certificate-authority-data=VEhJUyBJUyBTU08gQ0EgQ0VSVCBEQVRB",
+	}, exec.Args)
+}
+
+func TestReadAdminEncrypted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestReadAdminEncrypted")
+	if err != nil {
+		t.Skipf("could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.Setenv(encryption.PassphraseEnvVar, "passphrase")
+	defer os.Unsetenv(encryption.PassphraseEnvVar)
+
+	data := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://api.test.example.com:6443
+contexts:
+- name: admin
+  context:
+    cluster: test
+    user: admin
+current-context: admin
+users:
+- name: admin
+  user: {}
+`)
+	admin := &Admin{kubeconfig{File: &asset.File{Filename: kubeconfigAdminPath, Data: data}}}
+	if !assert.NoError(t, asset.PersistToFile(admin, dir)) {
+		return
+	}
+
+	raw, err := ioutil.ReadFile(AdminPath(dir))
+	assert.NoError(t, err)
+	assert.True(t, encryption.IsEncrypted(raw), "the kubeconfig is encrypted on disk")
+
+	read, err := ReadAdmin(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(read)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "https://api.test.example.com:6443", config.Host)
+	}
+
+	// Programs which read the kubeconfig themselves, e.g. hooks, get a
+	// plaintext copy.
+	path, cleanup, err := encryption.PlaintextFile(AdminPath(dir))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer cleanup()
+	loaded, err := clientcmd.LoadFromFile(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "admin", loaded.CurrentContext)
+	}
+}
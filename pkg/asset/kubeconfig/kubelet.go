@@ -39,6 +39,7 @@ func (k *Kubelet) Generate(parents asset.Parents) error {
 		rootCA,
 		kubeletCertKey,
 		installConfig.Config,
+		nil, // the kubelet reaches the API without the install's proxy
 		"kubelet",
 		kubeconfigKubeletPath,
 	)
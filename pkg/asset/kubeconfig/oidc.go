@@ -0,0 +1,123 @@
+package kubeconfig
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	clientcmd "k8s.io/client-go/tools/clientcmd/api/v1"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// OIDCIdentityProviderEnvVar names the environment variable holding
+	// the name of the OpenID identity provider in the install config for
+	// which the OIDC kubeconfig is generated.  Without it, there is no
+	// OIDC kubeconfig.
+	OIDCIdentityProviderEnvVar = "OPENSHIFT_INSTALL_OIDC_KUBECONFIG"
+)
+
+var (
+	kubeconfigOIDCPath = filepath.Join("auth", "kubeconfig-oidc")
+)
+
+// OIDC is the asset for a kubeconfig which logs users in with an OpenID
+// identity provider through the kubelogin exec credential plugin ('kubectl
+// oidc-login'), so day-one access does not need the admin client
+// certificate.
+type OIDC struct {
+	kubeconfig
+}
+
+var _ asset.WritableAsset = (*OIDC)(nil)
+
+// Dependencies returns the dependency of the kubeconfig.
+func (k *OIDC) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&tls.RootCA{},
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the kubeconfig.
+func (k *OIDC) Generate(parents asset.Parents) error {
+	rootCA := &tls.RootCA{}
+	installConfig := &installconfig.InstallConfig{}
+	parents.Get(rootCA, installConfig)
+
+	k.Config, k.File = nil, nil
+	name := os.Getenv(OIDCIdentityProviderEnvVar)
+	if name == "" {
+		return nil
+	}
+
+	var openID *types.OpenIDIdentityProvider
+	for _, idp := range installConfig.Config.IdentityProviders {
+		if idp.Name == name {
+			openID = idp.OpenID
+			if openID == nil {
+				return errors.Errorf("identity provider %q is not an OpenID identity provider", name)
+			}
+		}
+	}
+	if openID == nil {
+		return errors.Errorf("no identity provider %q in the install config", name)
+	}
+
+	return k.generateWithAuthInfo(
+		rootCA,
+		clientcmd.AuthInfo{Exec: oidcExecConfig(openID, installConfig.Config.ConfigMaps)},
+		installConfig.Config,
+		installConfig.Config.Proxy,
+		name,
+		kubeconfigOIDCPath,
+	)
+}
+
+// Name returns the human-friendly name of the asset.
+func (k *OIDC) Name() string {
+	return "Kubeconfig OIDC"
+}
+
+// Load returns the kubeconfig from disk.
+func (k *OIDC) Load(f asset.FileFetcher) (found bool, err error) {
+	return k.load(f, kubeconfigOIDCPath)
+}
+
+// oidcExecConfig returns the kubelogin invocation for the identity
+// provider.  The client secret is left out, as the kubeconfig is meant to
+// be handed to users; kubelogin authenticates with PKCE instead.
+func oidcExecConfig(openID *types.OpenIDIdentityProvider, configMaps []types.ConfigMap) *clientcmd.ExecConfig {
+	args := []string{
+		"oidc-login",
+		"get-token",
+		"--oidc-issuer-url=" + openID.Issuer,
+		"--oidc-client-id=" + openID.ClientID,
+	}
+	for _, scope := range openID.ExtraScopes {
+		args = append(args, "--oidc-extra-scope="+scope)
+	}
+
+	ca := openID.CA
+	if openID.CAConfigMap != "" {
+		for _, configMap := range configMaps {
+			if configMap.Name == openID.CAConfigMap {
+				ca = configMap.Data["ca.crt"]
+			}
+		}
+	}
+	if ca != "" {
+		args = append(args, "--certificate-authority-data="+base64.StdEncoding.EncodeToString([]byte(ca)))
+	}
+
+	return &clientcmd.ExecConfig{
+		APIVersion: "client.authentication.k8s.io/v1beta1",
+		Command:    "kubectl",
+		Args:       args,
+	}
+}
@@ -37,7 +37,7 @@ func Machines(config *types.InstallConfig, pool *types.MachinePool, role, userDa
 	var machines []clusterapi.Machine
 	for idx := int64(0); idx < total; idx++ {
 		azIndex := int(idx) % len(azs)
-		provider, err := provider(config.ClusterID, clustername, platform, mpool, azIndex, role, userDataSecret)
+		provider, err := provider(config.ClusterID, clustername, config.InfrastructureName(), platform, mpool, azIndex, role, userDataSecret)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create provider")
 		}
@@ -69,12 +69,21 @@ func Machines(config *types.InstallConfig, pool *types.MachinePool, role, userDa
 	return machines, nil
 }
 
-func provider(clusterID, clusterName string, platform *aws.Platform, mpool *aws.MachinePool, azIdx int, role, userDataSecret string) (*awsprovider.AWSMachineProviderConfig, error) {
+func provider(clusterID, clusterName, infraID string, platform *aws.Platform, mpool *aws.MachinePool, azIdx int, role, userDataSecret string) (*awsprovider.AWSMachineProviderConfig, error) {
 	az := mpool.Zones[azIdx]
-	tags, err := tagsFromUserTags(clusterID, clusterName, platform.UserTags)
+	tags, err := tagsFromUserTags(clusterID, clusterName, platform)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create awsprovider.TagSpecifications from UserTags")
 	}
+	securityGroups := []awsprovider.AWSResourceReference{{
+		Filters: []awsprovider.Filter{{
+			Name:   "tag:Name",
+			Values: []string{fmt.Sprintf("%s_%s_sg", infraID, role)},
+		}},
+	}}
+	for _, id := range mpool.AdditionalSecurityGroupIDs {
+		securityGroups = append(securityGroups, awsprovider.AWSResourceReference{ID: pointer.StringPtr(id)})
+	}
 	return &awsprovider.AWSMachineProviderConfig{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "awsproviderconfig.k8s.io/v1alpha1",
@@ -83,35 +92,30 @@ func provider(clusterID, clusterName string, platform *aws.Platform, mpool *aws.
 		InstanceType:       mpool.InstanceType,
 		AMI:                awsprovider.AWSResourceReference{ID: &mpool.AMIID},
 		Tags:               tags,
-		IAMInstanceProfile: &awsprovider.AWSResourceReference{ID: pointer.StringPtr(fmt.Sprintf("%s-%s-profile", clusterName, role))},
+		IAMInstanceProfile: &awsprovider.AWSResourceReference{ID: pointer.StringPtr(fmt.Sprintf("%s-%s-profile", infraID, role))},
 		UserDataSecret:     &corev1.LocalObjectReference{Name: userDataSecret},
 		Subnet: awsprovider.AWSResourceReference{
 			Filters: []awsprovider.Filter{{
 				Name:   "tag:Name",
-				Values: []string{fmt.Sprintf("%s-%s-%s", clusterName, role, az)},
+				Values: []string{fmt.Sprintf("%s-%s-%s", infraID, role, az)},
 			}},
 		},
-		Placement: awsprovider.Placement{Region: platform.Region, AvailabilityZone: az},
-		SecurityGroups: []awsprovider.AWSResourceReference{{
-			Filters: []awsprovider.Filter{{
-				Name:   "tag:Name",
-				Values: []string{fmt.Sprintf("%s_%s_sg", clusterName, role)},
-			}},
-		}},
+		Placement:      awsprovider.Placement{Region: platform.Region, AvailabilityZone: az},
+		SecurityGroups: securityGroups,
 	}, nil
 }
 
-func tagsFromUserTags(clusterID, clusterName string, usertags map[string]string) ([]awsprovider.TagSpecification, error) {
+func tagsFromUserTags(clusterID, clusterName string, platform *aws.Platform) ([]awsprovider.TagSpecification, error) {
 	tags := []awsprovider.TagSpecification{
-		{Name: "tectonicClusterID", Value: clusterID},
-		{Name: "openshiftClusterID", Value: clusterID},
+		{Name: platform.TagKey("tectonicClusterID"), Value: clusterID},
+		{Name: platform.TagKey("openshiftClusterID"), Value: clusterID},
 		{Name: fmt.Sprintf("kubernetes.io/cluster/%s", clusterName), Value: "owned"},
 	}
 	forbiddenTags := sets.NewString()
 	for idx := range tags {
 		forbiddenTags.Insert(tags[idx].Name)
 	}
-	for k, v := range usertags {
+	for k, v := range platform.UserTags {
 		if forbiddenTags.Has(k) {
 			return nil, fmt.Errorf("user tags may not clobber %s", k)
 		}
@@ -120,20 +124,30 @@ func tagsFromUserTags(clusterID, clusterName string, usertags map[string]string)
 	return tags, nil
 }
 
-// ConfigMasters sets the PublicIP flag and assigns a set of load balancers to the given machines
-func ConfigMasters(machines []clusterapi.Machine, clusterName string) {
+// ConfigMasters sets the PublicIP flag and assigns the API load balancers
+// configured by lb, which may be nil for the defaults, to the given machines
+func ConfigMasters(machines []clusterapi.Machine, infraID string, lb *aws.APILoadBalancer) {
+	lbType := awsprovider.NetworkLoadBalancerType
+	internal := false
+	if lb != nil {
+		if lb.Type == aws.ClassicLoadBalancer {
+			lbType = awsprovider.ClassicLoadBalancerType
+		}
+		internal = lb.Internal
+	}
 	for _, machine := range machines {
 		providerConfig := machine.Spec.ProviderConfig.Value.Object.(*awsprovider.AWSMachineProviderConfig)
 		providerConfig.PublicIP = pointer.BoolPtr(true)
-		providerConfig.LoadBalancers = []awsprovider.LoadBalancerReference{
-			{
-				Name: fmt.Sprintf("%s-ext", clusterName),
-				Type: awsprovider.NetworkLoadBalancerType,
-			},
-			{
-				Name: fmt.Sprintf("%s-int", clusterName),
-				Type: awsprovider.NetworkLoadBalancerType,
-			},
+		providerConfig.LoadBalancers = nil
+		if !internal {
+			providerConfig.LoadBalancers = append(providerConfig.LoadBalancers, awsprovider.LoadBalancerReference{
+				Name: fmt.Sprintf("%s-ext", infraID),
+				Type: lbType,
+			})
 		}
+		providerConfig.LoadBalancers = append(providerConfig.LoadBalancers, awsprovider.LoadBalancerReference{
+			Name: fmt.Sprintf("%s-int", infraID),
+			Type: lbType,
+		})
 	}
 }
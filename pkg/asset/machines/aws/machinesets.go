@@ -38,7 +38,7 @@ func MachineSets(config *types.InstallConfig, pool *types.MachinePool, role, use
 			replicas++
 		}
 
-		provider, err := provider(config.ClusterID, clustername, platform, mpool, idx, role, userDataSecret)
+		provider, err := provider(config.ClusterID, clustername, config.InfrastructureName(), platform, mpool, idx, role, userDataSecret)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create provider")
 		}
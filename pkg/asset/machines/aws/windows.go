@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+)
+
+// windowsImageName matches the names of the Windows Server images, with
+// container support, which Windows pools boot.
+const windowsImageName = "Windows_Server-2019-English-Full-ContainersLatest-*"
+
+// WindowsAMI retrieves the ID of the latest Windows Server AMI published
+// by Amazon in the platform's region.
+func WindowsAMI(platform *awstypes.Platform) (string, error) {
+	ssn, err := awsconfig.NewSession(platform)
+	if err != nil {
+		return "", err
+	}
+	resp, err := ec2.New(ssn).DescribeImages(&ec2.DescribeImagesInput{
+		Owners: []*string{aws.String("amazon")},
+		Filters: []*ec2.Filter{
+			{Name: aws.String("name"), Values: []*string{aws.String(windowsImageName)}},
+			{Name: aws.String("architecture"), Values: []*string{aws.String("x86_64")}},
+			{Name: aws.String("state"), Values: []*string{aws.String("available")}},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot fetch Windows AMIs: %v", err)
+	}
+	var latest *ec2.Image
+	for _, image := range resp.Images {
+		// Creation dates are ISO 8601 timestamps, which sort as strings.
+		if latest == nil || aws.StringValue(image.CreationDate) > aws.StringValue(latest.CreationDate) {
+			latest = image
+		}
+	}
+	if latest == nil {
+		return "", fmt.Errorf("no Windows AMIs found in %s", platform.Region)
+	}
+	return aws.StringValue(latest.ImageId), nil
+}
@@ -4,30 +4,26 @@ import (
 	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
+
+	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
 )
 
-// AvailabilityZones retrieves a list of availability zones for the given region.
-func AvailabilityZones(region string) ([]string, error) {
-	ec2Client := ec2Client(region)
-	zones, err := fetchAvailabilityZones(ec2Client, region)
+// AvailabilityZones retrieves a list of availability zones for the
+// platform's region.
+func AvailabilityZones(platform *awstypes.Platform) ([]string, error) {
+	ssn, err := awsconfig.NewSession(platform)
+	if err != nil {
+		return nil, err
+	}
+	zones, err := fetchAvailabilityZones(ec2.New(ssn), platform.Region)
 	if err != nil {
 		return nil, fmt.Errorf("cannot fetch availability zones: %v", err)
 	}
 	return zones, nil
 }
 
-func ec2Client(region string) *ec2.EC2 {
-	ssn := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-		Config: aws.Config{
-			Region: aws.String(region),
-		},
-	}))
-	return ec2.New(ssn)
-}
-
 func fetchAvailabilityZones(client *ec2.EC2, region string) ([]string, error) {
 	zoneFilter := &ec2.Filter{
 		Name:   aws.String("region-name"),
@@ -0,0 +1,76 @@
+package machines
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// defaultUnhealthyConditions are used when a pool's health check does not
+// list any.
+var defaultUnhealthyConditions = []types.UnhealthyCondition{
+	{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Timeout: "300s"},
+	{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Timeout: "300s"},
+}
+
+// machineHealthCheck mirrors healthchecking.openshift.io/v1alpha1
+// MachineHealthCheck. Its CRD is created by the machine API operator, so
+// it is rendered with the openshift manifests, which are retried until the
+// CRD exists.
+type machineHealthCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec machineHealthCheckSpec `json:"spec"`
+}
+
+type machineHealthCheckSpec struct {
+	Selector            metav1.LabelSelector       `json:"selector"`
+	UnhealthyConditions []types.UnhealthyCondition `json:"unhealthyConditions"`
+	MaxUnhealthy        *intstr.IntOrString        `json:"maxUnhealthy,omitempty"`
+}
+
+// machineHealthCheckRaw returns the MachineHealthCheck for the machines of
+// pool's machine sets, or nil if the pool has no health check.
+func machineHealthCheckRaw(clusterName string, pool *types.MachinePool) ([]byte, error) {
+	if pool.HealthCheck == nil {
+		return nil, nil
+	}
+
+	conditions := pool.HealthCheck.UnhealthyConditions
+	if len(conditions) == 0 {
+		conditions = defaultUnhealthyConditions
+	}
+	check := &machineHealthCheck{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "healthchecking.openshift.io/v1alpha1",
+			Kind:       "MachineHealthCheck",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "openshift-cluster-api",
+			Name:      fmt.Sprintf("%s-%s", clusterName, pool.Name),
+		},
+		Spec: machineHealthCheckSpec{
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"sigs.k8s.io/cluster-api-cluster":      clusterName,
+					"sigs.k8s.io/cluster-api-machine-role": pool.Name,
+				},
+			},
+			UnhealthyConditions: conditions,
+			MaxUnhealthy:        pool.HealthCheck.MaxUnhealthy,
+		},
+	}
+
+	raw, err := json.Marshal(check)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the %s machine health check", pool.Name)
+	}
+	return raw, nil
+}
@@ -1,9 +1,7 @@
 package machines
 
 import (
-	"context"
 	"fmt"
-	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
@@ -14,10 +12,6 @@ import (
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
 	"github.com/openshift/installer/pkg/asset/installconfig"
-	"github.com/openshift/installer/pkg/asset/machines/aws"
-	"github.com/openshift/installer/pkg/asset/machines/libvirt"
-	"github.com/openshift/installer/pkg/asset/machines/openstack"
-	"github.com/openshift/installer/pkg/rhcos"
 	"github.com/openshift/installer/pkg/types"
 )
 
@@ -58,82 +52,19 @@ func (m *Master) Generate(dependencies asset.Parents) error {
 
 	ic := installconfig.Config
 	pool := masterPool(ic.Machines)
-	switch ic.Platform.Name() {
-	case "aws":
-		mpool := defaultAWSMachinePoolPlatform()
-		mpool.Set(ic.Platform.AWS.DefaultMachinePlatform)
-		mpool.Set(pool.Platform.AWS)
-		if mpool.AMIID == "" {
-			ctx, cancel := context.WithTimeout(context.TODO(), 60*time.Second)
-			defer cancel()
-			ami, err := rhcos.AMI(ctx, rhcos.DefaultChannel, ic.Platform.AWS.Region)
-			if err != nil {
-				return errors.Wrap(err, "failed to determine default AMI")
-			}
-			mpool.AMIID = ami
-		}
-		if len(mpool.Zones) == 0 {
-			azs, err := aws.AvailabilityZones(ic.Platform.AWS.Region)
-			if err != nil {
-				return errors.Wrap(err, "failed to fetch availability zones")
-			}
-			mpool.Zones = azs
-		}
-		pool.Platform.AWS = &mpool
-		machines, err := aws.Machines(ic, &pool, "master", "master-user-data")
-		if err != nil {
-			return errors.Wrap(err, "failed to create master machine objects")
-		}
-		aws.ConfigMasters(machines, ic.ObjectMeta.Name)
-
-		list := listFromMachines(machines)
-		raw, err := yaml.Marshal(list)
-		if err != nil {
-			return errors.Wrap(err, "failed to marshal")
-		}
-		m.MachinesRaw = raw
-	case "libvirt":
-		machines, err := libvirt.Machines(ic, &pool, "master", "master-user-data")
-		if err != nil {
-			return errors.Wrap(err, "failed to create master machine objects")
-		}
-
-		list := listFromMachines(machines)
-		raw, err := yaml.Marshal(list)
-		if err != nil {
-			return errors.Wrap(err, "failed to marshal")
-		}
-		m.MachinesRaw = raw
-	case "openstack":
-		numOfMasters := int64(0)
-		if pool.Replicas != nil {
-			numOfMasters = *pool.Replicas
-		}
-		instances := []string{}
-		for i := 0; i < int(numOfMasters); i++ {
-			instances = append(instances, fmt.Sprintf("master-%d", i))
-		}
-		config := openstack.MasterConfig{
-			ClusterName: ic.ObjectMeta.Name,
-			Instances:   instances,
-			Image:       ic.Platform.OpenStack.BaseImage,
-			Region:      ic.Platform.OpenStack.Region,
-			Machine:     defaultOpenStackMachinePoolPlatform(),
-		}
-
-		tags := map[string]string{
-			"tectonicClusterID":  ic.ClusterID,
-			"openshiftClusterID": ic.ClusterID,
-		}
-		config.Tags = tags
-
-		config.Machine.Set(ic.Platform.OpenStack.DefaultMachinePlatform)
-		config.Machine.Set(pool.Platform.OpenStack)
-
-		m.MachinesRaw = applyTemplateData(openstack.MasterMachinesTmpl, config)
-	default:
+	p, ok := providers[ic.Platform.Name()]
+	if !ok {
 		return fmt.Errorf("invalid Platform")
 	}
+	machines, err := p.Machines(ic, &pool, "master", "master-user-data")
+	if err != nil {
+		return errors.Wrap(err, "failed to create master machine objects")
+	}
+
+	m.MachinesRaw, err = yaml.Marshal(listFromMachines(machines))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal")
+	}
 	return nil
 }
 
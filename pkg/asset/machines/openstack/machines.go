@@ -0,0 +1,157 @@
+// Package openstack generates Machine objects for openstack.
+package openstack
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterapi "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/openstack"
+)
+
+// providerConfig mirrors the OpenStackMachineProviderConfig of the
+// OpenStack actuator, which is not vendored.
+type providerConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Image            resourceID                   `json:"image"`
+	Flavor           string                       `json:"flavor"`
+	AvailabilityZone string                       `json:"availabilityZone,omitempty"`
+	RootVolume       *rootVolume                  `json:"rootVolume,omitempty"`
+	Placement        placement                    `json:"placement"`
+	Subnet           resourceReference            `json:"subnet"`
+	Tags             []tag                        `json:"tags"`
+	SecurityGroups   []resourceReference          `json:"securityGroups"`
+	UserDataSecret   *corev1.LocalObjectReference `json:"userDataSecret"`
+}
+
+type resourceID struct {
+	ID string `json:"id"`
+}
+
+type rootVolume struct {
+	DiskSize   int    `json:"diskSize"`
+	VolumeType string `json:"volumeType,omitempty"`
+}
+
+type placement struct {
+	Region string `json:"region"`
+}
+
+type resourceReference struct {
+	Filters []filter `json:"filters"`
+}
+
+type filter struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type tag struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Machines returns a list of machines for a machinepool.
+func Machines(config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]clusterapi.Machine, error) {
+	if configPlatform := config.Platform.Name(); configPlatform != openstack.Name {
+		return nil, fmt.Errorf("non-OpenStack configuration: %q", configPlatform)
+	}
+	if poolPlatform := pool.Platform.Name(); poolPlatform != openstack.Name {
+		return nil, fmt.Errorf("non-OpenStack machine-pool: %q", poolPlatform)
+	}
+	clustername := config.ObjectMeta.Name
+	platform := config.Platform.OpenStack
+	mpool := pool.Platform.OpenStack
+	azs := mpool.Zones
+
+	total := int64(1)
+	if pool.Replicas != nil {
+		total = *pool.Replicas
+	}
+	var machines []clusterapi.Machine
+	for idx := int64(0); idx < total; idx++ {
+		// An empty availability zone lets Nova pick.
+		az := ""
+		if len(azs) > 0 {
+			az = azs[int(idx)%len(azs)]
+		}
+		provider, err := provider(config.ClusterID, clustername, platform, mpool, az, role, userDataSecret)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create provider")
+		}
+		machine := clusterapi.Machine{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "cluster.k8s.io/v1alpha1",
+				Kind:       "Machine",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "openshift-cluster-api",
+				Name:      fmt.Sprintf("%s-%s-%d", clustername, pool.Name, idx),
+				Labels: map[string]string{
+					"sigs.k8s.io/cluster-api-cluster":      clustername,
+					"sigs.k8s.io/cluster-api-machine-role": role,
+					"sigs.k8s.io/cluster-api-machine-type": role,
+				},
+			},
+			Spec: clusterapi.MachineSpec{
+				ProviderConfig: clusterapi.ProviderConfig{
+					Value: provider,
+				},
+				// we don't need to set Versions, because we control those via operators.
+			},
+		}
+
+		machines = append(machines, machine)
+	}
+
+	return machines, nil
+}
+
+func provider(clusterID, clusterName string, platform *openstack.Platform, mpool *openstack.MachinePool, az, role, userDataSecret string) (*runtime.RawExtension, error) {
+	config := &providerConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "openstack.cluster.k8s.io/v1alpha1",
+			Kind:       "OpenStackMachineProviderConfig",
+		},
+		Image:            resourceID{ID: platform.BaseImage},
+		Flavor:           mpool.FlavorName,
+		AvailabilityZone: az,
+		Placement:        placement{Region: platform.Region},
+		Subnet: resourceReference{
+			Filters: []filter{{
+				Name:   "tag:Name",
+				Values: []string{fmt.Sprintf("%s-%s-*", clusterName, role)},
+			}},
+		},
+		Tags: []tag{
+			{Name: "openshiftClusterID", Value: clusterID},
+			{Name: "tectonicClusterID", Value: clusterID},
+		},
+		SecurityGroups: []resourceReference{{
+			Filters: []filter{{
+				Name:   "tag:Name",
+				Values: []string{fmt.Sprintf("%s_%s_sg", clusterName, role)},
+			}},
+		}},
+		UserDataSecret: &corev1.LocalObjectReference{Name: userDataSecret},
+	}
+	if mpool.RootVolume != nil {
+		config.RootVolume = &rootVolume{
+			DiskSize:   mpool.RootVolume.Size,
+			VolumeType: mpool.RootVolume.Type,
+		}
+	}
+
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: raw}, nil
+}
@@ -0,0 +1,146 @@
+package machines
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	clusterapi "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+
+	"github.com/openshift/installer/pkg/asset/machines/aws"
+	"github.com/openshift/installer/pkg/asset/machines/libvirt"
+	"github.com/openshift/installer/pkg/asset/machines/openstack"
+	"github.com/openshift/installer/pkg/rhcos"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	libvirttypes "github.com/openshift/installer/pkg/types/libvirt"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+)
+
+// provider generates the cluster-API objects for the machines of a
+// platform. Each fills in the platform defaults of the pool it is given.
+type provider interface {
+	// Machines returns the machines of the master pool.
+	Machines(config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]clusterapi.Machine, error)
+
+	// MachineSets returns the machinesets of a compute pool.
+	MachineSets(config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]clusterapi.MachineSet, error)
+}
+
+// providers maps platform names to their providers.
+var providers = map[string]provider{
+	awstypes.Name:       &awsProvider{},
+	libvirttypes.Name:   &libvirtProvider{},
+	openstacktypes.Name: &openstackProvider{},
+}
+
+func defaultAWSMachinePoolPlatform(arch types.Architecture) awstypes.MachinePool {
+	if arch == types.ArchitectureARM64 {
+		return awstypes.MachinePool{
+			InstanceType: "m6g.large",
+		}
+	}
+	return awstypes.MachinePool{
+		InstanceType: "t3.medium",
+	}
+}
+
+func defaultOpenStackMachinePoolPlatform() openstacktypes.MachinePool {
+	return openstacktypes.MachinePool{
+		FlavorName: "m1.medium",
+	}
+}
+
+type awsProvider struct{}
+
+func (p *awsProvider) Machines(config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]clusterapi.Machine, error) {
+	if err := p.setDefaults(config, pool); err != nil {
+		return nil, err
+	}
+	machines, err := aws.Machines(config, pool, role, userDataSecret)
+	if err != nil {
+		return nil, err
+	}
+	aws.ConfigMasters(machines, config.InfrastructureName(), config.Platform.AWS.APILoadBalancer)
+	return machines, nil
+}
+
+func (p *awsProvider) MachineSets(config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]clusterapi.MachineSet, error) {
+	if err := p.setDefaults(config, pool); err != nil {
+		return nil, err
+	}
+	return aws.MachineSets(config, pool, role, userDataSecret)
+}
+
+// setDefaults merges the default machine platform into the pool, and looks
+// up the RHCOS AMI of the pool's architecture, or the Windows AMI, and the
+// availability zones if the pool has none. arm64 pools do not take the
+// default machine platform's AMI and instance type, which are the master
+// pool's, and so amd64; nor do Windows pools take its RHCOS AMI.
+func (p *awsProvider) setDefaults(config *types.InstallConfig, pool *types.MachinePool) error {
+	mpool := defaultAWSMachinePoolPlatform(pool.Architecture)
+	mpool.Set(config.Platform.AWS.DefaultMachinePlatform)
+	if pool.Architecture == types.ArchitectureARM64 {
+		mpool.AMIID = ""
+		mpool.InstanceType = defaultAWSMachinePoolPlatform(pool.Architecture).InstanceType
+	}
+	if pool.OperatingSystem == types.OperatingSystemWindows {
+		mpool.AMIID = ""
+	}
+	mpool.Set(pool.Platform.AWS)
+	if mpool.AMIID == "" && pool.OperatingSystem == types.OperatingSystemWindows {
+		ami, err := aws.WindowsAMI(config.Platform.AWS)
+		if err != nil {
+			return errors.Wrap(err, "failed to determine the Windows AMI")
+		}
+		mpool.AMIID = ami
+	}
+	if mpool.AMIID == "" {
+		ctx, cancel := context.WithTimeout(context.TODO(), 60*time.Second)
+		ami, err := rhcos.AMI(ctx, rhcos.ArchitectureChannel(rhcos.DefaultChannel, string(pool.Architecture)), config.Platform.AWS.Region)
+		cancel()
+		if err != nil {
+			return errors.Wrap(err, "failed to determine default AMI")
+		}
+		mpool.AMIID = ami
+	}
+	if len(mpool.Zones) == 0 {
+		azs, err := aws.AvailabilityZones(config.Platform.AWS)
+		if err != nil {
+			return errors.Wrap(err, "failed to fetch availability zones")
+		}
+		mpool.Zones = azs
+	}
+	pool.Platform.AWS = &mpool
+	return nil
+}
+
+type libvirtProvider struct{}
+
+func (p *libvirtProvider) Machines(config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]clusterapi.Machine, error) {
+	return libvirt.Machines(config, pool, role, userDataSecret)
+}
+
+func (p *libvirtProvider) MachineSets(config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]clusterapi.MachineSet, error) {
+	return libvirt.MachineSets(config, pool, role, userDataSecret)
+}
+
+type openstackProvider struct{}
+
+func (p *openstackProvider) Machines(config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]clusterapi.Machine, error) {
+	p.setDefaults(config, pool)
+	return openstack.Machines(config, pool, role, userDataSecret)
+}
+
+func (p *openstackProvider) MachineSets(config *types.InstallConfig, pool *types.MachinePool, role, userDataSecret string) ([]clusterapi.MachineSet, error) {
+	p.setDefaults(config, pool)
+	return openstack.MachineSets(config, pool, role, userDataSecret)
+}
+
+// setDefaults merges the default machine platform into the pool.
+func (p *openstackProvider) setDefaults(config *types.InstallConfig, pool *types.MachinePool) {
+	mpool := defaultOpenStackMachinePoolPlatform()
+	mpool.Set(config.Platform.OpenStack.DefaultMachinePlatform)
+	mpool.Set(pool.Platform.OpenStack)
+	pool.Platform.OpenStack = &mpool
+}
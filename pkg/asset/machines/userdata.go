@@ -3,9 +3,15 @@ package machines
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
 	"text/template"
 
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
 	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/types"
 )
 
 var userDataListTmpl = template.Must(template.New("user-data-list").Parse(`
@@ -27,6 +33,40 @@ items:
 {{- end}}
 `))
 
+// windowsUserDataTmpl configures a Windows machine the way the Windows
+// Machine Config Operator expects: reachable over SSH, as Administrator,
+// with the install config's keys.
+var windowsUserDataTmpl = template.Must(template.New("windows-user-data").Parse(`<powershell>
+Add-WindowsCapability -Online -Name OpenSSH.Server~~~~0.0.1.0
+$authorizedKeys = "$env:ProgramData\ssh\administrators_authorized_keys"
+New-Item -Path (Split-Path -Path $authorizedKeys) -ItemType Directory -Force
+Set-Content -Path $authorizedKeys -Value @'
+{{- range .}}
+{{.}}
+{{- end}}
+'@
+$acl = Get-Acl $authorizedKeys
+$acl.SetAccessRuleProtection($true, $false)
+$acl.SetAccessRule((New-Object System.Security.AccessControl.FileSystemAccessRule("Administrators", "FullControl", "Allow")))
+$acl.SetAccessRule((New-Object System.Security.AccessControl.FileSystemAccessRule("SYSTEM", "FullControl", "Allow")))
+$acl | Set-Acl
+Set-Service -Name sshd -StartupType Automatic
+Start-Service sshd
+New-NetFirewallRule -DisplayName "OpenSSH Server (sshd)" -Direction Inbound -Protocol TCP -LocalPort 22 -Action Allow
+</powershell>
+<persist>true</persist>
+`))
+
+// windowsUserData returns the user data of Windows machines, authorizing
+// sshKeys.
+func windowsUserData(sshKeys types.SSHKeys) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := windowsUserDataTmpl.Execute(buf, sshKeys); err != nil {
+		return nil, errors.Wrap(err, "failed to execute the Windows user data template")
+	}
+	return buf.Bytes(), nil
+}
+
 func userDataList(data map[string][]byte) ([]byte, error) {
 	encodedData := map[string]string{}
 	for name, content := range data {
@@ -38,3 +78,24 @@ func userDataList(data map[string][]byte) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// poolPointerConfig returns the worker pointer Ignition config with its
+// source changed to the named pool's config on the machine config server.
+func poolPointerConfig(worker *igntypes.Config, pool string) ([]byte, error) {
+	config := *worker
+	config.Ignition.Config.Append = make([]igntypes.ConfigReference, 0, len(worker.Ignition.Config.Append))
+	for _, ref := range worker.Ignition.Config.Append {
+		source, err := url.Parse(ref.Source)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse the worker Ignition config source")
+		}
+		source.Path = fmt.Sprintf("/config/%s", pool)
+		ref.Source = source.String()
+		config.Ignition.Config.Append = append(config.Ignition.Config.Append, ref)
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the %s Ignition config", pool)
+	}
+	return data, nil
+}
@@ -1,14 +1,11 @@
 package machines
 
 import (
-	"bytes"
-	"context"
 	"fmt"
-	"text/template"
-	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clusterapi "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
@@ -16,31 +13,17 @@ import (
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/ignition/machine"
 	"github.com/openshift/installer/pkg/asset/installconfig"
-	"github.com/openshift/installer/pkg/asset/machines/aws"
-	"github.com/openshift/installer/pkg/asset/machines/libvirt"
-	"github.com/openshift/installer/pkg/asset/machines/openstack"
-	"github.com/openshift/installer/pkg/rhcos"
 	"github.com/openshift/installer/pkg/types"
-	awstypes "github.com/openshift/installer/pkg/types/aws"
-	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
 )
 
-func defaultAWSMachinePoolPlatform() awstypes.MachinePool {
-	return awstypes.MachinePool{
-		InstanceType: "t3.medium",
-	}
-}
-
-func defaultOpenStackMachinePoolPlatform() openstacktypes.MachinePool {
-	return openstacktypes.MachinePool{
-		FlavorName: "m1.medium",
-	}
-}
-
-// Worker generates the machinesets for `worker` machine pool.
+// Worker generates the machinesets for the compute machine pools: the
+// `worker` pool and any additional named pools (e.g. `infra`).
 type Worker struct {
 	MachineSetRaw     []byte
 	UserDataSecretRaw []byte
+
+	// MachineHealthCheckRaw is nil unless a pool has a health check.
+	MachineHealthCheckRaw []byte
 }
 
 var _ asset.Asset = (*Worker)(nil)
@@ -65,85 +48,138 @@ func (w *Worker) Generate(dependencies asset.Parents) error {
 	wign := &machine.Worker{}
 	dependencies.Get(installconfig, wign)
 
-	var err error
-	userDataMap := map[string][]byte{"worker-user-data": wign.File.Data}
-	w.UserDataSecretRaw, err = userDataList(userDataMap)
-	if err != nil {
-		return errors.Wrap(err, "failed to create user-data secret for worker machines")
-	}
-
 	ic := installconfig.Config
-	pool := workerPool(ic.Machines)
-	switch ic.Platform.Name() {
-	case "aws":
-		mpool := defaultAWSMachinePoolPlatform()
-		mpool.Set(ic.Platform.AWS.DefaultMachinePlatform)
-		mpool.Set(pool.Platform.AWS)
-		if mpool.AMIID == "" {
-			ctx, cancel := context.WithTimeout(context.TODO(), 60*time.Second)
-			defer cancel()
-			ami, err := rhcos.AMI(ctx, rhcos.DefaultChannel, ic.Platform.AWS.Region)
+	p, ok := providers[ic.Platform.Name()]
+	if !ok {
+		return fmt.Errorf("invalid Platform")
+	}
+	pools := ComputePools(ic.Machines)
+	userDataMap := map[string][]byte{}
+	var sets []clusterapi.MachineSet
+	healthChecks := &metav1.List{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "List",
+		},
+	}
+	for idx := range pools {
+		pool := &pools[idx]
+		userDataSecret := fmt.Sprintf("%s-user-data", pool.Name)
+		userData := wign.File.Data
+		if pool.OperatingSystem == types.OperatingSystemWindows {
+			var err error
+			userData, err = windowsUserData(ic.SSHKey)
 			if err != nil {
-				return errors.Wrap(err, "failed to determine default AMI")
+				return err
 			}
-			mpool.AMIID = ami
-		}
-		if len(mpool.Zones) == 0 {
-			azs, err := aws.AvailabilityZones(ic.Platform.AWS.Region)
+		} else if pool.Name != "worker" {
+			var err error
+			userData, err = poolPointerConfig(wign.Config, pool.Name)
 			if err != nil {
-				return errors.Wrap(err, "failed to fetch availability zones")
+				return err
 			}
-			mpool.Zones = azs
-		}
-		pool.Platform.AWS = &mpool
-		sets, err := aws.MachineSets(ic, &pool, "worker", "worker-user-data")
-		if err != nil {
-			return errors.Wrap(err, "failed to create worker machine objects")
 		}
+		userDataMap[userDataSecret] = userData
 
-		list := listFromMachineSets(sets)
-		raw, err := yaml.Marshal(list)
+		check, err := machineHealthCheckRaw(ic.ObjectMeta.Name, pool)
 		if err != nil {
-			return errors.Wrap(err, "failed to marshal")
+			return err
 		}
-		w.MachineSetRaw = raw
-	case "libvirt":
-		sets, err := libvirt.MachineSets(ic, &pool, "worker", "worker-user-data")
-		if err != nil {
-			return errors.Wrap(err, "failed to create worker machine objects")
+		if check != nil {
+			healthChecks.Items = append(healthChecks.Items, runtime.RawExtension{Raw: check})
 		}
 
-		list := listFromMachineSets(sets)
-		raw, err := yaml.Marshal(list)
+		// Named pools use the worker instance profile, security group
+		// and subnets.
+		poolSets, err := p.MachineSets(ic, pool, "worker", userDataSecret)
 		if err != nil {
-			return errors.Wrap(err, "failed to marshal")
+			return errors.Wrapf(err, "failed to create %s machine objects", pool.Name)
 		}
-		w.MachineSetRaw = raw
-	case "openstack":
-		numOfWorkers := int64(0)
-		if pool.Replicas != nil {
-			numOfWorkers = *pool.Replicas
+		sets = append(sets, withPoolRole(poolSets, pool)...)
+	}
+
+	var err error
+	w.UserDataSecretRaw, err = userDataList(userDataMap)
+	if err != nil {
+		return errors.Wrap(err, "failed to create user-data secret for worker machines")
+	}
+
+	w.MachineSetRaw, err = yaml.Marshal(listFromMachineSets(sets))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal")
+	}
+
+	w.MachineHealthCheckRaw = nil
+	if len(healthChecks.Items) > 0 {
+		w.MachineHealthCheckRaw, err = yaml.Marshal(healthChecks)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the machine health checks")
 		}
-		config := openstack.Config{
-			ClusterName: ic.ObjectMeta.Name,
-			Replicas:    numOfWorkers,
-			Image:       ic.Platform.OpenStack.BaseImage,
-			Region:      ic.Platform.OpenStack.Region,
-			Machine:     defaultOpenStackMachinePoolPlatform(),
+	}
+	return nil
+}
+
+// ComputePools returns the worker pool, followed by any additional named
+// compute pools. Every pool other than master is a compute pool.
+func ComputePools(pools []types.MachinePool) []types.MachinePool {
+	worker := workerPool(pools)
+	worker.Name = "worker"
+	compute := []types.MachinePool{worker}
+	for _, pool := range pools {
+		if pool.Name != "master" && pool.Name != "worker" {
+			compute = append(compute, pool)
 		}
+	}
+	return compute
+}
 
-		tags := map[string]string{
-			"tectonicClusterID":  ic.ClusterID,
-			"openshiftClusterID": ic.ClusterID,
+// withPoolRole labels the machine sets of a named pool with the pool's
+// role, and sets the pool's node labels and taints. The machines of Windows
+// pools are labeled machine.openshift.io/os-id=Windows, which the Windows
+// Machine Config Operator watches for.
+func withPoolRole(sets []clusterapi.MachineSet, pool *types.MachinePool) []clusterapi.MachineSet {
+	for idx := range sets {
+		if pool.Name != "worker" {
+			for _, labels := range []map[string]string{sets[idx].Labels, sets[idx].Spec.Template.Labels} {
+				labels["sigs.k8s.io/cluster-api-machine-role"] = pool.Name
+				labels["sigs.k8s.io/cluster-api-machine-type"] = pool.Name
+			}
+		}
+		if pool.OperatingSystem == types.OperatingSystemWindows {
+			sets[idx].Spec.Template.Labels["machine.openshift.io/os-id"] = "Windows"
 		}
-		config.Tags = tags
+		sets[idx].Spec.Template.Spec.Labels = poolNodeLabels(pool)
+		sets[idx].Spec.Template.Spec.Taints = poolTaints(pool)
+	}
+	return sets
+}
 
-		config.Machine.Set(ic.Platform.OpenStack.DefaultMachinePlatform)
-		config.Machine.Set(pool.Platform.OpenStack)
+// poolNodeLabels returns the labels of a compute pool's nodes beyond those
+// the kubelet sets: the node-role.kubernetes.io/<pool> label which the
+// MachineConfigPool of a named pool selects, and accelerator labels.
+func poolNodeLabels(pool *types.MachinePool) map[string]string {
+	labels := map[string]string{}
+	if pool.Name != "worker" {
+		labels[fmt.Sprintf("node-role.kubernetes.io/%s", pool.Name)] = ""
+	}
+	if pool.Accelerator != nil && pool.Accelerator.Type == types.NvidiaGPUAccelerator {
+		labels["nvidia.com/gpu.present"] = "true"
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
 
-		w.MachineSetRaw = applyTemplateData(openstack.WorkerMachineSetTmpl, config)
-	default:
-		return fmt.Errorf("invalid Platform")
+// poolTaints returns the taints of a compute pool's nodes, keeping pods
+// which do not need a pool's accelerators, or which are not Windows
+// workloads, off its nodes.
+func poolTaints(pool *types.MachinePool) []corev1.Taint {
+	if pool.OperatingSystem == types.OperatingSystemWindows {
+		return []corev1.Taint{{Key: "os", Value: "Windows", Effect: corev1.TaintEffectNoSchedule}}
+	}
+	if pool.Accelerator != nil && pool.Accelerator.Type == types.NvidiaGPUAccelerator {
+		return []corev1.Taint{{Key: "nvidia.com/gpu", Effect: corev1.TaintEffectNoSchedule}}
 	}
 	return nil
 }
@@ -157,14 +193,6 @@ func workerPool(pools []types.MachinePool) types.MachinePool {
 	return types.MachinePool{}
 }
 
-func applyTemplateData(template *template.Template, templateData interface{}) []byte {
-	buf := &bytes.Buffer{}
-	if err := template.Execute(buf, templateData); err != nil {
-		panic(err)
-	}
-	return buf.Bytes()
-}
-
 func listFromMachineSets(objs []clusterapi.MachineSet) *metav1.List {
 	list := &metav1.List{
 		TypeMeta: metav1.TypeMeta{
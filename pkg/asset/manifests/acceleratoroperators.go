@@ -0,0 +1,192 @@
+package manifests
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var (
+	acceleratorOperatorFilenameFormat = filepath.Join(openshiftManifestDir, "99_%s_%s.yaml")
+)
+
+// catalogOperator is an operator installed from an OLM catalog.
+type catalogOperator struct {
+	Namespace string
+	Package   string
+	Channel   string
+	Source    string
+}
+
+// acceleratorOperators are the operators which make the accelerators of
+// each type schedulable.
+var acceleratorOperators = map[types.AcceleratorType][]catalogOperator{
+	types.NvidiaGPUAccelerator: {
+		{Namespace: "openshift-nfd", Package: "nfd", Channel: "stable", Source: "redhat-operators"},
+		{Namespace: "nvidia-gpu-operator", Package: "gpu-operator-certified", Channel: "stable", Source: "certified-operators"},
+	},
+}
+
+// operatorGroup mirrors operators.coreos.com/v1 OperatorGroup.
+type operatorGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec operatorGroupSpec `json:"spec"`
+}
+
+type operatorGroupSpec struct {
+	TargetNamespaces []string `json:"targetNamespaces"`
+}
+
+// subscription mirrors operators.coreos.com/v1alpha1 Subscription.
+type subscription struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec subscriptionSpec `json:"spec"`
+}
+
+type subscriptionSpec struct {
+	Package                string `json:"name"`
+	Channel                string `json:"channel"`
+	CatalogSource          string `json:"source"`
+	CatalogSourceNamespace string `json:"sourceNamespace"`
+}
+
+// AcceleratorOperators generates the subscriptions to the operators of
+// the accelerators of compute pools which request them. The subscription
+// CRDs are created by OLM, so they are rendered with the openshift
+// manifests, which are retried until the CRDs exist.
+type AcceleratorOperators struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*AcceleratorOperators)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*AcceleratorOperators) Name() string {
+	return "Accelerator Operators"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*AcceleratorOperators) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the operator namespaces, operator groups and
+// subscriptions.
+func (a *AcceleratorOperators) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	a.FileList = []*asset.File{}
+	generated := map[types.AcceleratorType]bool{}
+	for _, pool := range installConfig.Config.Machines {
+		if pool.Accelerator == nil || !pool.Accelerator.Operators || generated[pool.Accelerator.Type] {
+			continue
+		}
+		generated[pool.Accelerator.Type] = true
+
+		for _, operator := range acceleratorOperators[pool.Accelerator.Type] {
+			resources := []struct {
+				kind   string
+				object interface{}
+			}{
+				{"namespace", &corev1.Namespace{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: "v1",
+						Kind:       "Namespace",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name: operator.Namespace,
+					},
+				}},
+				{"operatorgroup", &operatorGroup{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: "operators.coreos.com/v1",
+						Kind:       "OperatorGroup",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      operator.Package,
+						Namespace: operator.Namespace,
+					},
+					Spec: operatorGroupSpec{
+						TargetNamespaces: []string{operator.Namespace},
+					},
+				}},
+				{"subscription", &subscription{
+					TypeMeta: metav1.TypeMeta{
+						APIVersion: "operators.coreos.com/v1alpha1",
+						Kind:       "Subscription",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      operator.Package,
+						Namespace: operator.Namespace,
+					},
+					Spec: subscriptionSpec{
+						Package:                operator.Package,
+						Channel:                operator.Channel,
+						CatalogSource:          operator.Source,
+						CatalogSourceNamespace: "openshift-marketplace",
+					},
+				}},
+			}
+			// The index orders the files, so the namespace is created
+			// before the resources in it.
+			for idx, resource := range resources {
+				data, err := yaml.Marshal(resource.object)
+				if err != nil {
+					return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", a.Name())
+				}
+				a.FileList = append(a.FileList, &asset.File{
+					Filename: fmt.Sprintf(acceleratorOperatorFilenameFormat, operator.Namespace, fmt.Sprintf("%d-%s", idx, resource.kind)),
+					Data:     data,
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (a *AcceleratorOperators) Files() []*asset.File {
+	return a.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (a *AcceleratorOperators) Load(f asset.FileFetcher) (bool, error) {
+	namespaces := sets.NewString()
+	for _, operators := range acceleratorOperators {
+		for _, operator := range operators {
+			namespaces.Insert(operator.Namespace)
+		}
+	}
+	fileList := []*asset.File{}
+	for _, namespace := range namespaces.List() {
+		files, err := f.FetchByPattern(fmt.Sprintf(acceleratorOperatorFilenameFormat, namespace, "*"))
+		if err != nil {
+			return false, err
+		}
+		fileList = append(fileList, files...)
+	}
+	if len(fileList) == 0 {
+		return false, nil
+	}
+	a.FileList = fileList
+	return true, nil
+}
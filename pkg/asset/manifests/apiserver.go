@@ -0,0 +1,169 @@
+package manifests
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/templates/content"
+	"github.com/openshift/installer/pkg/types"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	apiServerCrdFilename = "cluster-apiserver-01-crd.yaml"
+	apiServerCfgFilename = filepath.Join(manifestDir, "cluster-apiserver-02-config.yml")
+)
+
+// apiServer mirrors config.openshift.io/v1 APIServer, which is not yet part
+// of the vendored API.
+type apiServer struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec apiServerSpec `json:"spec"`
+}
+
+type apiServerSpec struct {
+	Audit        apiServerAudit         `json:"audit"`
+	ServingCerts *apiServerServingCerts `json:"servingCerts,omitempty"`
+}
+
+type apiServerServingCerts struct {
+	NamedCertificates []apiServerNamedCertificate `json:"namedCertificates"`
+}
+
+type apiServerNamedCertificate struct {
+	Names []string `json:"names"`
+
+	// ServingCertificate references a kubernetes.io/tls secret in
+	// configNamespace.
+	ServingCertificate nameReference `json:"servingCertificate"`
+}
+
+type apiServerAudit struct {
+	Profile types.AuditProfile `json:"profile"`
+}
+
+// APIServer generates the cluster-apiserver-*.yml files.
+type APIServer struct {
+	config   *apiServer
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*APIServer)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*APIServer) Name() string {
+	return "API Server Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*APIServer) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the API server config and its CRD.
+func (a *APIServer) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	audit := types.Audit{}
+	if installConfig.Config.APIServer != nil {
+		audit = installConfig.Config.APIServer.Audit
+	}
+	if audit.Profile == "" {
+		audit.Profile = types.DefaultAuditProfile
+	}
+	var servingCerts *apiServerServingCerts
+	if installConfig.Config.APIServer != nil && len(installConfig.Config.APIServer.NamedCertificates) > 0 {
+		servingCerts = &apiServerServingCerts{}
+		for _, cert := range installConfig.Config.APIServer.NamedCertificates {
+			servingCerts.NamedCertificates = append(servingCerts.NamedCertificates, apiServerNamedCertificate{
+				Names:              cert.Names,
+				ServingCertificate: nameReference{Name: cert.Secret},
+			})
+		}
+	}
+
+	a.config = &apiServer{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "APIServer",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+		Spec: apiServerSpec{
+			Audit: apiServerAudit{
+				Profile: audit.Profile,
+			},
+			ServingCerts: servingCerts,
+		},
+	}
+
+	crdData, err := content.GetBootkubeTemplate(apiServerCrdFilename)
+	if err != nil {
+		return err
+	}
+
+	a.FileList = []*asset.File{
+		{
+			Filename: filepath.Join(manifestDir, apiServerCrdFilename),
+			Data:     []byte(crdData),
+		},
+	}
+
+	configData, err := yaml.Marshal(a.config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", a.Name())
+	}
+	a.FileList = append(a.FileList, &asset.File{
+		Filename: apiServerCfgFilename,
+		Data:     configData,
+	})
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (a *APIServer) Files() []*asset.File {
+	return a.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (a *APIServer) Load(f asset.FileFetcher) (bool, error) {
+	crdFile, err := f.FetchByName(filepath.Join(manifestDir, apiServerCrdFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	cfgFile, err := f.FetchByName(apiServerCfgFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	config := &apiServer{}
+	if err := yaml.Unmarshal(cfgFile.Data, config); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s", apiServerCfgFilename)
+	}
+
+	a.FileList, a.config = []*asset.File{crdFile, cfgFile}, config
+	return true, nil
+}
@@ -0,0 +1,28 @@
+package manifests
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// cloudProviderConfig returns the Kubernetes cloud provider configuration
+// stored in the kube-cloud-cfg secret. It is empty unless the platform
+// needs settings beyond the provider's defaults.
+func cloudProviderConfig(config *types.InstallConfig) string {
+	if config.Platform.AWS == nil || len(config.Platform.AWS.ServiceEndpoints) == 0 {
+		return ""
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintln(buf, "[Global]")
+	for i, endpoint := range config.Platform.AWS.ServiceEndpoints {
+		fmt.Fprintf(buf, "\n[ServiceOverride \"%d\"]\n", i)
+		fmt.Fprintf(buf, "Service = %s\n", endpoint.Name)
+		fmt.Fprintf(buf, "Region = %s\n", config.Platform.AWS.Region)
+		fmt.Fprintf(buf, "URL = %s\n", endpoint.URL)
+		fmt.Fprintf(buf, "SigningRegion = %s\n", config.Platform.AWS.Region)
+	}
+	return buf.String()
+}
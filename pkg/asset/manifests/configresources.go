@@ -0,0 +1,106 @@
+package manifests
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+)
+
+const (
+	// configNamespace holds the secrets and config maps referenced by the
+	// cluster configuration.
+	configNamespace = "openshift-config"
+)
+
+var (
+	configSecretFilename    = filepath.Join(manifestDir, "openshift-config-secret-%s.yml")
+	configConfigMapFilename = filepath.Join(manifestDir, "openshift-config-configmap-%s.yml")
+)
+
+// nameReference references a secret or config map in configNamespace.
+type nameReference struct {
+	Name string `json:"name"`
+}
+
+// ConfigResources generates the secrets and config maps of the install
+// config's configSecrets and configMaps, in the openshift-config namespace.
+type ConfigResources struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*ConfigResources)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*ConfigResources) Name() string {
+	return "Config Resources"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*ConfigResources) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the secrets and config maps.
+func (c *ConfigResources) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	c.FileList = nil
+	for _, s := range installConfig.Config.ConfigSecrets {
+		data := make(map[string][]byte, len(s.Data))
+		for key, value := range s.Data {
+			data[key] = []byte(value)
+		}
+		obj := secret(configNamespace, s.Name, data)
+		if s.Type != "" {
+			obj.Type = corev1.SecretType(s.Type)
+		}
+		secretData, err := yaml.Marshal(obj)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s/%s secret", configNamespace, s.Name)
+		}
+		c.FileList = append(c.FileList, &asset.File{
+			Filename: fmt.Sprintf(configSecretFilename, s.Name),
+			Data:     secretData,
+		})
+	}
+	for _, cm := range installConfig.Config.ConfigMaps {
+		configMapData, err := yaml.Marshal(configMap(configNamespace, cm.Name, genericData(cm.Data)))
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s/%s configmap", configNamespace, cm.Name)
+		}
+		c.FileList = append(c.FileList, &asset.File{
+			Filename: fmt.Sprintf(configConfigMapFilename, cm.Name),
+			Data:     configMapData,
+		})
+	}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (c *ConfigResources) Files() []*asset.File {
+	return c.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (c *ConfigResources) Load(f asset.FileFetcher) (bool, error) {
+	secretFiles, err := f.FetchByPattern(fmt.Sprintf(configSecretFilename, "*"))
+	if err != nil {
+		return false, err
+	}
+	configMapFiles, err := f.FetchByPattern(fmt.Sprintf(configConfigMapFilename, "*"))
+	if err != nil {
+		return false, err
+	}
+	c.FileList = append(secretFiles, configMapFiles...)
+	return len(c.FileList) > 0, nil
+}
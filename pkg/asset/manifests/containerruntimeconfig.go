@@ -0,0 +1,138 @@
+package manifests
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	containerRuntimeConfigFilenameFormat = filepath.Join(openshiftManifestDir, "99_openshift-machineconfig_%s-containerruntimeconfig.yaml")
+)
+
+// containerRuntimeConfig mirrors machineconfiguration.openshift.io/v1
+// ContainerRuntimeConfig. Its CRD is created by the machine config
+// operator, so it is rendered with the openshift manifests, which are
+// retried until the CRD exists.
+type containerRuntimeConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec containerRuntimeConfigSpec `json:"spec"`
+}
+
+type containerRuntimeConfigSpec struct {
+	MachineConfigPoolSelector *metav1.LabelSelector         `json:"machineConfigPoolSelector"`
+	ContainerRuntimeConfig    containerRuntimeConfiguration `json:"containerRuntimeConfig"`
+}
+
+type containerRuntimeConfiguration struct {
+	PidsLimit   *int64             `json:"pidsLimit,omitempty"`
+	LogSizeMax  *resource.Quantity `json:"logSizeMax,omitempty"`
+	OverlaySize *resource.Quantity `json:"overlaySize,omitempty"`
+}
+
+// ContainerRuntimeConfigs generates a ContainerRuntimeConfig for each
+// machine pool with CRI-O settings, so the pool's nodes are configured on
+// first boot.
+type ContainerRuntimeConfigs struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*ContainerRuntimeConfigs)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*ContainerRuntimeConfigs) Name() string {
+	return "Container Runtime Configs"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*ContainerRuntimeConfigs) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the container runtime configs.
+func (c *ContainerRuntimeConfigs) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	c.FileList = []*asset.File{}
+	for _, pool := range installConfig.Config.Machines {
+		if pool.ContainerRuntimeConfig == nil {
+			continue
+		}
+
+		runtimeConfig := containerRuntimeConfiguration{
+			PidsLimit: pool.ContainerRuntimeConfig.PidsLimit,
+		}
+		if pool.ContainerRuntimeConfig.LogSizeMax != "" {
+			size, err := resource.ParseQuantity(pool.ContainerRuntimeConfig.LogSizeMax)
+			if err != nil {
+				return errors.Wrapf(err, "invalid %s container log size %q", pool.Name, pool.ContainerRuntimeConfig.LogSizeMax)
+			}
+			runtimeConfig.LogSizeMax = &size
+		}
+		if pool.ContainerRuntimeConfig.OverlaySize != "" {
+			size, err := resource.ParseQuantity(pool.ContainerRuntimeConfig.OverlaySize)
+			if err != nil {
+				return errors.Wrapf(err, "invalid %s container overlay size %q", pool.Name, pool.ContainerRuntimeConfig.OverlaySize)
+			}
+			runtimeConfig.OverlaySize = &size
+		}
+
+		config := &containerRuntimeConfig{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "machineconfiguration.openshift.io/v1",
+				Kind:       "ContainerRuntimeConfig",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("%s-container-runtime", pool.Name),
+				// not namespaced
+			},
+			Spec: containerRuntimeConfigSpec{
+				MachineConfigPoolSelector: machineConfigPoolSelector(pool.Name),
+				ContainerRuntimeConfig:    runtimeConfig,
+			},
+		}
+
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", c.Name())
+		}
+		c.FileList = append(c.FileList, &asset.File{
+			Filename: fmt.Sprintf(containerRuntimeConfigFilenameFormat, pool.Name),
+			Data:     data,
+		})
+	}
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (c *ContainerRuntimeConfigs) Files() []*asset.File {
+	return c.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (c *ContainerRuntimeConfigs) Load(f asset.FileFetcher) (bool, error) {
+	fileList, err := f.FetchByPattern(fmt.Sprintf(containerRuntimeConfigFilenameFormat, "*"))
+	if err != nil {
+		return false, err
+	}
+	if len(fileList) == 0 {
+		return false, nil
+	}
+	c.FileList = fileList
+	return true, nil
+}
@@ -0,0 +1,78 @@
+package manifests
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/openstack"
+)
+
+// manualCredentialsRequest is a component secret which, in Manual
+// credentials mode, the user provides instead of the cloud credential
+// operator.
+type manualCredentialsRequest struct {
+	namespace string
+	name      string
+	keys      []string
+}
+
+// manualCredentialsRequests lists, per platform, the component secrets
+// which must be provided in Manual credentials mode.
+var manualCredentialsRequests = map[string][]manualCredentialsRequest{
+	aws.Name: {
+		{namespace: "openshift-image-registry", name: "installer-cloud-credentials", keys: []string{"aws_access_key_id", "aws_secret_access_key"}},
+		{namespace: "openshift-ingress-operator", name: "cloud-credentials", keys: []string{"aws_access_key_id", "aws_secret_access_key"}},
+		{namespace: "openshift-machine-api", name: "aws-cloud-credentials", keys: []string{"aws_access_key_id", "aws_secret_access_key"}},
+	},
+	openstack.Name: {
+		{namespace: "openshift-machine-api", name: "openstack-cloud-credentials", keys: []string{"clouds.yaml"}},
+	},
+}
+
+// webIdentityTokenFile is where components mount their projected service
+// account token for exchange with AWS STS.
+const webIdentityTokenFile = "/var/run/secrets/openshift/serviceaccount/token"
+
+// webIdentityCredentials is the AWS shared config which makes the SDK assume
+// the given role with the component's service account token.
+const webIdentityCredentials = `[default]
+role_arn = %s
+web_identity_token_file = %s
+`
+
+// manualCredentialsFiles generates the component secrets for Manual
+// credentials mode. Components with a role in componentRoles (AWS only) get
+// web identity credentials; the others get stub secrets whose values are
+// empty and must be filled in before the cluster is created.
+func manualCredentialsFiles(platform string, componentRoles map[string]string) ([]*asset.File, error) {
+	files := []*asset.File{}
+	for _, request := range manualCredentialsRequests[platform] {
+		filename := filepath.Join(openshiftManifestDir, fmt.Sprintf("99_manual-credentials_%s_%s.yaml", request.namespace, request.name))
+		s := secret(request.namespace, request.name, nil)
+		s.StringData = map[string]string{}
+		if role, ok := componentRoles[request.namespace+"/"+request.name]; ok {
+			s.StringData["credentials"] = fmt.Sprintf(webIdentityCredentials, role, webIdentityTokenFile)
+		} else {
+			for _, key := range request.keys {
+				s.StringData[key] = ""
+			}
+			logrus.Warnf("Credentials mode is %s: fill in %s before creating the cluster", types.ManualCredentialsMode, filename)
+		}
+		data, err := yaml.Marshal(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s/%s secret", request.namespace, request.name)
+		}
+		files = append(files, &asset.File{
+			Filename: filename,
+			Data:     data,
+		})
+	}
+	return files, nil
+}
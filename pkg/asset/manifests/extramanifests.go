@@ -0,0 +1,114 @@
+package manifests
+
+import (
+	"bytes"
+	"path/filepath"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// extraManifestDir is where users put additional manifests, which may
+	// be templates, for the openshift directory.
+	extraManifestDir = "extra-manifests"
+)
+
+var (
+	_ asset.WritableAsset = (*ExtraManifestTemplates)(nil)
+)
+
+// ExtraManifestTemplates holds the additional manifests provided by the
+// user, which may use the fields of extraManifestTemplateData, e.g.
+// {{.InfraID}}, to avoid copying values of the install config.  They are
+// rendered into the openshift directory.
+type ExtraManifestTemplates struct {
+	FileList []*asset.File
+}
+
+// Name returns a human friendly name for the asset.
+func (e *ExtraManifestTemplates) Name() string {
+	return "Extra Manifest Templates"
+}
+
+// Dependencies returns no dependencies.
+func (e *ExtraManifestTemplates) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate generates no templates; they can only be provided by the user.
+func (e *ExtraManifestTemplates) Generate(asset.Parents) error {
+	e.FileList = []*asset.File{}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (e *ExtraManifestTemplates) Files() []*asset.File {
+	return e.FileList
+}
+
+// Load returns the templates from disk.
+func (e *ExtraManifestTemplates) Load(f asset.FileFetcher) (bool, error) {
+	fileList, err := f.FetchByPattern(filepath.Join(extraManifestDir, "*"))
+	if err != nil {
+		return false, err
+	}
+	if len(fileList) == 0 {
+		return false, nil
+	}
+	e.FileList = fileList
+	return true, nil
+}
+
+// renderExtraManifests renders the extra manifest templates into the
+// openshift directory, failing on templates which would replace one of the
+// generated manifests.
+func renderExtraManifests(templates []*asset.File, generated []*asset.File, data *extraManifestTemplateData) ([]*asset.File, error) {
+	existing := make(map[string]bool, len(generated))
+	for _, f := range generated {
+		existing[f.Filename] = true
+	}
+
+	files := make([]*asset.File, 0, len(templates))
+	for _, t := range templates {
+		filename := filepath.Join(openshiftManifestDir, filepath.Base(t.Filename))
+		if existing[filename] {
+			return nil, errors.Errorf("%s: would replace the generated %s", t.Filename, filename)
+		}
+
+		tmpl, err := template.New(t.Filename).Funcs(customTmplFuncs).Parse(string(t.Data))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", t.Filename)
+		}
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, data); err != nil {
+			return nil, errors.Wrapf(err, "failed to render %s", t.Filename)
+		}
+		files = append(files, &asset.File{Filename: filename, Data: buf.Bytes()})
+	}
+	return files, nil
+}
+
+// newExtraManifestTemplateData returns the values available to the extra
+// manifest templates.
+func newExtraManifestTemplateData(config *types.InstallConfig, networking *Networking) (*extraManifestTemplateData, error) {
+	clusterNetwork, err := networking.ClusterNetwork()
+	if err != nil {
+		return nil, err
+	}
+	data := &extraManifestTemplateData{
+		ClusterName:         config.ObjectMeta.Name,
+		ClusterID:           config.ClusterID,
+		BaseDomain:          config.BaseDomain,
+		InfraID:             config.InfrastructureName(),
+		ClusterNetworkCIDRs: clusterNetwork.Pods.CIDRBlocks,
+		MachineCIDRs:        config.MachineCIDRs(),
+	}
+	if len(clusterNetwork.Services.CIDRBlocks) > 0 {
+		data.ServiceCIDR = clusterNetwork.Services.CIDRBlocks[0]
+	}
+	return data, nil
+}
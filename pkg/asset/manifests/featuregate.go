@@ -0,0 +1,147 @@
+package manifests
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/templates/content"
+	"github.com/openshift/installer/pkg/types"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	featureGateCrdFilename = "cluster-featuregate-01-crd.yaml"
+	featureGateCfgFilename = filepath.Join(manifestDir, "cluster-featuregate-02-config.yml")
+)
+
+// featureGate mirrors config.openshift.io/v1 FeatureGate, which is not yet
+// part of the vendored API.
+type featureGate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec featureGateSpec `json:"spec"`
+}
+
+type featureGateSpec struct {
+	FeatureSet      types.FeatureSet    `json:"featureSet,omitempty"`
+	CustomNoUpgrade *customFeatureGates `json:"customNoUpgrade,omitempty"`
+}
+
+type customFeatureGates struct {
+	Enabled  []string `json:"enabled,omitempty"`
+	Disabled []string `json:"disabled,omitempty"`
+}
+
+// FeatureGate generates the cluster-featuregate-*.yml files.
+type FeatureGate struct {
+	config   *featureGate
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*FeatureGate)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*FeatureGate) Name() string {
+	return "Feature Gate Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*FeatureGate) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the feature gate config and its CRD.
+func (fg *FeatureGate) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	fg.config = &featureGate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "FeatureGate",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+		Spec: featureGateSpec{
+			FeatureSet: installConfig.Config.FeatureSet,
+		},
+	}
+	if installConfig.Config.FeatureSet == types.CustomNoUpgrade {
+		enabled, disabled, err := types.ParseFeatureGates(installConfig.Config.FeatureGates)
+		if err != nil {
+			return err
+		}
+		fg.config.Spec.CustomNoUpgrade = &customFeatureGates{
+			Enabled:  enabled,
+			Disabled: disabled,
+		}
+	}
+
+	configData, err := yaml.Marshal(fg.config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", fg.Name())
+	}
+
+	crdData, err := content.GetBootkubeTemplate(featureGateCrdFilename)
+	if err != nil {
+		return err
+	}
+
+	fg.FileList = []*asset.File{
+		{
+			Filename: filepath.Join(manifestDir, featureGateCrdFilename),
+			Data:     []byte(crdData),
+		},
+		{
+			Filename: featureGateCfgFilename,
+			Data:     configData,
+		},
+	}
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (fg *FeatureGate) Files() []*asset.File {
+	return fg.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (fg *FeatureGate) Load(f asset.FileFetcher) (bool, error) {
+	crdFile, err := f.FetchByName(filepath.Join(manifestDir, featureGateCrdFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	cfgFile, err := f.FetchByName(featureGateCfgFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	config := &featureGate{}
+	if err := yaml.Unmarshal(cfgFile.Data, config); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s", featureGateCfgFilename)
+	}
+
+	fg.FileList, fg.config = []*asset.File{crdFile, cfgFile}, config
+	return true, nil
+}
@@ -0,0 +1,186 @@
+package manifests
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	hiveManifestDir = "hive"
+
+	hiveAPIVersion = "hive.openshift.io/v1alpha1"
+)
+
+// hiveClusterDeployment mirrors Hive's ClusterDeployment, which is not
+// vendored.
+type hiveClusterDeployment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec hiveClusterDeploymentSpec `json:"spec"`
+}
+
+type hiveClusterDeploymentSpec struct {
+	ClusterName  string                       `json:"clusterName"`
+	BaseDomain   string                       `json:"baseDomain"`
+	SSHKey       *corev1.LocalObjectReference `json:"sshKey,omitempty"`
+	PullSecret   corev1.LocalObjectReference  `json:"pullSecret"`
+	ImageSet     *hiveClusterImageSetRef      `json:"imageSet,omitempty"`
+	Networking   types.Networking             `json:"networking"`
+	ControlPlane types.MachinePool            `json:"controlPlane"`
+	Compute      []types.MachinePool          `json:"compute"`
+	Platform     types.Platform               `json:"platform"`
+}
+
+type hiveClusterImageSetRef struct {
+	Name string `json:"name"`
+}
+
+// hiveClusterImageSet mirrors Hive's ClusterImageSet, which is not
+// vendored.
+type hiveClusterImageSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec hiveClusterImageSetSpec `json:"spec"`
+}
+
+type hiveClusterImageSetSpec struct {
+	ReleaseImage string `json:"releaseImage"`
+}
+
+// Hive generates a Hive ClusterDeployment and ClusterImageSet, along with
+// the secrets they reference, equivalent to the install config. It is not
+// used to install the cluster.
+type Hive struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*Hive)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*Hive) Name() string {
+	return "Hive Manifests"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*Hive) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the Hive manifests.
+func (h *Hive) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+	config := installConfig.Config
+	name := config.ObjectMeta.Name
+
+	pullSecret := secret("", fmt.Sprintf("%s-pull-secret", name), map[string][]byte{
+		corev1.DockerConfigJsonKey: []byte(config.PullSecret),
+	})
+	pullSecret.Type = corev1.SecretTypeDockerConfigJson
+
+	imageSet := &hiveClusterImageSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: hiveAPIVersion,
+			Kind:       "ClusterImageSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			// not namespaced
+		},
+		Spec: hiveClusterImageSetSpec{
+			ReleaseImage: installconfig.ReleaseImagePullSpec(config),
+		},
+	}
+
+	deployment := &hiveClusterDeployment{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: hiveAPIVersion,
+			Kind:       "ClusterDeployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: hiveClusterDeploymentSpec{
+			ClusterName: name,
+			BaseDomain:  config.BaseDomain,
+			PullSecret:  corev1.LocalObjectReference{Name: pullSecret.Name},
+			ImageSet:    &hiveClusterImageSetRef{Name: imageSet.Name},
+			Networking:  config.Networking,
+			Platform:    config.Platform,
+		},
+	}
+	for _, pool := range config.Machines {
+		if pool.Name == "master" {
+			deployment.Spec.ControlPlane = pool
+		} else {
+			deployment.Spec.Compute = append(deployment.Spec.Compute, pool)
+		}
+	}
+
+	objects := map[string]interface{}{
+		"cluster-deployment.yaml": deployment,
+		"cluster-image-set.yaml":  imageSet,
+		"pull-secret.yaml":        pullSecret,
+	}
+	if len(config.SSHKey) > 0 {
+		sshKey := secret("", fmt.Sprintf("%s-ssh-key", name), map[string][]byte{
+			"ssh-publickey": []byte(strings.Join(config.SSHKey, "\n")),
+		})
+		deployment.Spec.SSHKey = &corev1.LocalObjectReference{Name: sshKey.Name}
+		objects["ssh-key.yaml"] = sshKey
+	}
+
+	filenames := make([]string, 0, len(objects))
+	for filename := range objects {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	h.FileList = []*asset.File{}
+	for _, filename := range filenames {
+		data, err := yaml.Marshal(objects[filename])
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s", filename)
+		}
+		h.FileList = append(h.FileList, &asset.File{
+			Filename: filepath.Join(hiveManifestDir, filename),
+			Data:     data,
+		})
+	}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (h *Hive) Files() []*asset.File {
+	return h.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (h *Hive) Load(f asset.FileFetcher) (bool, error) {
+	fileList, err := f.FetchByPattern(filepath.Join(hiveManifestDir, "*"))
+	if err != nil {
+		return false, err
+	}
+	if len(fileList) == 0 {
+		return false, nil
+	}
+
+	h.FileList = fileList
+	return true, nil
+}
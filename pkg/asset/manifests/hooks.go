@@ -0,0 +1,156 @@
+package manifests
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// AllowManifestHooksEnvVar names the environment variable which, when
+	// "true", allows the install config's manifest hooks to run.  Hooks
+	// run local commands, so an install config, which may be fetched from
+	// elsewhere, cannot run them without the operator opting in.
+	AllowManifestHooksEnvVar = "OPENSHIFT_INSTALL_ALLOW_MANIFEST_HOOKS"
+
+	// hookAssetDirEnvVar names the directory holding the install config
+	// and the generated manifests for a manifest hook.
+	hookAssetDirEnvVar = "OPENSHIFT_INSTALL_ASSET_DIR"
+
+	// hookOutputDirEnvVar names the directory a manifest hook writes the
+	// manifests it contributes to.
+	hookOutputDirEnvVar = "OPENSHIFT_INSTALL_HOOK_OUTPUT_DIR"
+)
+
+// runManifestHooks runs the install config's manifest hooks in order, each
+// with a copy of the install config and the given manifests, and returns
+// the manifests they contribute, which are added to the openshift
+// directory.
+func runManifestHooks(hooks []types.ManifestHook, installConfigData []byte, files []*asset.File) ([]*asset.File, error) {
+	var contributed []*asset.File
+	for _, hook := range hooks {
+		inputs := make([]*asset.File, 0, len(files)+len(contributed))
+		inputs = append(append(inputs, files...), contributed...)
+		hookFiles, err := runManifestHook(hook, installConfigData, inputs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "manifest hook %q", hook.Name)
+		}
+		contributed = append(contributed, hookFiles...)
+	}
+	return contributed, nil
+}
+
+func runManifestHook(hook types.ManifestHook, installConfigData []byte, files []*asset.File) ([]*asset.File, error) {
+	dir, err := ioutil.TempDir("", "openshift-install-hook-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the hook directory")
+	}
+	defer os.RemoveAll(dir)
+
+	assetDir := filepath.Join(dir, "assets")
+	outputDir := filepath.Join(dir, "output")
+	inputs := append([]*asset.File{{Filename: installconfig.InstallConfigFilename, Data: installConfigData}}, files...)
+	for _, f := range inputs {
+		path := filepath.Join(assetDir, f.Filename)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(path, f.Data, 0600); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(outputDir, 0700); err != nil {
+		return nil, err
+	}
+
+	logrus.Infof("Running manifest hook %q...", hook.Name)
+	cmd := exec.Command(hook.Command, hook.Args...)
+	cmd.Dir = assetDir
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("%s=%s", hookAssetDirEnvVar, assetDir),
+		fmt.Sprintf("%s=%s", hookOutputDirEnvVar, outputDir),
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.Output()
+	if len(stdout) > 0 {
+		logrus.Debugf("Manifest hook %q output: %s", hook.Name, strings.TrimSpace(string(stdout)))
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.Wrap(err, msg)
+		}
+		return nil, err
+	}
+
+	return readHookManifests(hook.Name, outputDir)
+}
+
+// readHookManifests reads the manifests a hook wrote to dir, naming them
+// after the hook.  Each must hold a single Kubernetes object.
+func readHookManifests(hookName string, dir string) ([]*asset.File, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*asset.File
+	for _, entry := range entries {
+		if entry.IsDir() {
+			return nil, errors.Errorf("%s: subdirectories are not supported", entry.Name())
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return nil, errors.Errorf("%s: must be a .yaml, .yml or .json manifest", entry.Name())
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if err := validateHookManifest(data); err != nil {
+			return nil, errors.Wrap(err, entry.Name())
+		}
+		files = append(files, &asset.File{
+			Filename: filepath.Join(openshiftManifestDir, fmt.Sprintf("99_%s_%s", hookName, entry.Name())),
+			Data:     data,
+		})
+	}
+	return files, nil
+}
+
+// validateHookManifest checks that data is a Kubernetes object.
+func validateHookManifest(data []byte) error {
+	var object struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Metadata   struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	if err := yaml.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	switch {
+	case object.APIVersion == "":
+		return errors.New("apiVersion is required")
+	case object.Kind == "":
+		return errors.New("kind is required")
+	case object.Metadata.Name == "":
+		return errors.New("metadata.name is required")
+	}
+	return nil
+}
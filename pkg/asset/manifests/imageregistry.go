@@ -0,0 +1,117 @@
+package manifests
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	imageRegistryCfgFilename = filepath.Join(openshiftManifestDir, "99_openshift-image-registry_config.yaml")
+)
+
+// imageRegistryConfig mirrors imageregistry.operator.openshift.io/v1 Config.
+// Its CRD is created by the registry operator, so it is rendered with the
+// openshift manifests, which are retried until the CRD exists.
+type imageRegistryConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec imageRegistrySpec `json:"spec"`
+}
+
+type imageRegistrySpec struct {
+	ManagementState string                     `json:"managementState"`
+	Storage         types.ImageRegistryStorage `json:"storage"`
+}
+
+// ImageRegistry generates the image registry operator config, if the install
+// config requests a particular storage.
+type ImageRegistry struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*ImageRegistry)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*ImageRegistry) Name() string {
+	return "Image Registry Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*ImageRegistry) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the image registry config.
+func (ir *ImageRegistry) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	ir.FileList = []*asset.File{}
+	if installConfig.Config.ImageRegistry == nil {
+		return nil
+	}
+
+	storage := installConfig.Config.ImageRegistry.Storage
+	if storage.S3 != nil && storage.S3.Region == "" && installConfig.Config.Platform.AWS != nil {
+		s3 := *storage.S3
+		s3.Region = installConfig.Config.Platform.AWS.Region
+		storage.S3 = &s3
+	}
+
+	config := &imageRegistryConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "imageregistry.operator.openshift.io/v1",
+			Kind:       "Config",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+		Spec: imageRegistrySpec{
+			ManagementState: "Managed",
+			Storage:         storage,
+		},
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", ir.Name())
+	}
+	ir.FileList = append(ir.FileList, &asset.File{
+		Filename: imageRegistryCfgFilename,
+		Data:     data,
+	})
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (ir *ImageRegistry) Files() []*asset.File {
+	return ir.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (ir *ImageRegistry) Load(f asset.FileFetcher) (bool, error) {
+	file, err := f.FetchByName(imageRegistryCfgFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	ir.FileList = []*asset.File{file}
+	return true, nil
+}
@@ -0,0 +1,199 @@
+package manifests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/templates/content"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/libvirt"
+	"github.com/openshift/installer/pkg/types/openstack"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	infraCrdFilename = "cluster-infrastructure-01-crd.yaml"
+	infraCfgFilename = filepath.Join(manifestDir, "cluster-infrastructure-02-config.yml")
+
+	// infraPlatformTypes maps install-config platform names to the
+	// Infrastructure platform types.
+	infraPlatformTypes = map[string]string{
+		aws.Name:       "AWS",
+		libvirt.Name:   "Libvirt",
+		openstack.Name: "OpenStack",
+	}
+)
+
+// infrastructure mirrors config.openshift.io/v1 Infrastructure, whose status
+// is not yet part of the vendored API.
+type infrastructure struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   struct{}             `json:"spec"`
+	Status infrastructureStatus `json:"status"`
+}
+
+type infrastructureStatus struct {
+	// InfrastructureName is the infra ID that prefixes the names of, and
+	// tags, the cluster's cloud resources.
+	InfrastructureName  string `json:"infrastructureName"`
+	Platform            string `json:"platform"`
+	Region              string `json:"region,omitempty"`
+	APIServerURL        string `json:"apiServerURL"`
+	AppsDomain          string `json:"appsDomain"`
+	EtcdDiscoveryDomain string `json:"etcdDiscoveryDomain"`
+	// MachineNetworks are the CIDRs of the networks the machines are on.
+	MachineNetworks []string `json:"machineNetworks,omitempty"`
+
+	PlatformStatus *infrastructurePlatformStatus `json:"platformStatus,omitempty"`
+}
+
+type infrastructurePlatformStatus struct {
+	Type string             `json:"type"`
+	AWS  *awsPlatformStatus `json:"aws,omitempty"`
+}
+
+type awsPlatformStatus struct {
+	Region string `json:"region"`
+	// ServiceEndpoints are the endpoint overrides for components
+	// which call AWS.
+	ServiceEndpoints []aws.ServiceEndpoint `json:"serviceEndpoints,omitempty"`
+	// APILoadBalancerType is the type of the API load balancers.
+	APILoadBalancerType aws.LoadBalancerType `json:"apiLoadBalancerType"`
+	// APILoadBalancerInternal is true if the API is only served by the
+	// internal load balancer.
+	APILoadBalancerInternal bool `json:"apiLoadBalancerInternal,omitempty"`
+}
+
+// Infrastructure generates the cluster-infrastructure-*.yml files.
+type Infrastructure struct {
+	config   *infrastructure
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*Infrastructure)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*Infrastructure) Name() string {
+	return "Infrastructure Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*Infrastructure) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the Infrastructure config and its CRD.
+func (i *Infrastructure) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	status := infrastructureStatus{
+		InfrastructureName:  installConfig.Config.InfrastructureName(),
+		Platform:            infraPlatformTypes[installConfig.Config.Platform.Name()],
+		APIServerURL:        getAPIServerURL(installConfig.Config),
+		AppsDomain:          fmt.Sprintf("apps.%s.%s", installConfig.Config.ObjectMeta.Name, installConfig.Config.BaseDomain),
+		EtcdDiscoveryDomain: installConfig.Config.BaseDomain,
+		MachineNetworks:     installConfig.Config.MachineCIDRs(),
+	}
+	switch {
+	case installConfig.Config.Platform.AWS != nil:
+		status.Region = installConfig.Config.Platform.AWS.Region
+		awsStatus := &awsPlatformStatus{
+			Region:              installConfig.Config.Platform.AWS.Region,
+			ServiceEndpoints:    installConfig.Config.Platform.AWS.ServiceEndpoints,
+			APILoadBalancerType: aws.NetworkLoadBalancer,
+		}
+		if lb := installConfig.Config.Platform.AWS.APILoadBalancer; lb != nil {
+			if lb.Type != "" {
+				awsStatus.APILoadBalancerType = lb.Type
+			}
+			awsStatus.APILoadBalancerInternal = lb.Internal
+		}
+		status.PlatformStatus = &infrastructurePlatformStatus{
+			Type: status.Platform,
+			AWS:  awsStatus,
+		}
+	case installConfig.Config.Platform.OpenStack != nil:
+		status.Region = installConfig.Config.Platform.OpenStack.Region
+	}
+
+	i.config = &infrastructure{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "Infrastructure",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+		Status: status,
+	}
+
+	configData, err := yaml.Marshal(i.config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", i.Name())
+	}
+
+	crdData, err := content.GetBootkubeTemplate(infraCrdFilename)
+	if err != nil {
+		return err
+	}
+
+	i.FileList = []*asset.File{
+		{
+			Filename: filepath.Join(manifestDir, infraCrdFilename),
+			Data:     []byte(crdData),
+		},
+		{
+			Filename: infraCfgFilename,
+			Data:     configData,
+		},
+	}
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (i *Infrastructure) Files() []*asset.File {
+	return i.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (i *Infrastructure) Load(f asset.FileFetcher) (bool, error) {
+	crdFile, err := f.FetchByName(filepath.Join(manifestDir, infraCrdFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	cfgFile, err := f.FetchByName(infraCfgFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	config := &infrastructure{}
+	if err := yaml.Unmarshal(cfgFile.Data, config); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s", infraCfgFilename)
+	}
+
+	i.FileList, i.config = []*asset.File{crdFile, cfgFile}, config
+	return true, nil
+}
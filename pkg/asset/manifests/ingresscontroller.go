@@ -0,0 +1,184 @@
+package manifests
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultIngressCertificateName = "default-ingress-certificate"
+)
+
+var (
+	defaultIngressCertificateFilename = filepath.Join(openshiftManifestDir, "99_openshift-ingress_default-ingress-certificate.yaml")
+	ingressControllerFilenameFormat   = filepath.Join(openshiftManifestDir, "99_openshift-ingress-operator_%s-ingresscontroller.yaml")
+)
+
+// ingressController mirrors operator.openshift.io/v1 IngressController.
+// Its CRD is created by the ingress operator, so it is rendered with the
+// openshift manifests, which are retried until the CRD exists.
+type ingressController struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ingressControllerSpec `json:"spec"`
+}
+
+type ingressControllerSpec struct {
+	Domain   string `json:"domain,omitempty"`
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// DefaultCertificate references a kubernetes.io/tls secret in the
+	// openshift-ingress namespace.
+	DefaultCertificate *corev1.LocalObjectReference `json:"defaultCertificate,omitempty"`
+
+	RouteSelector              *metav1.LabelSelector       `json:"routeSelector,omitempty"`
+	EndpointPublishingStrategy *endpointPublishingStrategy `json:"endpointPublishingStrategy,omitempty"`
+}
+
+type endpointPublishingStrategy struct {
+	Type types.EndpointPublishingStrategy `json:"type"`
+}
+
+// IngressControllers generates the ingress controller manifests, if the
+// install config customizes the default ingress controller or adds others.
+type IngressControllers struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*IngressControllers)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*IngressControllers) Name() string {
+	return "Ingress Controllers"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*IngressControllers) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the ingress controllers, and the default certificate
+// secret.
+func (ic *IngressControllers) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	ic.FileList = []*asset.File{}
+	ingress := installConfig.Config.Ingress
+	if ingress == nil {
+		return nil
+	}
+
+	if ingress.DefaultCertificate != nil {
+		if err := ic.generateDefault(ingress.DefaultCertificate); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range ingress.Controllers {
+		spec := ingressControllerSpec{
+			Domain:        c.Domain,
+			Replicas:      c.Replicas,
+			RouteSelector: c.RouteSelector,
+		}
+		if c.EndpointPublishingStrategy != "" {
+			spec.EndpointPublishingStrategy = &endpointPublishingStrategy{Type: c.EndpointPublishingStrategy}
+		}
+		if err := ic.addController(c.Name, spec); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// generateDefault generates the certificate secret, with the key read from
+// its file, and points the default ingress controller at it.
+func (ic *IngressControllers) generateDefault(certificate *types.IngressCertificate) error {
+	key, err := ioutil.ReadFile(certificate.KeyFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the default ingress certificate's key")
+	}
+	if _, err := tls.X509KeyPair([]byte(certificate.Certificate), key); err != nil {
+		return errors.Wrapf(err, "the default ingress certificate does not match the key in %s", certificate.KeyFile)
+	}
+
+	certSecret := secret("openshift-ingress", defaultIngressCertificateName, map[string][]byte{
+		corev1.TLSCertKey:       []byte(certificate.Certificate),
+		corev1.TLSPrivateKeyKey: key,
+	})
+	certSecret.Type = corev1.SecretTypeTLS
+	secretData, err := yaml.Marshal(certSecret)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", ic.Name())
+	}
+	ic.FileList = append(ic.FileList, &asset.File{
+		Filename: defaultIngressCertificateFilename,
+		Data:     secretData,
+	})
+
+	return ic.addController("default", ingressControllerSpec{
+		DefaultCertificate: &corev1.LocalObjectReference{Name: defaultIngressCertificateName},
+	})
+}
+
+func (ic *IngressControllers) addController(name string, spec ingressControllerSpec) error {
+	controller := &ingressController{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "operator.openshift.io/v1",
+			Kind:       "IngressController",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openshift-ingress-operator",
+		},
+		Spec: spec,
+	}
+	data, err := yaml.Marshal(controller)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", ic.Name())
+	}
+	ic.FileList = append(ic.FileList, &asset.File{
+		Filename: fmt.Sprintf(ingressControllerFilenameFormat, name),
+		Data:     data,
+	})
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (ic *IngressControllers) Files() []*asset.File {
+	return ic.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (ic *IngressControllers) Load(f asset.FileFetcher) (bool, error) {
+	fileList := []*asset.File{}
+	for _, pattern := range []string{defaultIngressCertificateFilename, fmt.Sprintf(ingressControllerFilenameFormat, "*")} {
+		files, err := f.FetchByPattern(pattern)
+		if err != nil {
+			return false, err
+		}
+		fileList = append(fileList, files...)
+	}
+	if len(fileList) == 0 {
+		return false, nil
+	}
+	ic.FileList = fileList
+	return true, nil
+}
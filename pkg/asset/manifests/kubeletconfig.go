@@ -0,0 +1,125 @@
+package manifests
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	kubeletConfigFilenameFormat = filepath.Join(openshiftManifestDir, "99_openshift-machineconfig_%s-kubeletconfig.yaml")
+)
+
+// kubeletConfig mirrors machineconfiguration.openshift.io/v1 KubeletConfig.
+// Its CRD is created by the machine config operator, so it is rendered with
+// the openshift manifests, which are retried until the CRD exists.
+type kubeletConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec kubeletConfigSpec `json:"spec"`
+}
+
+type kubeletConfigSpec struct {
+	MachineConfigPoolSelector *metav1.LabelSelector `json:"machineConfigPoolSelector"`
+	KubeletConfig             kubeletConfiguration  `json:"kubeletConfig"`
+}
+
+// kubeletConfiguration is the subset of kubelet.config.k8s.io/v1beta1
+// KubeletConfiguration the install config sets.
+type kubeletConfiguration struct {
+	MaxPods        *int32            `json:"maxPods,omitempty"`
+	SystemReserved map[string]string `json:"systemReserved,omitempty"`
+	KubeReserved   map[string]string `json:"kubeReserved,omitempty"`
+	EvictionHard   map[string]string `json:"evictionHard,omitempty"`
+}
+
+// KubeletConfigs generates a KubeletConfig for each machine pool with
+// kubelet settings, so the pool's nodes are configured on first boot.
+type KubeletConfigs struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*KubeletConfigs)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*KubeletConfigs) Name() string {
+	return "Kubelet Configs"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*KubeletConfigs) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the kubelet configs.
+func (k *KubeletConfigs) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	k.FileList = []*asset.File{}
+	for _, pool := range installConfig.Config.Machines {
+		if pool.KubeletConfig == nil {
+			continue
+		}
+
+		config := &kubeletConfig{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "machineconfiguration.openshift.io/v1",
+				Kind:       "KubeletConfig",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("%s-kubelet", pool.Name),
+				// not namespaced
+			},
+			Spec: kubeletConfigSpec{
+				MachineConfigPoolSelector: machineConfigPoolSelector(pool.Name),
+				KubeletConfig: kubeletConfiguration{
+					MaxPods:        pool.KubeletConfig.MaxPods,
+					SystemReserved: pool.KubeletConfig.SystemReserved,
+					KubeReserved:   pool.KubeletConfig.KubeReserved,
+					EvictionHard:   pool.KubeletConfig.EvictionHard,
+				},
+			},
+		}
+
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", k.Name())
+		}
+		k.FileList = append(k.FileList, &asset.File{
+			Filename: fmt.Sprintf(kubeletConfigFilenameFormat, pool.Name),
+			Data:     data,
+		})
+	}
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (k *KubeletConfigs) Files() []*asset.File {
+	return k.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (k *KubeletConfigs) Load(f asset.FileFetcher) (bool, error) {
+	fileList, err := f.FetchByPattern(fmt.Sprintf(kubeletConfigFilenameFormat, "*"))
+	if err != nil {
+		return false, err
+	}
+	if len(fileList) == 0 {
+		return false, nil
+	}
+	k.FileList = fileList
+	return true, nil
+}
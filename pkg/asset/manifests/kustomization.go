@@ -0,0 +1,59 @@
+package manifests
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+const kustomizationFilename = "kustomization.yaml"
+
+// kustomization mirrors kustomize's Kustomization, which is not vendored.
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Resources  []string `json:"resources"`
+}
+
+// IsKustomization returns true if the file is a kustomization, which lists
+// the manifests for GitOps tools (e.g. Argo CD or Flux) but is not itself
+// created in the cluster.
+func IsKustomization(filename string) bool {
+	return filepath.Base(filename) == kustomizationFilename
+}
+
+// withKustomization returns the files in dir, dropping any previous
+// kustomization, along with a kustomization listing them in the order they
+// are created in the cluster.
+func withKustomization(dir string, files []*asset.File) ([]*asset.File, error) {
+	manifests := make([]*asset.File, 0, len(files))
+	resources := []string{}
+	for _, file := range files {
+		if IsKustomization(file.Filename) {
+			continue
+		}
+		manifests = append(manifests, file)
+		switch filepath.Ext(file.Filename) {
+		case ".json", ".yaml", ".yml":
+			resources = append(resources, filepath.Base(file.Filename))
+		}
+	}
+	sort.Strings(resources)
+
+	data, err := yaml.Marshal(&kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Resources:  resources,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s kustomization", dir)
+	}
+	return append(manifests, &asset.File{
+		Filename: filepath.Join(dir, kustomizationFilename),
+		Data:     data,
+	}), nil
+}
@@ -0,0 +1,247 @@
+package manifests
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	igntypes "github.com/coreos/ignition/config/v2_2/types"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/ignition"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var (
+	// machineConfigFilenameFormat names the rendered machine configs,
+	// which bootkube.sh also hands to the bootstrap machine config
+	// controller so the masters get them at first boot.
+	machineConfigFilenameFormat = filepath.Join(openshiftManifestDir, "99_openshift-machineconfig_%s-machineconfig.yaml")
+)
+
+// machineConfig mirrors machineconfiguration.openshift.io/v1 MachineConfig.
+type machineConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec machineConfigSpec `json:"spec"`
+}
+
+type machineConfigSpec struct {
+	Config          igntypes.Config `json:"config"`
+	KernelArguments []string        `json:"kernelArguments,omitempty"`
+	KernelType      string          `json:"kernelType,omitempty"`
+}
+
+func newMachineConfig(name, role string, config igntypes.Config) *machineConfig {
+	config.Ignition.Version = igntypes.MaxVersion.String()
+	return &machineConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "machineconfiguration.openshift.io/v1",
+			Kind:       "MachineConfig",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			// not namespaced
+			Labels: map[string]string{
+				"machineconfiguration.openshift.io/role": role,
+			},
+		},
+		Spec: machineConfigSpec{
+			Config: config,
+		},
+	}
+}
+
+// MachineConfigs generates the machine configs for the machine pool
+// settings which are applied by the machine config operator.
+type MachineConfigs struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*MachineConfigs)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*MachineConfigs) Name() string {
+	return "Machine Configs"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*MachineConfigs) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the machine configs.
+func (m *MachineConfigs) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	m.FileList = []*asset.File{}
+	for _, pool := range installConfig.Config.Machines {
+		var configs []*machineConfig
+		if len(pool.DiskPartitions) > 0 {
+			configs = append(configs, diskPartitionsMachineConfig(pool.Name, pool.DiskPartitions))
+		}
+		if pool.KernelType == types.RealtimeKernel {
+			// The machine config daemon switches the kernel and reboots
+			// before the node joins.
+			config := newMachineConfig(fmt.Sprintf("99-%s-realtime-kernel", pool.Name), pool.Name, igntypes.Config{})
+			config.Spec.KernelType = string(types.RealtimeKernel)
+			configs = append(configs, config)
+		}
+		if pool.Hyperthreading == types.HyperthreadingDisabled {
+			config := newMachineConfig(fmt.Sprintf("99-%s-disable-hyperthreading", pool.Name), pool.Name, igntypes.Config{})
+			config.Spec.KernelArguments = []string{"nosmt"}
+			configs = append(configs, config)
+		}
+		if pool.DiskEncryption != nil {
+			config, err := diskEncryptionMachineConfig(pool.Name, pool.DiskEncryption)
+			if err != nil {
+				return err
+			}
+			configs = append(configs, config)
+		}
+
+		for _, config := range configs {
+			data, err := yaml.Marshal(config)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", m.Name())
+			}
+			m.FileList = append(m.FileList, &asset.File{
+				Filename: fmt.Sprintf(machineConfigFilenameFormat, config.Name),
+				Data:     data,
+			})
+		}
+	}
+
+	return nil
+}
+
+// diskPartitionsMachineConfig creates, formats and mounts the partitions.
+func diskPartitionsMachineConfig(role string, partitions []types.DiskPartition) *machineConfig {
+	config := igntypes.Config{}
+	disks := map[string]int{}
+	for _, partition := range partitions {
+		// Partition labels and mount unit names follow the mount path,
+		// e.g. var-lib-containers.
+		name := strings.Replace(strings.TrimPrefix(partition.MountPath, "/"), "/", "-", -1)
+		device := fmt.Sprintf("/dev/disk/by-partlabel/%s", name)
+		format := partition.Format
+		if format == "" {
+			format = "xfs"
+		}
+
+		idx, ok := disks[partition.Device]
+		if !ok {
+			idx = len(config.Storage.Disks)
+			disks[partition.Device] = idx
+			config.Storage.Disks = append(config.Storage.Disks, igntypes.Disk{Device: partition.Device})
+		}
+		// Ignition sizes partitions in 512-byte sectors.
+		config.Storage.Disks[idx].Partitions = append(config.Storage.Disks[idx].Partitions, igntypes.Partition{
+			Label: name,
+			Start: partition.StartMiB * 2048,
+			Size:  partition.SizeMiB * 2048,
+		})
+
+		label := name
+		config.Storage.Filesystems = append(config.Storage.Filesystems, igntypes.Filesystem{
+			Name: name,
+			Mount: &igntypes.Mount{
+				Device:         device,
+				Format:         format,
+				Label:          &label,
+				WipeFilesystem: true,
+			},
+		})
+
+		options := "defaults"
+		if format == "xfs" {
+			options += ",prjquota"
+		}
+		config.Systemd.Units = append(config.Systemd.Units, igntypes.Unit{
+			Name:    fmt.Sprintf("%s.mount", name),
+			Enabled: pointer.BoolPtr(true),
+			Contents: fmt.Sprintf(`[Unit]
+Before=local-fs.target
+
+[Mount]
+What=%s
+Where=%s
+Options=%s
+
+[Install]
+WantedBy=local-fs.target
+`, device, partition.MountPath, options),
+		})
+	}
+	return newMachineConfig(fmt.Sprintf("98-%s-disk-partitions", role), role, config)
+}
+
+// clevisPin is a Clevis pin configuration. The tang pin takes url and
+// thp, the tpm2 pin nothing, and the sss pin t and pins.
+type clevisPin struct {
+	URL        string                 `json:"url,omitempty"`
+	Thumbprint string                 `json:"thp,omitempty"`
+	Threshold  int                    `json:"t,omitempty"`
+	Pins       map[string]interface{} `json:"pins,omitempty"`
+}
+
+// diskEncryptionMachineConfig writes /etc/clevis.json, from which RHCOS
+// encrypts the root filesystem at first boot. A single pin is written as
+// is, and several are combined with Shamir's Secret Sharing.
+func diskEncryptionMachineConfig(role string, encryption *types.DiskEncryption) (*machineConfig, error) {
+	var pin clevisPin
+	if !encryption.TPM2 && len(encryption.Tang) == 1 {
+		pin = clevisPin{URL: encryption.Tang[0].URL, Thumbprint: encryption.Tang[0].Thumbprint}
+	} else if len(encryption.Tang) > 0 {
+		threshold := encryption.Threshold
+		if threshold == 0 {
+			threshold = 1
+		}
+		tang := make([]clevisPin, 0, len(encryption.Tang))
+		for _, server := range encryption.Tang {
+			tang = append(tang, clevisPin{URL: server.URL, Thumbprint: server.Thumbprint})
+		}
+		pin = clevisPin{Threshold: threshold, Pins: map[string]interface{}{"tang": tang}}
+		if encryption.TPM2 {
+			pin.Pins["tpm2"] = clevisPin{}
+		}
+	}
+
+	data, err := json.Marshal(pin)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the %s Clevis configuration", role)
+	}
+	config := igntypes.Config{}
+	config.Storage.Files = append(config.Storage.Files, ignition.FileFromBytes("/etc/clevis.json", 0644, append(data, '\n')))
+	return newMachineConfig(fmt.Sprintf("98-%s-disk-encryption", role), role, config), nil
+}
+
+// Files returns the files generated by the asset.
+func (m *MachineConfigs) Files() []*asset.File {
+	return m.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (m *MachineConfigs) Load(f asset.FileFetcher) (bool, error) {
+	fileList, err := f.FetchByPattern(fmt.Sprintf(machineConfigFilenameFormat, "*"))
+	if err != nil {
+		return false, err
+	}
+	if len(fileList) == 0 {
+		return false, nil
+	}
+	m.FileList = fileList
+	return true, nil
+}
@@ -0,0 +1,148 @@
+package manifests
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/machines"
+	"github.com/openshift/installer/pkg/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	machineConfigPoolFilenameFormat = filepath.Join(openshiftManifestDir, "99_openshift-machineconfig_%s-machineconfigpool.yaml")
+)
+
+// machineConfigPool mirrors machineconfiguration.openshift.io/v1
+// MachineConfigPool. Its CRD is created by the machine config operator, so
+// it is rendered with the openshift manifests, which are retried until the
+// CRD exists.
+type machineConfigPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec machineConfigPoolSpec `json:"spec"`
+}
+
+type machineConfigPoolSpec struct {
+	MachineConfigSelector *metav1.LabelSelector `json:"machineConfigSelector"`
+	NodeSelector          *metav1.LabelSelector `json:"nodeSelector"`
+}
+
+// MachineConfigPools generates a MachineConfigPool for each named compute
+// pool, which the machine config operator creates for master and worker
+// itself.
+type MachineConfigPools struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*MachineConfigPools)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*MachineConfigPools) Name() string {
+	return "Machine Config Pools"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*MachineConfigPools) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the machine config pools.
+func (p *MachineConfigPools) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	p.FileList = []*asset.File{}
+	for _, pool := range machines.ComputePools(installConfig.Config.Machines) {
+		// Windows machines are not configured by the machine config
+		// operator.
+		if pool.Name == "worker" || pool.OperatingSystem == types.OperatingSystemWindows {
+			continue
+		}
+
+		// The pool's machines get the worker machine configs as well as
+		// their own.
+		config := &machineConfigPool{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "machineconfiguration.openshift.io/v1",
+				Kind:       "MachineConfigPool",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: pool.Name,
+				// not namespaced
+				Labels: map[string]string{
+					machineConfigPoolLabel(pool.Name): "",
+				},
+			},
+			Spec: machineConfigPoolSpec{
+				MachineConfigSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      "machineconfiguration.openshift.io/role",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{"worker", pool.Name},
+					}},
+				},
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						fmt.Sprintf("node-role.kubernetes.io/%s", pool.Name): "",
+					},
+				},
+			},
+		}
+
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", p.Name())
+		}
+		p.FileList = append(p.FileList, &asset.File{
+			Filename: fmt.Sprintf(machineConfigPoolFilenameFormat, pool.Name),
+			Data:     data,
+		})
+	}
+
+	return nil
+}
+
+// machineConfigPoolLabel returns the label identifying the named
+// MachineConfigPool, which the machine config operator also sets on the
+// master and worker pools.
+func machineConfigPoolLabel(pool string) string {
+	return fmt.Sprintf("pools.operator.machineconfiguration.openshift.io/%s", pool)
+}
+
+// machineConfigPoolSelector selects the named MachineConfigPool.
+func machineConfigPoolSelector(pool string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			machineConfigPoolLabel(pool): "",
+		},
+	}
+}
+
+// Files returns the files generated by the asset.
+func (p *MachineConfigPools) Files() []*asset.File {
+	return p.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (p *MachineConfigPools) Load(f asset.FileFetcher) (bool, error) {
+	fileList, err := f.FetchByPattern(fmt.Sprintf(machineConfigPoolFilenameFormat, "*"))
+	if err != nil {
+		return false, err
+	}
+	if len(fileList) == 0 {
+		return false, nil
+	}
+	p.FileList = fileList
+	return true, nil
+}
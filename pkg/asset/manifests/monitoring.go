@@ -0,0 +1,159 @@
+package manifests
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var (
+	monitoringCfgFilename = filepath.Join(openshiftManifestDir, "99_openshift-monitoring_cluster-monitoring-config.yaml")
+)
+
+// clusterMonitoringConfig is the config.yaml consumed by the
+// cluster-monitoring-operator.
+type clusterMonitoringConfig struct {
+	PrometheusK8s    *monitoringComponentConfig `json:"prometheusK8s,omitempty"`
+	AlertmanagerMain *monitoringComponentConfig `json:"alertmanagerMain,omitempty"`
+	TelemeterClient  *telemeterClientConfig     `json:"telemeterClient,omitempty"`
+}
+
+type telemeterClientConfig struct {
+	Enabled bool `json:"enabled"`
+}
+
+type monitoringComponentConfig struct {
+	Retention           string               `json:"retention,omitempty"`
+	NodeSelector        map[string]string    `json:"nodeSelector,omitempty"`
+	VolumeClaimTemplate *volumeClaimTemplate `json:"volumeClaimTemplate,omitempty"`
+}
+
+type volumeClaimTemplate struct {
+	Spec corev1.PersistentVolumeClaimSpec `json:"spec"`
+}
+
+// Monitoring generates the cluster-monitoring-config config map, if the
+// install config has monitoring settings or disables telemetry. The openshift-monitoring namespace
+// is created by the monitoring operator, so it is rendered with the openshift
+// manifests, which are retried until the namespace exists.
+type Monitoring struct {
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*Monitoring)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*Monitoring) Name() string {
+	return "Monitoring Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*Monitoring) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the cluster-monitoring-config config map.
+func (m *Monitoring) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	m.FileList = []*asset.File{}
+	monitoring := installConfig.Config.Monitoring
+	telemetryDisabled := installConfig.Config.Telemetry == types.TelemetryDisabled
+	if monitoring == nil && !telemetryDisabled {
+		return nil
+	}
+
+	config := &clusterMonitoringConfig{}
+	if monitoring != nil {
+		claim, err := monitoringVolumeClaim(monitoring)
+		if err != nil {
+			return err
+		}
+		config.PrometheusK8s = &monitoringComponentConfig{
+			Retention:           monitoring.Retention,
+			NodeSelector:        monitoring.NodeSelector,
+			VolumeClaimTemplate: claim,
+		}
+		config.AlertmanagerMain = &monitoringComponentConfig{
+			NodeSelector:        monitoring.NodeSelector,
+			VolumeClaimTemplate: claim,
+		}
+	}
+	if telemetryDisabled {
+		config.TelemeterClient = &telemeterClientConfig{Enabled: false}
+	}
+	configData, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the cluster monitoring config")
+	}
+
+	data, err := yaml.Marshal(configMap("openshift-monitoring", "cluster-monitoring-config", genericData{
+		"config.yaml": string(configData),
+	}))
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", m.Name())
+	}
+	m.FileList = append(m.FileList, &asset.File{
+		Filename: monitoringCfgFilename,
+		Data:     data,
+	})
+
+	return nil
+}
+
+// monitoringVolumeClaim returns the persistent volume claim template of
+// Prometheus and Alertmanager, if the monitoring settings have a storage
+// size.
+func monitoringVolumeClaim(monitoring *types.Monitoring) (*volumeClaimTemplate, error) {
+	var claim *volumeClaimTemplate
+	if monitoring.StorageSize != "" {
+		size, err := resource.ParseQuantity(monitoring.StorageSize)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid monitoring storage size %q", monitoring.StorageSize)
+		}
+		claim = &volumeClaimTemplate{
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+				},
+			},
+		}
+		if monitoring.StorageClass != "" {
+			storageClass := monitoring.StorageClass
+			claim.Spec.StorageClassName = &storageClass
+		}
+	}
+	return claim, nil
+}
+
+// Files returns the files generated by the asset.
+func (m *Monitoring) Files() []*asset.File {
+	return m.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (m *Monitoring) Load(f asset.FileFetcher) (bool, error) {
+	file, err := f.FetchByName(monitoringCfgFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	m.FileList = []*asset.File{file}
+	return true, nil
+}
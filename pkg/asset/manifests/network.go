@@ -9,7 +9,10 @@ import (
 
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/templates/content"
+	"github.com/openshift/installer/pkg/types"
 
+	configv1 "github.com/openshift/api/config/v1"
 	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/networkoperator/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1a1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
@@ -18,8 +21,63 @@ import (
 var (
 	noCrdFilename = filepath.Join(manifestDir, "cluster-network-01-crd.yml")
 	noCfgFilename = filepath.Join(manifestDir, "cluster-network-02-config.yml")
+
+	clusterNetworkCrdFilename = "cluster-network-03-crd.yaml"
+	clusterNetworkCfgFilename = filepath.Join(manifestDir, "cluster-network-04-config.yml")
 )
 
+// networkConfig mirrors networkoperator.openshift.io/v1 NetworkConfig,
+// adding the ovn-kubernetes IPsec configuration and the egress
+// configuration, which are not yet part of the vendored API.
+type networkConfig struct {
+	netopv1.NetworkConfig `json:",inline"`
+
+	Spec networkConfigSpec `json:"spec"`
+}
+
+type networkConfigSpec struct {
+	netopv1.NetworkConfigSpec `json:",inline"`
+
+	DefaultNetwork defaultNetworkDefinition `json:"defaultNetwork"`
+}
+
+type defaultNetworkDefinition struct {
+	netopv1.DefaultNetworkDefinition `json:",inline"`
+
+	OpenshiftSDNConfig  *openshiftSDNConfig  `json:"openshiftSDNConfig,omitempty"`
+	OVNKubernetesConfig *ovnKubernetesConfig `json:"ovnKubernetesConfig,omitempty"`
+}
+
+type openshiftSDNConfig struct {
+	netopv1.OpenshiftSDNConfig `json:",inline"`
+
+	Egress *types.EgressConfig `json:"egress,omitempty"`
+}
+
+type ovnKubernetesConfig struct {
+	netopv1.OVNKubernetesConfig `json:",inline"`
+
+	IPsecConfig *struct{}           `json:"ipsecConfig,omitempty"`
+	Egress      *types.EgressConfig `json:"egress,omitempty"`
+}
+
+// clusterNetwork mirrors config.openshift.io/v1 Network, whose spec is not
+// yet part of the vendored API.
+type clusterNetwork struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec clusterNetworkSpec `json:"spec"`
+}
+
+type clusterNetworkSpec struct {
+	ServiceNetwork       []string `json:"serviceNetwork"`
+	NetworkType          string   `json:"networkType"`
+	ServiceNodePortRange string   `json:"serviceNodePortRange,omitempty"`
+
+	ExternalIP *types.ExternalIPConfig `json:"externalIP,omitempty"`
+}
+
 const (
 
 	// We need to manually create our CRD first, so we can create the
@@ -67,12 +125,18 @@ func (no *Networking) Dependencies() []asset.Asset {
 	}
 }
 
-// Generate generates the network operator config and its CRD.
+// Generate generates the network operator config, the cluster Network
+// config and their CRDs.
 func (no *Networking) Generate(dependencies asset.Parents) error {
 	installConfig := &installconfig.InstallConfig{}
 	dependencies.Get(installConfig)
 
 	netConfig := installConfig.Config.Networking
+	// The cluster DNS service's IP is derived from the service network
+	// wherever it is used, so only the network needs checking.
+	if _, err := netConfig.ClusterDNSIP(); err != nil {
+		return errors.Wrapf(err, "invalid service network %s", netConfig.ServiceCIDR.String())
+	}
 
 	// determine pod address space.
 	// This can go away when we get rid of PodCIDR
@@ -121,11 +185,65 @@ func (no *Networking) Generate(dependencies asset.Parents) error {
 		},
 	}
 
-	configData, err := yaml.Marshal(no.config)
+	config := &networkConfig{
+		NetworkConfig: *no.config,
+		Spec: networkConfigSpec{
+			NetworkConfigSpec: no.config.Spec,
+			DefaultNetwork: defaultNetworkDefinition{
+				DefaultNetworkDefinition: no.config.Spec.DefaultNetwork,
+			},
+		},
+	}
+	if c := netConfig.OVNKubernetesConfig; c != nil && c.IPsecConfig != nil {
+		config.Spec.DefaultNetwork.OVNKubernetesConfig = &ovnKubernetesConfig{
+			IPsecConfig: &struct{}{},
+		}
+	}
+	// The mirrored openshiftSDNConfig hides the vendored one, so it is
+	// always set with the SDN's defaults.
+	if defaultNet.OpenshiftSDNConfig != nil {
+		config.Spec.DefaultNetwork.OpenshiftSDNConfig = &openshiftSDNConfig{
+			OpenshiftSDNConfig: *defaultNet.OpenshiftSDNConfig,
+			Egress:             netConfig.Egress,
+		}
+	}
+	if netConfig.Egress != nil && netConfig.Type == netopv1.NetworkTypeOVNKubernetes {
+		if config.Spec.DefaultNetwork.OVNKubernetesConfig == nil {
+			config.Spec.DefaultNetwork.OVNKubernetesConfig = &ovnKubernetesConfig{}
+		}
+		config.Spec.DefaultNetwork.OVNKubernetesConfig.Egress = netConfig.Egress
+	}
+
+	configData, err := yaml.Marshal(config)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", no.Name())
 	}
 
+	clusterConfigData, err := yaml.Marshal(&clusterNetwork{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "Network",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+		Spec: clusterNetworkSpec{
+			ServiceNetwork:       []string{netConfig.ServiceCIDR.String()},
+			NetworkType:          string(netConfig.Type),
+			ServiceNodePortRange: netConfig.ServiceNodePortRange,
+			ExternalIP:           netConfig.ExternalIP,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", no.Name())
+	}
+
+	clusterCrdData, err := content.GetBootkubeTemplate(clusterNetworkCrdFilename)
+	if err != nil {
+		return err
+	}
+
 	no.FileList = []*asset.File{
 		{
 			Filename: noCrdFilename,
@@ -135,6 +253,14 @@ func (no *Networking) Generate(dependencies asset.Parents) error {
 			Filename: noCfgFilename,
 			Data:     configData,
 		},
+		{
+			Filename: filepath.Join(manifestDir, clusterNetworkCrdFilename),
+			Data:     []byte(clusterCrdData),
+		},
+		{
+			Filename: clusterNetworkCfgFilename,
+			Data:     clusterConfigData,
+		},
 	}
 
 	return nil
@@ -194,7 +320,25 @@ func (no *Networking) Load(f asset.FileFetcher) (bool, error) {
 		return false, errors.Wrapf(err, "failed to unmarshal %s", noCfgFilename)
 	}
 
-	fileList := []*asset.File{crdFile, cfgFile}
+	clusterCrdFile, err := f.FetchByName(filepath.Join(manifestDir, clusterNetworkCrdFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	clusterCfgFile, err := f.FetchByName(clusterNetworkCfgFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	fileList := []*asset.File{crdFile, cfgFile, clusterCrdFile, clusterCfgFile}
 
 	no.FileList, no.config = fileList, netConfig
 
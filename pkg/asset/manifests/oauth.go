@@ -0,0 +1,254 @@
+package manifests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/templates/content"
+	"github.com/openshift/installer/pkg/types"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	oauthCrdFilename       = "cluster-oauth-01-crd.yaml"
+	oauthCfgFilename       = filepath.Join(manifestDir, "cluster-oauth-02-config.yml")
+	oauthResourcesFilename = filepath.Join(manifestDir, "cluster-oauth-03-%s.yml")
+)
+
+// oauth mirrors config.openshift.io/v1 OAuth, whose spec is not yet part of
+// the vendored API.
+type oauth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec oauthSpec `json:"spec"`
+}
+
+type oauthSpec struct {
+	IdentityProviders []identityProvider `json:"identityProviders,omitempty"`
+}
+
+type identityProvider struct {
+	Name          string              `json:"name"`
+	MappingMethod types.MappingMethod `json:"mappingMethod"`
+	Type          string              `json:"type"`
+
+	HTPasswd *htpasswdIdentityProvider `json:"htpasswd,omitempty"`
+	LDAP     *ldapIdentityProvider     `json:"ldap,omitempty"`
+	OpenID   *openIDIdentityProvider   `json:"openID,omitempty"`
+}
+
+type htpasswdIdentityProvider struct {
+	FileData nameReference `json:"fileData"`
+}
+
+type ldapIdentityProvider struct {
+	URL          string               `json:"url"`
+	BindDN       string               `json:"bindDN,omitempty"`
+	BindPassword *nameReference       `json:"bindPassword,omitempty"`
+	Insecure     bool                 `json:"insecure,omitempty"`
+	CA           *nameReference       `json:"ca,omitempty"`
+	Attributes   types.LDAPAttributes `json:"attributes"`
+}
+
+type openIDIdentityProvider struct {
+	ClientID     string             `json:"clientID"`
+	ClientSecret nameReference      `json:"clientSecret"`
+	Issuer       string             `json:"issuer"`
+	CA           *nameReference     `json:"ca,omitempty"`
+	ExtraScopes  []string           `json:"extraScopes,omitempty"`
+	Claims       types.OpenIDClaims `json:"claims,omitempty"`
+}
+
+// OAuth generates the cluster-oauth-*.yml files.
+type OAuth struct {
+	config   *oauth
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*OAuth)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*OAuth) Name() string {
+	return "OAuth Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*OAuth) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the OAuth config, its CRD, and the secrets and config
+// maps referenced by the identity providers.
+func (o *OAuth) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	o.config = &oauth{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "OAuth",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+	}
+
+	crdData, err := content.GetBootkubeTemplate(oauthCrdFilename)
+	if err != nil {
+		return err
+	}
+	o.FileList = []*asset.File{
+		{
+			Filename: filepath.Join(manifestDir, oauthCrdFilename),
+			Data:     []byte(crdData),
+		},
+	}
+
+	for _, idp := range installConfig.Config.IdentityProviders {
+		provider, resources := identityProviderResources(idp)
+		o.config.Spec.IdentityProviders = append(o.config.Spec.IdentityProviders, provider)
+		names := make([]string, 0, len(resources))
+		for name := range resources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			data, err := yaml.Marshal(resources[name])
+			if err != nil {
+				return errors.Wrapf(err, "failed to create %s/%s for identity provider %q", configNamespace, name, idp.Name)
+			}
+			o.FileList = append(o.FileList, &asset.File{
+				Filename: fmt.Sprintf(oauthResourcesFilename, name),
+				Data:     data,
+			})
+		}
+	}
+
+	configData, err := yaml.Marshal(o.config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", o.Name())
+	}
+	o.FileList = append(o.FileList, &asset.File{
+		Filename: oauthCfgFilename,
+		Data:     configData,
+	})
+
+	return nil
+}
+
+// identityProviderResources converts an install-config identity provider to
+// its OAuth representation and the secrets and config maps it references,
+// keyed by name.
+func identityProviderResources(idp types.IdentityProvider) (identityProvider, map[string]interface{}) {
+	provider := identityProvider{
+		Name:          idp.Name,
+		MappingMethod: idp.MappingMethod,
+	}
+	if provider.MappingMethod == "" {
+		provider.MappingMethod = types.ClaimMappingMethod
+	}
+	resources := map[string]interface{}{}
+
+	caReference := func(ca, caConfigMap string) *nameReference {
+		if caConfigMap != "" {
+			return &nameReference{Name: caConfigMap}
+		}
+		if ca == "" {
+			return nil
+		}
+		name := fmt.Sprintf("%s-ca", idp.Name)
+		resources[name] = configMap(configNamespace, name, genericData{"ca.crt": ca})
+		return &nameReference{Name: name}
+	}
+
+	switch {
+	case idp.HTPasswd != nil:
+		provider.Type = "HTPasswd"
+		name := fmt.Sprintf("%s-htpasswd", idp.Name)
+		resources[name] = secret(configNamespace, name, map[string][]byte{"htpasswd": []byte(idp.HTPasswd.FileData)})
+		provider.HTPasswd = &htpasswdIdentityProvider{FileData: nameReference{Name: name}}
+	case idp.LDAP != nil:
+		provider.Type = "LDAP"
+		provider.LDAP = &ldapIdentityProvider{
+			URL:        idp.LDAP.URL,
+			BindDN:     idp.LDAP.BindDN,
+			Insecure:   idp.LDAP.Insecure,
+			CA:         caReference(idp.LDAP.CA, idp.LDAP.CAConfigMap),
+			Attributes: idp.LDAP.Attributes,
+		}
+		if idp.LDAP.BindPassword != "" {
+			name := fmt.Sprintf("%s-bind-password", idp.Name)
+			resources[name] = secret(configNamespace, name, map[string][]byte{"bindPassword": []byte(idp.LDAP.BindPassword)})
+			provider.LDAP.BindPassword = &nameReference{Name: name}
+		}
+	case idp.OpenID != nil:
+		provider.Type = "OpenID"
+		name := fmt.Sprintf("%s-client-secret", idp.Name)
+		resources[name] = secret(configNamespace, name, map[string][]byte{"clientSecret": []byte(idp.OpenID.ClientSecret)})
+		provider.OpenID = &openIDIdentityProvider{
+			ClientID:     idp.OpenID.ClientID,
+			ClientSecret: nameReference{Name: name},
+			Issuer:       idp.OpenID.Issuer,
+			CA:           caReference(idp.OpenID.CA, idp.OpenID.CAConfigMap),
+			ExtraScopes:  idp.OpenID.ExtraScopes,
+			Claims:       idp.OpenID.Claims,
+		}
+	}
+
+	return provider, resources
+}
+
+// Files returns the files generated by the asset.
+func (o *OAuth) Files() []*asset.File {
+	return o.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (o *OAuth) Load(f asset.FileFetcher) (bool, error) {
+	crdFile, err := f.FetchByName(filepath.Join(manifestDir, oauthCrdFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	cfgFile, err := f.FetchByName(oauthCfgFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	config := &oauth{}
+	if err := yaml.Unmarshal(cfgFile.Data, config); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s", oauthCfgFilename)
+	}
+
+	resourceFiles, err := f.FetchByPattern(fmt.Sprintf(oauthResourcesFilename, "*"))
+	if err != nil {
+		return false, err
+	}
+
+	fileList := []*asset.File{crdFile}
+	fileList = append(fileList, resourceFiles...)
+	fileList = append(fileList, cfgFile)
+
+	o.FileList, o.config = fileList, config
+	return true, nil
+}
@@ -2,10 +2,13 @@ package manifests
 
 import (
 	"encoding/base64"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
 
 	"github.com/gophercloud/utils/openstack/clientconfig"
 	"github.com/openshift/installer/pkg/asset"
@@ -13,6 +16,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/machines"
 	"github.com/openshift/installer/pkg/asset/password"
 	"github.com/openshift/installer/pkg/asset/templates/content/openshift"
+	"github.com/openshift/installer/pkg/types"
 )
 
 const (
@@ -38,10 +42,21 @@ func (o *Openshift) Name() string {
 func (o *Openshift) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&installconfig.InstallConfig{},
+		&Manifests{},
 		&ClusterK8sIO{},
 		&machines.Worker{},
 		&machines.Master{},
 		&password.KubeadminPassword{},
+		&ImageRegistry{},
+		&Monitoring{},
+		&IngressControllers{},
+		&MachineConfigPools{},
+		&KubeletConfigs{},
+		&ContainerRuntimeConfigs{},
+		&MachineConfigs{},
+		&AcceleratorOperators{},
+		&Networking{},
+		&ExtraManifestTemplates{},
 
 		&openshift.BindingDiscovery{},
 		&openshift.CloudCredsSecret{},
@@ -57,11 +72,27 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 	clusterk8sio := &ClusterK8sIO{}
 	worker := &machines.Worker{}
 	master := &machines.Master{}
-	dependencies.Get(installConfig, clusterk8sio, worker, master, kubeadminPassword)
+	imageRegistry := &ImageRegistry{}
+	monitoring := &Monitoring{}
+	ingressControllers := &IngressControllers{}
+	machineConfigPools := &MachineConfigPools{}
+	kubeletConfigs := &KubeletConfigs{}
+	containerRuntimeConfigs := &ContainerRuntimeConfigs{}
+	machineConfigs := &MachineConfigs{}
+	acceleratorOperators := &AcceleratorOperators{}
+	dependencies.Get(installConfig, clusterk8sio, worker, master, kubeadminPassword, imageRegistry, monitoring, ingressControllers, machineConfigPools, kubeletConfigs, containerRuntimeConfigs, machineConfigs, acceleratorOperators)
 	var cloudCreds cloudCredsSecretData
 	platform := installConfig.Config.Platform.Name()
-	switch platform {
-	case "aws":
+	credentialsMode := installConfig.Config.CredentialsMode
+	if credentialsMode == "" {
+		credentialsMode = types.MintCredentialsMode
+	}
+	// In Manual mode no credentials are stored in the cluster, so the
+	// installer does not need to read them.
+	manualCredentials := credentialsMode == types.ManualCredentialsMode
+	switch {
+	case manualCredentials:
+	case platform == "aws":
 		ssn := session.Must(session.NewSessionWithOptions(session.Options{
 			SharedConfigState: session.SharedConfigEnable,
 		}))
@@ -69,17 +100,32 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 		if err != nil {
 			return err
 		}
+		if creds.SessionToken != "" {
+			// Temporary credentials expire shortly after the install, which
+			// would leave the cluster's components without credentials.
+			return errors.Errorf("temporary (STS) credentials cannot be stored in the cluster; set credentialsMode to %s and provide the component credentials, e.g. with platform.aws.componentRoles", types.ManualCredentialsMode)
+		}
 		cloudCreds = cloudCredsSecretData{
 			AWS: &AwsCredsSecretData{
 				Base64encodeAccessKeyID:     base64.StdEncoding.EncodeToString([]byte(creds.AccessKeyID)),
 				Base64encodeSecretAccessKey: base64.StdEncoding.EncodeToString([]byte(creds.SecretAccessKey)),
 			},
 		}
-	case "openstack":
+	case platform == "openstack":
 		clouds, err := clientconfig.LoadCloudsYAML()
 		if err != nil {
 			return err
 		}
+		// The installer's own clients fall back to OS_PASSWORD when
+		// clouds.yaml holds no password, so the cluster's components
+		// need it too.
+		if password := os.Getenv("OS_PASSWORD"); password != "" {
+			for _, cloud := range clouds {
+				if cloud.AuthInfo != nil && cloud.AuthInfo.Password == "" {
+					cloud.AuthInfo.Password = password
+				}
+			}
+		}
 
 		marshalled, err := yaml.Marshal(clouds)
 		if err != nil {
@@ -96,6 +142,7 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 
 	templateData := &openshiftTemplateData{
 		CloudCreds:                   cloudCreds,
+		CredentialsMode:              strings.ToLower(string(credentialsMode)),
 		Base64EncodedKubeadminPwHash: base64.StdEncoding.EncodeToString(kubeadminPassword.PasswordHash),
 	}
 
@@ -117,22 +164,77 @@ func (o *Openshift) Generate(dependencies asset.Parents) error {
 		"99_openshift-cluster-api_worker-machineset.yaml":       worker.MachineSetRaw,
 		"99_openshift-cluster-api_worker-user-data-secret.yaml": worker.UserDataSecretRaw,
 	}
+	if worker.MachineHealthCheckRaw != nil {
+		assetData["99_openshift-cluster-api_worker-machinehealthcheck.yaml"] = worker.MachineHealthCheckRaw
+	}
 
-	switch platform {
-	case "aws", "openstack":
+	switch {
+	case manualCredentials:
+	case platform == "aws", platform == "openstack":
 		assetData["99_cloud-creds-secret.yaml"] = applyTemplateData(cloudCredsSecret.Files()[0].Data, templateData)
 		assetData["99_role-cloud-creds-secret-reader.yaml"] = applyTemplateData(roleCloudCredsSecretReader.Files()[0].Data, templateData)
 	}
 
 	o.FileList = []*asset.File{}
-	for name, data := range assetData {
+	for _, name := range sortedNames(assetData) {
 		o.FileList = append(o.FileList, &asset.File{
 			Filename: filepath.Join(openshiftManifestDir, name),
-			Data:     data,
+			Data:     assetData[name],
 		})
 	}
+	if manualCredentials {
+		var componentRoles map[string]string
+		if installConfig.Config.Platform.AWS != nil {
+			componentRoles = installConfig.Config.Platform.AWS.ComponentRoles
+		}
+		files, err := manualCredentialsFiles(platform, componentRoles)
+		if err != nil {
+			return err
+		}
+		o.FileList = append(o.FileList, files...)
+	}
+	o.FileList = append(o.FileList, imageRegistry.Files()...)
+	o.FileList = append(o.FileList, monitoring.Files()...)
+	o.FileList = append(o.FileList, ingressControllers.Files()...)
+	o.FileList = append(o.FileList, machineConfigPools.Files()...)
+	o.FileList = append(o.FileList, kubeletConfigs.Files()...)
+	o.FileList = append(o.FileList, containerRuntimeConfigs.Files()...)
+	o.FileList = append(o.FileList, machineConfigs.Files()...)
+	o.FileList = append(o.FileList, acceleratorOperators.Files()...)
+
+	extraManifestTemplates := &ExtraManifestTemplates{}
+	networking := &Networking{}
+	dependencies.Get(extraManifestTemplates, networking)
+	if templates := extraManifestTemplates.Files(); len(templates) > 0 {
+		data, err := newExtraManifestTemplateData(installConfig.Config, networking)
+		if err != nil {
+			return err
+		}
+		extraFiles, err := renderExtraManifests(templates, o.FileList, data)
+		if err != nil {
+			return err
+		}
+		o.FileList = append(o.FileList, extraFiles...)
+	}
+
+	if hooks := installConfig.Config.ManifestHooks; len(hooks) > 0 {
+		if os.Getenv(AllowManifestHooksEnvVar) != "true" {
+			return errors.Errorf("the install config has %d manifest hooks, which run local commands; pass --allow-manifest-hooks to run them", len(hooks))
+		}
+		mfsts := &Manifests{}
+		dependencies.Get(mfsts)
+		inputs := make([]*asset.File, 0, len(mfsts.Files())+len(o.FileList))
+		inputs = append(append(inputs, mfsts.Files()...), o.FileList...)
+		hookFiles, err := runManifestHooks(hooks, installConfig.Files()[0].Data, inputs)
+		if err != nil {
+			return err
+		}
+		o.FileList = append(o.FileList, hookFiles...)
+	}
 
-	return nil
+	var err error
+	o.FileList, err = withKustomization(openshiftManifestDir, o.FileList)
+	return err
 }
 
 // Files returns the files generated by the asset.
@@ -146,5 +248,15 @@ func (o *Openshift) Load(f asset.FileFetcher) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	return len(fileList) > 0, nil
+	if len(fileList) == 0 {
+		return false, nil
+	}
+
+	// Regenerate the kustomization, in case manifests were added or
+	// removed.
+	o.FileList, err = withKustomization(openshiftManifestDir, fileList)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
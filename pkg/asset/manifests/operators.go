@@ -4,6 +4,7 @@ package manifests
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"github.com/openshift/installer/pkg/asset/installconfig"
 	"github.com/openshift/installer/pkg/asset/templates/content/bootkube"
 	"github.com/openshift/installer/pkg/asset/tls"
+	"github.com/openshift/installer/pkg/types"
 )
 
 const (
@@ -55,6 +57,14 @@ func (m *Manifests) Dependencies() []asset.Asset {
 		&installconfig.InstallConfig{},
 		&Ingress{},
 		&Networking{},
+		&FeatureGate{},
+		&APIServer{},
+		&Scheduler{},
+		&Project{},
+		&OAuth{},
+		&Infrastructure{},
+		&ConfigResources{},
+		&Proxy{},
 		&tls.RootCA{},
 		&tls.EtcdCA{},
 		&tls.IngressCertKey{},
@@ -74,6 +84,7 @@ func (m *Manifests) Dependencies() []asset.Asset {
 		&bootkube.KubeSystemConfigmapRootCA{},
 		&bootkube.KubeSystemSecretEtcdClient{},
 
+		&bootkube.OpenshiftConfigNamespace{},
 		&bootkube.OpenshiftMachineConfigOperator{},
 		&bootkube.OpenshiftClusterAPINamespace{},
 		&bootkube.OpenshiftServiceCertSignerNamespace{},
@@ -86,12 +97,25 @@ func (m *Manifests) Dependencies() []asset.Asset {
 func (m *Manifests) Generate(dependencies asset.Parents) error {
 	ingress := &Ingress{}
 	network := &Networking{}
+	featureGate := &FeatureGate{}
+	apiServer := &APIServer{}
+	scheduler := &Scheduler{}
+	project := &Project{}
+	oauth := &OAuth{}
+	infrastructure := &Infrastructure{}
+	configResources := &ConfigResources{}
+	proxy := &Proxy{}
 	installConfig := &installconfig.InstallConfig{}
-	dependencies.Get(installConfig, ingress, network)
+	dependencies.Get(installConfig, ingress, network, featureGate, apiServer, scheduler, project, oauth, infrastructure, configResources, proxy)
+
+	clusterConfig, err := clusterConfigInstallConfig(installConfig.Config)
+	if err != nil {
+		return errors.Wrap(err, "failed to redact the install config")
+	}
 
 	// mao go to kube-system config map
 	m.KubeSysConfig = configMap("kube-system", "cluster-config-v1", genericData{
-		"install-config": string(installConfig.Files()[0].Data),
+		"install-config": string(clusterConfig),
 	})
 	kubeSysConfigData, err := yaml.Marshal(m.KubeSysConfig)
 	if err != nil {
@@ -104,12 +128,58 @@ func (m *Manifests) Generate(dependencies asset.Parents) error {
 			Data:     kubeSysConfigData,
 		},
 	}
-	m.FileList = append(m.FileList, m.generateBootKubeManifests(dependencies)...)
+	bootKubeFiles, err := m.generateBootKubeManifests(dependencies)
+	if err != nil {
+		return err
+	}
+	m.FileList = append(m.FileList, bootKubeFiles...)
 
 	m.FileList = append(m.FileList, ingress.Files()...)
 	m.FileList = append(m.FileList, network.Files()...)
+	m.FileList = append(m.FileList, featureGate.Files()...)
+	m.FileList = append(m.FileList, apiServer.Files()...)
+	m.FileList = append(m.FileList, scheduler.Files()...)
+	m.FileList = append(m.FileList, project.Files()...)
+	m.FileList = append(m.FileList, oauth.Files()...)
+	m.FileList = append(m.FileList, infrastructure.Files()...)
+	m.FileList = append(m.FileList, configResources.Files()...)
+	m.FileList = append(m.FileList, proxy.Files()...)
+
+	m.FileList, err = withKustomization(manifestDir, m.FileList)
+	return err
+}
+
+// clusterConfigInstallConfig returns the install config for the
+// kube-system/cluster-config-v1 config map, which many readers can see: a
+// copy of config without the credentials which the manifests only render
+// into secrets.
+func clusterConfigInstallConfig(config *types.InstallConfig) ([]byte, error) {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	redacted := &types.InstallConfig{}
+	if err := yaml.Unmarshal(data, redacted); err != nil {
+		return nil, err
+	}
 
-	return nil
+	for i := range redacted.IdentityProviders {
+		idp := &redacted.IdentityProviders[i]
+		if idp.HTPasswd != nil {
+			idp.HTPasswd.FileData = ""
+		}
+		if idp.LDAP != nil {
+			idp.LDAP.BindPassword = ""
+		}
+		if idp.OpenID != nil {
+			idp.OpenID.ClientSecret = ""
+		}
+	}
+	for i := range redacted.ConfigSecrets {
+		redacted.ConfigSecrets[i].Data = nil
+	}
+
+	return yaml.Marshal(redacted)
 }
 
 // Files returns the files generated by the asset.
@@ -117,7 +187,7 @@ func (m *Manifests) Files() []*asset.File {
 	return m.FileList
 }
 
-func (m *Manifests) generateBootKubeManifests(dependencies asset.Parents) []*asset.File {
+func (m *Manifests) generateBootKubeManifests(dependencies asset.Parents) ([]*asset.File, error) {
 	installConfig := &installconfig.InstallConfig{}
 	etcdCA := &tls.EtcdCA{}
 	kubeCA := &tls.KubeCA{}
@@ -140,8 +210,22 @@ func (m *Manifests) generateBootKubeManifests(dependencies asset.Parents) []*ass
 		etcdEndpointHostnames[i] = fmt.Sprintf("%s-etcd-%d", installConfig.Config.ObjectMeta.Name, i)
 	}
 
+	channel := installConfig.Config.Channel
+	if channel == "" {
+		channel = types.DefaultChannel
+	}
+	upstream := installConfig.Config.Upstream
+	if upstream == "" {
+		upstream = types.DefaultUpstream
+	}
+
+	pullSecret, err := clusterPullSecret(installConfig.Config)
+	if err != nil {
+		return nil, err
+	}
+
 	templateData := &bootkubeTemplateData{
-		Base64encodeCloudProviderConfig: "", // FIXME
+		Base64encodeCloudProviderConfig: base64.StdEncoding.EncodeToString([]byte(cloudProviderConfig(installConfig.Config))),
 		EtcdCaCert:                      string(etcdCA.Cert()),
 		EtcdClientCert:                  base64.StdEncoding.EncodeToString(etcdClientCertKey.Cert()),
 		EtcdClientKey:                   base64.StdEncoding.EncodeToString(etcdClientCertKey.Key()),
@@ -149,11 +233,14 @@ func (m *Manifests) generateBootKubeManifests(dependencies asset.Parents) []*ass
 		KubeCaKey:                       base64.StdEncoding.EncodeToString(kubeCA.Key()),
 		McsTLSCert:                      base64.StdEncoding.EncodeToString(mcsCertKey.Cert()),
 		McsTLSKey:                       base64.StdEncoding.EncodeToString(mcsCertKey.Key()),
-		PullSecretBase64:                base64.StdEncoding.EncodeToString([]byte(installConfig.Config.PullSecret)),
+		PullSecretBase64:                base64.StdEncoding.EncodeToString([]byte(pullSecret)),
 		RootCaCert:                      string(rootCA.Cert()),
 		ServiceServingCaCert:            base64.StdEncoding.EncodeToString(serviceServingCA.Cert()),
 		ServiceServingCaKey:             base64.StdEncoding.EncodeToString(serviceServingCA.Key()),
 		CVOClusterID:                    installConfig.Config.ClusterID,
+		CVOChannel:                      channel,
+		CVOUpstream:                     upstream,
+		CVOOverrides:                    installConfig.Config.CVOOverrides,
 		EtcdEndpointHostnames:           etcdEndpointHostnames,
 		EtcdEndpointDNSSuffix:           installConfig.Config.BaseDomain,
 	}
@@ -168,6 +255,7 @@ func (m *Manifests) generateBootKubeManifests(dependencies asset.Parents) []*ass
 	kubeSystemConfigmapRootCA := &bootkube.KubeSystemConfigmapRootCA{}
 	kubeSystemSecretEtcdClient := &bootkube.KubeSystemSecretEtcdClient{}
 
+	openshiftConfigNamespace := &bootkube.OpenshiftConfigNamespace{}
 	openshiftMachineConfigOperator := &bootkube.OpenshiftMachineConfigOperator{}
 	openshiftClusterAPINamespace := &bootkube.OpenshiftClusterAPINamespace{}
 	openshiftServiceCertSignerNamespace := &bootkube.OpenshiftServiceCertSignerNamespace{}
@@ -183,6 +271,7 @@ func (m *Manifests) generateBootKubeManifests(dependencies asset.Parents) []*ass
 		kubeSystemConfigmapEtcdServingCA,
 		kubeSystemConfigmapRootCA,
 		kubeSystemSecretEtcdClient,
+		openshiftConfigNamespace,
 		openshiftMachineConfigOperator,
 		openshiftClusterAPINamespace,
 		openshiftServiceCertSignerNamespace,
@@ -200,6 +289,7 @@ func (m *Manifests) generateBootKubeManifests(dependencies asset.Parents) []*ass
 		"kube-system-configmap-root-ca.yaml":         applyTemplateData(kubeSystemConfigmapRootCA.Files()[0].Data, templateData),
 		"kube-system-secret-etcd-client.yaml":        applyTemplateData(kubeSystemSecretEtcdClient.Files()[0].Data, templateData),
 
+		"03-openshift-config-namespace.yaml":         []byte(openshiftConfigNamespace.Files()[0].Data),
 		"04-openshift-machine-config-operator.yaml":  []byte(openshiftMachineConfigOperator.Files()[0].Data),
 		"05-openshift-cluster-api-namespace.yaml":    []byte(openshiftClusterAPINamespace.Files()[0].Data),
 		"09-openshift-service-signer-namespace.yaml": []byte(openshiftServiceCertSignerNamespace.Files()[0].Data),
@@ -208,14 +298,47 @@ func (m *Manifests) generateBootKubeManifests(dependencies asset.Parents) []*ass
 	}
 
 	files := make([]*asset.File, 0, len(assetData))
-	for name, data := range assetData {
+	for _, name := range sortedNames(assetData) {
 		files = append(files, &asset.File{
 			Filename: filepath.Join(manifestDir, name),
-			Data:     data,
+			Data:     assetData[name],
 		})
 	}
 
-	return files
+	return files, nil
+}
+
+// clusterPullSecret returns the pull secret of the cluster. With telemetry
+// disabled, it leaves out the auth the telemeter client and the insights
+// operator report with, so they report nothing from the first boot.
+func clusterPullSecret(config *types.InstallConfig) (string, error) {
+	if config.Telemetry != types.TelemetryDisabled {
+		return config.PullSecret, nil
+	}
+	var secret map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(config.PullSecret), &secret); err != nil {
+		return "", errors.Wrap(err, "failed to parse the pull secret")
+	}
+	var auths map[string]json.RawMessage
+	if raw, ok := secret["auths"]; ok {
+		if err := json.Unmarshal(raw, &auths); err != nil {
+			return "", errors.Wrap(err, "failed to parse the pull secret auths")
+		}
+	}
+	if _, ok := auths[types.TelemetryRegistry]; !ok {
+		return config.PullSecret, nil
+	}
+	delete(auths, types.TelemetryRegistry)
+	raw, err := json.Marshal(auths)
+	if err != nil {
+		return "", err
+	}
+	secret["auths"] = raw
+	data, err := json.Marshal(secret)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
 
 func applyTemplateData(data []byte, templateData interface{}) []byte {
@@ -253,6 +376,13 @@ func (m *Manifests) Load(f asset.FileFetcher) (bool, error) {
 
 	}
 
+	// Regenerate the kustomization, in case manifests were added or
+	// removed.
+	fileList, err = withKustomization(manifestDir, fileList)
+	if err != nil {
+		return false, err
+	}
+
 	m.FileList, m.KubeSysConfig = fileList, kubeSysConfig
 
 	return true, nil
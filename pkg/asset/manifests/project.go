@@ -0,0 +1,178 @@
+package manifests
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/templates/content"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// projectRequestTemplateName is the template in configNamespace from
+	// which requested projects are created.
+	projectRequestTemplateName = "project-request"
+)
+
+var (
+	projectCrdFilename             = "cluster-project-01-crd.yaml"
+	projectCfgFilename             = filepath.Join(manifestDir, "cluster-project-02-config.yml")
+	projectRequestTemplateFilename = filepath.Join(manifestDir, "cluster-project-03-request-template.yml")
+)
+
+// project mirrors config.openshift.io/v1 Project, whose spec is not yet
+// part of the vendored API.
+type project struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec projectSpec `json:"spec"`
+}
+
+type projectSpec struct {
+	ProjectRequestMessage string `json:"projectRequestMessage,omitempty"`
+
+	// ProjectRequestTemplate references a template in configNamespace.
+	ProjectRequestTemplate *nameReference `json:"projectRequestTemplate,omitempty"`
+}
+
+// Project generates the cluster-project-*.yml files.
+type Project struct {
+	config   *project
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*Project)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*Project) Name() string {
+	return "Project Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*Project) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the project config, its CRD and, if the install
+// config has a project request template, the template.
+func (p *Project) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	spec := projectSpec{}
+	var templateData []byte
+	if c := installConfig.Config.Project; c != nil {
+		spec.ProjectRequestMessage = c.RequestMessage
+		if c.RequestTemplate != "" {
+			spec.ProjectRequestTemplate = &nameReference{Name: projectRequestTemplateName}
+			var template map[string]interface{}
+			if err := yaml.Unmarshal([]byte(c.RequestTemplate), &template); err != nil {
+				return errors.Wrap(err, "failed to parse the project request template")
+			}
+			metadata, _ := template["metadata"].(map[string]interface{})
+			if metadata == nil {
+				metadata = map[string]interface{}{}
+			}
+			metadata["name"] = projectRequestTemplateName
+			metadata["namespace"] = configNamespace
+			template["metadata"] = metadata
+			var err error
+			templateData, err = yaml.Marshal(template)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create %s/%s template", configNamespace, projectRequestTemplateName)
+			}
+		}
+	}
+
+	p.config = &project{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "Project",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+		Spec: spec,
+	}
+
+	configData, err := yaml.Marshal(p.config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", p.Name())
+	}
+
+	crdData, err := content.GetBootkubeTemplate(projectCrdFilename)
+	if err != nil {
+		return err
+	}
+
+	p.FileList = []*asset.File{
+		{
+			Filename: filepath.Join(manifestDir, projectCrdFilename),
+			Data:     []byte(crdData),
+		},
+		{
+			Filename: projectCfgFilename,
+			Data:     configData,
+		},
+	}
+	if templateData != nil {
+		p.FileList = append(p.FileList, &asset.File{
+			Filename: projectRequestTemplateFilename,
+			Data:     templateData,
+		})
+	}
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (p *Project) Files() []*asset.File {
+	return p.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (p *Project) Load(f asset.FileFetcher) (bool, error) {
+	crdFile, err := f.FetchByName(filepath.Join(manifestDir, projectCrdFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	cfgFile, err := f.FetchByName(projectCfgFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	config := &project{}
+	if err := yaml.Unmarshal(cfgFile.Data, config); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s", projectCfgFilename)
+	}
+
+	p.FileList, p.config = []*asset.File{crdFile, cfgFile}, config
+
+	templateFile, err := f.FetchByName(projectRequestTemplateFilename)
+	switch {
+	case err == nil:
+		p.FileList = append(p.FileList, templateFile)
+	case !os.IsNotExist(err):
+		return false, err
+	}
+	return true, nil
+}
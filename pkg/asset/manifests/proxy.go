@@ -0,0 +1,174 @@
+package manifests
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/templates/content"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// proxyTrustBundleName is the config map in configNamespace holding
+	// the proxy's trust bundle.
+	proxyTrustBundleName = "user-ca-bundle"
+)
+
+var (
+	proxyCrdFilename         = "cluster-proxy-01-crd.yaml"
+	proxyCfgFilename         = filepath.Join(manifestDir, "cluster-proxy-02-config.yml")
+	proxyTrustBundleFilename = filepath.Join(manifestDir, "cluster-proxy-03-trusted-ca-bundle.yml")
+)
+
+// proxy mirrors config.openshift.io/v1 Proxy, which is not yet part of the
+// vendored API.
+type proxy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec proxySpec `json:"spec"`
+}
+
+type proxySpec struct {
+	HTTPProxy  string `json:"httpProxy,omitempty"`
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+	NoProxy    string `json:"noProxy,omitempty"`
+
+	// TrustedCA references a config map in configNamespace whose
+	// ca-bundle.crt key holds the proxy's trust bundle.
+	TrustedCA *nameReference `json:"trustedCA,omitempty"`
+}
+
+// Proxy generates the cluster-proxy-*.yml files.
+type Proxy struct {
+	config   *proxy
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*Proxy)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*Proxy) Name() string {
+	return "Proxy Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*Proxy) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the proxy config, its CRD and, if the proxy has a
+// trust bundle, the config map holding it.
+func (p *Proxy) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	spec := proxySpec{}
+	var trustBundleData []byte
+	if c := installConfig.Config.Proxy; c != nil {
+		spec.HTTPProxy = c.HTTPProxy
+		spec.HTTPSProxy = c.HTTPSProxy
+		spec.NoProxy = c.NoProxy
+		if c.TrustBundle != "" {
+			spec.TrustedCA = &nameReference{Name: proxyTrustBundleName}
+			var err error
+			trustBundleData, err = yaml.Marshal(configMap(configNamespace, proxyTrustBundleName, genericData{
+				"ca-bundle.crt": c.TrustBundle,
+			}))
+			if err != nil {
+				return errors.Wrapf(err, "failed to create %s/%s configmap", configNamespace, proxyTrustBundleName)
+			}
+		}
+	}
+
+	p.config = &proxy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "Proxy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+		Spec: spec,
+	}
+
+	configData, err := yaml.Marshal(p.config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", p.Name())
+	}
+
+	crdData, err := content.GetBootkubeTemplate(proxyCrdFilename)
+	if err != nil {
+		return err
+	}
+
+	p.FileList = []*asset.File{
+		{
+			Filename: filepath.Join(manifestDir, proxyCrdFilename),
+			Data:     []byte(crdData),
+		},
+		{
+			Filename: proxyCfgFilename,
+			Data:     configData,
+		},
+	}
+	if trustBundleData != nil {
+		p.FileList = append(p.FileList, &asset.File{
+			Filename: proxyTrustBundleFilename,
+			Data:     trustBundleData,
+		})
+	}
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (p *Proxy) Files() []*asset.File {
+	return p.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (p *Proxy) Load(f asset.FileFetcher) (bool, error) {
+	crdFile, err := f.FetchByName(filepath.Join(manifestDir, proxyCrdFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	cfgFile, err := f.FetchByName(proxyCfgFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	config := &proxy{}
+	if err := yaml.Unmarshal(cfgFile.Data, config); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s", proxyCfgFilename)
+	}
+
+	p.FileList, p.config = []*asset.File{crdFile, cfgFile}, config
+
+	trustBundleFile, err := f.FetchByName(proxyTrustBundleFilename)
+	switch {
+	case err == nil:
+		p.FileList = append(p.FileList, trustBundleFile)
+	case !os.IsNotExist(err):
+		return false, err
+	}
+	return true, nil
+}
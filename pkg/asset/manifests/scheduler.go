@@ -0,0 +1,140 @@
+package manifests
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/templates/content"
+	"github.com/openshift/installer/pkg/types"
+
+	configv1 "github.com/openshift/api/config/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	schedulerCrdFilename = "cluster-scheduler-01-crd.yaml"
+	schedulerCfgFilename = filepath.Join(manifestDir, "cluster-scheduler-02-config.yml")
+)
+
+// scheduler mirrors config.openshift.io/v1 Scheduler, which is not yet part
+// of the vendored API.
+type scheduler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec schedulerSpec `json:"spec"`
+}
+
+type schedulerSpec struct {
+	Profile             types.SchedulerProfile `json:"profile"`
+	DefaultNodeSelector string                 `json:"defaultNodeSelector,omitempty"`
+}
+
+// Scheduler generates the cluster-scheduler-*.yml files.
+type Scheduler struct {
+	config   *scheduler
+	FileList []*asset.File
+}
+
+var _ asset.WritableAsset = (*Scheduler)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*Scheduler) Name() string {
+	return "Scheduler Config"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*Scheduler) Dependencies() []asset.Asset {
+	return []asset.Asset{
+		&installconfig.InstallConfig{},
+	}
+}
+
+// Generate generates the scheduler config and its CRD.
+func (s *Scheduler) Generate(dependencies asset.Parents) error {
+	installConfig := &installconfig.InstallConfig{}
+	dependencies.Get(installConfig)
+
+	spec := schedulerSpec{
+		Profile: types.LowNodeUtilizationSchedulerProfile,
+	}
+	if c := installConfig.Config.Scheduler; c != nil {
+		if c.Profile != "" {
+			spec.Profile = c.Profile
+		}
+		spec.DefaultNodeSelector = c.DefaultNodeSelector
+	}
+
+	s.config = &scheduler{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: configv1.SchemeGroupVersion.String(),
+			Kind:       "Scheduler",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+			// not namespaced
+		},
+		Spec: spec,
+	}
+
+	configData, err := yaml.Marshal(s.config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s manifests from InstallConfig", s.Name())
+	}
+
+	crdData, err := content.GetBootkubeTemplate(schedulerCrdFilename)
+	if err != nil {
+		return err
+	}
+
+	s.FileList = []*asset.File{
+		{
+			Filename: filepath.Join(manifestDir, schedulerCrdFilename),
+			Data:     []byte(crdData),
+		},
+		{
+			Filename: schedulerCfgFilename,
+			Data:     configData,
+		},
+	}
+
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (s *Scheduler) Files() []*asset.File {
+	return s.FileList
+}
+
+// Load loads the already-rendered files back from disk.
+func (s *Scheduler) Load(f asset.FileFetcher) (bool, error) {
+	crdFile, err := f.FetchByName(filepath.Join(manifestDir, schedulerCrdFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	cfgFile, err := f.FetchByName(schedulerCfgFilename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	config := &scheduler{}
+	if err := yaml.Unmarshal(cfgFile.Data, config); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s", schedulerCfgFilename)
+	}
+
+	s.FileList, s.config = []*asset.File{crdFile, cfgFile}, config
+	return true, nil
+}
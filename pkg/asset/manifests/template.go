@@ -1,5 +1,9 @@
 package manifests
 
+import (
+	configv1 "github.com/openshift/api/config/v1"
+)
+
 // AwsCredsSecretData holds encoded credentials and is used to generate cloud-creds secret
 type AwsCredsSecretData struct {
 	Base64encodeAccessKeyID     string
@@ -31,11 +35,27 @@ type bootkubeTemplateData struct {
 	ServiceServingCaKey             string
 	WorkerIgnConfig                 string
 	CVOClusterID                    string
+	CVOChannel                      string
+	CVOUpstream                     string
+	CVOOverrides                    []configv1.ComponentOverride
 	EtcdEndpointHostnames           []string
 	EtcdEndpointDNSSuffix           string
 }
 
 type openshiftTemplateData struct {
 	CloudCreds                   cloudCredsSecretData
+	CredentialsMode              string
 	Base64EncodedKubeadminPwHash string
 }
+
+// extraManifestTemplateData is the data available to the extra manifest
+// templates.
+type extraManifestTemplateData struct {
+	ClusterName         string
+	ClusterID           string
+	BaseDomain          string
+	InfraID             string
+	ServiceCIDR         string
+	ClusterNetworkCIDRs []string
+	MachineCIDRs        []string
+}
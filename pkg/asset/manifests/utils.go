@@ -2,8 +2,10 @@ package manifests
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/openshift/installer/pkg/types"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -36,3 +38,29 @@ func configMap(namespace, name string, data genericData) *configurationObject {
 func getAPIServerURL(ic *types.InstallConfig) string {
 	return fmt.Sprintf("https://%s-api.%s:6443", ic.ObjectMeta.Name, ic.BaseDomain)
 }
+
+func secret(namespace, name string, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+// sortedNames returns the file names of assetData in order, so generated
+// file lists are reproducible.
+func sortedNames(assetData map[string][]byte) []string {
+	names := make([]string, 0, len(assetData))
+	for name := range assetData {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,204 @@
+package asset
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+// absentValue stands for a key missing from one side of a merge.
+type absentValue struct{}
+
+// mergeFiles merges the edits made to the base files in edited with the
+// changes made to them in generated: a three-way merge, so that files
+// regenerated by the installer keep the user's edits. YAML files are merged
+// key by key, and other files as a whole. Where both sides changed the same
+// file or key differently, the edited side is kept and the conflict is
+// returned.
+func mergeFiles(base, edited, generated []*File) ([]*File, []string, error) {
+	baseByName, editedByName, generatedByName := filesByName(base), filesByName(edited), filesByName(generated)
+
+	var names []string
+	for _, files := range [][]*File{generated, edited} {
+		for _, f := range files {
+			names = append(names, f.Filename)
+		}
+	}
+
+	var (
+		merged    []*File
+		conflicts []string
+		seen      = map[string]bool{}
+	)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		b, inBase := baseByName[name]
+		e, inEdited := editedByName[name]
+		g, inGenerated := generatedByName[name]
+
+		switch {
+		case inEdited && inGenerated && bytes.Equal(e.Data, g.Data):
+			merged = append(merged, g)
+		case !inBase && inEdited && !inGenerated:
+			// Added by the user.
+			merged = append(merged, e)
+		case !inBase && inGenerated && !inEdited:
+			// Added by the installer.
+			merged = append(merged, g)
+		case !inEdited:
+			// Deleted by the user, which stands unless the installer
+			// changed the file.
+			if !bytes.Equal(b.Data, g.Data) {
+				conflicts = append(conflicts, fmt.Sprintf("%s (deleted, but regenerated differently; keeping the regenerated file)", name))
+				merged = append(merged, g)
+			}
+		case !inGenerated:
+			// No longer generated, which stands unless the user changed
+			// the file.
+			if !bytes.Equal(b.Data, e.Data) {
+				conflicts = append(conflicts, fmt.Sprintf("%s (edited, but no longer generated; keeping the edited file)", name))
+				merged = append(merged, e)
+			}
+		case !inBase:
+			conflicts = append(conflicts, fmt.Sprintf("%s (added, but generated differently; keeping the added file)", name))
+			merged = append(merged, e)
+		case bytes.Equal(b.Data, e.Data):
+			merged = append(merged, g)
+		case bytes.Equal(b.Data, g.Data):
+			merged = append(merged, e)
+		default:
+			f, fileConflicts, err := mergeFile(b, e, g)
+			if err != nil {
+				return nil, nil, err
+			}
+			merged = append(merged, f)
+			conflicts = append(conflicts, fileConflicts...)
+		}
+	}
+	return merged, conflicts, nil
+}
+
+// mergeFile merges a file which both sides changed.
+func mergeFile(base, edited, generated *File) (*File, []string, error) {
+	switch filepath.Ext(base.Filename) {
+	case ".yaml", ".yml":
+	default:
+		return edited, []string{fmt.Sprintf("%s (keeping the edited file)", base.Filename)}, nil
+	}
+
+	var b, e, g interface{}
+	for _, side := range []struct {
+		file  *File
+		value *interface{}
+	}{{base, &b}, {edited, &e}, {generated, &g}} {
+		if err := yaml.Unmarshal(side.file.Data, side.value); err != nil {
+			// Not a single YAML document; merge it as a whole.
+			return edited, []string{fmt.Sprintf("%s (keeping the edited file)", base.Filename)}, nil
+		}
+	}
+
+	var conflicts []string
+	value := mergeValues(base.Filename, b, e, g, &conflicts)
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to marshal the merged %s", base.Filename)
+	}
+	return &File{Filename: base.Filename, Data: data}, conflicts, nil
+}
+
+// mergeValues merges the edited and generated changes to base, recursing
+// into maps. Other values, including lists, are merged as a whole.
+func mergeValues(path string, base, edited, generated interface{}, conflicts *[]string) interface{} {
+	switch {
+	case reflect.DeepEqual(edited, generated):
+		return edited
+	case reflect.DeepEqual(base, edited):
+		return generated
+	case reflect.DeepEqual(base, generated):
+		return edited
+	}
+
+	editedMap, editedIsMap := edited.(map[string]interface{})
+	generatedMap, generatedIsMap := generated.(map[string]interface{})
+	if !editedIsMap || !generatedIsMap {
+		*conflicts = append(*conflicts, fmt.Sprintf("%s (keeping the edited value)", path))
+		return edited
+	}
+	baseMap, _ := base.(map[string]interface{})
+
+	keys := map[string]bool{}
+	for _, m := range []map[string]interface{}{baseMap, editedMap, generatedMap} {
+		for key := range m {
+			keys[key] = true
+		}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	merged := make(map[string]interface{}, len(keys))
+	for _, key := range sortedKeys {
+		value := mergeValues(path+"."+key, mapValue(baseMap, key), mapValue(editedMap, key), mapValue(generatedMap, key), conflicts)
+		if _, absent := value.(absentValue); !absent {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// mapValue returns the value of key in m, or absentValue if there is none.
+func mapValue(m map[string]interface{}, key string) interface{} {
+	if value, ok := m[key]; ok {
+		return value
+	}
+	return absentValue{}
+}
+
+func filesByName(files []*File) map[string]*File {
+	byName := make(map[string]*File, len(files))
+	for _, f := range files {
+		byName[f.Filename] = f
+	}
+	return byName
+}
+
+// memoryFileFetcher fetches files from memory, so that assets can be loaded
+// from merged files.
+type memoryFileFetcher []*File
+
+// FetchByName returns the file with the given name.
+func (f memoryFileFetcher) FetchByName(name string) (*File, error) {
+	for _, file := range f {
+		if file.Filename == name {
+			return file, nil
+		}
+	}
+	return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+}
+
+// FetchByPattern returns the files whose name match the given glob.
+func (f memoryFileFetcher) FetchByPattern(pattern string) ([]*File, error) {
+	var files []*File
+	for _, file := range f {
+		matched, err := filepath.Match(pattern, file.Filename)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			files = append(files, file)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Filename < files[j].Filename })
+	return files, nil
+}
@@ -0,0 +1,106 @@
+package asset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeFiles(t *testing.T) {
+	file := func(name, data string) *File {
+		return &File{Filename: name, Data: []byte(data)}
+	}
+
+	cases := []struct {
+		name              string
+		base              []*File
+		edited            []*File
+		generated         []*File
+		expectedFiles     []*File
+		expectedConflicts []string
+	}{
+		{
+			name:          "unedited",
+			base:          []*File{file("a.yaml", "a: 1\n")},
+			edited:        []*File{file("a.yaml", "a: 1\n")},
+			generated:     []*File{file("a.yaml", "a: 2\n")},
+			expectedFiles: []*File{file("a.yaml", "a: 2\n")},
+		},
+		{
+			name:          "unchanged by the installer",
+			base:          []*File{file("a.yaml", "a: 1\n")},
+			edited:        []*File{file("a.yaml", "a: 3\n")},
+			generated:     []*File{file("a.yaml", "a: 1\n")},
+			expectedFiles: []*File{file("a.yaml", "a: 3\n")},
+		},
+		{
+			name:          "different keys",
+			base:          []*File{file("a.yaml", "a: 1\nb:\n  c: 1\n  d: 1\n")},
+			edited:        []*File{file("a.yaml", "a: 1\nb:\n  c: 2\n  d: 1\n")},
+			generated:     []*File{file("a.yaml", "a: 2\nb:\n  c: 1\n  d: 1\n  e: 1\n")},
+			expectedFiles: []*File{file("a.yaml", "a: 2\nb:\n  c: 2\n  d: 1\n  e: 1\n")},
+		},
+		{
+			name:          "deleted key",
+			base:          []*File{file("a.yaml", "a: 1\nb: 1\n")},
+			edited:        []*File{file("a.yaml", "a: 1\n")},
+			generated:     []*File{file("a.yaml", "a: 2\nb: 1\n")},
+			expectedFiles: []*File{file("a.yaml", "a: 2\n")},
+		},
+		{
+			name:              "same key",
+			base:              []*File{file("a.yaml", "a: 1\nb: 1\n")},
+			edited:            []*File{file("a.yaml", "a: 3\nb: 1\n")},
+			generated:         []*File{file("a.yaml", "a: 2\nb: 2\n")},
+			expectedFiles:     []*File{file("a.yaml", "a: 3\nb: 2\n")},
+			expectedConflicts: []string{"a.yaml.a (keeping the edited value)"},
+		},
+		{
+			name:              "not YAML",
+			base:              []*File{file("a.ign", "1")},
+			edited:            []*File{file("a.ign", "3")},
+			generated:         []*File{file("a.ign", "2")},
+			expectedFiles:     []*File{file("a.ign", "3")},
+			expectedConflicts: []string{"a.ign (keeping the edited file)"},
+		},
+		{
+			name:          "added files",
+			base:          []*File{file("a.yaml", "a: 1\n")},
+			edited:        []*File{file("a.yaml", "a: 1\n"), file("b.yaml", "b: 1\n")},
+			generated:     []*File{file("a.yaml", "a: 1\n"), file("c.yaml", "c: 1\n")},
+			expectedFiles: []*File{file("a.yaml", "a: 1\n"), file("c.yaml", "c: 1\n"), file("b.yaml", "b: 1\n")},
+		},
+		{
+			name:          "deleted file",
+			base:          []*File{file("a.yaml", "a: 1\n"), file("b.yaml", "b: 1\n")},
+			edited:        []*File{file("a.yaml", "a: 1\n")},
+			generated:     []*File{file("a.yaml", "a: 2\n"), file("b.yaml", "b: 1\n")},
+			expectedFiles: []*File{file("a.yaml", "a: 2\n")},
+		},
+		{
+			name:              "deleted file regenerated differently",
+			base:              []*File{file("b.yaml", "b: 1\n")},
+			edited:            []*File{},
+			generated:         []*File{file("b.yaml", "b: 2\n")},
+			expectedFiles:     []*File{file("b.yaml", "b: 2\n")},
+			expectedConflicts: []string{"b.yaml (deleted, but regenerated differently; keeping the regenerated file)"},
+		},
+		{
+			name:          "no longer generated",
+			base:          []*File{file("a.yaml", "a: 1\n"), file("b.yaml", "b: 1\n")},
+			edited:        []*File{file("a.yaml", "a: 1\n"), file("b.yaml", "b: 1\n")},
+			generated:     []*File{file("a.yaml", "a: 1\n")},
+			expectedFiles: []*File{file("a.yaml", "a: 1\n")},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			files, conflicts, err := mergeFiles(tc.base, tc.edited, tc.generated)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expectedFiles, files)
+			assert.Equal(t, tc.expectedConflicts, conflicts)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"math/big"
 
 	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/reproducible"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -41,7 +42,7 @@ func (a *KubeadminPassword) generateRandomPasswordHash(length int) error {
 	)
 	var password string
 	for i := 0; i < length; i++ {
-		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(all))))
+		n, err := rand.Int(reproducible.Reader("kubeadmin-password"), big.NewInt(int64(len(all))))
 		if err != nil {
 			return err
 		}
@@ -50,7 +51,7 @@ func (a *KubeadminPassword) generateRandomPasswordHash(length int) error {
 			password = newchar
 		}
 		if i < length-1 {
-			n, err = rand.Int(rand.Reader, big.NewInt(int64(len(password)+1)))
+			n, err = rand.Int(reproducible.Reader("kubeadmin-password"), big.NewInt(int64(len(password)+1)))
 			if err != nil {
 				return err
 			}
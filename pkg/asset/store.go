@@ -8,11 +8,18 @@ import (
 	"reflect"
 
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/encryption"
+	"github.com/openshift/installer/pkg/failure"
 )
 
 const (
 	stateFileName = ".openshift_install_state.json"
+
+	// generatedStatePrefix prefixes the state file keys of the files
+	// writable assets had when they were last generated, which are the
+	// base of the three-way merge of the user's edits to them.
+	generatedStatePrefix = "generated:"
 )
 
 // Store is a store for the states of assets.
@@ -24,6 +31,14 @@ type Store interface {
 	// Destroy removes the asset from all its internal state and also from
 	// disk if possible.
 	Destroy(Asset) error
+
+	// Invalidate removes the assets in the state file for which stale
+	// returns true, along with the assets depending on them, from the
+	// state and from disk, so that the next Fetch generates them again.
+	// Only targets and their dependencies are considered, and stale is
+	// called with each as loaded from the state file. It returns the
+	// removed assets.
+	Invalidate(stale func(Asset) bool, targets ...Asset) ([]Asset, error)
 }
 
 // assetSource indicates from where the asset was fetched
@@ -51,6 +66,11 @@ type assetState struct {
 	// presentOnDisk is true if the asset in on-disk. This is set whether the
 	// asset is sourced from on-disk or not. It is used in purging consumed assets.
 	presentOnDisk bool
+	// editedFiles and baseFiles are the files of an asset which must be
+	// regenerated, as edited in the target directory and as they were last
+	// generated. The edits are merged into the regenerated asset.
+	editedFiles []*File
+	baseFiles   []*File
 }
 
 // StoreImpl is the implementation of Store.
@@ -59,6 +79,10 @@ type StoreImpl struct {
 	assets          map[reflect.Type]*assetState
 	stateFileAssets map[string]json.RawMessage
 	fileFetcher     FileFetcher
+
+	// generatedFiles are the files of the writable assets generated by
+	// this store, before any edits were merged into them.
+	generatedFiles map[reflect.Type][]*File
 }
 
 // NewStore returns an asset store that implements the Store interface.
@@ -78,9 +102,15 @@ func NewStore(dir string) (Store, error) {
 // Fetch retrieves the state of the given asset, generating it and its
 // dependencies if necessary.
 func (s *StoreImpl) Fetch(asset Asset) error {
+	if err := checkCycles(asset); err != nil {
+		return err
+	}
 	if err := s.fetch(asset, ""); err != nil {
 		return err
 	}
+	if err := s.checkConflicts(); err != nil {
+		return err
+	}
 	if err := s.saveStateFile(); err != nil {
 		return errors.Wrapf(err, "failed to save state")
 	}
@@ -111,10 +141,74 @@ func (s *StoreImpl) Destroy(asset Asset) error {
 	}
 
 	delete(s.assets, reflect.TypeOf(asset))
+	delete(s.generatedFiles, reflect.TypeOf(asset))
 	delete(s.stateFileAssets, reflect.TypeOf(asset).String())
+	delete(s.stateFileAssets, generatedStatePrefix+reflect.TypeOf(asset).String())
 	return s.saveStateFile()
 }
 
+// Invalidate removes the assets in the state file for which stale returns
+// true, along with the assets depending on them, from the state and from
+// disk, so that the next Fetch generates them again.
+func (s *StoreImpl) Invalidate(stale func(Asset) bool, targets ...Asset) ([]Asset, error) {
+	for _, target := range targets {
+		if err := checkCycles(target); err != nil {
+			return nil, err
+		}
+	}
+
+	invalid := map[reflect.Type]bool{}
+	removed := []Asset{}
+	var visit func(Asset) (bool, error)
+	visit = func(a Asset) (bool, error) {
+		if dirty, ok := invalid[reflect.TypeOf(a)]; ok {
+			return dirty, nil
+		}
+
+		dirty := false
+		for _, d := range a.Dependencies() {
+			parentDirty, err := visit(d)
+			if err != nil {
+				return false, err
+			}
+			dirty = dirty || parentDirty
+		}
+		if s.isAssetInState(a) {
+			if err := s.loadAssetFromState(a); err != nil {
+				return false, err
+			}
+			if dirty || stale(a) {
+				dirty = true
+				removed = append(removed, a)
+			}
+		}
+		invalid[reflect.TypeOf(a)] = dirty
+		return dirty, nil
+	}
+	for _, target := range targets {
+		if _, err := visit(target); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, a := range removed {
+		logger.Debugf("Invalidating %q", a.Name())
+		if wa, ok := a.(WritableAsset); ok {
+			if err := deleteAssetFromDisk(wa, s.directory); err != nil {
+				return nil, err
+			}
+		}
+		delete(s.assets, reflect.TypeOf(a))
+		delete(s.generatedFiles, reflect.TypeOf(a))
+		delete(s.stateFileAssets, reflect.TypeOf(a).String())
+		delete(s.stateFileAssets, generatedStatePrefix+reflect.TypeOf(a).String())
+	}
+	if len(removed) == 0 {
+		return removed, nil
+	}
+	return removed, s.saveStateFile()
+}
+
 // loadStateFile retrieves the state from the state file present in the given directory
 // and returns the assets map
 func (s *StoreImpl) loadStateFile() error {
@@ -127,6 +221,10 @@ func (s *StoreImpl) loadStateFile() error {
 		}
 		return err
 	}
+	data, err = encryption.Decrypt(data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read state file %q", path)
+	}
 	err = json.Unmarshal(data, &assets)
 	if err != nil {
 		return errors.Wrapf(err, "failed to unmarshal state file %q", path)
@@ -165,10 +263,21 @@ func (s *StoreImpl) saveStateFile() error {
 		}
 		s.stateFileAssets[k.String()] = json.RawMessage(data)
 	}
+	for k, files := range s.generatedFiles {
+		data, err := json.MarshalIndent(files, "", "    ")
+		if err != nil {
+			return err
+		}
+		s.stateFileAssets[generatedStatePrefix+k.String()] = json.RawMessage(data)
+	}
 	data, err := json.MarshalIndent(s.stateFileAssets, "", "    ")
 	if err != nil {
 		return err
 	}
+	data, err = encryption.Encrypt(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt state file")
+	}
 
 	path := filepath.Join(s.directory, stateFileName)
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
@@ -184,7 +293,7 @@ func (s *StoreImpl) saveStateFile() error {
 // necessary, and returns whether or not the asset had to be regenerated and
 // any errors.
 func (s *StoreImpl) fetch(asset Asset, indent string) error {
-	logrus.Debugf("%sFetching %q...", indent, asset.Name())
+	logger.Debugf("%sFetching %q...", indent, asset.Name())
 
 	assetState, ok := s.assets[reflect.TypeOf(asset)]
 	if !ok {
@@ -199,7 +308,7 @@ func (s *StoreImpl) fetch(asset Asset, indent string) error {
 	// that we always fetch the parent before children, so we don't need
 	// to worry about invalidating anything in the cache.
 	if assetState.source != unfetched {
-		logrus.Debugf("%sReusing previously-fetched %q", indent, asset.Name())
+		logger.Debugf("%sReusing previously-fetched %q", indent, asset.Name())
 		reflect.ValueOf(asset).Elem().Set(reflect.ValueOf(assetState.asset).Elem())
 		return nil
 	}
@@ -213,18 +322,55 @@ func (s *StoreImpl) fetch(asset Asset, indent string) error {
 		}
 		parents.Add(d)
 	}
-	logrus.Debugf("%sGenerating %q...", indent, asset.Name())
+	logger.Debugf("%sGenerating %q...", indent, asset.Name())
 	if err := asset.Generate(parents); err != nil {
-		return errors.Wrapf(err, "failed to generate asset %q", asset.Name())
+		return failure.New(failure.AssetGeneration, "assets", "check the install config and rerun with '--log-level assets=debug' to see how the assets were generated", errors.Wrapf(err, "failed to generate asset %q", asset.Name()))
+	}
+	if wa, ok := asset.(WritableAsset); ok {
+		if s.generatedFiles == nil {
+			s.generatedFiles = map[reflect.Type][]*File{}
+		}
+		s.generatedFiles[reflect.TypeOf(asset)] = wa.Files()
+		if assetState.editedFiles != nil {
+			if err := s.mergeEdits(wa, assetState, indent); err != nil {
+				return errors.Wrapf(err, "failed to merge the edits to %q", asset.Name())
+			}
+		}
 	}
 	assetState.asset = asset
 	assetState.source = generatedSource
 	return nil
 }
 
+// mergeEdits merges the edits made in the target directory to the files of
+// the asset, which has been regenerated, and reloads the asset from the
+// merged files.
+func (s *StoreImpl) mergeEdits(asset WritableAsset, state *assetState, indent string) error {
+	merged, conflicts, err := mergeFiles(state.baseFiles, state.editedFiles, asset.Files())
+	if err != nil {
+		return err
+	}
+	for _, conflict := range conflicts {
+		logger.Warningf("%sConflicting changes to %s in the target directory and the regenerated %q", indent, conflict, asset.Name())
+	}
+	if reflect.DeepEqual(merged, asset.Files()) {
+		return nil
+	}
+
+	logger.Infof("%sMerging the edits to %q in the target directory into the regenerated asset", indent, asset.Name())
+	found, err := asset.Load(memoryFileFetcher(merged))
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("the merged files are incomplete")
+	}
+	return nil
+}
+
 // load loads the asset and all of its ancestors from on-disk and the state file.
 func (s *StoreImpl) load(asset Asset, indent string) (*assetState, error) {
-	logrus.Debugf("%sLoading %q...", indent, asset.Name())
+	logger.Debugf("%sLoading %q...", indent, asset.Name())
 
 	// Stop descent if the asset has already been loaded.
 	if state, ok := s.assets[reflect.TypeOf(asset)]; ok {
@@ -275,13 +421,13 @@ func (s *StoreImpl) load(asset Asset, indent string) (*assetState, error) {
 		}
 
 		if foundOnDisk && foundInStateFile {
-			logrus.Debugf("%sLoading %q from both state file and target directory", indent, asset.Name())
+			logger.Debugf("%sLoading %q from both state file and target directory", indent, asset.Name())
 
 			// If the on-disk asset is the same as the one in the state file, there
 			// is no need to consider the one on disk and to mark the asset dirty.
 			onDiskMatchesStateFile = reflect.DeepEqual(onDiskAsset, stateFileAsset)
 			if onDiskMatchesStateFile {
-				logrus.Debugf("%sOn-disk %q matches asset in state file", indent, asset.Name())
+				logger.Debugf("%sOn-disk %q matches asset in state file", indent, asset.Name())
 			}
 		}
 	}
@@ -290,22 +436,30 @@ func (s *StoreImpl) load(asset Asset, indent string) (*assetState, error) {
 		assetToStore Asset
 		source       assetSource
 	)
+	var editedFiles, baseFiles []*File
 	switch {
-	// A parent is dirty. The asset must be re-generated.
+	// A parent is dirty. The asset must be re-generated, and any edits
+	// made to it on disk merged into it.
 	case anyParentsDirty:
 		if foundOnDisk {
-			logrus.Warningf("%sDiscarding the %q that was provided in the target directory because its dependencies are dirty and it needs to be regenerated", indent, asset.Name())
+			baseFiles = s.lastGeneratedFiles(asset)
+			if baseFiles == nil {
+				logger.Warningf("%sDiscarding the %q that was provided in the target directory because its dependencies are dirty and it needs to be regenerated", indent, asset.Name())
+			} else if !reflect.DeepEqual(onDiskAsset.Files(), baseFiles) {
+				logger.Debugf("%sMerging the %q in the target directory into the regenerated asset", indent, asset.Name())
+				editedFiles = onDiskAsset.Files()
+			}
 		}
 		source = unfetched
 	// The asset is on disk and that differs from what is in the source file.
 	// The asset is sourced from on disk.
 	case foundOnDisk && !onDiskMatchesStateFile:
-		logrus.Debugf("%sUsing %q loaded from target directory", indent, asset.Name())
+		logger.Debugf("%sUsing %q loaded from target directory", indent, asset.Name())
 		assetToStore = onDiskAsset
 		source = onDiskSource
 	// The asset is in the state file. The asset is sourced from state file.
 	case foundInStateFile:
-		logrus.Debugf("%sUsing %q loaded from state file", indent, asset.Name())
+		logger.Debugf("%sUsing %q loaded from state file", indent, asset.Name())
 		assetToStore = stateFileAsset
 		source = stateFileSource
 	// There is no existing source for the asset. The asset will be generated.
@@ -318,11 +472,34 @@ func (s *StoreImpl) load(asset Asset, indent string) (*assetState, error) {
 		source:          source,
 		anyParentsDirty: anyParentsDirty,
 		presentOnDisk:   foundOnDisk,
+		editedFiles:     editedFiles,
+		baseFiles:       baseFiles,
 	}
 	s.assets[reflect.TypeOf(asset)] = state
 	return state, nil
 }
 
+// lastGeneratedFiles returns the files the writable asset had when it was
+// last generated, or nil if it was never generated. State files written
+// before the generated files were recorded only have the asset as last
+// fetched.
+func (s *StoreImpl) lastGeneratedFiles(asset Asset) []*File {
+	if data, ok := s.stateFileAssets[generatedStatePrefix+reflect.TypeOf(asset).String()]; ok {
+		var files []*File
+		if err := json.Unmarshal(data, &files); err == nil {
+			return files
+		}
+	}
+	if !s.isAssetInState(asset) {
+		return nil
+	}
+	stateFileAsset := reflect.New(reflect.TypeOf(asset).Elem()).Interface().(WritableAsset)
+	if err := s.loadAssetFromState(stateFileAsset); err != nil {
+		return nil
+	}
+	return stateFileAsset.Files()
+}
+
 // purge deletes the on-disk assets that are consumed already.
 // E.g., install-config.yml will be deleted after fetching 'manifests'.
 // The target asset is excluded.
@@ -334,7 +511,7 @@ func (s *StoreImpl) purge(excluded WritableAsset) error {
 		if reflect.TypeOf(assetState.asset) == reflect.TypeOf(excluded) {
 			continue
 		}
-		logrus.Infof("Consuming %q from target directory", assetState.asset.Name())
+		logger.Infof("Consuming %q from target directory", assetState.asset.Name())
 		if err := deleteAssetFromDisk(assetState.asset.(WritableAsset), s.directory); err != nil {
 			return err
 		}
@@ -1,6 +1,7 @@
 package asset
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"os"
 	"reflect"
@@ -16,12 +17,14 @@ var (
 	generationLog []string
 	dependencies  map[reflect.Type][]Asset
 	onDiskAssets  map[reflect.Type]bool
+	fileNames     map[reflect.Type]string
 )
 
 func clearAssetBehaviors() {
 	generationLog = []string{}
 	dependencies = map[reflect.Type][]Asset{}
 	onDiskAssets = map[reflect.Type]bool{}
+	fileNames = map[reflect.Type]string{}
 }
 
 func dependenciesTestStoreAsset(a Asset) []Asset {
@@ -34,6 +37,9 @@ func generateTestStoreAsset(a Asset) error {
 }
 
 func fileTestStoreAsset(a Asset) []*File {
+	if name, ok := fileNames[reflect.TypeOf(a)]; ok {
+		return []*File{{Filename: name}}
+	}
 	return []*File{{Filename: a.Name()}}
 }
 
@@ -291,6 +297,96 @@ func TestStoreFetch(t *testing.T) {
 	}
 }
 
+func TestStoreFetchGraphErrors(t *testing.T) {
+	cases := []struct {
+		name          string
+		assets        map[string][]string
+		fileNames     map[string]string
+		target        string
+		expectedError string
+	}{
+		{
+			name: "cycle",
+			assets: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {"b"},
+			},
+			target:        "a",
+			expectedError: `dependency cycle: b -> c -> b`,
+		},
+		{
+			name: "self-dependency",
+			assets: map[string][]string{
+				"a": {"a"},
+			},
+			target:        "a",
+			expectedError: `dependency cycle: a -> a`,
+		},
+		{
+			name: "conflicting files",
+			assets: map[string][]string{
+				"a": {"b", "c"},
+				"b": {},
+				"c": {},
+			},
+			fileNames: map[string]string{
+				"b": "shared",
+				"c": "shared",
+			},
+			target:        "a",
+			expectedError: `assets "b" and "c" both write shared`,
+		},
+		{
+			name: "file of a dependency",
+			assets: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {},
+			},
+			fileNames: map[string]string{
+				"a": "shared",
+				"c": "shared",
+			},
+			target: "a",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clearAssetBehaviors()
+			dir, err := ioutil.TempDir("", "TestStoreFetchGraphErrors")
+			if err != nil {
+				t.Fatalf("failed to create temporary directory: %v", err)
+			}
+			defer os.RemoveAll(dir)
+			store := &StoreImpl{
+				directory: dir,
+				assets:    map[reflect.Type]*assetState{},
+			}
+			assets := make(map[string]Asset, len(tc.assets))
+			for name := range tc.assets {
+				assets[name] = newTestStoreAsset(name)
+			}
+			for name, deps := range tc.assets {
+				dependenciesOfAsset := make([]Asset, len(deps))
+				for i, d := range deps {
+					dependenciesOfAsset[i] = assets[d]
+				}
+				dependencies[reflect.TypeOf(assets[name])] = dependenciesOfAsset
+			}
+			for name, fileName := range tc.fileNames {
+				fileNames[reflect.TypeOf(assets[name])] = fileName
+			}
+			err = store.Fetch(assets[tc.target])
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
 func TestStoreFetchOnDiskAssets(t *testing.T) {
 	cases := []struct {
 		name                  string
@@ -385,3 +481,125 @@ func TestStoreFetchOnDiskAssets(t *testing.T) {
 		})
 	}
 }
+
+func TestStoreInvalidate(t *testing.T) {
+	cases := []struct {
+		name            string
+		assets          map[string][]string
+		stateFileAssets []string
+		stale           string
+		target          string
+		expectedRemoved []string
+	}{
+		{
+			name: "nothing stale",
+			assets: map[string][]string{
+				"a": {"b"},
+				"b": {},
+			},
+			stateFileAssets: []string{"a", "b"},
+			stale:           "c",
+			target:          "a",
+			expectedRemoved: []string{},
+		},
+		{
+			name: "stale target",
+			assets: map[string][]string{
+				"a": {"b"},
+				"b": {},
+			},
+			stateFileAssets: []string{"a", "b"},
+			stale:           "a",
+			target:          "a",
+			expectedRemoved: []string{"a"},
+		},
+		{
+			name: "stale dependency invalidates its children",
+			assets: map[string][]string{
+				"a": {"b", "c"},
+				"b": {"d"},
+				"c": {},
+				"d": {},
+			},
+			stateFileAssets: []string{"a", "b", "c", "d"},
+			stale:           "d",
+			target:          "a",
+			expectedRemoved: []string{"d", "b", "a"},
+		},
+		{
+			name: "stale dependency invalidates children through assets not in the state file",
+			assets: map[string][]string{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {},
+			},
+			stateFileAssets: []string{"a", "c"},
+			stale:           "c",
+			target:          "a",
+			expectedRemoved: []string{"c", "a"},
+		},
+		{
+			name: "assets outside the target are kept",
+			assets: map[string][]string{
+				"a": {"c"},
+				"b": {"c"},
+				"c": {},
+			},
+			stateFileAssets: []string{"a", "b", "c"},
+			stale:           "c",
+			target:          "a",
+			expectedRemoved: []string{"c", "a"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clearAssetBehaviors()
+			dir, err := ioutil.TempDir("", "TestStoreInvalidate")
+			if err != nil {
+				t.Fatalf("failed to create temporary directory: %v", err)
+			}
+			defer os.RemoveAll(dir)
+			store := &StoreImpl{
+				directory:       dir,
+				assets:          map[reflect.Type]*assetState{},
+				stateFileAssets: map[string]json.RawMessage{},
+			}
+			assets := make(map[string]Asset, len(tc.assets))
+			for name := range tc.assets {
+				assets[name] = newTestStoreAsset(name)
+			}
+			for name, deps := range tc.assets {
+				dependenciesOfAsset := make([]Asset, len(deps))
+				for i, d := range deps {
+					dependenciesOfAsset[i] = assets[d]
+				}
+				dependencies[reflect.TypeOf(assets[name])] = dependenciesOfAsset
+			}
+			for _, name := range tc.stateFileAssets {
+				store.stateFileAssets[reflect.TypeOf(assets[name]).String()] = json.RawMessage("{}")
+			}
+
+			removed, err := store.Invalidate(func(a Asset) bool {
+				return a.Name() == tc.stale
+			}, assets[tc.target])
+			assert.NoError(t, err, "unexpected error")
+			removedNames := make([]string, len(removed))
+			for i, a := range removed {
+				removedNames[i] = a.Name()
+			}
+			assert.Equal(t, tc.expectedRemoved, removedNames)
+			for _, name := range tc.stateFileAssets {
+				assert.Equal(t, !contains(tc.expectedRemoved, name), store.isAssetInState(assets[name]), "asset %q in state file", name)
+			}
+		})
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
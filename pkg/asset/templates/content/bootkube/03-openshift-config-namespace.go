@@ -0,0 +1,65 @@
+package bootkube
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/templates/content"
+)
+
+const (
+	openshiftConfigNamespaceFileName = "03-openshift-config-namespace.yaml"
+)
+
+var _ asset.WritableAsset = (*OpenshiftConfigNamespace)(nil)
+
+// OpenshiftConfigNamespace is the constant to represent contents of 03-openshift-config-namespace.yaml file
+type OpenshiftConfigNamespace struct {
+	fileName string
+	FileList []*asset.File
+}
+
+// Dependencies returns all of the dependencies directly needed by the asset
+func (t *OpenshiftConfigNamespace) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Name returns the human-friendly name of the asset.
+func (t *OpenshiftConfigNamespace) Name() string {
+	return "OpenshiftConfigNamespace"
+}
+
+// Generate generates the actual files by this asset
+func (t *OpenshiftConfigNamespace) Generate(parents asset.Parents) error {
+	t.fileName = openshiftConfigNamespaceFileName
+	data, err := content.GetBootkubeTemplate(t.fileName)
+	if err != nil {
+		return err
+	}
+	t.FileList = []*asset.File{
+		{
+			Filename: filepath.Join(content.TemplateDir, t.fileName),
+			Data:     []byte(data),
+		},
+	}
+	return nil
+}
+
+// Files returns the files generated by the asset.
+func (t *OpenshiftConfigNamespace) Files() []*asset.File {
+	return t.FileList
+}
+
+// Load returns the asset from disk.
+func (t *OpenshiftConfigNamespace) Load(f asset.FileFetcher) (bool, error) {
+	file, err := f.FetchByName(filepath.Join(content.TemplateDir, openshiftConfigNamespaceFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	t.FileList = []*asset.File{file}
+	return true, nil
+}
@@ -58,17 +58,17 @@ func (c *CertKey) Generate(
 	var crt *x509.Certificate
 	var err error
 
-	caKey, err := PemToPrivateKey(parentCA.Key())
+	caCert, err := PemToCertificate(parentCA.Cert())
 	if err != nil {
-		return errors.Wrap(err, "failed to parse rsa private key")
+		return errors.Wrap(err, "failed to parse x509 certificate")
 	}
 
-	caCert, err := PemToCertificate(parentCA.Cert())
+	caKey, err := caSigner(parentCA, caCert)
 	if err != nil {
-		return errors.Wrap(err, "failed to parse x509 certificate")
+		return err
 	}
 
-	key, crt, err = GenerateCert(caKey, caCert, cfg)
+	key, crt, err = GenerateCert(caKey, caCert, cfg, filenameBase)
 	if err != nil {
 		return errors.Wrap(err, "failed to generate cert/key pair")
 	}
@@ -91,16 +91,18 @@ func (c *CertKey) Files() []*asset.File {
 }
 
 func (c *CertKey) generateFiles(filenameBase string) {
-	c.FileList = []*asset.File{
-		{
+	c.FileList = []*asset.File{}
+	// Keys held by an external signer are not written.
+	if len(c.KeyRaw) > 0 {
+		c.FileList = append(c.FileList, &asset.File{
 			Filename: assetFilePath(filenameBase + ".key"),
 			Data:     c.KeyRaw,
-		},
-		{
-			Filename: assetFilePath(filenameBase + ".crt"),
-			Data:     c.CertRaw,
-		},
+		})
 	}
+	c.FileList = append(c.FileList, &asset.File{
+		Filename: assetFilePath(filenameBase + ".crt"),
+		Data:     c.CertRaw,
+	})
 }
 
 // Load is a no-op because TLS assets are not written to disk.
@@ -0,0 +1,146 @@
+package tls
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// awsKMSSigner signs with an asymmetric AWS KMS key.  The SDK vendored here
+// predates asymmetric keys, so it calls the KMS API directly.
+type awsKMSSigner struct {
+	keyARN    string
+	region    string
+	endpoint  string
+	signer    *v4.Signer
+	client    *http.Client
+	publicKey crypto.PublicKey
+}
+
+var _ crypto.Signer = (*awsKMSSigner)(nil)
+
+func newAWSKMSSigner(keyARN string) (*awsKMSSigner, error) {
+	// arn:<partition>:kms:<region>:<account>:key/<key ID>
+	fields := strings.SplitN(keyARN, ":", 6)
+	if len(fields) != 6 || fields[0] != "arn" || fields[2] != "kms" || fields[3] == "" {
+		return nil, errors.Errorf("invalid KMS key ARN %q", keyARN)
+	}
+
+	ssn, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            aws.Config{Region: aws.String(fields[3])},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create AWS session")
+	}
+	config := ssn.ClientConfig("kms")
+
+	s := &awsKMSSigner{
+		keyARN:   keyARN,
+		region:   config.SigningRegion,
+		endpoint: config.Endpoint,
+		signer:   v4.NewSigner(ssn.Config.Credentials),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+
+	var response struct {
+		PublicKey []byte
+		KeyUsage  string
+	}
+	if err := s.do("GetPublicKey", map[string]interface{}{"KeyId": keyARN}, &response); err != nil {
+		return nil, errors.Wrap(err, "failed to get the public key")
+	}
+	if response.KeyUsage != "SIGN_VERIFY" {
+		return nil, errors.Errorf("%s is not a signing key", keyARN)
+	}
+	s.publicKey, err = x509.ParsePKIXPublicKey(response.PublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the public key")
+	}
+	return s, nil
+}
+
+// Public returns the KMS key's public key.
+func (s *awsKMSSigner) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the digest with the KMS key.
+func (s *awsKMSSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	_, hash, err := signerHash(opts)
+	if err != nil {
+		return nil, err
+	}
+	var algorithm string
+	switch s.publicKey.(type) {
+	case *rsa.PublicKey:
+		algorithm = "RSASSA_PKCS1_V1_5_SHA_" + strings.TrimPrefix(hash, "sha")
+	case *ecdsa.PublicKey:
+		algorithm = "ECDSA_SHA_" + strings.TrimPrefix(hash, "sha")
+	default:
+		return nil, errors.Errorf("unsupported public key %T", s.publicKey)
+	}
+
+	var response struct {
+		Signature []byte
+	}
+	err = s.do("Sign", map[string]interface{}{
+		"KeyId":            s.keyARN,
+		"Message":          digest,
+		"MessageType":      "DIGEST",
+		"SigningAlgorithm": algorithm,
+	}, &response)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to sign with %s", s.keyARN)
+	}
+	return response.Signature, nil
+}
+
+// do calls a KMS API action and decodes its response into out.
+func (s *awsKMSSigner) do(action string, in interface{}, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", s.endpoint, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to build request")
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	if _, err := s.signer.Sign(req, bytes.NewReader(body), "kms", s.region, time.Now()); err != nil {
+		return errors.Wrap(err, "failed to sign request")
+	}
+
+	logrus.WithField("component", "http").Debugf("Calling %s for %s", action, s.keyARN)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&failure)
+		if failure.Type != "" {
+			return errors.Errorf("incorrect HTTP response (%s): %s: %s", resp.Status, failure.Type, failure.Message)
+		}
+		return errors.Errorf("incorrect HTTP response (%s)", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
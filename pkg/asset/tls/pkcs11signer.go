@@ -0,0 +1,142 @@
+package tls
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// PKCS11PINEnvVar names the environment variable holding the user PIN of
+// the PKCS #11 token, if it needs one.
+const PKCS11PINEnvVar = "OPENSHIFT_INSTALL_PKCS11_PIN"
+
+// digestInfoPrefixes are the DER-encoded DigestInfo prefixes which PKCS #1
+// v1.5 signatures wrap digests in.
+var digestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// pkcs11Signer signs with a key in an HSM or smart card through OpenSC's
+// pkcs11-tool, which loads the token's PKCS #11 module, so the installer
+// does not have to link against it.
+type pkcs11Signer struct {
+	args      []string
+	publicKey crypto.PublicKey
+}
+
+var _ crypto.Signer = (*pkcs11Signer)(nil)
+
+// newPKCS11Signer takes the path attributes of a PKCS #11 URI (RFC 7512),
+// e.g. 'module-path=/usr/lib64/pkcs11/libsofthsm2.so;id=%01;token=openshift'.
+func newPKCS11Signer(uri string) (*pkcs11Signer, error) {
+	attributes := map[string]string{}
+	for _, attribute := range strings.Split(strings.SplitN(uri, "?", 2)[0], ";") {
+		kv := strings.SplitN(attribute, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.Errorf("invalid PKCS #11 URI attribute %q", attribute)
+		}
+		value, err := url.PathUnescape(kv[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid PKCS #11 URI attribute %q", attribute)
+		}
+		attributes[kv[0]] = value
+	}
+	if attributes["module-path"] == "" || attributes["id"] == "" {
+		return nil, errors.New("the PKCS #11 URI must have a module-path and an id")
+	}
+
+	s := &pkcs11Signer{
+		args: []string{"--module", attributes["module-path"], "--id", hex.EncodeToString([]byte(attributes["id"]))},
+	}
+	if token := attributes["token"]; token != "" {
+		s.args = append(s.args, "--token-label", token)
+	}
+
+	out, err := s.run(nil, "--read-object", "--type", "pubkey")
+	if err != nil {
+		return nil, err
+	}
+	s.publicKey, err = x509.ParsePKIXPublicKey(out)
+	if err != nil {
+		// Older versions of pkcs11-tool print RSA public keys in PKCS #1.
+		var rsaErr error
+		s.publicKey, rsaErr = x509.ParsePKCS1PublicKey(out)
+		if rsaErr != nil {
+			return nil, errors.Wrap(err, "failed to parse the public key")
+		}
+	}
+	return s, nil
+}
+
+// Public returns the token key's public key.
+func (s *pkcs11Signer) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the digest with the token's key.
+func (s *pkcs11Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	hash, _, err := signerHash(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"--sign"}
+	switch s.publicKey.(type) {
+	case *rsa.PublicKey:
+		// The RSA-PKCS mechanism pads, but does not wrap, the input.
+		digest = append(append([]byte{}, digestInfoPrefixes[hash]...), digest...)
+		args = append(args, "--mechanism", "RSA-PKCS")
+	case *ecdsa.PublicKey:
+		args = append(args, "--mechanism", "ECDSA", "--signature-format", "openssl")
+	default:
+		return nil, errors.Errorf("unsupported public key %T", s.publicKey)
+	}
+
+	// Without --pin, pkcs11-tool reads the PIN from stdin, which keeps it
+	// out of the process list, so the digest is passed in a file instead.
+	input, err := ioutil.TempFile("", "openshift-install-digest-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the digest file")
+	}
+	defer os.Remove(input.Name())
+	_, err = input.Write(digest)
+	if closeErr := input.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write the digest file")
+	}
+	args = append(args, "--input-file", input.Name())
+
+	var stdin []byte
+	if pin := os.Getenv(PKCS11PINEnvVar); pin != "" {
+		args = append(args, "--login")
+		stdin = []byte(pin + "\n")
+	}
+	return s.run(stdin, args...)
+}
+
+func (s *pkcs11Signer) run(stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command("pkcs11-tool", append(append([]string{}, s.args...), args...)...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to run pkcs11-tool %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,68 @@
+package tls
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/reproducible"
+)
+
+// generateFiles generates assets in order and returns the files they
+// produce, keyed by name. Each asset's dependencies must come before it.
+func generateFiles(t *testing.T, assets ...asset.WritableAsset) map[string][]byte {
+	reproducible.Reset()
+	parents := asset.Parents{}
+	files := map[string][]byte{}
+	for _, a := range assets {
+		if err := a.Generate(parents); err != nil {
+			t.Fatalf("failed to generate %s: %v", a.Name(), err)
+		}
+		parents.Add(a)
+		for _, f := range a.Files() {
+			files[f.Filename] = f.Data
+		}
+	}
+	return files
+}
+
+func TestReproducibleAssets(t *testing.T) {
+	os.Setenv(reproducible.SeedEnvVar, "seed")
+	defer os.Unsetenv(reproducible.SeedEnvVar)
+	os.Setenv(reproducible.SourceDateEpochEnvVar, "1546300800")
+	defer os.Unsetenv(reproducible.SourceDateEpochEnvVar)
+
+	first := generateFiles(t,
+		&RootCA{},
+		&KubeCA{},
+		&EtcdCA{},
+		&AggregatorCA{},
+		&ServiceServingCA{},
+		&AdminCertKey{},
+		&KubeletCertKey{},
+		&EtcdClientCertKey{},
+		&APIServerProxyCertKey{},
+		&ServiceAccountKeyPair{},
+	)
+	second := generateFiles(t,
+		&ServiceAccountKeyPair{},
+		&RootCA{},
+		&AggregatorCA{},
+		&APIServerProxyCertKey{},
+		&EtcdCA{},
+		&EtcdClientCertKey{},
+		&ServiceServingCA{},
+		&KubeCA{},
+		&KubeletCertKey{},
+		&AdminCertKey{},
+	)
+	assert.Equal(t, first, second)
+
+	os.Setenv(reproducible.SeedEnvVar, "other-seed")
+	other := generateFiles(t, &ServiceAccountKeyPair{})
+	for name, data := range other {
+		assert.NotEqual(t, first[name], data, name)
+	}
+}
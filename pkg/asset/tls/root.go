@@ -9,7 +9,8 @@ import (
 )
 
 // RootCA contains the private key and the cert that's
-// self-signed as the root CA.
+// self-signed as the root CA.  With an external signer (see
+// RootCASignerEnvVar), it holds no private key.
 type RootCA struct {
 	CertKey
 }
@@ -30,7 +31,22 @@ func (c *RootCA) Generate(parents asset.Parents) error {
 		IsCA:      true,
 	}
 
-	key, crt, err := GenerateRootCertKey(cfg)
+	signer, err := rootCASigner()
+	if err != nil {
+		return err
+	}
+	if signer != nil {
+		crt, err := GenerateRootCA(signer, cfg)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate RootCA")
+		}
+		c.KeyRaw = nil
+		c.CertRaw = CertToPem(crt)
+		c.generateFiles("root-ca")
+		return nil
+	}
+
+	key, crt, err := GenerateRootCertKey(cfg, "root-ca")
 	if err != nil {
 		return errors.Wrap(err, "failed to generate RootCA")
 	}
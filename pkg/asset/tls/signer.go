@@ -0,0 +1,167 @@
+package tls
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// RootCASignerEnvVar names the environment variable configuring an
+	// external signer for the root CA, so its private key never exists on
+	// the installer host.  It takes one of:
+	//
+	//   awskms:<key ARN>
+	//   pkcs11:module-path=<module>;id=<key ID>[;token=<label>]
+	//   exec:<command>
+	//
+	// The root CA's certificate is self-signed, and the CAs below it are
+	// signed, by the external signer.
+	RootCASignerEnvVar = "OPENSHIFT_INSTALL_ROOT_CA_SIGNER"
+)
+
+// rootCASigner returns the external signer configured for the root CA, or
+// nil if there is none.
+func rootCASigner() (crypto.Signer, error) {
+	spec := os.Getenv(RootCASignerEnvVar)
+	if spec == "" {
+		return nil, nil
+	}
+
+	var signer crypto.Signer
+	var err error
+	kind := strings.SplitN(spec, ":", 2)
+	if len(kind) != 2 || kind[1] == "" {
+		return nil, errors.Errorf("invalid %s %q; must be awskms:<key ARN>, pkcs11:<URI> or exec:<command>", RootCASignerEnvVar, spec)
+	}
+	switch kind[0] {
+	case "awskms":
+		signer, err = newAWSKMSSigner(kind[1])
+	case "pkcs11":
+		signer, err = newPKCS11Signer(kind[1])
+	case "exec":
+		signer, err = newExecSigner(kind[1])
+	default:
+		return nil, errors.Errorf("unsupported %s %q; must be awskms, pkcs11 or exec", RootCASignerEnvVar, kind[0])
+	}
+	return signer, errors.Wrapf(err, "failed to set up the %s root CA signer", kind[0])
+}
+
+// caSigner returns the signer for a parent CA: its private key, or the
+// external root CA signer if its key is not held by the installer.
+func caSigner(ca CertKeyInterface, caCert *x509.Certificate) (crypto.Signer, error) {
+	if len(ca.Key()) > 0 {
+		key, err := PemToPrivateKey(ca.Key())
+		return key, errors.Wrap(err, "failed to parse rsa private key")
+	}
+
+	signer, err := rootCASigner()
+	if err != nil {
+		return nil, err
+	}
+	if signer == nil {
+		return nil, errors.Errorf("the CA's private key is held by an external signer; set %s to sign with it", RootCASignerEnvVar)
+	}
+	if !samePublicKey(signer.Public(), caCert.PublicKey) {
+		return nil, errors.Errorf("the key of the %s signer does not match the CA's certificate", RootCASignerEnvVar)
+	}
+	return signer, nil
+}
+
+func samePublicKey(a, b crypto.PublicKey) bool {
+	aDER, err := x509.MarshalPKIXPublicKey(a)
+	if err != nil {
+		return false
+	}
+	bDER, err := x509.MarshalPKIXPublicKey(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(aDER, bDER)
+}
+
+// hashNames are the names of the digests the external signers sign.
+var hashNames = map[crypto.Hash]string{
+	crypto.SHA256: "sha256",
+	crypto.SHA384: "sha384",
+	crypto.SHA512: "sha512",
+}
+
+// signerHash returns the name of the digest to sign, rejecting the options
+// the external signers do not support.
+func signerHash(opts crypto.SignerOpts) (crypto.Hash, string, error) {
+	if _, ok := opts.(*rsa.PSSOptions); ok {
+		return 0, "", errors.New("RSA-PSS signatures are not supported")
+	}
+	hash := opts.HashFunc()
+	name, ok := hashNames[hash]
+	if !ok {
+		return 0, "", errors.Errorf("unsupported digest %v", hash)
+	}
+	return hash, name, nil
+}
+
+// execSigner signs with a command, so any HSM or KMS can be used through a
+// wrapper script.  '<command> public-key' must print the PEM-encoded public
+// key, and '<command> sign <sha256|sha384|sha512>' must read the digest on
+// its standard input and write the PKCS #1 v1.5 (RSA) or ASN.1 (ECDSA)
+// signature to its standard output.
+type execSigner struct {
+	command   string
+	publicKey crypto.PublicKey
+}
+
+var _ crypto.Signer = (*execSigner)(nil)
+
+func newExecSigner(command string) (*execSigner, error) {
+	s := &execSigner{command: command}
+	out, err := s.run(nil, "public-key")
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(out)
+	if block == nil {
+		return nil, errors.New("'public-key' did not print a PEM-encoded public key")
+	}
+	s.publicKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the public key")
+	}
+	return s, nil
+}
+
+// Public returns the signer's public key.
+func (s *execSigner) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the digest with the command.
+func (s *execSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	_, hash, err := signerHash(opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(digest, "sign", hash)
+}
+
+func (s *execSigner) run(stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command("/bin/sh", append([]string{"-c", s.command + ` "$@"`, "sh"}, args...)...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to run '%s %s'", s.command, strings.Join(args, " "))
+	}
+	if len(out) == 0 {
+		return nil, errors.Errorf("'%s %s' printed nothing", s.command, strings.Join(args, " "))
+	}
+	return out, nil
+}
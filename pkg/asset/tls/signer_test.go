@@ -0,0 +1,152 @@
+package tls
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalRootCASigner(t *testing.T) {
+	if _, err := exec.LookPath("openssl"); err != nil {
+		t.Skip("openssl is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "openshift-install-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, err := PrivateKey("root-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(dir, "root-ca.key")
+	if err := ioutil.WriteFile(keyPath, PrivateKeyToPem(key), 0600); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(dir, "signer.sh")
+	if err := ioutil.WriteFile(script, []byte(`#!/bin/sh
+case "$1" in
+public-key) exec openssl pkey -in `+keyPath+` -pubout ;;
+sign) exec openssl pkeyutl -sign -inkey `+keyPath+` -pkeyopt digest:$2 ;;
+esac
+exit 1
+`), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv(RootCASignerEnvVar, "exec:"+script)
+	defer os.Unsetenv(RootCASignerEnvVar)
+
+	rootCA := &RootCA{}
+	if !assert.NoError(t, rootCA.Generate(nil)) {
+		return
+	}
+	assert.Empty(t, rootCA.Key(), "root CA has a private key")
+	if assert.Len(t, rootCA.Files(), 1) {
+		assert.Equal(t, "tls/root-ca.crt", rootCA.Files()[0].Filename)
+	}
+	rootCert, err := PemToCertificate(rootCA.Cert())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, samePublicKey(key.Public(), rootCert.PublicKey), "root CA is not for the signer's key")
+
+	cfg := &CertCfg{
+		Subject:   pkix.Name{CommonName: "test-ca", OrganizationalUnit: []string{"openshift"}},
+		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  ValidityTenYears,
+		IsCA:      true,
+	}
+	certKey := &CertKey{}
+	if !assert.NoError(t, certKey.Generate(cfg, rootCA, "test-ca", DoNotAppendParent)) {
+		return
+	}
+	cert, err := PemToCertificate(certKey.Cert())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, cert.CheckSignatureFrom(rootCert))
+
+	os.Unsetenv(RootCASignerEnvVar)
+	err = certKey.Generate(cfg, rootCA, "test-ca", DoNotAppendParent)
+	assert.EqualError(t, err, "the CA's private key is held by an external signer; set OPENSHIFT_INSTALL_ROOT_CA_SIGNER to sign with it")
+
+	os.Setenv(RootCASignerEnvVar, "vault:transit")
+	err = certKey.Generate(cfg, rootCA, "test-ca", DoNotAppendParent)
+	assert.EqualError(t, err, `unsupported OPENSHIFT_INSTALL_ROOT_CA_SIGNER "vault"; must be awskms, pkcs11 or exec`)
+}
+
+func TestPKCS11SignerPIN(t *testing.T) {
+	if _, err := exec.LookPath("openssl"); err != nil {
+		t.Skip("openssl is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "openshift-install-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, err := PrivateKey("root-ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(dir, "root-ca.key")
+	if err := ioutil.WriteFile(keyPath, PrivateKeyToPem(key), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// The fake pkcs11-tool records its arguments and stdin, and signs the
+	// --input-file with the key.
+	if err := ioutil.WriteFile(filepath.Join(dir, "pkcs11-tool"), []byte(`#!/bin/sh
+echo "$@" >> `+dir+`/args
+case " $* " in
+*" --read-object "*) exec openssl pkey -in `+keyPath+` -pubout -outform DER ;;
+*" --sign "*)
+	cat > `+dir+`/stdin
+	while [ $# -gt 0 ]; do
+		if [ "$1" = --input-file ]; then input=$2; fi
+		shift
+	done
+	exec openssl pkeyutl -sign -inkey `+keyPath+` -in "$input" ;;
+esac
+exit 1
+`), 0700); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	os.Setenv(PKCS11PINEnvVar, "123456")
+	defer os.Unsetenv(PKCS11PINEnvVar)
+
+	signer, err := newPKCS11Signer("module-path=/usr/lib64/pkcs11/libsofthsm2.so;id=%01")
+	if !assert.NoError(t, err) {
+		return
+	}
+	digest := sha256.Sum256([]byte("data"))
+	signature, err := signer.Sign(nil, digest[:], crypto.SHA256)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+	args, err := ioutil.ReadFile(filepath.Join(dir, "args"))
+	if assert.NoError(t, err) {
+		assert.NotContains(t, string(args), "123456")
+		assert.Contains(t, string(args), "--login")
+	}
+	stdin, err := ioutil.ReadFile(filepath.Join(dir, "stdin"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "123456\n", string(stdin))
+	}
+}
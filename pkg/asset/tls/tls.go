@@ -10,12 +10,15 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"io"
 	"math"
 	"math/big"
 	"net"
 	"time"
 
 	"github.com/pkg/errors"
+
+	"github.com/openshift/installer/pkg/reproducible"
 )
 
 const (
@@ -27,6 +30,11 @@ const (
 	// ValidityThirtyMinutes sets the validity of a cert to 30 minutes.
 	// This is for the kubelet bootstrap.
 	ValidityThirtyMinutes = time.Minute * 30
+
+	// randomPurpose prefixes the reproducible streams used for TLS assets.
+	// Each asset reads its own stream, so its key and serial do not depend
+	// on the order in which the assets are generated.
+	randomPurpose = "tls/"
 )
 
 // CertCfg contains all needed fields to configure a new certificate
@@ -46,9 +54,16 @@ type rsaPublicKey struct {
 	E int
 }
 
-// PrivateKey generates an RSA Private key and returns the value
-func PrivateKey() (*rsa.PrivateKey, error) {
-	rsaKey, err := rsa.GenerateKey(rand.Reader, keySize)
+// PrivateKey generates an RSA Private key and returns the value. With a
+// seed, the key is derived from the stream for purpose.
+func PrivateKey(purpose string) (*rsa.PrivateKey, error) {
+	var rsaKey *rsa.PrivateKey
+	var err error
+	if reproducible.Seed() == "" {
+		rsaKey, err = rsa.GenerateKey(rand.Reader, keySize)
+	} else {
+		rsaKey, err = seededPrivateKey(reproducible.Reader(randomPurpose+purpose), keySize)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "error generating RSA private key")
 	}
@@ -56,16 +71,86 @@ func PrivateKey() (*rsa.PrivateKey, error) {
 	return rsaKey, nil
 }
 
-// SelfSignedCACert Creates a self signed CA certificate
-func SelfSignedCACert(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate, error) {
-	var err error
+// seededPrivateKey derives an RSA key from random. rsa.GenerateKey cannot
+// be used for this because it does not read from random deterministically.
+func seededPrivateKey(random io.Reader, bits int) (*rsa.PrivateKey, error) {
+	e := big.NewInt(65537)
+	one := big.NewInt(1)
+	for {
+		p, err := seededPrime(random, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := seededPrime(random, bits-bits/2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		totient := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+		d := new(big.Int).ModInverse(e, totient)
+		if d == nil {
+			continue
+		}
+
+		key := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{N: new(big.Int).Mul(p, q), E: int(e.Int64())},
+			D:         d,
+			Primes:    []*big.Int{p, q},
+		}
+		key.Precompute()
+		return key, key.Validate()
+	}
+}
+
+// seededPrime reads candidates from random until it finds a prime of the
+// given length. As with crypto/rand.Prime, the top two bits are set so the
+// product of two such primes has twice their length.
+func seededPrime(random io.Reader, bits int) (*big.Int, error) {
+	b := uint(bits % 8)
+	if b == 0 {
+		b = 8
+	}
+	bytes := make([]byte, (bits+7)/8)
+	p := new(big.Int)
+	for {
+		if _, err := io.ReadFull(random, bytes); err != nil {
+			return nil, err
+		}
+		bytes[0] &= uint8(int(1<<b) - 1)
+		if b >= 2 {
+			bytes[0] |= 3 << (b - 2)
+		} else {
+			bytes[0] |= 1
+			if len(bytes) > 1 {
+				bytes[1] |= 0x80
+			}
+		}
+		bytes[len(bytes)-1] |= 1
+
+		p.SetBytes(bytes)
+		if p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
+
+// SelfSignedCACert Creates a self signed CA certificate. The key may be
+// held by an external signer.
+func SelfSignedCACert(cfg *CertCfg, key crypto.Signer) (*x509.Certificate, error) {
+	now, err := reproducible.Now()
+	if err != nil {
+		return nil, err
+	}
 
 	cert := x509.Certificate{
 		BasicConstraintsValid: true,
 		IsCA:         cfg.IsCA,
 		KeyUsage:     cfg.KeyUsages,
-		NotAfter:     time.Now().Add(cfg.Validity),
-		NotBefore:    time.Now(),
+		NotAfter:     now.Add(cfg.Validity),
+		NotBefore:    now,
 		SerialNumber: new(big.Int).SetInt64(0),
 		Subject:      cfg.Subject,
 	}
@@ -78,6 +163,8 @@ func SelfSignedCACert(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate, err
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to set subject key identifier")
 	}
+	// The serial is fixed and PKCS #1 v1.5 signatures are deterministic, so
+	// nothing here needs the seed.
 	certBytes, err := x509.CreateCertificate(rand.Reader, &cert, &cert, key.Public(), key)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create certificate")
@@ -91,9 +178,14 @@ func SignedCertificate(
 	csr *x509.CertificateRequest,
 	key *rsa.PrivateKey,
 	caCert *x509.Certificate,
-	caKey *rsa.PrivateKey,
+	caKey crypto.Signer,
+	purpose string,
 ) (*x509.Certificate, error) {
-	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
+	serial, err := rand.Int(reproducible.Reader(randomPurpose+purpose), new(big.Int).SetInt64(math.MaxInt64))
+	if err != nil {
+		return nil, err
+	}
+	now, err := reproducible.Now()
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +195,7 @@ func SignedCertificate(
 		ExtKeyUsage:           cfg.ExtKeyUsages,
 		IPAddresses:           csr.IPAddresses,
 		KeyUsage:              cfg.KeyUsages,
-		NotAfter:              time.Now().Add(cfg.Validity),
+		NotAfter:              now.Add(cfg.Validity),
 		NotBefore:             caCert.NotBefore,
 		SerialNumber:          serial,
 		Subject:               csr.Subject,
@@ -111,12 +203,11 @@ func SignedCertificate(
 		Version:               3,
 		BasicConstraintsValid: true,
 	}
-	pub := caCert.PublicKey.(*rsa.PublicKey)
-	certTmpl.SubjectKeyId, err = generateSubjectKeyID(pub)
+	certTmpl.SubjectKeyId, err = generateSubjectKeyID(caCert.PublicKey)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to set subject key identifier")
 	}
-	certBytes, err := x509.CreateCertificate(rand.Reader, &certTmpl, caCert, key.Public(), caKey)
+	certBytes, err := x509.CreateCertificate(reproducible.Reader(randomPurpose+purpose), &certTmpl, caCert, key.Public(), caKey)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create x509 certificate")
 	}
@@ -146,20 +237,22 @@ func generateSubjectKeyID(pub crypto.PublicKey) ([]byte, error) {
 
 // GenerateCert creates a key, csr & a signed cert
 // This is useful for apiserver and openshift-apiser cert which will be
-// authenticated by the kubeconfig using root-ca.
-func GenerateCert(caKey *rsa.PrivateKey,
+// authenticated by the kubeconfig using root-ca. With a seed, the key and
+// serial are derived from the stream for purpose.
+func GenerateCert(caKey crypto.Signer,
 	caCert *x509.Certificate,
-	cfg *CertCfg) (*rsa.PrivateKey, *x509.Certificate, error) {
+	cfg *CertCfg,
+	purpose string) (*rsa.PrivateKey, *x509.Certificate, error) {
 
 	// create a private key
-	key, err := PrivateKey()
+	key, err := PrivateKey(purpose)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to generate private key")
 	}
 
 	// create a CSR
 	csrTmpl := x509.CertificateRequest{Subject: cfg.Subject, DNSNames: cfg.DNSNames, IPAddresses: cfg.IPAddresses}
-	csrBytes, err := x509.CreateCertificateRequest(rand.Reader, &csrTmpl, key)
+	csrBytes, err := x509.CreateCertificateRequest(reproducible.Reader(randomPurpose+purpose), &csrTmpl, key)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to create certificate request")
 	}
@@ -169,7 +262,7 @@ func GenerateCert(caKey *rsa.PrivateKey,
 	}
 
 	// create a cert
-	cert, err := GenerateSignedCert(cfg, csr, key, caKey, caCert)
+	cert, err := GenerateSignedCert(cfg, csr, key, caKey, caCert, purpose)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to create a signed certificate")
 	}
@@ -177,7 +270,7 @@ func GenerateCert(caKey *rsa.PrivateKey,
 }
 
 // GenerateRootCA creates and returns the root CA
-func GenerateRootCA(key *rsa.PrivateKey, cfg *CertCfg) (*x509.Certificate, error) {
+func GenerateRootCA(key crypto.Signer, cfg *CertCfg) (*x509.Certificate, error) {
 	cert, err := SelfSignedCACert(cfg, key)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to generate self signed certificate")
@@ -189,9 +282,10 @@ func GenerateRootCA(key *rsa.PrivateKey, cfg *CertCfg) (*x509.Certificate, error
 func GenerateSignedCert(cfg *CertCfg,
 	csr *x509.CertificateRequest,
 	key *rsa.PrivateKey,
-	caKey *rsa.PrivateKey,
-	caCert *x509.Certificate) (*x509.Certificate, error) {
-	cert, err := SignedCertificate(cfg, csr, key, caCert, caKey)
+	caKey crypto.Signer,
+	caCert *x509.Certificate,
+	purpose string) (*x509.Certificate, error) {
+	cert, err := SignedCertificate(cfg, csr, key, caCert, caKey, purpose)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create a signed certificate")
 	}
@@ -199,8 +293,8 @@ func GenerateSignedCert(cfg *CertCfg,
 }
 
 // GenerateRootCertKey generates a root key/cert pair.
-func GenerateRootCertKey(cfg *CertCfg) (*rsa.PrivateKey, *x509.Certificate, error) {
-	key, err := PrivateKey()
+func GenerateRootCertKey(cfg *CertCfg, purpose string) (*rsa.PrivateKey, *x509.Certificate, error) {
+	key, err := PrivateKey(purpose)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to generate private key")
 	}
@@ -9,7 +9,7 @@ import (
 )
 
 func TestSelfSignedCACert(t *testing.T) {
-	key, err := PrivateKey()
+	key, err := PrivateKey("test")
 	if err != nil {
 		t.Fatalf("Failed to generate Private Key: %v", err)
 	}
@@ -61,7 +61,7 @@ func TestSelfSignedCACert(t *testing.T) {
 }
 
 func TestSignedCertificate(t *testing.T) {
-	key, err := PrivateKey()
+	key, err := PrivateKey("test")
 	if err != nil {
 		t.Fatalf("Failed to generate private key: %v", err)
 	}
@@ -0,0 +1,34 @@
+package destroy
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// AdoptFunc completes the platform metadata of a cluster, e.g. its resource
+// identifiers, from the tags of the cluster's resources.
+type AdoptFunc func(logger logrus.FieldLogger, metadata *types.ClusterMetadata) error
+
+// AdoptRegistry maps ClusterMetadata.Platform() to per-platform AdoptFuncs.
+var AdoptRegistry = make(map[string]AdoptFunc)
+
+// Adopt completes the metadata of a cluster whose metadata.json was lost
+// from what is left of it, so that it can be destroyed.  The metadata must
+// hold the cluster's name and where to look for it, e.g. the AWS region.
+func Adopt(logger logrus.FieldLogger, metadata *types.ClusterMetadata) error {
+	if metadata.ClusterName == "" {
+		return errors.New("no cluster name in metadata")
+	}
+	platform := metadata.Platform()
+	if platform == "" {
+		return errors.New("no platform configured in metadata")
+	}
+
+	adopt, ok := AdoptRegistry[platform]
+	if !ok {
+		return errors.Errorf("clusters cannot be adopted on %q", platform)
+	}
+	return adopt(logger, metadata)
+}
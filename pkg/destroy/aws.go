@@ -1,26 +1,173 @@
 package destroy
 
 import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/s3"
 	atd "github.com/openshift/hive/contrib/pkg/awstagdeprovision"
-	"github.com/openshift/installer/pkg/types"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
 )
 
+// awsDestroyer destroys AWS clusters with the deprovisioner, releasing
+// what blocks the deletion of resources if forced.
+type awsDestroyer struct {
+	uninstaller *atd.ClusterUninstaller
+	session     *session.Session
+	filters     []map[string]string
+	force       bool
+	logger      logrus.FieldLogger
+}
+
 // NewAWS returns an AWS destroyer from ClusterMetadata.
-func NewAWS(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (Destroyer, error) {
+func NewAWS(logger logrus.FieldLogger, metadata *types.ClusterMetadata, opts Options) (Destroyer, error) {
+	if metadata.ClusterPlatformMetadata.AWS.Region == "" {
+		return nil, errors.New("no AWS region in metadata")
+	}
+	if len(metadata.ClusterPlatformMetadata.AWS.Identifier) == 0 {
+		return nil, errors.New("no AWS resource identifiers in metadata")
+	}
+
 	filters := make([]atd.AWSFilter, 0, len(metadata.ClusterPlatformMetadata.AWS.Identifier))
 	for _, filter := range metadata.ClusterPlatformMetadata.AWS.Identifier {
 		filters = append(filters, filter)
 	}
 
-	return &atd.ClusterUninstaller{
-		Filters:     filters,
-		Region:      metadata.ClusterPlatformMetadata.AWS.Region,
-		ClusterName: metadata.ClusterName,
-		Logger:      logger,
+	if len(metadata.ClusterPlatformMetadata.AWS.ServiceEndpoints) > 0 {
+		// The deprovisioner does not take an endpoint resolver.
+		logger.Warn("The cluster was installed with AWS service endpoint overrides, but the default endpoints are used to destroy it")
+	}
+	if opts.Backoff != DefaultBackoff {
+		// Nor does it take a backoff.
+		return nil, errors.New("the retries and backoff cannot be configured for AWS clusters")
+	}
+
+	// The IAM roles, which cannot be tagged, are found by their names, which
+	// are prefixed with the infra ID.
+	infraID := metadata.InfraID
+	if infraID == "" {
+		infraID = metadata.ClusterName
+	}
+
+	ssn, err := awsconfig.NewSession(&awstypes.Platform{
+		Region:           metadata.ClusterPlatformMetadata.AWS.Region,
+		ServiceEndpoints: metadata.ClusterPlatformMetadata.AWS.ServiceEndpoints,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &awsDestroyer{
+		uninstaller: &atd.ClusterUninstaller{
+			Filters:     filters,
+			Region:      metadata.ClusterPlatformMetadata.AWS.Region,
+			ClusterName: infraID,
+			Logger:      logger,
+		},
+		session: ssn,
+		filters: metadata.ClusterPlatformMetadata.AWS.Identifier,
+		force:   opts.Force,
+		logger:  logger,
 	}, nil
 }
 
+// Run destroys the cluster.  If forced, it repeatedly releases what keeps
+// the deprovisioner from deleting resources until it is done.
+func (d *awsDestroyer) Run() error {
+	return d.RunContext(context.Background())
+}
+
+// RunContext is Run, but returns the context's error once ctx is done.
+// The deprovisioner cannot be interrupted, so it is left to run until the
+// process exits, but the resources are no longer released for it.
+func (d *awsDestroyer) RunContext(ctx context.Context) error {
+	if d.force {
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			wait.Until(d.release, forcePeriod, stop)
+		}()
+		defer func() {
+			close(stop)
+			<-done
+		}()
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- d.uninstaller.Run()
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Remaining lists the EC2 resources and S3 buckets of the cluster which
+// still exist.
+func (d *awsDestroyer) Remaining() ([]string, error) {
+	ec2Client := ec2.New(d.session)
+	s3Client := s3.New(d.session)
+	seen := map[string]bool{}
+	var remaining []string
+	add := func(resource string) {
+		if !seen[resource] {
+			seen[resource] = true
+			remaining = append(remaining, resource)
+		}
+	}
+
+	for _, filter := range d.filters {
+		instances, err := ec2Client.DescribeInstances(&ec2.DescribeInstancesInput{
+			Filters: append(ec2TagFilters(filter), &ec2.Filter{
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice([]string{"pending", "running", "shutting-down", "stopping", "stopped"}),
+			}),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, reservation := range instances.Reservations {
+			for _, instance := range reservation.Instances {
+				add("instance/" + aws.StringValue(instance.InstanceId))
+			}
+		}
+
+		// Terminated instances keep their tags for a while, so they are
+		// described above instead.
+		err = ec2Client.DescribeTagsPages(&ec2.DescribeTagsInput{Filters: ec2TagResourceFilters(filter)}, func(page *ec2.DescribeTagsOutput, lastPage bool) bool {
+			for _, tag := range page.Tags {
+				if aws.StringValue(tag.ResourceType) != ec2.ResourceTypeInstance {
+					add(aws.StringValue(tag.ResourceType) + "/" + aws.StringValue(tag.ResourceId))
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		buckets, err := taggedBuckets(s3Client, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, bucket := range buckets {
+			add("bucket/" + bucket)
+		}
+	}
+	return remaining, nil
+}
+
 func init() {
 	Registry["aws"] = NewAWS
 }
@@ -0,0 +1,90 @@
+package destroy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	awsconfig "github.com/openshift/installer/pkg/asset/installconfig/aws"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+)
+
+// maxFilterValues is how many values EC2 takes in a filter.
+const maxFilterValues = 200
+
+// AdoptAWS finds the EC2 resources owned by the cluster, and completes the
+// metadata with the identifiers the installer tagged them with: the
+// cluster ID, in the tag namespace it was installed with, and the
+// kubernetes.io/cluster tag.
+func AdoptAWS(logger logrus.FieldLogger, metadata *types.ClusterMetadata) error {
+	if metadata.AWS.Region == "" {
+		return errors.New("no AWS region in metadata")
+	}
+	ssn, err := awsconfig.NewSession(&awstypes.Platform{
+		Region:           metadata.AWS.Region,
+		ServiceEndpoints: metadata.AWS.ServiceEndpoints,
+	})
+	if err != nil {
+		return err
+	}
+	client := ec2.New(ssn)
+
+	ownedTag := fmt.Sprintf("kubernetes.io/cluster/%s", metadata.ClusterName)
+	var resources []*string
+	err = client.DescribeTagsPages(&ec2.DescribeTagsInput{Filters: ec2TagResourceFilters(map[string]string{ownedTag: "owned"})}, func(page *ec2.DescribeTagsOutput, lastPage bool) bool {
+		for _, tag := range page.Tags {
+			resources = append(resources, tag.ResourceId)
+		}
+		return true
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to find the cluster's resources")
+	}
+	if len(resources) == 0 {
+		return errors.Errorf("no resources tagged %s=owned in %s", ownedTag, metadata.AWS.Region)
+	}
+	logger.Debugf("Found %d resources tagged %s=owned", len(resources), ownedTag)
+
+	if len(resources) > maxFilterValues {
+		resources = resources[:maxFilterValues]
+	}
+	tags, err := client.DescribeTags(&ec2.DescribeTagsInput{
+		Filters: []*ec2.Filter{
+			{Name: aws.String("resource-id"), Values: resources},
+			{Name: aws.String("key"), Values: []*string{aws.String("*openshiftClusterID")}},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to find the cluster ID")
+	}
+
+	metadata.AWS.Identifier = []map[string]string{{ownedTag: "owned"}}
+	for _, tag := range tags.Tags {
+		key := aws.StringValue(tag.Key)
+		if key != "openshiftClusterID" && !strings.HasSuffix(key, "/openshiftClusterID") {
+			continue
+		}
+		platform := &awstypes.Platform{TagNamespace: strings.TrimSuffix(strings.TrimSuffix(key, "openshiftClusterID"), "/")}
+		clusterID := aws.StringValue(tag.Value)
+		logger.Debugf("Found the cluster ID %s in the %s tag", clusterID, key)
+		metadata.AWS.Identifier = []map[string]string{
+			{platform.TagKey("tectonicClusterID"): clusterID},
+			{platform.TagKey("openshiftClusterID"): clusterID},
+			{ownedTag: "owned"},
+		}
+		break
+	}
+	if len(metadata.AWS.Identifier) == 1 {
+		logger.Warnf("No cluster ID found on the cluster's resources; only those tagged %s=owned are destroyed", ownedTag)
+	}
+	return nil
+}
+
+func init() {
+	AdoptRegistry["aws"] = AdoptAWS
+}
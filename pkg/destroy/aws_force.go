@@ -0,0 +1,198 @@
+package destroy
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/sirupsen/logrus"
+)
+
+// forcePeriod is how often the force-release pass is repeated while the
+// cluster is being destroyed.
+const forcePeriod = 10 * time.Second
+
+// release releases the object versions in buckets, the associations of
+// elastic IPs and the network interfaces left in the cluster's VPCs, which
+// otherwise keep the deprovisioner retrying until it times out.
+func (d *awsDestroyer) release() {
+	for _, filter := range d.filters {
+		emptyVersionedBuckets(s3.New(d.session), filter, d.logger)
+		disassociateEIPs(ec2.New(d.session), filter, d.logger)
+		releaseNetworkInterfaces(ec2.New(d.session), filter, d.logger)
+	}
+}
+
+// emptyVersionedBuckets deletes all the object versions and delete markers
+// in the buckets matching the filter, which otherwise cannot be deleted.
+func emptyVersionedBuckets(client *s3.S3, filter map[string]string, logger logrus.FieldLogger) {
+	buckets, err := taggedBuckets(client, filter)
+	if err != nil {
+		logger.Debugf("error listing S3 buckets: %v", err)
+		return
+	}
+	for _, bucket := range buckets {
+		var deleted int
+		err = client.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: aws.String(bucket)}, func(page *s3.ListObjectVersionsOutput, lastPage bool) bool {
+			objects := make([]*s3.ObjectIdentifier, 0, len(page.Versions)+len(page.DeleteMarkers))
+			for _, version := range page.Versions {
+				objects = append(objects, &s3.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+			}
+			for _, marker := range page.DeleteMarkers {
+				objects = append(objects, &s3.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+			}
+			if len(objects) == 0 {
+				return true
+			}
+			_, err := client.DeleteObjects(&s3.DeleteObjectsInput{
+				Bucket: aws.String(bucket),
+				Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+			})
+			if err != nil {
+				logger.Debugf("error deleting object versions from bucket %s: %v", bucket, err)
+				return false
+			}
+			deleted += len(objects)
+			return true
+		})
+		if err != nil {
+			logger.Debugf("error listing object versions of bucket %s: %v", bucket, err)
+		}
+		if deleted > 0 {
+			logger.WithField("name", bucket).Infof("Deleted %d object versions", deleted)
+		}
+	}
+}
+
+// disassociateEIPs disassociates the elastic IPs matching the filter, which
+// cannot be released while they are associated.
+func disassociateEIPs(client *ec2.EC2, filter map[string]string, logger logrus.FieldLogger) {
+	addresses, err := client.DescribeAddresses(&ec2.DescribeAddressesInput{Filters: ec2TagFilters(filter)})
+	if err != nil {
+		logger.Debugf("error describing elastic IPs: %v", err)
+		return
+	}
+	for _, address := range addresses.Addresses {
+		if address.AssociationId == nil {
+			continue
+		}
+		_, err := client.DisassociateAddress(&ec2.DisassociateAddressInput{AssociationId: address.AssociationId})
+		if err != nil {
+			logger.Debugf("error disassociating elastic IP %s: %v", aws.StringValue(address.PublicIp), err)
+			continue
+		}
+		logger.WithField("ip", aws.StringValue(address.PublicIp)).Info("Disassociated Elastic IP")
+	}
+}
+
+// releaseNetworkInterfaces detaches the secondary network interfaces in the
+// VPCs matching the filter and deletes the unattached ones, which are
+// usually untagged and keep the subnets and security groups from being
+// deleted.
+func releaseNetworkInterfaces(client *ec2.EC2, filter map[string]string, logger logrus.FieldLogger) {
+	vpcs, err := client.DescribeVpcs(&ec2.DescribeVpcsInput{Filters: ec2TagFilters(filter)})
+	if err != nil {
+		logger.Debugf("error describing VPCs: %v", err)
+		return
+	}
+	for _, vpc := range vpcs.Vpcs {
+		err := client.DescribeNetworkInterfacesPages(&ec2.DescribeNetworkInterfacesInput{
+			Filters: []*ec2.Filter{{Name: aws.String("vpc-id"), Values: []*string{vpc.VpcId}}},
+		}, func(page *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+			for _, iface := range page.NetworkInterfaces {
+				releaseNetworkInterface(client, iface, logger)
+			}
+			return true
+		})
+		if err != nil {
+			logger.Debugf("error describing network interfaces in %s: %v", aws.StringValue(vpc.VpcId), err)
+		}
+	}
+}
+
+func releaseNetworkInterface(client *ec2.EC2, iface *ec2.NetworkInterface, logger logrus.FieldLogger) {
+	logger = logger.WithField("id", aws.StringValue(iface.NetworkInterfaceId))
+	if attachment := iface.Attachment; attachment != nil {
+		// The primary interfaces go away with their instances.
+		if aws.Int64Value(attachment.DeviceIndex) == 0 || aws.StringValue(attachment.Status) != ec2.AttachmentStatusAttached {
+			return
+		}
+		_, err := client.DetachNetworkInterface(&ec2.DetachNetworkInterfaceInput{
+			AttachmentId: attachment.AttachmentId,
+			Force:        aws.Bool(true),
+		})
+		if err != nil {
+			logger.Debugf("error detaching network interface: %v", err)
+			return
+		}
+		logger.Info("Detached network interface")
+		return
+	}
+	if aws.StringValue(iface.Status) != ec2.NetworkInterfaceStatusAvailable {
+		return
+	}
+	_, err := client.DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: iface.NetworkInterfaceId})
+	if err != nil {
+		logger.Debugf("error deleting network interface: %v", err)
+		return
+	}
+	logger.Info("Deleted network interface")
+}
+
+// taggedBuckets returns the names of the buckets matching the filter.
+func taggedBuckets(client *s3.S3, filter map[string]string) ([]string, error) {
+	buckets, err := client.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, bucket := range buckets.Buckets {
+		tagging, err := client.GetBucketTagging(&s3.GetBucketTaggingInput{Bucket: bucket.Name})
+		if err != nil {
+			// Untagged buckets, and those in other regions, cannot be ours.
+			continue
+		}
+		tags := make(map[string]string, len(tagging.TagSet))
+		for _, tag := range tagging.TagSet {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		if matchesFilter(tags, filter) {
+			names = append(names, aws.StringValue(bucket.Name))
+		}
+	}
+	return names, nil
+}
+
+func ec2TagFilters(filter map[string]string) []*ec2.Filter {
+	filters := make([]*ec2.Filter, 0, len(filter))
+	for key, value := range filter {
+		filters = append(filters, &ec2.Filter{
+			Name:   aws.String("tag:" + key),
+			Values: []*string{aws.String(value)},
+		})
+	}
+	return filters
+}
+
+// ec2TagResourceFilters filters DescribeTags to the resources with a tag
+// of the filter.  DescribeTags matches tags one at a time, which is enough
+// for the identifiers, each of which is a single tag.
+func ec2TagResourceFilters(filter map[string]string) []*ec2.Filter {
+	for key, value := range filter {
+		return []*ec2.Filter{
+			{Name: aws.String("key"), Values: []*string{aws.String(key)}},
+			{Name: aws.String("value"), Values: []*string{aws.String(value)}},
+		}
+	}
+	return nil
+}
+
+func matchesFilter(tags map[string]string, filter map[string]string) bool {
+	for key, value := range filter {
+		if tags[key] != value {
+			return false
+		}
+	}
+	return true
+}
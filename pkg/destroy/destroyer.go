@@ -1,8 +1,14 @@
 package destroy
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/openshift/installer/pkg/asset/cluster"
 	"github.com/openshift/installer/pkg/types"
@@ -14,19 +20,108 @@ type Destroyer interface {
 	Run() error
 }
 
+// Options holds the user's choices on how to destroy a cluster.
+type Options struct {
+	// Force removes what blocks the deletion of resources (e.g. the
+	// objects in buckets and the associations of addresses) instead of
+	// waiting for it to go away.
+	Force bool
+
+	// Timeout bounds how long destroying the cluster may take.  Zero
+	// waits for as long as it takes.
+	Timeout time.Duration
+
+	// Backoff is how deleting each kind of resource is retried.  The
+	// zero value is replaced by DefaultBackoff.
+	Backoff wait.Backoff
+}
+
+// DefaultBackoff is how deleting each kind of resource is retried unless
+// configured otherwise.
+var DefaultBackoff = wait.Backoff{
+	Duration: 10 * time.Second,
+	Factor:   1.3,
+	Steps:    100,
+}
+
+// RemainingResourcesError is returned when the cluster could not be
+// completely destroyed, and lists the resources which remain.
+type RemainingResourcesError struct {
+	Resources []string
+}
+
+func (e *RemainingResourcesError) Error() string {
+	return fmt.Sprintf("%d resources remain: %s", len(e.Resources), strings.Join(e.Resources, ", "))
+}
+
+// remainingLister is implemented by destroyers which can list the
+// resources they have yet to destroy.
+type remainingLister interface {
+	Remaining() ([]string, error)
+}
+
+// contextRunner is implemented by destroyers which stop when their context
+// is canceled.
+type contextRunner interface {
+	RunContext(ctx context.Context) error
+}
+
+// ExponentialBackoff is wait.ExponentialBackoff, except that it gives up
+// with the context's error as soon as ctx is done.
+func ExponentialBackoff(ctx context.Context, backoff wait.Backoff, condition wait.ConditionFunc) error {
+	duration := backoff.Duration
+	for i := 0; i < backoff.Steps; i++ {
+		if i != 0 {
+			adjusted := duration
+			if backoff.Jitter > 0.0 {
+				adjusted = wait.Jitter(duration, backoff.Jitter)
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(adjusted):
+			}
+			duration = time.Duration(float64(duration) * backoff.Factor)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if ok, err := condition(); err != nil || ok {
+			return err
+		}
+	}
+	return wait.ErrWaitTimeout
+}
+
 // NewFunc is an interface for creating platform-specific destroyers.
-type NewFunc func(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (Destroyer, error)
+type NewFunc func(logger logrus.FieldLogger, metadata *types.ClusterMetadata, opts Options) (Destroyer, error)
 
 // Registry maps ClusterMetadata.Platform() to per-platform Destroyer creators.
 var Registry = make(map[string]NewFunc)
 
 // New returns a Destroyer based on `metadata.json` in `rootDir`.
-func New(logger logrus.FieldLogger, rootDir string) (Destroyer, error) {
+func New(logger logrus.FieldLogger, rootDir string, opts Options) (Destroyer, error) {
 	metadata, err := cluster.LoadMetadata(rootDir)
 	if err != nil {
 		return nil, err
 	}
+	return NewFromMetadata(logger, metadata, opts)
+}
 
+// NewFromFile returns a Destroyer based on the metadata file at `path`,
+// e.g. a copy of `metadata.json` taken to another machine.
+func NewFromFile(logger logrus.FieldLogger, path string, opts Options) (Destroyer, error) {
+	metadata, err := cluster.LoadMetadataFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewFromMetadata(logger, metadata, opts)
+}
+
+// NewFromMetadata returns a Destroyer based on the given metadata.  All
+// the destroyers find the cluster's resources from the metadata alone,
+// so they do not depend on the environment the cluster was created in.
+func NewFromMetadata(logger logrus.FieldLogger, metadata *types.ClusterMetadata, opts Options) (Destroyer, error) {
 	platform := metadata.Platform()
 	if platform == "" {
 		return nil, errors.New("no platform configured in metadata")
@@ -36,5 +131,59 @@ func New(logger logrus.FieldLogger, rootDir string) (Destroyer, error) {
 	if !ok {
 		return nil, errors.Errorf("no destroyers registered for %q", platform)
 	}
-	return creator(logger, metadata)
+	if opts.Backoff.Steps == 0 {
+		opts.Backoff = DefaultBackoff
+	}
+	destroyer, err := creator(logger, metadata, opts)
+	if err != nil || opts.Timeout == 0 {
+		return destroyer, err
+	}
+	return &timeoutDestroyer{destroyer: destroyer, timeout: opts.Timeout, logger: logger}, nil
+}
+
+// timeoutDestroyer gives up on a destroyer which takes too long, and
+// reports what it left behind if it can tell.
+type timeoutDestroyer struct {
+	destroyer Destroyer
+	timeout   time.Duration
+	logger    logrus.FieldLogger
+}
+
+// Run runs the destroyer until it is done or the timeout expires, when
+// the destroyer is canceled if it supports it.
+func (d *timeoutDestroyer) Run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	runner, cancelable := d.destroyer.(contextRunner)
+	done := make(chan error, 1)
+	go func() {
+		if cancelable {
+			done <- runner.RunContext(ctx)
+		} else {
+			done <- d.destroyer.Run()
+		}
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || ctx.Err() == nil {
+			return err
+		}
+	case <-ctx.Done():
+		if cancelable {
+			// Wait for it to stop, so the remaining resources are not
+			// listed while they are still being deleted.
+			<-done
+		}
+	}
+
+	if lister, ok := d.destroyer.(remainingLister); ok {
+		if remaining, err := lister.Remaining(); err != nil {
+			d.logger.Debugf("Failed to list the remaining resources: %v", err)
+		} else if len(remaining) > 0 {
+			return errors.Wrapf(&RemainingResourcesError{Resources: remaining}, "timed out after %s", d.timeout)
+		}
+	}
+	return errors.Errorf("timed out after %s", d.timeout)
 }
@@ -0,0 +1,58 @@
+package destroy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// blockingDestroyer runs until its context is canceled.
+type blockingDestroyer struct {
+	stopped bool
+}
+
+func (d *blockingDestroyer) Run() error {
+	return d.RunContext(context.Background())
+}
+
+func (d *blockingDestroyer) RunContext(ctx context.Context) error {
+	<-ctx.Done()
+	d.stopped = true
+	return ctx.Err()
+}
+
+func (d *blockingDestroyer) Remaining() ([]string, error) {
+	return []string{"i-0123456789abcdef0"}, nil
+}
+
+func TestTimeoutDestroyerCancels(t *testing.T) {
+	destroyer := &blockingDestroyer{}
+	err := (&timeoutDestroyer{destroyer: destroyer, timeout: 10 * time.Millisecond, logger: logrus.New()}).Run()
+	assert.EqualError(t, err, "timed out after 10ms: 1 resources remain: i-0123456789abcdef0")
+	assert.True(t, destroyer.stopped, "the destroyer was not canceled")
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+	attempts := 0
+	err := ExponentialBackoff(context.Background(), backoff, func() (bool, error) {
+		attempts++
+		return false, nil
+	})
+	assert.Equal(t, wait.ErrWaitTimeout, err)
+	assert.Equal(t, 3, attempts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts = 0
+	err = ExponentialBackoff(ctx, wait.Backoff{Duration: time.Hour, Factor: 1, Steps: 3}, func() (bool, error) {
+		attempts++
+		cancel()
+		return false, nil
+	})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, attempts)
+}
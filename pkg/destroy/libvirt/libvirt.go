@@ -3,6 +3,7 @@
 package libvirt
 
 import (
+	"context"
 	"strings"
 
 	libvirt "github.com/libvirt/libvirt-go"
@@ -49,6 +50,12 @@ type ClusterUninstaller struct {
 
 // Run is the entrypoint to start the uninstall process.
 func (o *ClusterUninstaller) Run() error {
+	return o.RunContext(context.Background())
+}
+
+// RunContext is Run, but stops before the next kind of resource once ctx
+// is done.
+func (o *ClusterUninstaller) RunContext(ctx context.Context) error {
 	conn, err := libvirt.NewConnect(o.LibvirtURI)
 	if err != nil {
 		return errors.Wrap(err, "failed to connect to Libvirt daemon")
@@ -59,6 +66,9 @@ func (o *ClusterUninstaller) Run() error {
 		deleteNetwork,
 		deleteVolumes,
 	} {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		err = del(conn, o.Filter, o.Logger)
 		if err != nil {
 			return err
@@ -206,10 +216,19 @@ func deleteNetwork(conn *libvirt.Connect, filter filterFunc, logger logrus.Field
 }
 
 // New returns libvirt Uninstaller from ClusterMetadata.
-func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (destroy.Destroyer, error) {
+func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata, opts destroy.Options) (destroy.Destroyer, error) {
 	return &ClusterUninstaller{
 		LibvirtURI: metadata.ClusterPlatformMetadata.Libvirt.URI,
 		Filter:     ClusterNamePrefixFilter(metadata.ClusterName),
 		Logger:     logger,
 	}, nil
 }
+
+// Adopt checks the metadata of a cluster to adopt.  Its resources are found
+// by the cluster name alone, so there is nothing to complete.
+func Adopt(logger logrus.FieldLogger, metadata *types.ClusterMetadata) error {
+	if metadata.ClusterPlatformMetadata.Libvirt.URI == "" {
+		return errors.New("no libvirt URI in metadata")
+	}
+	return nil
+}
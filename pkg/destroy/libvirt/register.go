@@ -8,4 +8,5 @@ import (
 
 func init() {
 	destroy.Registry["libvirt"] = New
+	destroy.AdoptRegistry["libvirt"] = Adopt
 }
@@ -0,0 +1,48 @@
+package openstack
+
+import (
+	"fmt"
+
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+// Adopt finds the servers owned by the cluster, and completes the metadata
+// with the cluster ID the installer tagged them with.
+func Adopt(logger logrus.FieldLogger, metadata *types.ClusterMetadata) error {
+	if metadata.OpenStack.Cloud == "" {
+		return errors.New("no OpenStack cloud in metadata")
+	}
+	conn, err := clientconfig.NewServiceClient("compute", &clientconfig.ClientOpts{Cloud: metadata.OpenStack.Cloud})
+	if err != nil {
+		return err
+	}
+	allPages, err := servers.List(conn, servers.ListOpts{}).AllPages()
+	if err != nil {
+		return errors.Wrap(err, "failed to list the servers")
+	}
+	allServers, err := servers.ExtractServers(allPages)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the servers")
+	}
+
+	// Nova does not filter by metadata, so the servers are filtered here.
+	owned := fmt.Sprintf("kubernetes.io/cluster/%s", metadata.ClusterName)
+	for _, server := range allServers {
+		clusterID := server.Metadata["openshiftClusterID"]
+		if server.Metadata["owned"] != owned || clusterID == "" {
+			continue
+		}
+		logger.Debugf("Found the cluster ID %s on server %s", clusterID, server.ID)
+		metadata.OpenStack.Identifier = map[string]string{
+			"tectonicClusterID":  clusterID,
+			"openshiftClusterID": clusterID,
+		}
+		return nil
+	}
+	return errors.Errorf("no servers owned by %s found in %s", metadata.ClusterName, metadata.OpenStack.Cloud)
+}
@@ -2,9 +2,11 @@
 package openstack
 
 import (
+	"context"
+	"net/url"
 	"os"
+	"sort"
 	"strings"
-	"time"
 
 	"github.com/openshift/installer/pkg/destroy"
 	"github.com/openshift/installer/pkg/types"
@@ -49,13 +51,26 @@ type ClusterUninstaller struct {
 	// Filter contains the openshiftClusterID to filter tags
 	Filter Filter
 	Logger logrus.FieldLogger
+	// Backoff is how deleting each kind of resource is retried.
+	Backoff wait.Backoff
+}
+
+// deleteResult is what a deleteRunner reports when it is done.
+type deleteResult struct {
+	name string
+	err  error
 }
 
 // Run is the entrypoint to start the uninstall process.
 func (o *ClusterUninstaller) Run() error {
+	return o.RunContext(context.Background())
+}
+
+// RunContext is Run, but stops retrying once ctx is done.
+func (o *ClusterUninstaller) RunContext(ctx context.Context) error {
 	deleteFuncs := map[string]deleteFunc{}
 	populateDeleteFuncs(deleteFuncs)
-	returnChannel := make(chan string)
+	returnChannel := make(chan deleteResult)
 
 	opts := &clientconfig.ClientOpts{
 		Cloud: o.Cloud,
@@ -63,39 +78,37 @@ func (o *ClusterUninstaller) Run() error {
 
 	// launch goroutines
 	for name, function := range deleteFuncs {
-		go deleteRunner(name, function, opts, o.Filter, o.Logger, returnChannel)
+		go deleteRunner(ctx, name, function, opts, o.Filter, o.Backoff, o.Logger, returnChannel)
 	}
 
 	// wait for them to finish
+	var remaining []string
 	for i := 0; i < len(deleteFuncs); i++ {
 		select {
 		case res := <-returnChannel:
-			o.Logger.Debugf("goroutine %v complete", res)
+			if res.err != nil {
+				o.Logger.Errorf("Unrecoverable error/timed out in %v: %v", res.name, res.err)
+				remaining = append(remaining, strings.TrimPrefix(res.name, "delete"))
+				continue
+			}
+			o.Logger.Debugf("goroutine %v complete", res.name)
 		}
 	}
 
+	if len(remaining) > 0 {
+		sort.Strings(remaining)
+		return &destroy.RemainingResourcesError{Resources: remaining}
+	}
 	return nil
 }
 
-func deleteRunner(deleteFuncName string, dFunction deleteFunc, opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger, channel chan string) {
-	backoffSettings := wait.Backoff{
-		Duration: time.Second * 10,
-		Factor:   1.3,
-		Steps:    100,
-	}
-
-	err := wait.ExponentialBackoff(backoffSettings, func() (bool, error) {
+func deleteRunner(ctx context.Context, deleteFuncName string, dFunction deleteFunc, opts *clientconfig.ClientOpts, filter Filter, backoff wait.Backoff, logger logrus.FieldLogger, channel chan deleteResult) {
+	err := destroy.ExponentialBackoff(ctx, backoff, func() (bool, error) {
 		return dFunction(opts, filter, logger)
 	})
 
-	if err != nil {
-		logger.Fatalf("Unrecoverable error/timed out: %v", err)
-		os.Exit(1)
-	}
-
 	// record that the goroutine has run to completion
-	channel <- deleteFuncName
-	return
+	channel <- deleteResult{name: deleteFuncName, err: err}
 }
 
 // populateDeleteFuncs is the list of functions that will be launched as
@@ -103,6 +116,7 @@ func deleteRunner(deleteFuncName string, dFunction deleteFunc, opts *clientconfi
 func populateDeleteFuncs(funcs map[string]deleteFunc) {
 	funcs["deleteServers"] = deleteServers
 	funcs["deletePorts"] = deletePorts
+	funcs["deleteFloatingIPs"] = deleteFloatingIPs
 	funcs["deleteSecurityGroups"] = deleteSecurityGroups
 	funcs["deleteRouters"] = deleteRouters
 	funcs["deleteSubnets"] = deleteSubnets
@@ -242,6 +256,41 @@ func deletePorts(opts *clientconfig.ClientOpts, filter Filter, logger logrus.Fie
 	return len(allPorts) == 0, nil
 }
 
+// deleteFloatingIPs releases the floating IPs allocated for the cluster.
+// The floatingips package of gophercloud is not vendored, so the Neutron
+// API is called directly.
+func deleteFloatingIPs(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
+	logger.Debug("Deleting openstack floating IPs")
+	defer logger.Debugf("Exiting deleting openstack floating IPs")
+
+	conn, err := clientconfig.NewServiceClient("network", opts)
+	if err != nil {
+		logger.Fatalf("%v", err)
+		os.Exit(1)
+	}
+	tags := filterTags(filter)
+	listURL := conn.ServiceURL("floatingips") + "?tags-any=" + url.QueryEscape(strings.Join(tags, ","))
+
+	var allFloatingIPs struct {
+		FloatingIPs []struct {
+			ID string `json:"id"`
+		} `json:"floatingips"`
+	}
+	_, err = conn.Get(listURL, &allFloatingIPs, nil)
+	if err != nil {
+		logger.Fatalf("%v", err)
+		os.Exit(1)
+	}
+	for _, fip := range allFloatingIPs.FloatingIPs {
+		logger.Debugf("Deleting Floating IP: %+v", fip.ID)
+		_, err = conn.Delete(conn.ServiceURL("floatingips", fip.ID), nil)
+		if err != nil {
+			return false, nil
+		}
+	}
+	return len(allFloatingIPs.FloatingIPs) == 0, nil
+}
+
 func deleteSecurityGroups(opts *clientconfig.ClientOpts, filter Filter, logger logrus.FieldLogger) (bool, error) {
 	logger.Debug("Deleting openstack security-groups")
 	defer logger.Debugf("Exiting deleting openstack security-groups")
@@ -479,10 +528,11 @@ func deleteContainers(opts *clientconfig.ClientOpts, filter Filter, logger logru
 }
 
 // New returns an OpenStack destroyer from ClusterMetadata.
-func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (destroy.Destroyer, error) {
+func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata, opts destroy.Options) (destroy.Destroyer, error) {
 	return &ClusterUninstaller{
-		Cloud:  metadata.ClusterPlatformMetadata.OpenStack.Cloud,
-		Filter: metadata.ClusterPlatformMetadata.OpenStack.Identifier,
-		Logger: logger,
+		Cloud:   metadata.ClusterPlatformMetadata.OpenStack.Cloud,
+		Filter:  metadata.ClusterPlatformMetadata.OpenStack.Identifier,
+		Logger:  logger,
+		Backoff: opts.Backoff,
 	}, nil
 }
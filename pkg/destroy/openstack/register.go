@@ -7,4 +7,5 @@ import (
 
 func init() {
 	destroy.Registry["openstack"] = New
+	destroy.AdoptRegistry["openstack"] = Adopt
 }
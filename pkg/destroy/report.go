@@ -0,0 +1,222 @@
+package destroy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/installer/pkg/redact"
+	"github.com/openshift/installer/pkg/types"
+)
+
+// ReportFileName is the name of the file destroy reports are written to.
+const ReportFileName = "destroy-report.json"
+
+// DeletedResource is a resource the destroyer deleted.
+type DeletedResource struct {
+	Type    string    `json:"type"`
+	ID      string    `json:"id"`
+	Region  string    `json:"region,omitempty"`
+	Deleted time.Time `json:"deleted"`
+}
+
+// RemainingResource is a resource the destroyer could not delete.
+type RemainingResource struct {
+	Type   string `json:"type"`
+	ID     string `json:"id,omitempty"`
+	Region string `json:"region,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Report is what destroy-report.json holds: what destroying a cluster
+// deleted and what it left behind, for audit trails and for checking
+// the cleanup.
+type Report struct {
+	ClusterName string              `json:"clusterName"`
+	InfraID     string              `json:"infraID,omitempty"`
+	Platform    string              `json:"platform"`
+	Start       time.Time           `json:"start"`
+	End         time.Time           `json:"end"`
+	Success     bool                `json:"success"`
+	Error       string              `json:"error,omitempty"`
+	Deleted     []DeletedResource   `json:"deleted"`
+	Remaining   []RemainingResource `json:"remaining"`
+}
+
+// deletedMessages match the deletions which the AWS deprovisioner logs
+// without fields, capturing the resource's parent, if any, and ID.
+var deletedMessages = []struct {
+	pattern      *regexp.Regexp
+	resourceType string
+}{
+	{regexp.MustCompile(`^Deleted record (.+) from r53 zone (.+)$`), "route53 record"},
+	{regexp.MustCompile(`^Deleted route (.+) from route table (.+)$`), "route"},
+	{regexp.MustCompile(`^Deleted all policies from role: (.+)$`), "role policies"},
+	{regexp.MustCompile(`^deleted profile (.+)$`), "instance profile"},
+	{regexp.MustCompile(`^deleted role (.+)$`), "role"},
+}
+
+// Recorder is a logrus hook which builds the report of a destroy from the
+// destroyers' log entries.  The destroyers log most deletions as "Deleted
+// <type>", with the resource's ID or name as a field, and the rest in the
+// message, as matched by deletedMessages.  They log their failures to
+// delete as errors or, when retried, at the debug level.
+type Recorder struct {
+	lock   sync.Mutex
+	region string
+	report Report
+	errors []string
+}
+
+var _ logrus.Hook = (*Recorder)(nil)
+
+// NewRecorder returns a recorder for the destroy of the cluster described
+// by metadata, which starts now.
+func NewRecorder(metadata *types.ClusterMetadata) *Recorder {
+	r := &Recorder{
+		report: Report{
+			ClusterName: metadata.ClusterName,
+			InfraID:     metadata.InfraID,
+			Platform:    metadata.Platform(),
+			Start:       time.Now().UTC(),
+			Deleted:     []DeletedResource{},
+			Remaining:   []RemainingResource{},
+		},
+	}
+	switch {
+	case metadata.AWS != nil:
+		r.region = metadata.AWS.Region
+	case metadata.OpenStack != nil:
+		r.region = metadata.OpenStack.Region
+	}
+	return r
+}
+
+// Levels returns the levels of the entries the recorder reads.
+func (r *Recorder) Levels() []logrus.Level {
+	return []logrus.Level{logrus.ErrorLevel, logrus.InfoLevel, logrus.DebugLevel}
+}
+
+// Fire records the deletion or failure to delete of an entry of the
+// destroy component.
+func (r *Recorder) Fire(entry *logrus.Entry) error {
+	if component, _ := entry.Data["component"].(string); component != "destroy" {
+		return nil
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch {
+	case entry.Level == logrus.InfoLevel && strings.HasPrefix(strings.ToLower(entry.Message), "deleted "):
+		resourceType, id := deletedResource(entry)
+		r.report.Deleted = append(r.report.Deleted, DeletedResource{
+			Type:    resourceType,
+			ID:      id,
+			Region:  r.region,
+			Deleted: entry.Time.UTC(),
+		})
+	case entry.Level == logrus.ErrorLevel,
+		entry.Level == logrus.DebugLevel && strings.Contains(strings.ToLower(entry.Message), "error"):
+		r.errors = append(r.errors, entry.Message)
+	}
+	return nil
+}
+
+// deletedResource returns the type and ID of the resource whose deletion
+// an entry records.  Unknown deletions logged without fields are recorded
+// with their message as the type.
+func deletedResource(entry *logrus.Entry) (resourceType string, id string) {
+	if id := entryID(entry); id != "" {
+		return entry.Message[len("Deleted "):], id
+	}
+	for _, m := range deletedMessages {
+		if match := m.pattern.FindStringSubmatch(entry.Message); match != nil {
+			// Resources within a parent are identified as <parent>/<ID>.
+			if len(match) == 3 {
+				return m.resourceType, match[2] + "/" + match[1]
+			}
+			return m.resourceType, match[1]
+		}
+	}
+	return entry.Message[len("Deleted "):], ""
+}
+
+// entryID returns the value of the field, other than the component, which
+// identifies the resource of an entry.
+func entryID(entry *logrus.Entry) string {
+	var keys []string
+	for key := range entry.Data {
+		if key != "component" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	switch id := entry.Data[keys[0]].(type) {
+	case string:
+		return id
+	case *string:
+		if id != nil {
+			return *id
+		}
+		return ""
+	default:
+		return fmt.Sprint(id)
+	}
+}
+
+// End completes the report with the outcome of the destroy.  The resources
+// a RemainingResourcesError lists, as <type>/<id> or as a type, are
+// reported with the last failure logged about them, if any.
+func (r *Recorder) End(err error) *Report {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.report.End = time.Now().UTC()
+	r.report.Success = err == nil
+	if err == nil {
+		return &r.report
+	}
+	r.report.Error = redact.String(err.Error())
+
+	remaining, ok := errors.Cause(err).(*RemainingResourcesError)
+	if !ok {
+		return &r.report
+	}
+	for _, resource := range remaining.Resources {
+		rr := RemainingResource{Type: resource, Region: r.region, Reason: r.report.Error}
+		if i := strings.Index(resource, "/"); i >= 0 {
+			rr.Type, rr.ID = resource[:i], resource[i+1:]
+		}
+		needle := strings.ToLower(rr.ID)
+		if needle == "" {
+			needle = strings.ToLower(rr.Type)
+		}
+		for i := len(r.errors) - 1; i >= 0; i-- {
+			if strings.Contains(strings.ToLower(r.errors[i]), needle) {
+				rr.Reason = redact.String(r.errors[i])
+				break
+			}
+		}
+		r.report.Remaining = append(r.report.Remaining, rr)
+	}
+	return &r.report
+}
+
+// Write writes the report to path.
+func (r *Report) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the destroy report")
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,49 @@
+package destroy
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+)
+
+func TestRecorderDeleted(t *testing.T) {
+	recorder := NewRecorder(&types.ClusterMetadata{
+		ClusterName: "test",
+		ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+			AWS: &awstypes.Metadata{Region: "us-east-1"},
+		},
+	})
+	logger := logrus.New()
+	logger.Out = ioutil.Discard
+	logger.AddHook(recorder)
+	destroyLogger := logger.WithField("component", "destroy")
+
+	bucket := "test-image-registry"
+	destroyLogger.WithField("id", "i-0123456789abcdef0").Info("Deleted instance")
+	destroyLogger.WithField("name", &bucket).Info("Deleted bucket")
+	destroyLogger.Infof("Deleted record %v from r53 zone %v", "api.test.example.com.", "Z3URY6TWQ91KVV")
+	destroyLogger.Infof("Deleted route %v from route table %v", "0.0.0.0/0", "rtb-0123456789abcdef0")
+	destroyLogger.Infof("Deleted all policies from role: %v", "test-master-role")
+	destroyLogger.Info("Deleted something new")
+	destroyLogger.Info("Deleting instance")
+	logger.WithField("id", "i-fedcba9876543210f").Info("Deleted instance")
+
+	var deleted [][2]string
+	for _, resource := range recorder.End(nil).Deleted {
+		assert.Equal(t, "us-east-1", resource.Region)
+		deleted = append(deleted, [2]string{resource.Type, resource.ID})
+	}
+	assert.Equal(t, [][2]string{
+		{"instance", "i-0123456789abcdef0"},
+		{"bucket", "test-image-registry"},
+		{"route53 record", "Z3URY6TWQ91KVV/api.test.example.com."},
+		{"route", "rtb-0123456789abcdef0/0.0.0.0/0"},
+		{"role policies", "test-master-role"},
+		{"something new", ""},
+	}, deleted)
+}
@@ -0,0 +1,225 @@
+// Package encryption encrypts the state file and the auth files at rest
+// with a key derived from a passphrase, or from the output of a command
+// which can fetch the key from a KMS.
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// PassphraseEnvVar names the environment variable holding the
+	// passphrase from which the encryption key is derived.
+	PassphraseEnvVar = "OPENSHIFT_INSTALL_PASSPHRASE"
+
+	// KeyCommandEnvVar names the environment variable holding a shell
+	// command which prints the secret from which the encryption key is
+	// derived (e.g. one decrypting a data key with a KMS). It is used when
+	// no passphrase is set.
+	KeyCommandEnvVar = "OPENSHIFT_INSTALL_KEY_COMMAND"
+)
+
+const (
+	saltSize   = 16
+	keySize    = 32
+	iterations = 100000
+)
+
+// header prefixes encrypted data. It is followed by the salt, the nonce
+// and the AES-256-GCM ciphertext.
+var header = []byte("openshift-install-encrypted:v1\n")
+
+var (
+	secretLock sync.Mutex
+	secret     []byte
+	secretRead bool
+)
+
+// Enabled returns true if a passphrase or key command is configured, in
+// which case Encrypt encrypts.
+func Enabled() bool {
+	return os.Getenv(PassphraseEnvVar) != "" || os.Getenv(KeyCommandEnvVar) != ""
+}
+
+// IsEncrypted returns true if data was encrypted by Encrypt.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+// Encrypt encrypts data when encryption is enabled, and otherwise returns
+// it unchanged.
+func Encrypt(data []byte) ([]byte, error) {
+	if !Enabled() {
+		return data, nil
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+	aead, err := newAEAD(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+
+	out := make([]byte, 0, len(header)+len(salt)+len(nonce)+len(data)+aead.Overhead())
+	out = append(out, header...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return aead.Seal(out, nonce, data, header), nil
+}
+
+// Decrypt decrypts data encrypted by Encrypt, and returns any other data
+// unchanged.
+func Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	if !Enabled() {
+		return nil, errors.Errorf("encrypted; set %s or %s to decrypt", PassphraseEnvVar, KeyCommandEnvVar)
+	}
+
+	data = data[len(header):]
+	if len(data) < saltSize {
+		return nil, errors.New("truncated encrypted data")
+	}
+	aead, err := newAEAD(data[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	data = data[saltSize:]
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("truncated encrypted data")
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], header)
+	if err != nil {
+		return nil, errors.New("failed to decrypt; the passphrase or key may be wrong")
+	}
+	return plaintext, nil
+}
+
+// ReadFile reads the file at path, decrypting it if necessary.
+func ReadFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err = Decrypt(data)
+	return data, errors.Wrapf(err, "failed to read %s", path)
+}
+
+// PlaintextFile returns the path of a plaintext copy of the file at path,
+// for programs which read it themselves, and a function removing the copy.
+// Files which are not encrypted are not copied.
+func PlaintextFile(path string) (string, func(), error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	if !IsEncrypted(data) {
+		return path, func() {}, nil
+	}
+	data, err = Decrypt(data)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to read %s", path)
+	}
+
+	file, err := ioutil.TempFile("", "openshift-install-"+filepath.Base(path)+"-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.Remove(file.Name()) }
+	_, err = file.Write(data)
+	if err2 := file.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return file.Name(), cleanup, nil
+}
+
+func newAEAD(salt []byte) (cipher.AEAD, error) {
+	secret, err := getSecret()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(pbkdf2(secret, salt, iterations, keySize))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// getSecret returns the passphrase or, failing that, the output of the key
+// command, which is only run once.
+func getSecret() ([]byte, error) {
+	if passphrase := os.Getenv(PassphraseEnvVar); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	secretLock.Lock()
+	defer secretLock.Unlock()
+	if secretRead {
+		return secret, nil
+	}
+
+	cmd := exec.Command("/bin/sh", "-c", os.Getenv(KeyCommandEnvVar))
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to run %s", KeyCommandEnvVar)
+	}
+	out = []byte(strings.TrimRight(string(out), "\r\n"))
+	if len(out) == 0 {
+		return nil, errors.Errorf("%s printed an empty key", KeyCommandEnvVar)
+	}
+	secret, secretRead = out, true
+	return secret, nil
+}
+
+// pbkdf2 derives a key following RFC 2898 with HMAC-SHA256, as
+// golang.org/x/crypto/pbkdf2 is not vendored.
+func pbkdf2(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	key := make([]byte, 0, keyLen)
+	counter := make([]byte, 4)
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter, block)
+		prf.Write(counter)
+		u := prf.Sum(nil)
+		t := append([]byte{}, u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
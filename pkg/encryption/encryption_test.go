@@ -0,0 +1,129 @@
+package encryption
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPBKDF2(t *testing.T) {
+	// PBKDF2-HMAC-SHA256 test vectors, the first from RFC 7914.
+	cases := []struct {
+		password, salt string
+		iterations     int
+		keyLen         int
+		expected       string
+	}{
+		{"passwd", "salt", 1, 64, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+		{"password", "salt", 4096, 32, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+	}
+	for _, tc := range cases {
+		key := pbkdf2([]byte(tc.password), []byte(tc.salt), tc.iterations, tc.keyLen)
+		assert.Equal(t, tc.expected, hex.EncodeToString(key))
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	os.Unsetenv(KeyCommandEnvVar)
+	os.Unsetenv(PassphraseEnvVar)
+	plaintext := []byte(`{"secret": "data"}`)
+
+	data, err := Encrypt(plaintext)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, data, "unchanged when disabled")
+
+	os.Setenv(PassphraseEnvVar, "correct horse battery staple")
+	defer os.Unsetenv(PassphraseEnvVar)
+
+	encrypted, err := Encrypt(plaintext)
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, string(encrypted), "secret")
+
+	again, err := Encrypt(plaintext)
+	assert.NoError(t, err)
+	assert.NotEqual(t, encrypted, again, "salt and nonce are random")
+
+	decrypted, err := Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	decrypted, err = Decrypt(plaintext)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted, "plaintext is passed through")
+
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = Decrypt(tampered)
+	assert.EqualError(t, err, "failed to decrypt; the passphrase or key may be wrong")
+
+	_, err = Decrypt(encrypted[:len(header)+4])
+	assert.EqualError(t, err, "truncated encrypted data")
+
+	os.Setenv(PassphraseEnvVar, "wrong")
+	_, err = Decrypt(encrypted)
+	assert.EqualError(t, err, "failed to decrypt; the passphrase or key may be wrong")
+
+	os.Unsetenv(PassphraseEnvVar)
+	_, err = Decrypt(encrypted)
+	assert.EqualError(t, err, "encrypted; set OPENSHIFT_INSTALL_PASSPHRASE or OPENSHIFT_INSTALL_KEY_COMMAND to decrypt")
+}
+
+func TestKeyCommand(t *testing.T) {
+	os.Unsetenv(PassphraseEnvVar)
+	os.Setenv(KeyCommandEnvVar, "echo key-from-kms")
+	defer os.Unsetenv(KeyCommandEnvVar)
+	secretRead = false
+
+	encrypted, err := Encrypt([]byte("data"))
+	assert.NoError(t, err)
+
+	// The key command's output is used like a passphrase.
+	os.Setenv(PassphraseEnvVar, "key-from-kms")
+	defer os.Unsetenv(PassphraseEnvVar)
+	decrypted, err := Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data"), decrypted)
+}
+
+func TestPlaintextFile(t *testing.T) {
+	os.Setenv(PassphraseEnvVar, "correct horse battery staple")
+	defer os.Unsetenv(PassphraseEnvVar)
+	plaintext := []byte(`{"secret": "data"}`)
+
+	dir, err := ioutil.TempDir("", "openshift-install-")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	plainPath := filepath.Join(dir, "plain")
+	assert.NoError(t, ioutil.WriteFile(plainPath, plaintext, 0600))
+	path, cleanup, err := PlaintextFile(plainPath)
+	if assert.NoError(t, err) {
+		assert.Equal(t, plainPath, path, "not copied when not encrypted")
+		cleanup()
+		assert.FileExists(t, plainPath)
+	}
+
+	encrypted, err := Encrypt(plaintext)
+	if !assert.NoError(t, err) {
+		return
+	}
+	encryptedPath := filepath.Join(dir, "encrypted")
+	assert.NoError(t, ioutil.WriteFile(encryptedPath, encrypted, 0600))
+	path, cleanup, err = PlaintextFile(encryptedPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, data)
+	cleanup()
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "removed by the cleanup")
+}
@@ -0,0 +1,102 @@
+// Package failure describes why the installer failed: which component
+// failed, a code identifying the failure, and a hint on what to do about
+// it, so users get more to go on than the underlying error.
+package failure
+
+import (
+	"fmt"
+)
+
+// Code identifies a kind of failure.
+type Code string
+
+const (
+	// AssetGeneration is a failure to generate an asset, e.g. from an
+	// invalid install config.
+	AssetGeneration Code = "AssetGeneration"
+
+	// InfrastructureCreation is a failure to create the cluster's
+	// infrastructure with Terraform.
+	InfrastructureCreation Code = "InfrastructureCreation"
+
+	// APITimeout is the Kubernetes API not coming up in time.
+	APITimeout Code = "APITimeout"
+
+	// BootstrapTimeout is bootstrapping not completing in time.
+	BootstrapTimeout Code = "BootstrapTimeout"
+
+	// InstallTimeout is the operators not settling in time after
+	// bootstrapping.
+	InstallTimeout Code = "InstallTimeout"
+
+	// DestroyIncomplete is resources remaining after destroying a
+	// cluster.
+	DestroyIncomplete Code = "DestroyIncomplete"
+)
+
+// Error is an error with the failing component, a code and a hint.
+type Error struct {
+	// Code identifies the failure.
+	Code Code
+
+	// Component is the part of the installer which failed, e.g.
+	// "terraform".
+	Component string
+
+	// Hint suggests how to remedy the failure.
+	Hint string
+
+	// Err is the underlying error.
+	Err error
+}
+
+// New returns an Error wrapping err, or nil if err is nil.  If err already
+// carries an Error, which describes the failure more precisely, err is
+// returned as it is.
+func New(code Code, component string, hint string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if Find(err) != nil {
+		return err
+	}
+	return &Error{Code: code, Component: component, Hint: hint, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Cause returns the underlying error, for github.com/pkg/errors.Cause.
+func (e *Error) Cause() error {
+	return e.Err
+}
+
+// Summary describes the failure on a line, e.g. to be logged at the end
+// of a failed run.
+func (e *Error) Summary() string {
+	if e.Hint == "" {
+		return fmt.Sprintf("%s (%s)", e.Code, e.Component)
+	}
+	return fmt.Sprintf("%s (%s): %s", e.Code, e.Component, e.Hint)
+}
+
+// Find returns the outermost Error in the chain of causes of err, or nil
+// if there is none.
+func Find(err error) *Error {
+	type causer interface {
+		Cause() error
+	}
+
+	for err != nil {
+		if e, ok := err.(*Error); ok {
+			return e
+		}
+		cause, ok := err.(causer)
+		if !ok {
+			return nil
+		}
+		err = cause.Cause()
+	}
+	return nil
+}
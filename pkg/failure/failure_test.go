@@ -0,0 +1,64 @@
+package failure
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	assert.Nil(t, New(AssetGeneration, "assets", "hint", nil))
+
+	err := New(AssetGeneration, "assets", "hint", errors.New("invalid"))
+	assert.EqualError(t, err, "invalid")
+	assert.Equal(t, AssetGeneration, Find(err).Code)
+
+	// The innermost, more precise, failure is kept.
+	inner := New(InfrastructureCreation, "terraform", "quota", errors.New("apply"))
+	err = New(AssetGeneration, "assets", "hint", errors.Wrap(inner, "failed to generate asset"))
+	assert.EqualError(t, err, "failed to generate asset: apply")
+	assert.Equal(t, InfrastructureCreation, Find(err).Code)
+}
+
+func TestFind(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected Code
+	}{
+		{
+			name: "plain error",
+			err:  errors.New("plain"),
+		},
+		{
+			name:     "failure",
+			err:      &Error{Code: BootstrapTimeout, Err: errors.New("deadline")},
+			expected: BootstrapTimeout,
+		},
+		{
+			name:     "wrapped failure",
+			err:      errors.Wrap(errors.WithMessage(&Error{Code: APITimeout, Err: errors.New("deadline")}, "waiting"), "create"),
+			expected: APITimeout,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			found := Find(tc.err)
+			if tc.expected == "" {
+				assert.Nil(t, found)
+				return
+			}
+			if assert.NotNil(t, found) {
+				assert.Equal(t, tc.expected, found.Code)
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	err := &Error{Code: InstallTimeout, Component: "operators", Hint: "check the cluster operators", Err: errors.New("deadline")}
+	assert.Equal(t, "InstallTimeout (operators): check the cluster operators", err.Summary())
+	err.Hint = ""
+	assert.Equal(t, "InstallTimeout (operators)", err.Summary())
+}
@@ -0,0 +1,136 @@
+// Package redact removes secrets, such as pull secrets, private keys and
+// passwords, from text before it is logged or printed.
+package redact
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Placeholder replaces redacted values.
+const Placeholder = "REDACTED"
+
+// minSecretLength is the length below which registered values are ignored,
+// because replacing them would mangle unrelated text.
+const minSecretLength = 8
+
+type pattern struct {
+	regexp      *regexp.Regexp
+	replacement string
+}
+
+var (
+	// patterns match secrets by shape, regardless of whether they have been
+	// registered.
+	patterns = []pattern{
+		{
+			// PEM-encoded private keys, e.g. in TLS assets.
+			regexp:      regexp.MustCompile(`(?s)(-----BEGIN [A-Z ]*PRIVATE KEY-----).*?(-----END [A-Z ]*PRIVATE KEY-----)`),
+			replacement: "${1}" + Placeholder + "${2}",
+		},
+		{
+			// JSON string values, possibly escaped within another string,
+			// e.g. the auths in a pull secret.
+			regexp:      regexp.MustCompile(`(\\*"(?:auth|password|bindPassword|clientSecret|pullSecret)\\*"\s*:\s*\\*")[^"\\]*`),
+			replacement: "${1}" + Placeholder,
+		},
+		{
+			// INI, YAML and tfvars values, e.g. in AWS credentials files.
+			regexp:      regexp.MustCompile(`(\b(?:aws_secret_access_key|aws_session_token|bindPassword|clientSecret)[ \t]*[:=][ \t]*)[^\s"']+`),
+			replacement: "${1}" + Placeholder,
+		},
+	}
+
+	// secretEnvironmentVariables hold credentials the installer, Terraform
+	// or the cloud SDKs read from the environment.
+	secretEnvironmentVariables = []string{
+		"AWS_SECRET_ACCESS_KEY",
+		"AWS_SESSION_TOKEN",
+		"OPENSHIFT_INSTALL_PASSPHRASE",
+		"OPENSHIFT_INSTALL_PKCS11_PIN",
+		"OS_AUTH_TOKEN",
+		"OS_PASSWORD",
+		"OS_TOKEN",
+	}
+
+	lock sync.RWMutex
+	// secrets holds the registered values, longest first, so a value is
+	// replaced before any registered value it contains.
+	secrets []string
+)
+
+// Register adds a value which is always redacted, whatever its context.
+func Register(secret string) {
+	secret = strings.TrimSpace(secret)
+	if len(secret) < minSecretLength {
+		return
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	for _, s := range secrets {
+		if s == secret {
+			return
+		}
+	}
+	secrets = append(secrets, secret)
+	sort.SliceStable(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+}
+
+// RegisterPullSecret registers a pull secret along with its base64 encoding
+// and the credentials for each of its registries, so the pull secret is
+// redacted even where it has been embedded in another asset.
+func RegisterPullSecret(pullSecret string) {
+	Register(pullSecret)
+	Register(base64.StdEncoding.EncodeToString([]byte(pullSecret)))
+
+	var parsed struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal([]byte(pullSecret), &parsed); err != nil {
+		return
+	}
+	for _, registry := range parsed.Auths {
+		Register(registry.Auth)
+	}
+}
+
+// RegisterEnvironment registers the credentials and the pull secret given
+// in environment variables, so those passed to the installer without ever
+// being written to disk are not written to its log either.
+func RegisterEnvironment() {
+	for _, name := range secretEnvironmentVariables {
+		Register(os.Getenv(name))
+	}
+	if pullSecret := os.Getenv("OPENSHIFT_INSTALL_PULL_SECRET"); pullSecret != "" {
+		RegisterPullSecret(pullSecret)
+	}
+}
+
+// String returns s with all registered and recognized secrets replaced by
+// Placeholder.
+func String(s string) string {
+	lock.RLock()
+	for _, secret := range secrets {
+		s = strings.Replace(s, secret, Placeholder, -1)
+	}
+	lock.RUnlock()
+
+	for _, p := range patterns {
+		s = p.regexp.ReplaceAllString(s, p.replacement)
+	}
+	return s
+}
+
+// Bytes is like String, but for byte slices.
+func Bytes(b []byte) []byte {
+	return []byte(String(string(b)))
+}
@@ -0,0 +1,113 @@
+// Package reproducible provides the randomness and timestamps used when
+// generating assets, so that two runs with the same seed and source date
+// produce byte-identical assets.
+package reproducible
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SeedEnvVar names the environment variable holding the seed from
+	// which all randomness is derived. Randomness comes from crypto/rand
+	// when it is unset.
+	SeedEnvVar = "OPENSHIFT_INSTALL_SEED"
+
+	// SourceDateEpochEnvVar names the environment variable holding the
+	// Unix time used in place of the current time, following
+	// https://reproducible-builds.org/specs/source-date-epoch/.
+	SourceDateEpochEnvVar = "SOURCE_DATE_EPOCH"
+)
+
+// Seed returns the configured seed, or an empty string if none is set.
+func Seed() string {
+	return os.Getenv(SeedEnvVar)
+}
+
+var (
+	streamsLock sync.Mutex
+	streams     = map[string]*stream{}
+)
+
+// Reader returns the source of randomness for purpose. Readers for the
+// same purpose share a single stream, so consumers get the same bytes as
+// long as they read in the same order. Without a seed, it is
+// crypto/rand.Reader.
+func Reader(purpose string) io.Reader {
+	seed := Seed()
+	if seed == "" {
+		return rand.Reader
+	}
+
+	streamsLock.Lock()
+	defer streamsLock.Unlock()
+	s, ok := streams[purpose]
+	if !ok || string(s.key) != seed {
+		s = &stream{key: []byte(seed), purpose: []byte(purpose)}
+		streams[purpose] = s
+	}
+	return s
+}
+
+// Reset discards the state of every stream, so readers start again from the
+// beginning of their streams.
+func Reset() {
+	streamsLock.Lock()
+	defer streamsLock.Unlock()
+	streams = map[string]*stream{}
+}
+
+// Now returns the time from SOURCE_DATE_EPOCH, or the current time when it
+// is unset.
+func Now() (time.Time, error) {
+	value, ok := os.LookupEnv(SourceDateEpochEnvVar)
+	if !ok {
+		return time.Now(), nil
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "invalid %s", SourceDateEpochEnvVar)
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
+
+// stream is a deterministic byte stream of HMAC-SHA256(seed, purpose ||
+// counter) blocks.
+type stream struct {
+	sync.Mutex
+	key     []byte
+	purpose []byte
+	counter uint64
+	buf     []byte
+}
+
+func (s *stream) Read(p []byte) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+	n := 0
+	for n < len(p) {
+		if len(s.buf) == 0 {
+			mac := hmac.New(sha256.New, s.key)
+			mac.Write(s.purpose)
+			counter := make([]byte, 8)
+			binary.BigEndian.PutUint64(counter, s.counter)
+			mac.Write(counter)
+			s.buf = mac.Sum(nil)
+			s.counter++
+		}
+		copied := copy(p[n:], s.buf)
+		s.buf = s.buf[copied:]
+		n += copied
+	}
+	return n, nil
+}
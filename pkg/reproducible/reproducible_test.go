@@ -0,0 +1,50 @@
+package reproducible
+
+import (
+	"crypto/rand"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func read(t *testing.T, r io.Reader, n int) []byte {
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	assert.NoError(t, err)
+	return buf
+}
+
+func TestReader(t *testing.T) {
+	os.Unsetenv(SeedEnvVar)
+	assert.Equal(t, rand.Reader, Reader("test"))
+
+	os.Setenv(SeedEnvVar, "seed")
+	defer os.Unsetenv(SeedEnvVar)
+
+	Reset()
+	first := read(t, Reader("test"), 48)
+	other := read(t, Reader("other"), 48)
+	next := read(t, Reader("test"), 16)
+
+	Reset()
+	again := read(t, Reader("test"), 64)
+
+	assert.NotEqual(t, first, other)
+	assert.Equal(t, append(first, next...), again)
+}
+
+func TestNow(t *testing.T) {
+	os.Setenv(SourceDateEpochEnvVar, "1546300800")
+	defer os.Unsetenv(SourceDateEpochEnvVar)
+
+	now, err := Now()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), now)
+
+	os.Setenv(SourceDateEpochEnvVar, "yesterday")
+	_, err = Now()
+	assert.EqualError(t, err, `invalid SOURCE_DATE_EPOCH: strconv.ParseInt: parsing "yesterday": invalid syntax`)
+}
@@ -32,6 +32,27 @@ type metadata struct {
 	OSTreeVersion string `json:"ostree-version"`
 }
 
+// ArchitectureChannel returns the channel on which the RHCOS builds for
+// arch, named as by Go (e.g. arm64), are published: channel itself for
+// amd64, and <channel>-<RHCOS architecture> (e.g. maipo-aarch64) for the
+// others.
+func ArchitectureChannel(channel, arch string) string {
+	switch arch {
+	case "", "amd64":
+		return channel
+	case "arm64":
+		return channel + "-aarch64"
+	default:
+		return channel + "-" + arch
+	}
+}
+
+// LatestBuild fetches the name of the latest Red Hat CoreOS build on the
+// channel, which is the build new clusters are installed with.
+func LatestBuild(ctx context.Context, channel string) (string, error) {
+	return fetchLatestBuild(ctx, channel)
+}
+
 func fetchLatestMetadata(ctx context.Context, channel string) (metadata, error) {
 	build, err := fetchLatestBuild(ctx, channel)
 	if err != nil {
@@ -39,7 +60,7 @@ func fetchLatestMetadata(ctx context.Context, channel string) (metadata, error)
 	}
 
 	url := fmt.Sprintf("%s/%s/%s/meta.json", baseURL, channel, build)
-	logrus.Debugf("Fetching RHCOS metadata from %q", url)
+	logrus.WithField("component", "http").Debugf("Fetching RHCOS metadata from %q", url)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return metadata{}, errors.Wrap(err, "failed to build request")
@@ -71,7 +92,7 @@ func fetchLatestMetadata(ctx context.Context, channel string) (metadata, error)
 
 func fetchLatestBuild(ctx context.Context, channel string) (string, error) {
 	url := fmt.Sprintf("%s/%s/builds.json", baseURL, channel)
-	logrus.Debugf("Fetching RHCOS builds from %q", url)
+	logrus.WithField("component", "http").Debugf("Fetching RHCOS builds from %q", url)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to build request")
@@ -0,0 +1,36 @@
+package terraform
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// state is the part of a Terraform state file holding the outputs.
+type state struct {
+	Modules []struct {
+		Path    []string `json:"path"`
+		Outputs map[string]struct {
+			Value interface{} `json:"value"`
+		} `json:"outputs"`
+	} `json:"modules"`
+}
+
+// Outputs returns the outputs of the root module of a Terraform state
+// file.
+func Outputs(stateData []byte) (map[string]interface{}, error) {
+	var s state
+	if err := json.Unmarshal(stateData, &s); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the Terraform state")
+	}
+	outputs := map[string]interface{}{}
+	for _, module := range s.Modules {
+		if len(module.Path) != 1 || module.Path[0] != "root" {
+			continue
+		}
+		for name, output := range module.Outputs {
+			outputs[name] = output.Value
+		}
+	}
+	return outputs, nil
+}
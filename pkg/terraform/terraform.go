@@ -1,8 +1,12 @@
 package terraform
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"path"
 	"path/filepath"
+	"regexp"
 
 	"github.com/openshift/installer/data"
 	"github.com/pkg/errors"
@@ -20,11 +24,57 @@ const (
 	VarFileName string = "terraform.tfvars"
 )
 
+var (
+	providerPattern        = regexp.MustCompile(`^provider "([^"]+)" {$`)
+	providerVersionPattern = regexp.MustCompile(`^  version\s*=\s*"([^"]*)"$`)
+
+	// logger tags Terraform's output, so its level can be set apart.
+	logger = logrus.WithField("component", "terraform")
+)
+
 // Version gets the output of 'terrraform version'.
 func Version() (version string, err error) {
 	return texec.Version(), nil
 }
 
+// ProviderVersions gets the version constraints of the providers used by
+// the platform-specific Terraform modules, keyed by provider name.
+// Providers without a constraint map to an empty string.
+func ProviderVersions(platform string) (map[string]string, error) {
+	file, err := data.Assets.Open(path.Join(platform, "main.tf"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseProviderVersions(file)
+}
+
+// parseProviderVersions reads the version constraints from the top-level
+// provider blocks of a Terraform configuration.
+func parseProviderVersions(r io.Reader) (map[string]string, error) {
+	versions := map[string]string{}
+	provider := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := providerPattern.FindStringSubmatch(line); match != nil {
+			provider = match[1]
+			versions[provider] = ""
+			continue
+		}
+		if provider == "" {
+			continue
+		}
+		if line == "}" {
+			provider = ""
+		} else if match := providerVersionPattern.FindStringSubmatch(line); match != nil {
+			versions[provider] = match[1]
+		}
+	}
+	return versions, scanner.Err()
+}
+
 // Apply unpacks the platform-specific Terraform modules into the
 // given directory and then runs 'terraform init' and 'terraform
 // apply'.  It returns the absolute path of the tfstate file, rooted
@@ -46,8 +96,8 @@ func Apply(dir string, platform string, extraArgs ...string) (path string, err e
 	args = append(args, dir)
 	sf := filepath.Join(dir, StateFileName)
 
-	tDebug := &lineprinter.Trimmer{WrappedPrint: logrus.Debug}
-	tError := &lineprinter.Trimmer{WrappedPrint: logrus.Error}
+	tDebug := &lineprinter.Trimmer{WrappedPrint: logger.Debug}
+	tError := &lineprinter.Trimmer{WrappedPrint: logger.Error}
 	lpDebug := &lineprinter.LinePrinter{Print: tDebug.Print}
 	lpError := &lineprinter.LinePrinter{Print: tError.Print}
 	defer lpDebug.Close()
@@ -78,8 +128,8 @@ func Destroy(dir string, platform string, extraArgs ...string) (err error) {
 	args := append(defaultArgs, extraArgs...)
 	args = append(args, dir)
 
-	tDebug := &lineprinter.Trimmer{WrappedPrint: logrus.Debug}
-	tError := &lineprinter.Trimmer{WrappedPrint: logrus.Error}
+	tDebug := &lineprinter.Trimmer{WrappedPrint: logger.Debug}
+	tError := &lineprinter.Trimmer{WrappedPrint: logger.Error}
 	lpDebug := &lineprinter.LinePrinter{Print: tDebug.Print}
 	lpError := &lineprinter.LinePrinter{Print: tError.Print}
 	defer lpDebug.Close()
@@ -115,8 +165,8 @@ func unpackAndInit(dir string, platform string) (err error) {
 		return errors.Wrap(err, "failed to unpack Terraform modules")
 	}
 
-	tDebug := &lineprinter.Trimmer{WrappedPrint: logrus.Debug}
-	tError := &lineprinter.Trimmer{WrappedPrint: logrus.Error}
+	tDebug := &lineprinter.Trimmer{WrappedPrint: logger.Debug}
+	tError := &lineprinter.Trimmer{WrappedPrint: logger.Error}
 	lpDebug := &lineprinter.LinePrinter{Print: tDebug.Print}
 	lpError := &lineprinter.LinePrinter{Print: tError.Print}
 	defer lpDebug.Close()
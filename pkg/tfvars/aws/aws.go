@@ -14,21 +14,62 @@ const (
 
 // AWS converts AWS related config.
 type AWS struct {
-	EC2AMIOverride string    `json:"aws_ec2_ami_override,omitempty"`
-	Endpoints      Endpoints `json:"aws_endpoints,omitempty"`
-	External       `json:",inline"`
-	ExtraTags      map[string]string `json:"aws_extra_tags,omitempty"`
-	InstallerRole  string            `json:"aws_installer_role,omitempty"`
-	Master         `json:",inline"`
-	Region         string `json:"aws_region,omitempty"`
-	VPCCIDRBlock   string `json:"aws_vpc_cidr_block,omitempty"`
-	Worker         `json:",inline"`
+	APILoadBalancer  `json:",inline"`
+	Bastion          `json:",inline"`
+	BootstrapEC2Type string    `json:"aws_bootstrap_ec2_type,omitempty"`
+	EC2AMIOverride   string    `json:"aws_ec2_ami_override,omitempty"`
+	Endpoints        Endpoints `json:"aws_endpoints,omitempty"`
+	External         `json:",inline"`
+	ExtraTags        map[string]string `json:"aws_extra_tags,omitempty"`
+	InstallerRole    string            `json:"aws_installer_role,omitempty"`
+	Master           `json:",inline"`
+	PrivateZoneOnly  bool   `json:"aws_private_zone_only,omitempty"`
+	Region           string `json:"aws_region,omitempty"`
+	// ServiceEndpoints maps AWS SDK endpoint IDs to URLs.
+	ServiceEndpoints map[string]string `json:"aws_service_endpoints,omitempty"`
+	// TagKeyPrefix prefixes the keys of the cluster identifying tags.
+	TagKeyPrefix string `json:"aws_tag_key_prefix,omitempty"`
+	VPCCIDRBlock string `json:"aws_vpc_cidr_block,omitempty"`
+	// VPCSecondaryCIDRBlocks are the machine networks after the first,
+	// associated with the VPC.
+	VPCSecondaryCIDRBlocks []string `json:"aws_vpc_secondary_cidr_blocks,omitempty"`
+	Worker                 `json:",inline"`
+}
+
+// APILoadBalancer converts API load balancer related config.
+type APILoadBalancer struct {
+	Type                   string `json:"aws_api_lb_type,omitempty"`
+	Internal               bool   `json:"aws_api_lb_internal,omitempty"`
+	IdleTimeout            int    `json:"aws_api_lb_idle_timeout,omitempty"`
+	APIHealthCheckInterval int    `json:"aws_api_lb_api_health_check_interval,omitempty"`
+	APIHealthyThreshold    int    `json:"aws_api_lb_api_healthy_threshold,omitempty"`
+	APIUnhealthyThreshold  int    `json:"aws_api_lb_api_unhealthy_threshold,omitempty"`
+	MCSHealthCheckInterval int    `json:"aws_api_lb_mcs_health_check_interval,omitempty"`
+	MCSHealthyThreshold    int    `json:"aws_api_lb_mcs_healthy_threshold,omitempty"`
+	MCSUnhealthyThreshold  int    `json:"aws_api_lb_mcs_unhealthy_threshold,omitempty"`
+}
+
+// Bastion converts SSH bastion related config.
+type Bastion struct {
+	Enabled      bool     `json:"aws_bastion,omitempty"`
+	EC2Type      string   `json:"aws_bastion_ec2_type,omitempty"`
+	AllowedCIDRs []string `json:"aws_bastion_allowed_cidrs,omitempty"`
+	SSHKeys      []string `json:"aws_bastion_ssh_keys,omitempty"`
+}
+
+// HostedZone is an existing Route53 hosted zone for the cluster's records.
+type HostedZone struct {
+	// ID is the ID of the zone, without the "/hostedzone/" prefix.
+	ID string
+	// Private is true for zones only resolvable within their VPCs.
+	Private bool
 }
 
 // External converts external related config.
 type External struct {
 	MasterSubnetIDs []string `json:"aws_external_master_subnet_ids,omitempty"`
 	PrivateZone     string   `json:"aws_external_private_zone,omitempty"`
+	PublicZone      string   `json:"aws_external_public_zone,omitempty"`
 	VPCID           string   `json:"aws_external_vpc_id,omitempty"`
 	WorkerSubnetIDs []string `json:"aws_external_worker_subnet_ids,omitempty"`
 }
@@ -40,6 +81,8 @@ type Master struct {
 	ExtraSGIDs       []string          `json:"aws_master_extra_sg_ids,omitempty"`
 	IAMRoleName      string            `json:"aws_master_iam_role_name,omitempty"`
 	MasterRootVolume `json:",inline"`
+	PlacementGroup   string `json:"aws_master_placement_group,omitempty"`
+	Tenancy          string `json:"aws_master_tenancy,omitempty"`
 }
 
 // MasterRootVolume converts master rool volume related config.
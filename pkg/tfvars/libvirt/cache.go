@@ -1,11 +1,15 @@
 package libvirt
 
 import (
+	"bytes"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -15,12 +19,26 @@ import (
 	"golang.org/x/sys/unix"
 )
 
+const (
+	// CacheDirEnvVar names the environment variable holding the directory
+	// in which OS images are cached.
+	CacheDirEnvVar = "OPENSHIFT_INSTALL_LIBVIRT_CACHE_DIR"
+
+	// CachePoolEnvVar names the environment variable holding the name of
+	// a libvirt directory storage pool whose target is the image
+	// directory of the cache.  When it is set, cluster volumes are backed
+	// by the cached image instead of a copy of it.
+	CachePoolEnvVar = "OPENSHIFT_INSTALL_LIBVIRT_CACHE_POOL"
+)
+
 // UseCachedImage leaves non-file:// image URIs unalterered.
 // Other URIs are retrieved with a local cache at
+// $OPENSHIFT_INSTALL_LIBVIRT_CACHE_DIR, defaulting to
 // $XDG_CACHE_HOME/openshift-install/libvirt [1].  This allows you to
 // use the same remote image URI multiple times without needing to
 // worry about redundant downloads, although you will want to
-// periodically blow away your cache.
+// periodically blow away your cache.  Cached images are checked against
+// the SHA-256 recorded when they were downloaded before they are used.
 //
 // [1]: https://standards.freedesktop.org/basedir-spec/basedir-spec-0.7.html
 func (libvirt *Libvirt) UseCachedImage() (err error) {
@@ -30,14 +48,7 @@ func (libvirt *Libvirt) UseCachedImage() (err error) {
 
 	logrus.Infof("Fetching OS image...")
 
-	// FIXME: Use os.UserCacheDir() once we bump to Go 1.11
-	// baseCacheDir, err := os.UserCacheDir()
-	// if err != nil {
-	// 	return err
-	// }
-	baseCacheDir := filepath.Join(os.Getenv("HOME"), ".cache")
-
-	cacheDir := filepath.Join(baseCacheDir, "openshift-install", "libvirt")
+	cacheDir := cacheDir()
 	httpCacheDir := filepath.Join(cacheDir, "http")
 	err = os.MkdirAll(httpCacheDir, 0777)
 	if err != nil {
@@ -70,6 +81,10 @@ func (libvirt *Libvirt) UseCachedImage() (err error) {
 	_, err = os.Stat(imagePath)
 	if err == nil {
 		logrus.Debugf("Using cached OS image %q", imagePath)
+		err = verifyImage(imagePath)
+		if err != nil {
+			return err
+		}
 	} else {
 		if !os.IsNotExist(err) {
 			return err
@@ -82,9 +97,67 @@ func (libvirt *Libvirt) UseCachedImage() (err error) {
 	}
 
 	libvirt.Image = fmt.Sprintf("file://%s", filepath.ToSlash(imagePath))
+
+	if pool := os.Getenv(CachePoolEnvVar); pool != "" {
+		// libvirt only sees files added to a directory pool once the
+		// pool is refreshed.
+		logrus.Debugf("Refreshing libvirt storage pool %q", pool)
+		out, err := exec.Command("virsh", "-c", libvirt.URI, "pool-refresh", pool).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to refresh libvirt storage pool %q: %v: %s", pool, err, bytes.TrimSpace(out))
+		}
+		libvirt.BaseVolumePool = pool
+		libvirt.BaseVolumeName = key
+	}
+	return nil
+}
+
+// cacheDir returns the directory in which OS images are cached.
+func cacheDir() string {
+	if dir := os.Getenv(CacheDirEnvVar); dir != "" {
+		return dir
+	}
+
+	// FIXME: Use os.UserCacheDir() once we bump to Go 1.11
+	baseCacheDir := os.Getenv("XDG_CACHE_HOME")
+	if baseCacheDir == "" {
+		baseCacheDir = filepath.Join(os.Getenv("HOME"), ".cache")
+	}
+	return filepath.Join(baseCacheDir, "openshift-install", "libvirt")
+}
+
+// verifyImage checks the image at imagePath against the SHA-256 recorded
+// by cacheImage.
+func verifyImage(imagePath string) error {
+	expected, err := ioutil.ReadFile(checksumPath(imagePath))
+	if os.IsNotExist(err) {
+		// Cached before checksums were recorded.
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return err
+	}
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != strings.TrimSpace(string(expected)) {
+		return fmt.Errorf("cached OS image %q is corrupt (SHA-256 %s, expected %s); remove it to download it again", imagePath, actual, strings.TrimSpace(string(expected)))
+	}
 	return nil
 }
 
+func checksumPath(imagePath string) string {
+	return fmt.Sprintf("%s.sha256", imagePath)
+}
+
 func cacheKey(etag string) (key string, err error) {
 	if etag == "" {
 		return "", fmt.Errorf("caching is not supported when ETag is unset")
@@ -107,18 +180,14 @@ func cacheKey(etag string) (key string, err error) {
 func cacheImage(reader io.Reader, imagePath string) (err error) {
 	logrus.Debugf("Unpacking OS image into %q...", imagePath)
 
+	// The lock file is left behind: removing it would let an installer
+	// waiting on it and one creating it afresh unpack the image at once.
 	flockPath := fmt.Sprintf("%s.lock", imagePath)
-	flock, err := os.Create(flockPath)
+	flock, err := os.OpenFile(flockPath, os.O_WRONLY|os.O_CREATE, 0666)
 	if err != nil {
 		return err
 	}
 	defer flock.Close()
-	defer func() {
-		err2 := os.Remove(flockPath)
-		if err == nil {
-			err = err2
-		}
-	}()
 
 	err = unix.Flock(int(flock.Fd()), unix.LOCK_EX)
 	if err != nil {
@@ -132,8 +201,10 @@ func cacheImage(reader io.Reader, imagePath string) (err error) {
 	}()
 
 	_, err = os.Stat(imagePath)
-	if err != nil && !os.IsNotExist(err) {
+	if err == nil {
 		return nil // another cacheImage beat us to it
+	} else if !os.IsNotExist(err) {
+		return err
 	}
 
 	tempPath := fmt.Sprintf("%s.tmp", imagePath)
@@ -148,7 +219,8 @@ func cacheImage(reader io.Reader, imagePath string) (err error) {
 		}
 	}()
 
-	_, err = io.Copy(file, reader)
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(file, hash), reader)
 	if err != nil {
 		return err
 	}
@@ -159,5 +231,10 @@ func cacheImage(reader io.Reader, imagePath string) (err error) {
 	}
 	closed = true
 
+	err = ioutil.WriteFile(checksumPath(imagePath), []byte(hex.EncodeToString(hash.Sum(nil))+"\n"), 0644)
+	if err != nil {
+		return err
+	}
+
 	return os.Rename(tempPath, imagePath)
 }
@@ -7,6 +7,17 @@ import (
 	"github.com/apparentlymart/go-cidr/cidr"
 )
 
+// Host offsets in the network's ipRange of the cluster's default addresses.
+const (
+	bootstrapHostOffset = 10
+	masterHostOffset    = 11
+
+	// ingressHostOffset is where the cluster-API libvirt actuator starts
+	// handing out worker addresses, so the first worker hosts the default
+	// router.
+	ingressHostOffset = 51
+)
+
 // Libvirt encompasses configuration specific to libvirt.
 type Libvirt struct {
 	URI         string `json:"libvirt_uri,omitempty"`
@@ -14,6 +25,13 @@ type Libvirt struct {
 	Network     `json:",inline"`
 	MasterIPs   []string `json:"libvirt_master_ips,omitempty"`
 	BootstrapIP string   `json:"libvirt_bootstrap_ip,omitempty"`
+	// BootstrapMemory is in MiB.
+	BootstrapMemory int    `json:"libvirt_bootstrap_memory,omitempty"`
+	IngressIP       string `json:"libvirt_ingress_ip,omitempty"`
+	// BaseVolumePool and BaseVolumeName name a cached image shared by
+	// clusters, which backs the cluster's base volume instead of Image.
+	BaseVolumePool string `json:"libvirt_base_volume_pool,omitempty"`
+	BaseVolumeName string `json:"libvirt_base_volume_name,omitempty"`
 }
 
 // Network describes a libvirt network configuration.
@@ -30,19 +48,30 @@ func (l *Libvirt) TFVars(masterCount int) error {
 	}
 
 	if l.BootstrapIP == "" {
-		ip, err := cidr.Host(network, 10)
+		ip, err := hostIP(network, bootstrapHostOffset)
 		if err != nil {
 			return fmt.Errorf("failed to generate bootstrap IP: %v", err)
 		}
 		l.BootstrapIP = ip.String()
 	}
 
+	if l.IngressIP == "" {
+		ip, err := hostIP(network, ingressHostOffset)
+		if err != nil {
+			return fmt.Errorf("failed to generate ingress IP: %v", err)
+		}
+		l.IngressIP = ip.String()
+	}
+
 	if len(l.MasterIPs) > 0 {
 		if len(l.MasterIPs) != masterCount {
 			return fmt.Errorf("length of MasterIPs doesn't match master count")
 		}
 	} else {
-		if ips, err := generateIPs("master", network, masterCount, 11); err == nil {
+		if masterHostOffset+masterCount > ingressHostOffset {
+			return fmt.Errorf("%d masters do not fit below the ingress IP", masterCount)
+		}
+		if ips, err := generateIPs("master", network, masterCount, masterHostOffset); err == nil {
 			l.MasterIPs = ips
 		} else {
 			return err
@@ -55,7 +84,7 @@ func (l *Libvirt) TFVars(masterCount int) error {
 func generateIPs(name string, network *net.IPNet, count int, offset int) ([]string, error) {
 	var ips []string
 	for i := 0; i < count; i++ {
-		ip, err := cidr.Host(network, offset+i)
+		ip, err := hostIP(network, offset+i)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate %s IPs: %v", name, err)
 		}
@@ -64,3 +93,16 @@ func generateIPs(name string, network *net.IPNet, count int, offset int) ([]stri
 
 	return ips, nil
 }
+
+// hostIP returns the host at offset in network, which must be neither the
+// network's nor its broadcast address.
+func hostIP(network *net.IPNet, offset int) (net.IP, error) {
+	ip, err := cidr.Host(network, offset)
+	if err != nil {
+		return nil, err
+	}
+	if _, broadcast := cidr.AddressRange(network); offset <= 0 || ip.Equal(broadcast) {
+		return nil, fmt.Errorf("%s is not a host address of %s", ip, network)
+	}
+	return ip, nil
+}
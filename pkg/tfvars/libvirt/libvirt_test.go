@@ -0,0 +1,90 @@
+package libvirt
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTFVars(t *testing.T) {
+	cases := []struct {
+		name          string
+		ipRange       string
+		masterCount   int
+		expected      *Libvirt
+		expectedError string
+	}{
+		{
+			name:        "default",
+			ipRange:     "192.168.126.0/24",
+			masterCount: 3,
+			expected: &Libvirt{
+				Network:     Network{IPRange: "192.168.126.0/24"},
+				BootstrapIP: "192.168.126.10",
+				MasterIPs:   []string{"192.168.126.11", "192.168.126.12", "192.168.126.13"},
+				IngressIP:   "192.168.126.51",
+			},
+		},
+		{
+			name:        "smallest range",
+			ipRange:     "10.0.0.0/26",
+			masterCount: 1,
+			expected: &Libvirt{
+				Network:     Network{IPRange: "10.0.0.0/26"},
+				BootstrapIP: "10.0.0.10",
+				MasterIPs:   []string{"10.0.0.11"},
+				IngressIP:   "10.0.0.51",
+			},
+		},
+		{
+			name:          "ingress IP outside of range",
+			ipRange:       "10.0.0.0/27",
+			masterCount:   1,
+			expectedError: `^failed to generate ingress IP: .*$`,
+		},
+		{
+			name:          "masters overlap the ingress IP",
+			ipRange:       "192.168.126.0/24",
+			masterCount:   41,
+			expectedError: `^41 masters do not fit below the ingress IP$`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := &Libvirt{Network: Network{IPRange: tc.ipRange}}
+			err := l.TFVars(tc.masterCount)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, l)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestHostIP(t *testing.T) {
+	_, network, _ := net.ParseCIDR("10.0.0.0/28")
+	cases := []struct {
+		offset        int
+		expected      string
+		expectedError string
+	}{
+		{offset: 1, expected: "10.0.0.1"},
+		{offset: 14, expected: "10.0.0.14"},
+		{offset: 0, expectedError: `^10\.0\.0\.0 is not a host address of 10\.0\.0\.0/28$`},
+		{offset: 15, expectedError: `^10\.0\.0\.15 is not a host address of 10\.0\.0\.0/28$`},
+		{offset: 16, expectedError: `^prefix of 28 does not accommodate a host numbered 16$`},
+	}
+	for _, tc := range cases {
+		ip, err := hostIP(network, tc.offset)
+		if tc.expectedError == "" {
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, ip.String())
+			}
+		} else {
+			assert.Regexp(t, tc.expectedError, err)
+		}
+	}
+}
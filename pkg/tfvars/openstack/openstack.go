@@ -2,14 +2,18 @@ package openstack
 
 // OpenStack converts OpenStack related config.
 type OpenStack struct {
-	BaseImage        string `json:"openstack_base_image,omitempty"`
-	Credentials      `json:",inline"`
-	External         `json:",inline"`
-	ExternalNetwork  string            `json:"openstack_external_network,omitempty"`
-	ExtraTags        map[string]string `json:"openstack_extra_tags,omitempty"`
-	Master           `json:",inline"`
-	Region           string `json:"openstack_region,omitempty"`
-	NetworkCIDRBlock string `json:"openstack_network_cidr_block,omitempty"`
+	AllocateFloatingIPs bool   `json:"openstack_allocate_floating_ips,omitempty"`
+	APIFloatingIP       string `json:"openstack_api_floating_ip,omitempty"`
+	BaseImage           string `json:"openstack_base_image,omitempty"`
+	BootstrapFlavorName string `json:"openstack_bootstrap_flavor_name,omitempty"`
+	Credentials         `json:",inline"`
+	External            `json:",inline"`
+	ExternalNetwork     string            `json:"openstack_external_network,omitempty"`
+	ExtraTags           map[string]string `json:"openstack_extra_tags,omitempty"`
+	IngressFloatingIP   string            `json:"openstack_ingress_floating_ip,omitempty"`
+	Master              `json:",inline"`
+	Region              string `json:"openstack_region,omitempty"`
+	NetworkCIDRBlock    string `json:"openstack_network_cidr_block,omitempty"`
 }
 
 // External converts external related config.
@@ -19,8 +23,11 @@ type External struct {
 
 // Master converts master related config.
 type Master struct {
-	FlavorName string   `json:"openstack_master_flavor_name,omitempty"`
-	ExtraSGIDs []string `json:"openstack_master_extra_sg_ids,omitempty"`
+	FlavorName     string   `json:"openstack_master_flavor_name,omitempty"`
+	ExtraSGIDs     []string `json:"openstack_master_extra_sg_ids,omitempty"`
+	RootVolumeSize int      `json:"openstack_master_root_volume_size,omitempty"`
+	RootVolumeType string   `json:"openstack_master_root_volume_type,omitempty"`
+	Zones          []string `json:"openstack_master_availability_zones,omitempty"`
 }
 
 // Credentials converts credentials related config.
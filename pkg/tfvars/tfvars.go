@@ -11,15 +11,20 @@ import (
 	"github.com/openshift/installer/pkg/tfvars/libvirt"
 	"github.com/openshift/installer/pkg/tfvars/openstack"
 	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
 	"github.com/pkg/errors"
 )
 
 type config struct {
 	ClusterID  string `json:"cluster_id,omitempty"`
 	Name       string `json:"cluster_name,omitempty"`
+	InfraID    string `json:"cluster_infra_id,omitempty"`
 	BaseDomain string `json:"base_domain,omitempty"`
 	Masters    int    `json:"master_count,omitempty"`
 
+	MachineConfigServerPort int `json:"machine_config_server_port,omitempty"`
+
 	IgnitionBootstrap string `json:"ignition_bootstrap,omitempty"`
 	IgnitionMaster    string `json:"ignition_master,omitempty"`
 
@@ -29,20 +34,23 @@ type config struct {
 }
 
 // TFVars converts the InstallConfig and Ignition content to
-// terraform.tfvar JSON.
-func TFVars(cfg *types.InstallConfig, bootstrapIgn, masterIgn string) ([]byte, error) {
+// terraform.tfvar JSON.  hostedZone is the AWS hosted zone configured in
+// the InstallConfig, as looked up by the caller, or nil.
+func TFVars(cfg *types.InstallConfig, bootstrapIgn, masterIgn string, hostedZone *aws.HostedZone) ([]byte, error) {
 	config := &config{
 		ClusterID:  cfg.ClusterID,
 		Name:       cfg.ObjectMeta.Name,
+		InfraID:    cfg.InfrastructureName(),
 		BaseDomain: cfg.BaseDomain,
 
+		MachineConfigServerPort: cfg.MachineConfigServerPort(),
+
 		IgnitionMaster:    masterIgn,
 		IgnitionBootstrap: bootstrapIgn,
 	}
 
 	for _, m := range cfg.Machines {
-		switch m.Name {
-		case "master":
+		if m.Name == "master" {
 			var replicas int
 			if m.Replicas == nil {
 				replicas = 1
@@ -51,34 +59,43 @@ func TFVars(cfg *types.InstallConfig, bootstrapIgn, masterIgn string) ([]byte, e
 			}
 
 			config.Masters += replicas
-			if m.Platform.AWS != nil {
-				config.AWS.Master = aws.Master{
-					EC2Type:     m.Platform.AWS.InstanceType,
-					IAMRoleName: m.Platform.AWS.IAMRoleName,
-					MasterRootVolume: aws.MasterRootVolume{
-						IOPS: m.Platform.AWS.EC2RootVolume.IOPS,
-						Size: m.Platform.AWS.EC2RootVolume.Size,
-						Type: m.Platform.AWS.EC2RootVolume.Type,
-					},
-				}
-			}
-		case "worker":
-			if m.Platform.AWS != nil {
-				config.AWS.Worker = aws.Worker{
-					IAMRoleName: m.Platform.AWS.IAMRoleName,
-				}
-			}
-		default:
-			return nil, errors.Errorf("unrecognized machine pool %q", m.Name)
 		}
 	}
 
 	if cfg.Platform.AWS != nil {
-		ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
-		defer cancel()
-		ami, err := rhcos.AMI(ctx, rhcos.DefaultChannel, cfg.Platform.AWS.Region)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to determine default AMI")
+		masterPool := awstypes.MachinePool{}
+		masterPool.Set(cfg.Platform.AWS.DefaultMachinePlatform)
+		workerPool := awstypes.MachinePool{}
+		workerPool.Set(cfg.Platform.AWS.DefaultMachinePlatform)
+		for _, m := range cfg.Machines {
+			switch m.Name {
+			case "master":
+				masterPool.Set(m.Platform.AWS)
+			case "worker":
+				workerPool.Set(m.Platform.AWS)
+			default:
+				// Named compute pools are created by the machine API,
+				// and use the worker IAM role.
+			}
+		}
+
+		// An AMI is required in regions without published RHCOS AMIs
+		// (e.g. those of the aws-cn partition).
+		ami := masterPool.AMIID
+		if ami == "" {
+			ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+			defer cancel()
+			var err error
+			ami, err = rhcos.AMI(ctx, rhcos.DefaultChannel, cfg.Platform.AWS.Region)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to determine default AMI")
+			}
+		}
+
+		vpcCIDRBlock := cfg.Platform.AWS.VPCCIDRBlock
+		var vpcSecondaryCIDRBlocks []string
+		if cidrs := cfg.MachineCIDRs(); len(cidrs) > 0 {
+			vpcCIDRBlock, vpcSecondaryCIDRBlocks = cidrs[0], cidrs[1:]
 		}
 
 		config.AWS = aws.AWS{
@@ -88,8 +105,78 @@ func TFVars(cfg *types.InstallConfig, bootstrapIgn, masterIgn string) ([]byte, e
 			External: aws.External{
 				VPCID: cfg.Platform.AWS.VPCID,
 			},
-			VPCCIDRBlock:   cfg.Platform.AWS.VPCCIDRBlock,
-			EC2AMIOverride: ami,
+			TagKeyPrefix:           cfg.Platform.AWS.TagKey(""),
+			VPCCIDRBlock:           vpcCIDRBlock,
+			VPCSecondaryCIDRBlocks: vpcSecondaryCIDRBlocks,
+			EC2AMIOverride:         ami,
+			BootstrapEC2Type:       cfg.Platform.AWS.BootstrapInstanceType,
+			PrivateZoneOnly:        cfg.Platform.AWS.PrivateZoneOnly,
+			Master: aws.Master{
+				EC2Type:     masterPool.InstanceType,
+				ExtraSGIDs:  masterPool.AdditionalSecurityGroupIDs,
+				IAMRoleName: masterPool.IAMRoleName,
+				MasterRootVolume: aws.MasterRootVolume{
+					IOPS: masterPool.EC2RootVolume.IOPS,
+					Size: masterPool.EC2RootVolume.Size,
+					Type: masterPool.EC2RootVolume.Type,
+				},
+				PlacementGroup: masterPool.PlacementGroup,
+				Tenancy:        string(masterPool.Tenancy),
+			},
+			Worker: aws.Worker{
+				IAMRoleName: workerPool.IAMRoleName,
+			},
+		}
+		if lb := cfg.Platform.AWS.APILoadBalancer; lb != nil {
+			config.AWS.APILoadBalancer = aws.APILoadBalancer{
+				Internal:    lb.Internal,
+				IdleTimeout: lb.IdleTimeout,
+			}
+			if lb.Type == awstypes.ClassicLoadBalancer {
+				config.AWS.APILoadBalancer.Type = "classic"
+			}
+			for _, listener := range lb.Listeners {
+				switch listener.Name {
+				case awstypes.APIServerListener:
+					config.AWS.APILoadBalancer.APIHealthCheckInterval = listener.HealthCheck.IntervalSeconds
+					config.AWS.APILoadBalancer.APIHealthyThreshold = listener.HealthCheck.HealthyThreshold
+					config.AWS.APILoadBalancer.APIUnhealthyThreshold = listener.HealthCheck.UnhealthyThreshold
+				case awstypes.MachineConfigServerListener:
+					config.AWS.APILoadBalancer.MCSHealthCheckInterval = listener.HealthCheck.IntervalSeconds
+					config.AWS.APILoadBalancer.MCSHealthyThreshold = listener.HealthCheck.HealthyThreshold
+					config.AWS.APILoadBalancer.MCSUnhealthyThreshold = listener.HealthCheck.UnhealthyThreshold
+				}
+			}
+		}
+		if bastion := cfg.Platform.AWS.Bastion; bastion != nil {
+			config.AWS.Bastion = aws.Bastion{
+				Enabled:      true,
+				EC2Type:      bastion.InstanceType,
+				AllowedCIDRs: bastion.AllowedCIDRs,
+				SSHKeys:      cfg.SSHKey,
+			}
+		}
+		if len(cfg.Platform.AWS.ServiceEndpoints) > 0 {
+			config.AWS.ServiceEndpoints = make(map[string]string, len(cfg.Platform.AWS.ServiceEndpoints))
+			for _, endpoint := range cfg.Platform.AWS.ServiceEndpoints {
+				config.AWS.ServiceEndpoints[endpoint.Name] = endpoint.URL
+			}
+		}
+		if awstypes.Partition(cfg.Platform.AWS.Region) == "aws-cn" {
+			if _, ok := config.AWS.ServiceEndpoints["route53"]; !ok {
+				if config.AWS.ServiceEndpoints == nil {
+					config.AWS.ServiceEndpoints = map[string]string{}
+				}
+				config.AWS.ServiceEndpoints["route53"] = awstypes.ChinaRoute53Endpoint
+			}
+		}
+
+		if hostedZone != nil {
+			if hostedZone.Private {
+				config.AWS.External.PrivateZone = hostedZone.ID
+			} else {
+				config.AWS.External.PublicZone = hostedZone.ID
+			}
 		}
 	} else if cfg.Platform.Libvirt != nil {
 		masterIPs := make([]string, len(cfg.Platform.Libvirt.MasterIPs))
@@ -97,13 +184,14 @@ func TFVars(cfg *types.InstallConfig, bootstrapIgn, masterIgn string) ([]byte, e
 			masterIPs[i] = ip.String()
 		}
 		config.Libvirt = libvirt.Libvirt{
-			URI: cfg.Platform.Libvirt.URI,
+			URI: cfg.Platform.Libvirt.ConnectionURI(),
 			Network: libvirt.Network{
 				IfName:  cfg.Platform.Libvirt.Network.IfName,
 				IPRange: cfg.Platform.Libvirt.Network.IPRange,
 			},
-			Image:     cfg.Platform.Libvirt.DefaultMachinePlatform.Image,
-			MasterIPs: masterIPs,
+			Image:           cfg.Platform.Libvirt.DefaultMachinePlatform.Image,
+			MasterIPs:       masterIPs,
+			BootstrapMemory: cfg.Platform.Libvirt.BootstrapMemoryMiB,
 		}
 		if err := config.Libvirt.TFVars(config.Masters); err != nil {
 			return nil, errors.Wrap(err, "failed to insert libvirt variables")
@@ -112,11 +200,32 @@ func TFVars(cfg *types.InstallConfig, bootstrapIgn, masterIgn string) ([]byte, e
 			return nil, errors.Wrap(err, "failed to use cached libvirt image")
 		}
 	} else if cfg.Platform.OpenStack != nil {
+		networkCIDRBlock := cfg.Platform.OpenStack.NetworkCIDRBlock
+		if cidrs := cfg.MachineCIDRs(); len(cidrs) > 0 {
+			networkCIDRBlock = cidrs[0]
+		}
 		config.OpenStack = openstack.OpenStack{
-			Region:           cfg.Platform.OpenStack.Region,
-			NetworkCIDRBlock: cfg.Platform.OpenStack.NetworkCIDRBlock,
-			BaseImage:        cfg.Platform.OpenStack.BaseImage,
+			Region:              cfg.Platform.OpenStack.Region,
+			NetworkCIDRBlock:    networkCIDRBlock,
+			BaseImage:           cfg.Platform.OpenStack.BaseImage,
+			BootstrapFlavorName: cfg.Platform.OpenStack.BootstrapFlavorName,
+			APIFloatingIP:       cfg.Platform.OpenStack.APIFloatingIP,
+			IngressFloatingIP:   cfg.Platform.OpenStack.IngressFloatingIP,
+			AllocateFloatingIPs: cfg.Platform.OpenStack.AllocateFloatingIPs,
+		}
+		mpool := openstacktypes.MachinePool{}
+		mpool.Set(cfg.Platform.OpenStack.DefaultMachinePlatform)
+		for _, m := range cfg.Machines {
+			if m.Name == "master" {
+				mpool.Set(m.Platform.OpenStack)
+			}
+		}
+		config.OpenStack.Master.FlavorName = mpool.FlavorName
+		if mpool.RootVolume != nil {
+			config.OpenStack.Master.RootVolumeSize = mpool.RootVolume.Size
+			config.OpenStack.Master.RootVolumeType = mpool.RootVolume.Type
 		}
+		config.OpenStack.Master.Zones = mpool.Zones
 		config.OpenStack.Credentials.Cloud = cfg.Platform.OpenStack.Cloud
 		config.OpenStack.ExternalNetwork = cfg.Platform.OpenStack.ExternalNetwork
 	}
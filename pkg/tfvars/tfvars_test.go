@@ -0,0 +1,70 @@
+package tfvars
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/tfvars/aws"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+)
+
+func TestTFVarsAWSPrivateZoneOnly(t *testing.T) {
+	cases := []struct {
+		name                string
+		privateZoneOnly     bool
+		hostedZone          *aws.HostedZone
+		expectedOnly        interface{}
+		expectedPrivateZone interface{}
+		expectedPublicZone  interface{}
+	}{
+		{
+			name: "public and private records",
+		},
+		{
+			name:            "private zone only",
+			privateZoneOnly: true,
+			expectedOnly:    true,
+		},
+		{
+			name:                "private zone only in a private hosted zone",
+			privateZoneOnly:     true,
+			hostedZone:          &aws.HostedZone{ID: "Z1ILINNUJGTAO1", Private: true},
+			expectedOnly:        true,
+			expectedPrivateZone: "Z1ILINNUJGTAO1",
+		},
+		{
+			name:               "public hosted zone",
+			hostedZone:         &aws.HostedZone{ID: "Z1ILINNUJGTAO1"},
+			expectedPublicZone: "Z1ILINNUJGTAO1",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &types.InstallConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "test"},
+				BaseDomain: "example.com",
+				Platform: types.Platform{AWS: &awstypes.Platform{
+					Region:          "us-east-1",
+					PrivateZoneOnly: tc.privateZoneOnly,
+					// An AMI keeps TFVars from looking up the RHCOS AMI.
+					DefaultMachinePlatform: &awstypes.MachinePool{AMIID: "ami-0123456789abcdef0"},
+				}},
+			}
+			data, err := TFVars(cfg, "", "", tc.hostedZone)
+			if !assert.NoError(t, err) {
+				return
+			}
+			vars := map[string]interface{}{}
+			if err := json.Unmarshal(data, &vars); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.expectedOnly, vars["aws_private_zone_only"])
+			assert.Equal(t, tc.expectedPrivateZone, vars["aws_external_private_zone"])
+			assert.Equal(t, tc.expectedPublicZone, vars["aws_external_public_zone"])
+		})
+	}
+}
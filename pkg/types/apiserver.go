@@ -0,0 +1,49 @@
+package types
+
+// AuditProfile is a named audit logging policy for the API servers.
+type AuditProfile string
+
+const (
+	// DefaultAuditProfile logs metadata for all requests. It is used when
+	// no profile is configured.
+	DefaultAuditProfile AuditProfile = "Default"
+
+	// WriteRequestBodiesAuditProfile additionally logs the request bodies
+	// of write requests (create, update, patch, and delete).
+	WriteRequestBodiesAuditProfile AuditProfile = "WriteRequestBodies"
+
+	// AllRequestBodiesAuditProfile additionally logs the request bodies of
+	// all requests, including reads.
+	AllRequestBodiesAuditProfile AuditProfile = "AllRequestBodies"
+)
+
+// APIServer configures the cluster API servers.
+type APIServer struct {
+	// Audit configures API server audit logging.
+	// +optional
+	Audit Audit `json:"audit,omitempty"`
+
+	// NamedCertificates are additional serving certificates for the API
+	// server, served to clients requesting their names.
+	// +optional
+	NamedCertificates []NamedCertificate `json:"namedCertificates,omitempty"`
+}
+
+// NamedCertificate is an API server serving certificate for a set of
+// names.
+type NamedCertificate struct {
+	// Names are the DNS names the certificate is served for. Wildcards
+	// (e.g. *.example.com) are allowed.
+	Names []string `json:"names"`
+
+	// Secret is the name of a kubernetes.io/tls secret in configSecrets
+	// holding the certificate and key.
+	Secret string `json:"secret"`
+}
+
+// Audit configures API server audit logging.
+type Audit struct {
+	// Profile is the audit policy to apply. Defaults to Default.
+	// +optional
+	Profile AuditProfile `json:"profile,omitempty"`
+}
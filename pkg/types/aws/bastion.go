@@ -0,0 +1,16 @@
+package aws
+
+// Bastion configures an SSH bastion host, for reaching cluster machines
+// which are not reachable from outside the VPC.
+type Bastion struct {
+	// InstanceType is the EC2 instance type of the bastion.
+	// If empty, t3.micro is used.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// AllowedCIDRs are the networks SSH connections to the bastion are
+	// allowed from.
+	// If empty, connections are allowed from anywhere.
+	// +optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
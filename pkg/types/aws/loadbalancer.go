@@ -0,0 +1,83 @@
+package aws
+
+// LoadBalancerType is the type of the API load balancers.
+type LoadBalancerType string
+
+const (
+	// NetworkLoadBalancer is an Elastic Load Balancing network load
+	// balancer.
+	NetworkLoadBalancer LoadBalancerType = "NLB"
+	// ClassicLoadBalancer is an Elastic Load Balancing classic load
+	// balancer.
+	ClassicLoadBalancer LoadBalancerType = "Classic"
+)
+
+// APIListenerName names a listener of the API load balancers.
+type APIListenerName string
+
+const (
+	// APIServerListener serves the Kubernetes API on port 6443.
+	APIServerListener APIListenerName = "api"
+	// MachineConfigServerListener serves the machine-config server. It is
+	// only on the internal load balancer.
+	MachineConfigServerListener APIListenerName = "machineConfigServer"
+)
+
+// APILoadBalancer configures the load balancers in front of the
+// Kubernetes API.
+type APILoadBalancer struct {
+	// Type is the type of the load balancers.
+	// If empty, NLB is used.
+	// +optional
+	Type LoadBalancerType `json:"type,omitempty"`
+
+	// Internal creates only the internal load balancer, so the API is
+	// not reachable from outside the VPC.
+	// If false, both the internal and the external load balancers are
+	// created.
+	// +optional
+	Internal bool `json:"internal,omitempty"`
+
+	// IdleTimeout is the time in seconds a connection may be idle. It is
+	// only supported for the Classic type, as network load balancers
+	// have a fixed idle timeout.
+	// If zero, 3600 is used.
+	// +optional
+	IdleTimeout int `json:"idleTimeout,omitempty"`
+
+	// Listeners configures the health checks of the listeners. Classic
+	// load balancers have a single health check, that of the api
+	// listener.
+	// +optional
+	Listeners []APIListener `json:"listeners,omitempty"`
+}
+
+// APIListener configures a listener of the API load balancers.
+type APIListener struct {
+	// Name is the name of the listener: api or machineConfigServer.
+	Name APIListenerName `json:"name"`
+
+	// HealthCheck configures the health check of the listener's targets.
+	HealthCheck APIHealthCheck `json:"healthCheck"`
+}
+
+// APIHealthCheck configures the health check of the targets of an API
+// load balancer listener. Zero values use the defaults: a 10 second
+// interval and thresholds of 3.
+type APIHealthCheck struct {
+	// IntervalSeconds is the time between health checks. Network load
+	// balancers support 10 or 30, classic load balancers 5 to 300.
+	// +optional
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+
+	// HealthyThreshold is the number of consecutive successful health
+	// checks after which a target is healthy, from 2 to 10.
+	// +optional
+	HealthyThreshold int `json:"healthyThreshold,omitempty"`
+
+	// UnhealthyThreshold is the number of consecutive failed health
+	// checks after which a target is unhealthy, from 2 to 10. Network
+	// load balancers require it to equal HealthyThreshold.
+	// +optional
+	UnhealthyThreshold int `json:"unhealthyThreshold,omitempty"`
+}
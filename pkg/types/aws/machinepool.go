@@ -1,5 +1,14 @@
 package aws
 
+import (
+	"regexp"
+	"strings"
+)
+
+// gravitonFamily matches the instance families of 64-bit ARM (Graviton)
+// instances, like a1, m6g, c6gn and t4g.
+var gravitonFamily = regexp.MustCompile(`^(a1|[a-z]+[0-9]+g[a-z]*)$`)
+
 // MachinePool stores the configuration for a machine pool installed
 // on AWS.
 type MachinePool struct {
@@ -19,8 +28,37 @@ type MachinePool struct {
 
 	// EC2RootVolume defines the storage for ec2 instance.
 	EC2RootVolume `json:"rootVolume"`
+
+	// Tenancy defines the tenancy of the ec2 instances: default,
+	// dedicated or host.
+	// It is only supported for the master pool.
+	// +optional
+	Tenancy Tenancy `json:"tenancy,omitempty"`
+
+	// PlacementGroup is the name of an existing placement group in
+	// which to launch the ec2 instances.
+	// It is only supported for the master pool.
+	// +optional
+	PlacementGroup string `json:"placementGroup,omitempty"`
+
+	// AdditionalSecurityGroupIDs are the IDs of existing security groups
+	// attached to the ec2 instances in addition to the cluster's own.
+	// +optional
+	AdditionalSecurityGroupIDs []string `json:"additionalSecurityGroupIDs,omitempty"`
 }
 
+// Tenancy is the tenancy of an ec2 instance.
+type Tenancy string
+
+const (
+	// DefaultTenancy runs instances on shared hardware.
+	DefaultTenancy Tenancy = "default"
+	// DedicatedTenancy runs instances on single-tenant hardware.
+	DedicatedTenancy Tenancy = "dedicated"
+	// HostTenancy runs instances on a dedicated host.
+	HostTenancy Tenancy = "host"
+)
+
 // Set sets the values from `required` to `a`.
 func (a *MachinePool) Set(required *MachinePool) {
 	if required == nil || a == nil {
@@ -49,6 +87,16 @@ func (a *MachinePool) Set(required *MachinePool) {
 	if required.EC2RootVolume.Type != "" {
 		a.EC2RootVolume.Type = required.EC2RootVolume.Type
 	}
+
+	if required.Tenancy != "" {
+		a.Tenancy = required.Tenancy
+	}
+	if required.PlacementGroup != "" {
+		a.PlacementGroup = required.PlacementGroup
+	}
+	if len(required.AdditionalSecurityGroupIDs) > 0 {
+		a.AdditionalSecurityGroupIDs = required.AdditionalSecurityGroupIDs
+	}
 }
 
 // EC2RootVolume defines the storage for an ec2 instance.
@@ -60,3 +108,9 @@ type EC2RootVolume struct {
 	// Type defines the type of the instance.
 	Type string `json:"type"`
 }
+
+// ARM64InstanceType returns whether instances of instanceType (e.g.
+// m6g.large) have 64-bit ARM rather than x86 processors.
+func ARM64InstanceType(instanceType string) bool {
+	return gravitonFamily.MatchString(strings.SplitN(instanceType, ".", 2)[0])
+}
@@ -9,4 +9,12 @@ type Metadata struct {
 	// resource matches the map if all of the key/value pairs are in its
 	// tags.  A resource matches Identifier if it matches any of the maps.
 	Identifier []map[string]string `json:"identifier"`
+
+	// ServiceEndpoints are the service endpoint overrides the cluster was
+	// installed with.
+	ServiceEndpoints []ServiceEndpoint `json:"serviceEndpoints,omitempty"`
+
+	// BastionInstanceID is the EC2 instance ID of the SSH bastion host,
+	// if one was created.
+	BastionInstanceID string `json:"bastionInstanceID,omitempty"`
 }
@@ -1,5 +1,9 @@
 package aws
 
+import (
+	"strings"
+)
+
 // Platform stores all the global configuration that all machinesets
 // use.
 type Platform struct {
@@ -9,6 +13,15 @@ type Platform struct {
 	// UserTags specifies additional tags for AWS resources created for the cluster.
 	UserTags map[string]string `json:"userTags,omitempty"`
 
+	// TagNamespace is an organization namespace (e.g. example.com)
+	// prefixing the keys of the tags identifying the cluster's resources,
+	// which become <namespace>/openshiftClusterID and
+	// <namespace>/tectonicClusterID. The kubernetes.io/cluster/<name> tag
+	// is unchanged, because the Kubernetes AWS cloud provider looks
+	// resources up by it.
+	// +optional
+	TagNamespace string `json:"tagNamespace,omitempty"`
+
 	// DefaultMachinePlatform is the default configuration used when
 	// installing on AWS for machine pools which do not define their own
 	// platform configuration.
@@ -22,4 +35,101 @@ type Platform struct {
 	// VPCCIDRBlock
 	// +optional
 	VPCCIDRBlock string `json:"vpcCIDRBlock"`
+
+	// HostedZone is the ID of an existing Route53 hosted zone (public or
+	// private) in which the cluster records are created. The installer
+	// never creates or deletes this zone. A private zone must be associated
+	// with the VPC given in VPCID.
+	// If empty, the public zone is looked up by the base domain.
+	// +optional
+	HostedZone string `json:"hostedZone,omitempty"`
+
+	// PrivateZoneOnly restricts the cluster DNS records to the private
+	// hosted zone associated with the VPC. No public zone is looked up and
+	// no public records are created, so the public API and ingress names
+	// must be served by DNS managed outside of AWS.
+	// +optional
+	PrivateZoneOnly bool `json:"privateZoneOnly,omitempty"`
+
+	// APILoadBalancer configures the load balancers in front of the
+	// Kubernetes API.
+	// If empty, an internal and an external network load balancer are
+	// created.
+	// +optional
+	APILoadBalancer *APILoadBalancer `json:"apiLoadBalancer,omitempty"`
+
+	// Bastion creates an SSH bastion host in a public subnet, with the
+	// cluster SSH keys, for debugging clusters whose machines are not
+	// reachable from outside the VPC. It is destroyed with the cluster.
+	// +optional
+	Bastion *Bastion `json:"bastion,omitempty"`
+
+	// BootstrapInstanceType is the EC2 instance type of the bootstrap
+	// machine.
+	// If empty, the installer's default (t3.medium) is used.
+	// +optional
+	BootstrapInstanceType string `json:"bootstrapInstanceType,omitempty"`
+
+	// ServiceEndpoints overrides the endpoints of AWS services, for
+	// regions (e.g. GovCloud or C2S) whose endpoints differ from the
+	// public ones. They are used by the installer and by the cluster.
+	// +optional
+	ServiceEndpoints []ServiceEndpoint `json:"serviceEndpoints,omitempty"`
+
+	// ComponentRoles maps in-cluster component credentials, named
+	// <namespace>/<secret> (e.g. openshift-machine-api/aws-cloud-credentials),
+	// to the ARN of the IAM role the component assumes with its service
+	// account token through STS. Components with a role get short-lived
+	// credentials instead of long-lived keys. It requires the Manual
+	// credentials mode.
+	// +optional
+	ComponentRoles map[string]string `json:"componentRoles,omitempty"`
+}
+
+// TagKey returns the key of the cluster identifying tag named key, in the
+// platform's tag namespace.
+func (p *Platform) TagKey(key string) string {
+	if p.TagNamespace == "" {
+		return key
+	}
+	return p.TagNamespace + "/" + key
+}
+
+// ChinaRoute53Endpoint is the global Route53 endpoint of the aws-cn
+// partition, which the SDK does not know of.
+const ChinaRoute53Endpoint = "https://route53.amazonaws.com.cn"
+
+// Partition returns the AWS partition of region: aws-cn for the China
+// regions, aws-us-gov for GovCloud and aws for the others.
+func Partition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	default:
+		return "aws"
+	}
+}
+
+// ServiceEndpoint overrides the endpoint of an AWS service.
+type ServiceEndpoint struct {
+	// Name is the AWS SDK endpoint ID of the service (e.g. ec2,
+	// elasticloadbalancing, iam, route53, s3 or sts).
+	Name string `json:"name"`
+
+	// URL is the https URL of the service endpoint.
+	URL string `json:"url"`
+}
+
+// ServiceEndpointNames are the services whose endpoints can be
+// overridden, which are those the installer and the cluster use.
+var ServiceEndpointNames = []string{
+	"ec2",
+	"elasticloadbalancing",
+	"iam",
+	"route53",
+	"s3",
+	"sts",
+	"tagging",
 }
@@ -10,6 +10,8 @@ import (
 // regarding the cluster that was created by installer.
 type ClusterMetadata struct {
 	ClusterName             string `json:"clusterName"`
+	InfraID                 string `json:"infraID,omitempty"`
+	ReleaseImage            string `json:"releaseImage,omitempty"`
 	ClusterPlatformMetadata `json:",inline"`
 }
 
@@ -0,0 +1,29 @@
+package types
+
+// ConfigSecret is a secret created in the openshift-config namespace, where
+// cluster configuration (e.g. API server serving certificates) references
+// it by name.
+type ConfigSecret struct {
+	// Name is the name of the secret.
+	Name string `json:"name"`
+
+	// Type is the type of the secret (e.g. kubernetes.io/tls). Defaults to
+	// Opaque.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Data maps keys to values, which are given as plain text (e.g.
+	// PEM-encoded certificates and keys) rather than base64-encoded.
+	Data map[string]string `json:"data"`
+}
+
+// ConfigMap is a config map created in the openshift-config namespace,
+// where cluster configuration (e.g. identity provider CA bundles)
+// references it by name.
+type ConfigMap struct {
+	// Name is the name of the config map.
+	Name string `json:"name"`
+
+	// Data maps keys to values.
+	Data map[string]string `json:"data"`
+}
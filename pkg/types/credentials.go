@@ -0,0 +1,22 @@
+package types
+
+// CredentialsMode controls how in-cluster components obtain cloud
+// credentials.
+type CredentialsMode string
+
+const (
+	// MintCredentialsMode stores the installer's credentials in the
+	// cluster, where the cloud credential operator uses them to mint
+	// narrowly-scoped credentials for each component. It is used when no
+	// mode is configured.
+	MintCredentialsMode CredentialsMode = "Mint"
+
+	// PassthroughCredentialsMode stores the installer's credentials in the
+	// cluster and hands them to each component as-is.
+	PassthroughCredentialsMode CredentialsMode = "Passthrough"
+
+	// ManualCredentialsMode stores no credentials in the cluster. The
+	// installer generates a stub secret for each component, which the
+	// user must fill in before creating the cluster.
+	ManualCredentialsMode CredentialsMode = "Manual"
+)
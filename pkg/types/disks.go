@@ -0,0 +1,56 @@
+package types
+
+// DiskPartition is a partition created, formatted and mounted on a
+// machine pool's nodes at first boot, e.g. a dedicated /var.
+type DiskPartition struct {
+	// Device is the disk holding the partition (e.g. /dev/nvme0n1 or
+	// /dev/vda for the root disk).
+	Device string `json:"device"`
+
+	// MountPath is where the partition is mounted. It must be /var or a
+	// directory beneath it (e.g. /var/lib/containers).
+	MountPath string `json:"mountPath"`
+
+	// StartMiB is the offset of the partition on the disk. When the
+	// partition is on the root disk, it must leave room for the root
+	// filesystem (e.g. 25000). Defaults to the start of the largest free
+	// space.
+	// +optional
+	StartMiB int `json:"startMiB,omitempty"`
+
+	// SizeMiB is the size of the partition. Defaults to the rest of the
+	// disk.
+	// +optional
+	SizeMiB int `json:"sizeMiB,omitempty"`
+
+	// Format is the filesystem, xfs (the default) or ext4.
+	// +optional
+	Format string `json:"format,omitempty"`
+}
+
+// DiskEncryption encrypts the root filesystem of a machine pool's nodes
+// with LUKS at first boot, binding the key with Clevis.
+type DiskEncryption struct {
+	// TPM2 binds the key to the node's TPM 2.0 chip.
+	// +optional
+	TPM2 bool `json:"tpm2,omitempty"`
+
+	// Tang binds the key to these Tang servers.
+	// +optional
+	Tang []TangServer `json:"tang,omitempty"`
+
+	// Threshold is how many of the TPM and the Tang servers must be
+	// available to unlock the disk. Defaults to 1.
+	// +optional
+	Threshold int `json:"threshold,omitempty"`
+}
+
+// TangServer is a Tang server the disk encryption key is bound to.
+type TangServer struct {
+	// URL is the server's URL (e.g. http://tang.example.com:7500).
+	URL string `json:"url"`
+
+	// Thumbprint is the thumbprint of the server's signing key, as printed
+	// by tang-show-keys.
+	Thumbprint string `json:"thumbprint"`
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FeatureSet is a named collection of feature gates.
+type FeatureSet string
+
+const (
+	// DefaultFeatureSet enables the features supported for production use.
+	DefaultFeatureSet FeatureSet = ""
+
+	// TechPreviewNoUpgrade enables the tech-preview features. Clusters
+	// with this feature set cannot be upgraded.
+	TechPreviewNoUpgrade FeatureSet = "TechPreviewNoUpgrade"
+
+	// CustomNoUpgrade enables and disables the individual feature gates
+	// listed in FeatureGates. Clusters with this feature set cannot be
+	// upgraded.
+	CustomNoUpgrade FeatureSet = "CustomNoUpgrade"
+)
+
+// ParseFeatureGates splits feature gates in Name=true or Name=false form
+// into the names to enable and the names to disable.
+func ParseFeatureGates(gates []string) (enabled []string, disabled []string, err error) {
+	for _, gate := range gates {
+		parts := strings.SplitN(gate, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, nil, errors.Errorf("invalid feature gate %q (must be Name=true or Name=false)", gate)
+		}
+		value, err := strconv.ParseBool(parts[1])
+		if err != nil {
+			return nil, nil, errors.Errorf("invalid feature gate %q (must be Name=true or Name=false)", gate)
+		}
+		if value {
+			enabled = append(enabled, parts[0])
+		} else {
+			disabled = append(disabled, parts[0])
+		}
+	}
+	return enabled, disabled, nil
+}
@@ -0,0 +1,152 @@
+package types
+
+// MappingMethod controls how identities from a provider are mapped to
+// users.
+type MappingMethod string
+
+const (
+	// ClaimMappingMethod provisions a user with the identity's preferred
+	// user name, failing if that user is already mapped to another
+	// identity. It is used when no mapping method is configured.
+	ClaimMappingMethod MappingMethod = "claim"
+
+	// LookupMappingMethod looks up existing users and never provisions
+	// new ones.
+	LookupMappingMethod MappingMethod = "lookup"
+
+	// AddMappingMethod provisions a user with the identity's preferred
+	// user name, adding the identity to that user if it already exists.
+	AddMappingMethod MappingMethod = "add"
+)
+
+// IdentityProvider configures a source of cluster users. Exactly one of
+// HTPasswd, LDAP, and OpenID must be set.
+type IdentityProvider struct {
+	// Name is the unique name of the identity provider. It is prefixed to
+	// the identities it provides.
+	Name string `json:"name"`
+
+	// MappingMethod controls how identities are mapped to users. Defaults
+	// to claim.
+	// +optional
+	MappingMethod MappingMethod `json:"mappingMethod,omitempty"`
+
+	// HTPasswd authenticates users against an htpasswd file.
+	// +optional
+	HTPasswd *HTPasswdIdentityProvider `json:"htpasswd,omitempty"`
+
+	// LDAP authenticates users against an LDAP server.
+	// +optional
+	LDAP *LDAPIdentityProvider `json:"ldap,omitempty"`
+
+	// OpenID authenticates users against an OpenID Connect provider.
+	// +optional
+	OpenID *OpenIDIdentityProvider `json:"openID,omitempty"`
+}
+
+// HTPasswdIdentityProvider configures an htpasswd identity provider.
+type HTPasswdIdentityProvider struct {
+	// FileData is the content of the htpasswd file.
+	FileData string `json:"fileData"`
+}
+
+// LDAPIdentityProvider configures an LDAP identity provider.
+type LDAPIdentityProvider struct {
+	// URL is an RFC 2255 URL specifying the LDAP host and search
+	// parameters (e.g. ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid).
+	URL string `json:"url"`
+
+	// BindDN is an optional DN to bind with during the search phase.
+	// +optional
+	BindDN string `json:"bindDN,omitempty"`
+
+	// BindPassword is an optional password to bind with during the search
+	// phase.
+	// +optional
+	BindPassword string `json:"bindPassword,omitempty"`
+
+	// Insecure disables TLS for ldap:// URLs. It may not be set for
+	// ldaps:// URLs.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// CA is a PEM-encoded CA bundle used to verify the server certificate.
+	// +optional
+	CA string `json:"ca,omitempty"`
+
+	// CAConfigMap is the name of a config map in configMaps whose ca.crt
+	// key holds the CA bundle, as an alternative to CA.
+	// +optional
+	CAConfigMap string `json:"caConfigMap,omitempty"`
+
+	// Attributes maps LDAP attributes to identities.
+	Attributes LDAPAttributes `json:"attributes"`
+}
+
+// LDAPAttributes maps LDAP attributes to identities. For each field, the
+// first non-empty attribute is used.
+type LDAPAttributes struct {
+	// ID is the list of attributes to use as the identity ID.
+	ID []string `json:"id"`
+
+	// PreferredUsername is the list of attributes to use as the preferred
+	// user name.
+	// +optional
+	PreferredUsername []string `json:"preferredUsername,omitempty"`
+
+	// Name is the list of attributes to use as the display name.
+	// +optional
+	Name []string `json:"name,omitempty"`
+
+	// Email is the list of attributes to use as the email address.
+	// +optional
+	Email []string `json:"email,omitempty"`
+}
+
+// OpenIDIdentityProvider configures an OpenID Connect identity provider.
+type OpenIDIdentityProvider struct {
+	// ClientID is the OAuth client ID.
+	ClientID string `json:"clientID"`
+
+	// ClientSecret is the OAuth client secret.
+	ClientSecret string `json:"clientSecret"`
+
+	// Issuer is the https URL the provider asserts as its issuer
+	// identifier.
+	Issuer string `json:"issuer"`
+
+	// CA is a PEM-encoded CA bundle used to verify the provider's
+	// certificate.
+	// +optional
+	CA string `json:"ca,omitempty"`
+
+	// CAConfigMap is the name of a config map in configMaps whose ca.crt
+	// key holds the CA bundle, as an alternative to CA.
+	// +optional
+	CAConfigMap string `json:"caConfigMap,omitempty"`
+
+	// ExtraScopes are scopes to request in addition to openid.
+	// +optional
+	ExtraScopes []string `json:"extraScopes,omitempty"`
+
+	// Claims maps ID token claims to identities.
+	// +optional
+	Claims OpenIDClaims `json:"claims,omitempty"`
+}
+
+// OpenIDClaims maps ID token claims to identities. For each field, the
+// first non-empty claim is used.
+type OpenIDClaims struct {
+	// PreferredUsername is the list of claims to use as the preferred user
+	// name. Defaults to the sub claim.
+	// +optional
+	PreferredUsername []string `json:"preferredUsername,omitempty"`
+
+	// Name is the list of claims to use as the display name.
+	// +optional
+	Name []string `json:"name,omitempty"`
+
+	// Email is the list of claims to use as the email address.
+	// +optional
+	Email []string `json:"email,omitempty"`
+}
@@ -0,0 +1,55 @@
+package types
+
+// ImageRegistry configures the cluster's internal image registry.
+type ImageRegistry struct {
+	// Storage is the backing storage for the registry.
+	Storage ImageRegistryStorage `json:"storage"`
+}
+
+// ImageRegistryStorage is the backing storage for the internal image
+// registry. Exactly one of its fields must be set.
+type ImageRegistryStorage struct {
+	// S3 stores images in an S3 bucket.
+	// +optional
+	S3 *ImageRegistryStorageS3 `json:"s3,omitempty"`
+
+	// PVC stores images on a persistent volume claim in the
+	// openshift-image-registry namespace.
+	// +optional
+	PVC *ImageRegistryStoragePVC `json:"pvc,omitempty"`
+
+	// EmptyDir stores images on the registry pod's ephemeral storage.
+	// Images are lost when the pod restarts, so this is only suitable for
+	// proofs of concept.
+	// +optional
+	EmptyDir *ImageRegistryStorageEmptyDir `json:"emptyDir,omitempty"`
+}
+
+// ImageRegistryStorageS3 configures S3 storage for the image registry.
+type ImageRegistryStorageS3 struct {
+	// Bucket is the name of the bucket. It is created if it does not
+	// exist.
+	Bucket string `json:"bucket"`
+
+	// Region is the region of the bucket. Defaults to the cluster region
+	// on AWS.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Encrypt enables server-side encryption of the stored images.
+	// +optional
+	Encrypt bool `json:"encrypt,omitempty"`
+}
+
+// ImageRegistryStoragePVC configures persistent volume claim storage for
+// the image registry.
+type ImageRegistryStoragePVC struct {
+	// Claim is the name of the claim. The registry operator creates one if
+	// it is not set.
+	// +optional
+	Claim string `json:"claim,omitempty"`
+}
+
+// ImageRegistryStorageEmptyDir configures ephemeral storage for the image
+// registry.
+type ImageRegistryStorageEmptyDir struct{}
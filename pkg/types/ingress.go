@@ -0,0 +1,74 @@
+package types
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Ingress configures the cluster's ingress controllers.
+type Ingress struct {
+	// DefaultCertificate is the wildcard certificate for *.apps.<name>.<baseDomain>
+	// served for application routes which do not have their own. Without
+	// it, the ingress operator generates a self-signed one.
+	// +optional
+	DefaultCertificate *IngressCertificate `json:"defaultCertificate,omitempty"`
+
+	// Controllers are ingress controllers to create in addition to the
+	// default one, each serving a shard of the cluster's routes.
+	// +optional
+	Controllers []IngressController `json:"controllers,omitempty"`
+}
+
+// IngressCertificate is a PEM-encoded certificate and its private key.
+type IngressCertificate struct {
+	// Certificate is the PEM-encoded certificate, followed by any
+	// intermediate certificates.
+	Certificate string `json:"certificate"`
+
+	// KeyFile is the path of the PEM-encoded private key of the
+	// certificate. It is read when the manifests are generated, so the key
+	// is only rendered into the certificate's secret.
+	KeyFile string `json:"keyFile"`
+}
+
+// IngressController is an additional ingress controller.
+type IngressController struct {
+	// Name is the name of the IngressController in the
+	// openshift-ingress-operator namespace.
+	Name string `json:"name"`
+
+	// Domain is the domain the controller serves routes under. It must
+	// differ from the default apps domain.
+	Domain string `json:"domain"`
+
+	// RouteSelector restricts the controller to routes with matching
+	// labels. Without it, the controller serves all routes.
+	// +optional
+	RouteSelector *metav1.LabelSelector `json:"routeSelector,omitempty"`
+
+	// Replicas is the number of router pods. Defaults to the ingress
+	// operator's default.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// EndpointPublishingStrategy is how the routers are exposed. Defaults
+	// to the ingress operator's default for the platform.
+	// +optional
+	EndpointPublishingStrategy EndpointPublishingStrategy `json:"endpointPublishingStrategy,omitempty"`
+}
+
+// EndpointPublishingStrategy is how an ingress controller's routers are
+// exposed.
+type EndpointPublishingStrategy string
+
+const (
+	// LoadBalancerServiceStrategy exposes the routers with a service of
+	// type LoadBalancer.
+	LoadBalancerServiceStrategy EndpointPublishingStrategy = "LoadBalancerService"
+
+	// HostNetworkStrategy runs the routers on the host network of the
+	// nodes they are scheduled on.
+	HostNetworkStrategy EndpointPublishingStrategy = "HostNetwork"
+
+	// PrivateStrategy does not expose the routers outside the cluster.
+	PrivateStrategy EndpointPublishingStrategy = "Private"
+)
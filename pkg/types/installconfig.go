@@ -1,6 +1,11 @@
 package types
 
 import (
+	"fmt"
+	"net"
+
+	"github.com/apparentlymart/go-cidr/cidr"
+	configv1 "github.com/openshift/api/config/v1"
 	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/networkoperator/v1"
 	"github.com/openshift/installer/pkg/ipnet"
 	"github.com/openshift/installer/pkg/types/aws"
@@ -9,6 +14,18 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// DefaultChannel is the update channel used when none is configured.
+	DefaultChannel = "fast"
+
+	// DefaultUpstream is the update service used when none is configured.
+	DefaultUpstream = "http://localhost:8080/graph"
+
+	// DefaultReleaseImage is the release payload installed when none is
+	// configured.
+	DefaultReleaseImage = "registry.svc.ci.openshift.org/openshift/origin-release:v4.0"
+)
+
 var (
 	// PlatformNames is a slice with all the supported platform names in
 	// alphabetical order.
@@ -29,8 +46,13 @@ type InstallConfig struct {
 	// ClusterID is the ID of the cluster.
 	ClusterID string `json:"clusterID"`
 
-	// SSHKey is the public ssh key to provide access to instances.
-	SSHKey string `json:"sshKey"`
+	// InfraID prefixes the names of the cluster's cloud resources.  It is
+	// only supported on AWS, and defaults to the cluster name.
+	// +optional
+	InfraID string `json:"infraID,omitempty"`
+
+	// SSHKey is the list of public ssh keys to provide access to instances.
+	SSHKey SSHKeys `json:"sshKey"`
 
 	// BaseDomain is the base domain to which the cluster should belong.
 	BaseDomain string `json:"baseDomain"`
@@ -47,6 +69,116 @@ type InstallConfig struct {
 
 	// PullSecret is the secret to use when pulling images.
 	PullSecret string `json:"pullSecret"`
+
+	// MachineConfigServer configures how machines reach the
+	// machine-config server.
+	// +optional
+	MachineConfigServer *MachineConfigServer `json:"machineConfigServer,omitempty"`
+
+	// CVOOverrides is the list of components the cluster-version operator
+	// should treat differently, rendered into the ClusterVersion's
+	// spec.overrides. Setting unmanaged on an operator's deployment, for
+	// example, keeps that operator from being installed.
+	// +optional
+	CVOOverrides []configv1.ComponentOverride `json:"cvoOverrides,omitempty"`
+
+	// Channel is the update channel the cluster follows. Defaults to
+	// DefaultChannel.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// Upstream is the URL of the update service (policy engine) the cluster
+	// queries for available updates. Disconnected clusters can point this
+	// at an internal instance. Defaults to DefaultUpstream.
+	// +optional
+	Upstream string `json:"upstream,omitempty"`
+
+	// ReleaseImage is the digest-pinned pull spec of the release payload to
+	// install (e.g. quay.io/openshift-release-dev/ocp-release@sha256:...).
+	// Defaults to DefaultReleaseImage.
+	// +optional
+	ReleaseImage string `json:"releaseImage,omitempty"`
+
+	// FeatureSet is the collection of feature gates enabled on the
+	// cluster from first boot.
+	// +optional
+	FeatureSet FeatureSet `json:"featureSet,omitempty"`
+
+	// FeatureGates is the list of individual feature gates to enable or
+	// disable, in Name=true or Name=false form. It may only be set when
+	// FeatureSet is CustomNoUpgrade.
+	// +optional
+	FeatureGates []string `json:"featureGates,omitempty"`
+
+	// APIServer configures the cluster API servers.
+	// +optional
+	APIServer *APIServer `json:"apiServer,omitempty"`
+
+	// Scheduler configures the cluster scheduler.
+	// +optional
+	Scheduler *Scheduler `json:"scheduler,omitempty"`
+
+	// Project configures how users request projects, so multi-tenant
+	// policies apply from the first user project on.
+	// +optional
+	Project *Project `json:"project,omitempty"`
+
+	// IdentityProviders is the list of identity providers cluster users
+	// can log in with in addition to kubeadmin. Their credentials are
+	// only rendered into secrets, and are left out of the copy of the
+	// install config stored in the cluster.
+	// +optional
+	IdentityProviders []IdentityProvider `json:"identityProviders,omitempty"`
+
+	// ConfigSecrets are secrets created in the openshift-config namespace
+	// for other configuration to reference (e.g. apiServer.namedCertificates).
+	// Their data is left out of the copy of the install config stored in
+	// the cluster.
+	// +optional
+	ConfigSecrets []ConfigSecret `json:"configSecrets,omitempty"`
+
+	// ConfigMaps are config maps created in the openshift-config namespace
+	// for other configuration to reference (e.g. the caConfigMap of
+	// identity providers).
+	// +optional
+	ConfigMaps []ConfigMap `json:"configMaps,omitempty"`
+
+	// ImageRegistry configures the internal image registry. When it is not
+	// set, the registry operator chooses the storage.
+	// +optional
+	ImageRegistry *ImageRegistry `json:"imageRegistry,omitempty"`
+
+	// Monitoring configures the cluster monitoring stack.
+	// +optional
+	Monitoring *Monitoring `json:"monitoring,omitempty"`
+
+	// Proxy configures the HTTP proxy through which the cluster reaches
+	// the outside.
+	// +optional
+	Proxy *Proxy `json:"proxy,omitempty"`
+
+	// Telemetry is whether the cluster reports its health to Red Hat.
+	// When disabled, the telemeter client is turned off and the
+	// cloud.openshift.com auth, which the insights operator also reports
+	// with, is left out of the cluster's pull secret.
+	// If empty, telemetry is enabled.
+	// +optional
+	Telemetry Telemetry `json:"telemetry,omitempty"`
+
+	// Ingress configures the cluster's ingress controllers.
+	// +optional
+	Ingress *Ingress `json:"ingress,omitempty"`
+
+	// CredentialsMode controls how in-cluster components obtain cloud
+	// credentials. Defaults to Mint.
+	// +optional
+	CredentialsMode CredentialsMode `json:"credentialsMode,omitempty"`
+
+	// ManifestHooks are run, in order, after the manifests are generated,
+	// and may contribute additional manifests.  They only run when the
+	// installer is invoked with --allow-manifest-hooks.
+	// +optional
+	ManifestHooks []ManifestHook `json:"manifestHooks,omitempty"`
 }
 
 // MasterCount returns the number of replicas in the master machine pool,
@@ -60,6 +192,53 @@ func (c *InstallConfig) MasterCount() int {
 	return 1
 }
 
+// InfrastructureName returns the infra ID, defaulting to the cluster name.
+func (c *InstallConfig) InfrastructureName() string {
+	if c.InfraID != "" {
+		return c.InfraID
+	}
+	return c.ObjectMeta.Name
+}
+
+// MachineConfigServerPort returns the port machines use to fetch their
+// Ignition configs, defaulting to DefaultMachineConfigServerPort.
+func (c *InstallConfig) MachineConfigServerPort() int {
+	if c.MachineConfigServer != nil && c.MachineConfigServer.Port != 0 {
+		return c.MachineConfigServer.Port
+	}
+	return DefaultMachineConfigServerPort
+}
+
+// ReleaseImagePullSpec returns the pull spec of the release payload to
+// install, defaulting to DefaultReleaseImage.
+func (c *InstallConfig) ReleaseImagePullSpec() string {
+	if c.ReleaseImage != "" {
+		return c.ReleaseImage
+	}
+	return DefaultReleaseImage
+}
+
+// MachineCIDRs returns the CIDRs of the machine networks, defaulting to the
+// platform's network if no machine networks were set.
+func (c *InstallConfig) MachineCIDRs() []string {
+	if len(c.Networking.MachineNetwork) > 0 {
+		cidrs := make([]string, 0, len(c.Networking.MachineNetwork))
+		for _, n := range c.Networking.MachineNetwork {
+			cidrs = append(cidrs, n.CIDR.String())
+		}
+		return cidrs
+	}
+	switch {
+	case c.Platform.AWS != nil && c.Platform.AWS.VPCCIDRBlock != "":
+		return []string{c.Platform.AWS.VPCCIDRBlock}
+	case c.Platform.OpenStack != nil && c.Platform.OpenStack.NetworkCIDRBlock != "":
+		return []string{c.Platform.OpenStack.NetworkCIDRBlock}
+	case c.Platform.Libvirt != nil && c.Platform.Libvirt.Network.IPRange != "":
+		return []string{c.Platform.Libvirt.Network.IPRange}
+	}
+	return nil
+}
+
 // Platform is the configuration for the specific platform upon which to perform
 // the installation. Only one of the platform configuration should be set.
 type Platform struct {
@@ -108,4 +287,125 @@ type Networking struct {
 	// we will fall back to the PodCIDR
 	// TODO(cdc) remove this.
 	PodCIDR *ipnet.IPNet `json:"podCIDR,omitempty"`
+
+	// MachineNetwork is the list of IP address pools for machines.  The
+	// first entry is the primary network (e.g. the AWS VPC CIDR block).
+	// Defaults to the platform's network.
+	// +optional
+	MachineNetwork []MachineNetworkEntry `json:"machineNetwork,omitempty"`
+
+	// ServiceNodePortRange is the range of ports, in the form
+	// <first>-<last>, from which NodePort services are allocated.  Defaults
+	// to the API server's 30000-32767.
+	// +optional
+	ServiceNodePortRange string `json:"serviceNodePortRange,omitempty"`
+
+	// ExternalIP configures the services' external IPs.  By default no
+	// external IPs are allowed.
+	// +optional
+	ExternalIP *ExternalIPConfig `json:"externalIP,omitempty"`
+
+	// OVNKubernetesConfig configures the OVNKubernetes network type.
+	// +optional
+	OVNKubernetesConfig *OVNKubernetesConfig `json:"ovnKubernetesConfig,omitempty"`
+
+	// Egress configures the egress of pods from the first boot of the
+	// cluster, so it is never open while it is being locked down. It is
+	// only supported for the OpenshiftSDN and OVNKubernetes network types.
+	// +optional
+	Egress *EgressConfig `json:"egress,omitempty"`
+}
+
+// EgressConfig configures the egress of pods.
+type EgressConfig struct {
+	// DefaultPolicy is the egress policy of projects without their own
+	// egress network policy or firewall. Defaults to EgressPolicyAllow.
+	// +optional
+	DefaultPolicy EgressPolicy `json:"defaultPolicy,omitempty"`
+
+	// AllowedCIDRs are the blocks outside the cluster which pods may reach
+	// even when the default policy is EgressPolicyDeny, e.g. a registry
+	// mirror or proxy.
+	// +optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+
+	// EgressIPCIDRs are the blocks, within the machine networks, from which
+	// egress IPs are allocated to nodes, so projects' egress can be given
+	// fixed source addresses.
+	// +optional
+	EgressIPCIDRs []string `json:"egressIPCIDRs,omitempty"`
+}
+
+// EgressPolicy is whether pods may reach destinations outside the cluster.
+type EgressPolicy string
+
+const (
+	// EgressPolicyAllow allows egress to any destination.
+	EgressPolicyAllow EgressPolicy = "Allow"
+
+	// EgressPolicyDeny denies egress to destinations other than the
+	// allowed CIDRs.
+	EgressPolicyDeny EgressPolicy = "Deny"
+)
+
+// ExternalIPConfig configures the external IPs of services.
+type ExternalIPConfig struct {
+	// Policy restricts the external IPs that users may set.
+	// +optional
+	Policy *ExternalIPPolicy `json:"policy,omitempty"`
+
+	// AutoAssignCIDRs are the blocks from which external IPs are
+	// automatically assigned to LoadBalancer services, on platforms without
+	// a cloud load balancer (e.g. bare metal).
+	// +optional
+	AutoAssignCIDRs []string `json:"autoAssignCIDRs,omitempty"`
+}
+
+// ExternalIPPolicy restricts the external IPs of services.
+type ExternalIPPolicy struct {
+	// AllowedCIDRs are the blocks external IPs may be set from.
+	// +optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+
+	// RejectedCIDRs are the blocks external IPs may not be set from, even
+	// if allowed by AllowedCIDRs.
+	// +optional
+	RejectedCIDRs []string `json:"rejectedCIDRs,omitempty"`
+}
+
+// clusterDNSHost is the host number, within the service network, of the
+// cluster DNS service's IP.
+const clusterDNSHost = 10
+
+// ClusterDNSIP returns the IP of the cluster DNS service, the tenth address
+// of the service network, which the kubelets and the DNS operator derive
+// from it as well. It fails if the service network is too small to hold
+// it.
+func (n *Networking) ClusterDNSIP() (net.IP, error) {
+	if n.ServiceCIDR.IPNet.IP == nil {
+		return nil, fmt.Errorf("no service network")
+	}
+	ones, bits := n.ServiceCIDR.Mask.Size()
+	if bits-ones < 4 {
+		return nil, fmt.Errorf("too small for the cluster DNS service, whose IP is address %d of the block; it must have at least 16 addresses (e.g. a /%d)", clusterDNSHost, bits-4)
+	}
+	return cidr.Host(&n.ServiceCIDR.IPNet, clusterDNSHost)
+}
+
+// OVNKubernetesConfig is the configuration of the OVNKubernetes network type.
+type OVNKubernetesConfig struct {
+	// IPsecConfig, if set, enables IPsec encryption of the traffic between
+	// pods on different nodes.
+	// +optional
+	IPsecConfig *IPsecConfig `json:"ipsecConfig,omitempty"`
+}
+
+// IPsecConfig configures IPsec for pod traffic.  It has no settings yet;
+// setting it enables IPsec.
+type IPsecConfig struct{}
+
+// MachineNetworkEntry is a single IP address block for the machines.
+type MachineNetworkEntry struct {
+	// CIDR is the IP block address pool for machines within the cluster.
+	CIDR ipnet.IPNet `json:"cidr"`
 }
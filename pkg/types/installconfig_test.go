@@ -1,10 +1,13 @@
 package types
 
 import (
+	"net"
 	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/ipnet"
 )
 
 func TestPlatformNamesSorted(t *testing.T) {
@@ -13,3 +16,34 @@ func TestPlatformNamesSorted(t *testing.T) {
 	sort.Strings(sorted)
 	assert.Equal(t, sorted, PlatformNames)
 }
+
+func TestClusterDNSIP(t *testing.T) {
+	cases := []struct {
+		serviceCIDR   string
+		expectedIP    string
+		expectedError string
+	}{
+		{serviceCIDR: "172.30.0.0/16", expectedIP: "172.30.0.10"},
+		{serviceCIDR: "10.0.0.16/28", expectedIP: "10.0.0.26"},
+		{serviceCIDR: "fd02::/112", expectedIP: "fd02::a"},
+		{serviceCIDR: "10.0.0.0/29", expectedError: "too small for the cluster DNS service, whose IP is address 10 of the block; it must have at least 16 addresses (e.g. a /28)"},
+		{serviceCIDR: "10.0.0.0/30", expectedError: "too small for the cluster DNS service, whose IP is address 10 of the block; it must have at least 16 addresses (e.g. a /28)"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.serviceCIDR, func(t *testing.T) {
+			_, network, err := net.ParseCIDR(tc.serviceCIDR)
+			if !assert.NoError(t, err) {
+				return
+			}
+			n := &Networking{ServiceCIDR: ipnet.IPNet{IPNet: *network}}
+			ip, err := n.ClusterDNSIP()
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedIP, ip.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package types
+
+// KubeletConfig is the kubelet configuration of a machine pool's nodes.
+type KubeletConfig struct {
+	// MaxPods is the most pods a node runs.
+	// +optional
+	MaxPods *int32 `json:"maxPods,omitempty"`
+
+	// SystemReserved is the resources (cpu, memory, ephemeral-storage or
+	// pid) reserved for system daemons, e.g. memory: 1Gi.
+	// +optional
+	SystemReserved map[string]string `json:"systemReserved,omitempty"`
+
+	// KubeReserved is the resources reserved for the kubelet and the
+	// container runtime.
+	// +optional
+	KubeReserved map[string]string `json:"kubeReserved,omitempty"`
+
+	// EvictionHard is the eviction signals (e.g. memory.available) and
+	// the quantity (e.g. 500Mi) or percentage (e.g. 10%) at which pods
+	// are evicted.
+	// +optional
+	EvictionHard map[string]string `json:"evictionHard,omitempty"`
+}
+
+// ContainerRuntimeConfig is the CRI-O configuration of a machine pool's
+// nodes.
+type ContainerRuntimeConfig struct {
+	// PidsLimit is the most processes a container may run.
+	// +optional
+	PidsLimit *int64 `json:"pidsLimit,omitempty"`
+
+	// LogSizeMax is the size (e.g. 50Mi) at which a container's log is
+	// truncated. It must be at least 8Ki.
+	// +optional
+	LogSizeMax string `json:"logSizeMax,omitempty"`
+
+	// OverlaySize is the most space (e.g. 10Gi) a container's writable
+	// layer may use.
+	// +optional
+	OverlaySize string `json:"overlaySize,omitempty"`
+}
@@ -2,6 +2,8 @@ package libvirt
 
 import (
 	"net"
+	"net/url"
+	"strconv"
 )
 
 // Platform stores all the global configuration that all
@@ -12,6 +14,13 @@ type Platform struct {
 	// cluster (where the cluster-API controller pod will be running).
 	URI string `json:"URI"`
 
+	// SSH holds the options with which the installer connects to a
+	// qemu+ssh (or qemu+libssh2, qemu+libssh) URI. They are used by the
+	// installer (when creating and destroying the cluster) but not by the
+	// cluster, so they may refer to files on the installer's host.
+	// +optional
+	SSH *SSH `json:"ssh,omitempty"`
+
 	// DefaultMachinePlatform is the default configuration used when
 	// installing on libvirt for machine pools which do not define their
 	// own platform configuration.
@@ -22,4 +31,52 @@ type Platform struct {
 
 	// MasterIPs
 	MasterIPs []net.IP `json:"masterIPs"`
+
+	// BootstrapMemoryMiB is the memory of the bootstrap machine, in MiB.
+	// If zero, the installer's default (2048) is used.
+	// +optional
+	BootstrapMemoryMiB int `json:"bootstrapMemoryMiB,omitempty"`
+}
+
+// SSH holds the options for connecting to libvirtd over SSH.
+type SSH struct {
+	// Port is the SSH port of the hypervisor, overriding any port in the
+	// URI.
+	// +optional
+	Port int `json:"port,omitempty"`
+
+	// KeyFile is the path of the private key to authenticate with.
+	// +optional
+	KeyFile string `json:"keyFile,omitempty"`
+
+	// KnownHosts is the path of the known_hosts file to verify the
+	// hypervisor's host key against. It is only supported by the libssh2
+	// and libssh transports.
+	// +optional
+	KnownHosts string `json:"knownHosts,omitempty"`
+}
+
+// ConnectionURI returns the URI with which the installer connects to
+// libvirtd, which is URI with the SSH options added to it.
+func (p *Platform) ConnectionURI() string {
+	if p.SSH == nil {
+		return p.URI
+	}
+	u, err := url.Parse(p.URI)
+	if err != nil {
+		// Rejected by validation.
+		return p.URI
+	}
+	if p.SSH.Port != 0 {
+		u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(p.SSH.Port))
+	}
+	query := u.Query()
+	if p.SSH.KeyFile != "" {
+		query.Set("keyfile", p.SSH.KeyFile)
+	}
+	if p.SSH.KnownHosts != "" {
+		query.Set("known_hosts", p.SSH.KnownHosts)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
 }
@@ -0,0 +1,24 @@
+package types
+
+// DefaultMachineConfigServerPort is the port on which machines fetch their
+// Ignition configs from the machine-config server unless configured
+// otherwise.
+const DefaultMachineConfigServerPort = 49500
+
+// MachineConfigServer configures how machines reach the machine-config
+// server when fetching their Ignition configs.
+type MachineConfigServer struct {
+	// Port is the port machines connect to when fetching their Ignition
+	// configs. The load balancer in front of the machine-config server
+	// listens on this port, so it is not configurable on libvirt and
+	// OpenStack, which have no such load balancer. Defaults to 49500.
+	// +optional
+	Port int `json:"port,omitempty"`
+
+	// CertificateAuthority is a PEM-encoded CA bundle machines use to
+	// verify the certificate served on Port. Set this when a fronting load
+	// balancer terminates TLS with its own certificate. Defaults to the
+	// cluster root CA.
+	// +optional
+	CertificateAuthority string `json:"certificateAuthority,omitempty"`
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
 	"github.com/openshift/installer/pkg/types/aws"
 	"github.com/openshift/installer/pkg/types/libvirt"
 	"github.com/openshift/installer/pkg/types/openstack"
@@ -17,6 +20,157 @@ type MachinePool struct {
 
 	// Platform is configuration for machine pool specific to the platfrom.
 	Platform MachinePoolPlatform `json:"platform"`
+
+	// HealthCheck enables the remediation of the pool's unhealthy
+	// machines. It is not supported for the master pool.
+	// +optional
+	HealthCheck *MachineHealthCheck `json:"healthCheck,omitempty"`
+
+	// KubeletConfig configures the kubelet of the pool's nodes.
+	// +optional
+	KubeletConfig *KubeletConfig `json:"kubeletConfig,omitempty"`
+
+	// ContainerRuntimeConfig configures the container runtime of the
+	// pool's nodes.
+	// +optional
+	ContainerRuntimeConfig *ContainerRuntimeConfig `json:"containerRuntimeConfig,omitempty"`
+
+	// DiskPartitions are additional partitions for the pool's nodes.
+	// +optional
+	DiskPartitions []DiskPartition `json:"diskPartitions,omitempty"`
+
+	// DiskEncryption encrypts the root filesystem of the pool's nodes.
+	// +optional
+	DiskEncryption *DiskEncryption `json:"diskEncryption,omitempty"`
+
+	// KernelType is the kernel the pool's nodes boot. Defaults to
+	// DefaultKernel.
+	// +optional
+	KernelType KernelType `json:"kernelType,omitempty"`
+
+	// Hyperthreading controls simultaneous multithreading on the pool's
+	// nodes. Defaults to HyperthreadingEnabled.
+	// +optional
+	Hyperthreading Hyperthreading `json:"hyperthreading,omitempty"`
+
+	// Accelerator marks the pool's machines as having accelerators (e.g.
+	// GPUs). Their instance type or flavor must provide them. It is not
+	// supported for the master pool.
+	// +optional
+	Accelerator *Accelerator `json:"accelerator,omitempty"`
+
+	// Architecture is the CPU architecture of the pool's machines, which
+	// selects their RHCOS image and default instance type. Defaults to
+	// ArchitectureAMD64. Only AWS compute pools may be ArchitectureARM64.
+	// +optional
+	Architecture Architecture `json:"architecture,omitempty"`
+
+	// OperatingSystem is the operating system of the pool's machines.
+	// Defaults to OperatingSystemLinux. Only named AWS compute pools may be
+	// OperatingSystemWindows.
+	// +optional
+	OperatingSystem OperatingSystem `json:"operatingSystem,omitempty"`
+}
+
+// OperatingSystem is the operating system of a pool's machines.
+type OperatingSystem string
+
+const (
+	// OperatingSystemLinux is RHCOS, configured by the machine config
+	// operator.
+	OperatingSystemLinux OperatingSystem = "linux"
+
+	// OperatingSystemWindows is Windows Server, whose machines boot
+	// reachable over SSH with the install config's keys, for the Windows
+	// Machine Config Operator (WMCO) to make them nodes. Their nodes are
+	// tainted os=Windows:NoSchedule, so only pods tolerating it use them.
+	OperatingSystemWindows OperatingSystem = "windows"
+)
+
+// Architecture is a CPU architecture, named as by Go and Kubernetes'
+// kubernetes.io/arch node label.
+type Architecture string
+
+const (
+	// ArchitectureAMD64 is 64-bit x86.
+	ArchitectureAMD64 Architecture = "amd64"
+
+	// ArchitectureARM64 is 64-bit ARM (aarch64), e.g. AWS Graviton.
+	ArchitectureARM64 Architecture = "arm64"
+)
+
+// Accelerator describes the accelerators of a compute pool's machines.
+type Accelerator struct {
+	// Type is the kind of accelerator.
+	Type AcceleratorType `json:"type"`
+
+	// Operators installs the Node Feature Discovery and accelerator
+	// operators from the operator catalogs, so the nodes advertise the
+	// accelerators without post-install steps.
+	// +optional
+	Operators bool `json:"operators,omitempty"`
+}
+
+// AcceleratorType is a kind of accelerator.
+type AcceleratorType string
+
+const (
+	// NvidiaGPUAccelerator is an NVIDIA GPU. Nodes are tainted
+	// nvidia.com/gpu:NoSchedule, so only pods tolerating it use them.
+	NvidiaGPUAccelerator AcceleratorType = "NvidiaGPU"
+)
+
+// Hyperthreading controls simultaneous multithreading (SMT).
+type Hyperthreading string
+
+const (
+	// HyperthreadingEnabled leaves SMT to the hardware's setting.
+	HyperthreadingEnabled Hyperthreading = "Enabled"
+
+	// HyperthreadingDisabled boots nodes with the nosmt kernel argument,
+	// halving the logical CPUs of SMT-capable machines.
+	HyperthreadingDisabled Hyperthreading = "Disabled"
+)
+
+// KernelType is a kernel RHCOS nodes can boot.
+type KernelType string
+
+const (
+	// DefaultKernel is the standard RHCOS kernel.
+	DefaultKernel KernelType = "default"
+
+	// RealtimeKernel is the kernel-rt kernel, for latency-sensitive
+	// workloads.
+	RealtimeKernel KernelType = "realtime"
+)
+
+// MachineHealthCheck configures when a pool's machines are unhealthy and
+// replaced.
+type MachineHealthCheck struct {
+	// UnhealthyConditions are the node conditions which make a machine
+	// unhealthy once they have held for their timeout. Defaults to the
+	// Ready condition being False or Unknown for five minutes.
+	// +optional
+	UnhealthyConditions []UnhealthyCondition `json:"unhealthyConditions,omitempty"`
+
+	// MaxUnhealthy is the number (e.g. 2) or percentage (e.g. 40%) of
+	// the pool's machines which may be unhealthy before remediation stops,
+	// so a wider outage does not replace the whole pool. Without it,
+	// remediation never stops.
+	// +optional
+	MaxUnhealthy *intstr.IntOrString `json:"maxUnhealthy,omitempty"`
+}
+
+// UnhealthyCondition is a node condition which makes a machine unhealthy.
+type UnhealthyCondition struct {
+	// Type is the node condition type (e.g. Ready).
+	Type corev1.NodeConditionType `json:"type"`
+
+	// Status is the condition status (True, False or Unknown).
+	Status corev1.ConditionStatus `json:"status"`
+
+	// Timeout is how long the condition must hold (e.g. 300s).
+	Timeout string `json:"timeout"`
 }
 
 // MachinePoolPlatform is the platform-specific configuration for a machine
@@ -0,0 +1,17 @@
+package types
+
+// ManifestHook is an executable run after the manifests are generated,
+// which may contribute additional manifests, so distributions can extend
+// the payload without changing the installer.
+type ManifestHook struct {
+	// Name identifies the hook, and prefixes the names of the manifests
+	// it contributes.
+	Name string `json:"name"`
+
+	// Command is the path of the executable.
+	Command string `json:"command"`
+
+	// Args are the arguments passed to the executable.
+	// +optional
+	Args []string `json:"args,omitempty"`
+}
@@ -0,0 +1,23 @@
+package types
+
+// Monitoring configures the cluster monitoring stack.
+type Monitoring struct {
+	// Retention is how long Prometheus keeps metrics (e.g. 15d).
+	// +optional
+	Retention string `json:"retention,omitempty"`
+
+	// StorageClass is the storage class of the persistent volumes backing
+	// Prometheus and Alertmanager. Without it (and StorageSize), they use
+	// ephemeral storage.
+	// +optional
+	StorageClass string `json:"storageClass,omitempty"`
+
+	// StorageSize is the size of each persistent volume (e.g. 40Gi).
+	// +optional
+	StorageSize string `json:"storageSize,omitempty"`
+
+	// NodeSelector restricts the monitoring components to nodes with
+	// these labels.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+}
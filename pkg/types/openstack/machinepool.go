@@ -6,6 +6,28 @@ type MachinePool struct {
 	// FlavorName defines the OpenStack Nova flavor.
 	// eg. m1.large
 	FlavorName string `json:"type"`
+
+	// RootVolume boots the machines from a Cinder volume instead of the
+	// flavor's ephemeral disk.
+	// +optional
+	RootVolume *RootVolume `json:"rootVolume,omitempty"`
+
+	// Zones is the list of Nova availability zones across which the
+	// machines are spread.
+	// If empty, Nova picks the zone.
+	// +optional
+	Zones []string `json:"zones,omitempty"`
+}
+
+// RootVolume defines the Cinder root volume of the machines.
+type RootVolume struct {
+	// Size is the size of the volume in GiB.
+	Size int `json:"size"`
+
+	// Type is the Cinder volume type.
+	// If empty, the cloud's default type is used.
+	// +optional
+	Type string `json:"type,omitempty"`
 }
 
 // Set sets the values from `required` to `a`.
@@ -17,4 +39,10 @@ func (o *MachinePool) Set(required *MachinePool) {
 	if required.FlavorName != "" {
 		o.FlavorName = required.FlavorName
 	}
+	if required.RootVolume != nil {
+		o.RootVolume = required.RootVolume
+	}
+	if len(required.Zones) > 0 {
+		o.Zones = required.Zones
+	}
 }
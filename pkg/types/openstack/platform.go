@@ -26,4 +26,26 @@ type Platform struct {
 	// ExternalNetwork
 	// The OpenStack external network to be used for installation.
 	ExternalNetwork string `json:"externalNetwork"`
+
+	// APIFloatingIP is an existing floating IP on the external network
+	// which is associated with the first master, for the API.
+	// +optional
+	APIFloatingIP string `json:"apiFloatingIP,omitempty"`
+
+	// IngressFloatingIP is an existing floating IP on the external
+	// network reserved for the ingress router, to which the wildcard
+	// apps record can point.
+	// +optional
+	IngressFloatingIP string `json:"ingressFloatingIP,omitempty"`
+
+	// AllocateFloatingIPs allocates the API and ingress floating IPs
+	// which are not given from the external network. Allocated floating
+	// IPs are released when the cluster is destroyed; given ones are not.
+	// +optional
+	AllocateFloatingIPs bool `json:"allocateFloatingIPs,omitempty"`
+
+	// BootstrapFlavorName is the Nova flavor of the bootstrap machine.
+	// If empty, the master flavor is used.
+	// +optional
+	BootstrapFlavorName string `json:"bootstrapFlavorName,omitempty"`
 }
@@ -0,0 +1,19 @@
+package types
+
+// Project configures how users request projects.
+type Project struct {
+	// RequestMessage is shown to users who may not request projects
+	// themselves, e.g. to say whom to ask for one.
+	// +optional
+	RequestMessage string `json:"requestMessage,omitempty"`
+
+	// RequestTemplate is a template.openshift.io/v1 Template, in YAML,
+	// from which requested projects are created, e.g. to give every
+	// project default network policies, limit ranges and quotas. Its
+	// parameters are those of the default template (PROJECT_NAME,
+	// PROJECT_DISPLAYNAME, PROJECT_DESCRIPTION, PROJECT_ADMIN_USER and
+	// PROJECT_REQUESTING_USER). It is created as project-request in the
+	// openshift-config namespace.
+	// +optional
+	RequestTemplate string `json:"requestTemplate,omitempty"`
+}
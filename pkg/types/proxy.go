@@ -0,0 +1,28 @@
+package types
+
+// Proxy configures the HTTP proxy through which the cluster, and the
+// bootstrap node, reach the outside.  Unless NoProxy covers the API, the
+// admin kubeconfig, and the installer while it waits for the cluster,
+// reach the API through it too.
+type Proxy struct {
+	// HTTPProxy is the URL of the proxy for HTTP requests.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the URL of the proxy for HTTPS requests.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is a comma-separated list of hosts, domains (e.g.
+	// .example.com) and CIDRs which are reached without the proxy. The
+	// cluster's own names and networks are added to it.
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+
+	// TrustBundle is a PEM-encoded bundle of the CAs of the proxy's
+	// certificates, which is trusted by the bootstrap node, by the admin
+	// kubeconfig and, as the user-ca-bundle config map in
+	// openshift-config, by the cluster.
+	// +optional
+	TrustBundle string `json:"trustBundle,omitempty"`
+}
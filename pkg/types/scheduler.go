@@ -0,0 +1,27 @@
+package types
+
+// SchedulerProfile is a named scheduling policy for the cluster scheduler.
+type SchedulerProfile string
+
+const (
+	// LowNodeUtilizationSchedulerProfile spreads pods across nodes to keep
+	// per-node utilization low. It is used when no profile is configured.
+	LowNodeUtilizationSchedulerProfile SchedulerProfile = "LowNodeUtilization"
+
+	// HighNodeUtilizationSchedulerProfile packs pods onto as few nodes as
+	// possible to keep the node count low.
+	HighNodeUtilizationSchedulerProfile SchedulerProfile = "HighNodeUtilization"
+)
+
+// Scheduler configures the cluster scheduler.
+type Scheduler struct {
+	// Profile is the scheduling policy to apply. Defaults to
+	// LowNodeUtilization.
+	// +optional
+	Profile SchedulerProfile `json:"profile,omitempty"`
+
+	// DefaultNodeSelector is the label selector (e.g. "type=user") applied
+	// to pods in projects that do not set their own node selector.
+	// +optional
+	DefaultNodeSelector string `json:"defaultNodeSelector,omitempty"`
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SSHKeys is a list of public SSH keys in authorized_keys format. It can be
+// serialized either as a single string holding one key per line or as a list
+// of strings.
+type SSHKeys []string
+
+// ParseSSHKeys splits authorized_keys content into its individual keys,
+// skipping blank lines and comments.
+func ParseSSHKeys(content string) SSHKeys {
+	var keys SSHKeys
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, line)
+	}
+	return keys
+}
+
+// String returns the keys in authorized_keys format.
+func (k SSHKeys) String() string {
+	return strings.Join(k, "\n")
+}
+
+// MarshalJSON serializes a single key as a string, for compatibility with
+// consumers of the original single-key format, and multiple keys as a list.
+func (k SSHKeys) MarshalJSON() ([]byte, error) {
+	if len(k) <= 1 {
+		return json.Marshal(k.String())
+	}
+	return json.Marshal([]string(k))
+}
+
+// UnmarshalJSON accepts either a string of newline-separated keys or a list
+// of keys.
+func (k *SSHKeys) UnmarshalJSON(b []byte) error {
+	var content string
+	if err := json.Unmarshal(b, &content); err == nil {
+		*k = ParseSSHKeys(content)
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(b, &list); err != nil {
+		return errors.Wrap(err, "sshKey must be a string or a list of strings")
+	}
+
+	*k = nil
+	for _, key := range list {
+		*k = append(*k, ParseSSHKeys(key)...)
+	}
+	return nil
+}
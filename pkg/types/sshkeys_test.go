@@ -0,0 +1,71 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSHKeysUnmarshalJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		expected SSHKeys
+	}{
+		{
+			name: "null",
+			data: `null`,
+		},
+		{
+			name: "empty string",
+			data: `""`,
+		},
+		{
+			name:     "single key",
+			data:     `"ssh-rsa AAAA a@example.com"`,
+			expected: SSHKeys{"ssh-rsa AAAA a@example.com"},
+		},
+		{
+			name:     "authorized_keys content",
+			data:     `"# comment\nssh-rsa AAAA a@example.com\n\nssh-ed25519 BBBB b@example.com\n"`,
+			expected: SSHKeys{"ssh-rsa AAAA a@example.com", "ssh-ed25519 BBBB b@example.com"},
+		},
+		{
+			name:     "list",
+			data:     `["ssh-rsa AAAA a@example.com", " ssh-ed25519 BBBB b@example.com "]`,
+			expected: SSHKeys{"ssh-rsa AAAA a@example.com", "ssh-ed25519 BBBB b@example.com"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var keys SSHKeys
+			err := json.Unmarshal([]byte(tc.data), &keys)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, keys)
+			}
+		})
+	}
+}
+
+func TestSSHKeysUnmarshalJSONInvalid(t *testing.T) {
+	var keys SSHKeys
+	assert.Error(t, json.Unmarshal([]byte(`{"key": "value"}`), &keys))
+}
+
+func TestSSHKeysMarshalJSON(t *testing.T) {
+	cases := []struct {
+		keys     SSHKeys
+		expected string
+	}{
+		{keys: nil, expected: `""`},
+		{keys: SSHKeys{"ssh-rsa AAAA"}, expected: `"ssh-rsa AAAA"`},
+		{keys: SSHKeys{"ssh-rsa AAAA", "ssh-ed25519 BBBB"}, expected: `["ssh-rsa AAAA","ssh-ed25519 BBBB"]`},
+	}
+	for _, tc := range cases {
+		data, err := json.Marshal(tc.keys)
+		if assert.NoError(t, err) {
+			assert.Equal(t, tc.expected, string(data))
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package types
+
+// Telemetry is whether the cluster reports its health to Red Hat.
+type Telemetry string
+
+const (
+	// TelemetryEnabled reports through the telemeter client and the
+	// insights operator.
+	TelemetryEnabled Telemetry = "enabled"
+	// TelemetryDisabled reports nothing, from the first boot of the
+	// cluster.
+	TelemetryDisabled Telemetry = "disabled"
+)
+
+// TelemetryRegistry is the registry whose pull secret auth the telemeter
+// client and the insights operator report with.
+const TelemetryRegistry = "cloud.openshift.com"
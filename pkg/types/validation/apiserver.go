@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func validateAudit(a *types.Audit, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch a.Profile {
+	case "", types.DefaultAuditProfile, types.WriteRequestBodiesAuditProfile, types.AllRequestBodiesAuditProfile:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("profile"), a.Profile, []string{
+			string(types.DefaultAuditProfile),
+			string(types.WriteRequestBodiesAuditProfile),
+			string(types.AllRequestBodiesAuditProfile),
+		}))
+	}
+	return allErrs
+}
+
+func validateNamedCertificates(certs []types.NamedCertificate, secrets []types.ConfigSecret, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, cert := range certs {
+		certPath := fldPath.Index(i)
+		if len(cert.Names) == 0 {
+			allErrs = append(allErrs, field.Required(certPath.Child("names"), "at least one name is required"))
+		}
+		for j, name := range cert.Names {
+			msgs := validation.IsDNS1123Subdomain(name)
+			if strings.HasPrefix(name, "*.") {
+				msgs = validation.IsWildcardDNS1123Subdomain(name)
+			}
+			for _, msg := range msgs {
+				allErrs = append(allErrs, field.Invalid(certPath.Child("names").Index(j), name, msg))
+			}
+		}
+		secretPath := certPath.Child("secret")
+		if cert.Secret == "" {
+			allErrs = append(allErrs, field.Required(secretPath, "the name of a kubernetes.io/tls secret in configSecrets is required"))
+			continue
+		}
+		secret := findConfigSecret(secrets, cert.Secret)
+		switch {
+		case secret == nil:
+			allErrs = append(allErrs, field.NotFound(secretPath, cert.Secret))
+		case secret.Type != string(corev1.SecretTypeTLS):
+			allErrs = append(allErrs, field.Invalid(secretPath, cert.Secret, fmt.Sprintf("must be a secret of type %s", corev1.SecretTypeTLS)))
+		}
+	}
+	return allErrs
+}
+
+func findConfigSecret(secrets []types.ConfigSecret, name string) *types.ConfigSecret {
+	for i := range secrets {
+		if secrets[i].Name == name {
+			return &secrets[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestValidateAPIServer(t *testing.T) {
+	testInstallConfigCases(t, []installConfigCase{
+		{
+			name: "audit profile",
+			modify: func(c *types.InstallConfig) {
+				c.APIServer = &types.APIServer{Audit: types.Audit{Profile: types.WriteRequestBodiesAuditProfile}}
+			},
+		},
+		{
+			name: "unknown audit profile",
+			modify: func(c *types.InstallConfig) {
+				c.APIServer = &types.APIServer{Audit: types.Audit{Profile: "Everything"}}
+			},
+			expectedError: `^apiServer\.audit\.profile: Unsupported value: "Everything": supported values: "Default", "WriteRequestBodies", "AllRequestBodies"$`,
+		},
+		{
+			name: "api server named certificate",
+			modify: func(c *types.InstallConfig) {
+				cert, key := certificateKeyPair("api.example.com")
+				c.ConfigSecrets = []types.ConfigSecret{{
+					Name: "api-cert",
+					Type: "kubernetes.io/tls",
+					Data: map[string]string{"tls.crt": cert, "tls.key": key},
+				}}
+				c.APIServer = &types.APIServer{NamedCertificates: []types.NamedCertificate{{
+					Names:  []string{"api.example.com", "*.api.example.com"},
+					Secret: "api-cert",
+				}}}
+			},
+		},
+		{
+			name: "api server named certificate with a missing secret",
+			modify: func(c *types.InstallConfig) {
+				c.APIServer = &types.APIServer{NamedCertificates: []types.NamedCertificate{{
+					Names:  []string{"api.example.com"},
+					Secret: "api-cert",
+				}}}
+			},
+			expectedError: `^apiServer\.namedCertificates\[0\]\.secret: Not found: "api-cert"$`,
+		},
+		{
+			name: "api server named certificate with an opaque secret",
+			modify: func(c *types.InstallConfig) {
+				c.ConfigSecrets = []types.ConfigSecret{{Name: "api-cert"}}
+				c.APIServer = &types.APIServer{NamedCertificates: []types.NamedCertificate{{
+					Names:  []string{"api.example.com"},
+					Secret: "api-cert",
+				}}}
+			},
+			expectedError: `^apiServer\.namedCertificates\[0\]\.secret: Invalid value: "api-cert": must be a secret of type kubernetes\.io/tls$`,
+		},
+	})
+}
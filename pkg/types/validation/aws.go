@@ -0,0 +1,262 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+)
+
+var (
+	hostedZoneIDPattern = regexp.MustCompile(`^(/hostedzone/)?Z[A-Z0-9]+$`)
+
+	// securityGroupIDPattern matches EC2 security group IDs.
+	securityGroupIDPattern = regexp.MustCompile(`^sg-[0-9a-f]+$`)
+
+	// iamRoleARNPattern matches IAM role ARNs in any AWS partition.
+	iamRoleARNPattern = regexp.MustCompile(`^arn:aws[a-z-]*:iam::[0-9]{12}:role/.+$`)
+)
+
+func validateAWSPlatform(p *aws.Platform, machines []types.MachinePool, credentialsMode types.CredentialsMode, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p.TagNamespace != "" {
+		allErrs = append(allErrs, validateTagNamespace(p.TagNamespace, fldPath.Child("tagNamespace"))...)
+	}
+	if p.HostedZone != "" && !hostedZoneIDPattern.MatchString(p.HostedZone) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("hostedZone"), p.HostedZone, "must be a Route53 hosted zone ID (e.g. Z1ILINNUJGTAO1)"))
+	}
+	allErrs = append(allErrs, validateServiceEndpoints(p.ServiceEndpoints, fldPath.Child("serviceEndpoints"))...)
+	if len(p.ComponentRoles) > 0 {
+		allErrs = append(allErrs, validateComponentRoles(p.ComponentRoles, aws.Partition(p.Region), credentialsMode, fldPath.Child("componentRoles"))...)
+	}
+	if aws.Partition(p.Region) == "aws-cn" {
+		allErrs = append(allErrs, validateAWSAMIs(p, machines, fldPath)...)
+	}
+	if p.APILoadBalancer != nil {
+		allErrs = append(allErrs, validateAPILoadBalancer(p.APILoadBalancer, fldPath.Child("apiLoadBalancer"))...)
+	}
+	if p.DefaultMachinePlatform != nil {
+		allErrs = append(allErrs, validateAWSMachinePool(p.DefaultMachinePlatform, false, fldPath.Child("defaultMachinePlatform"))...)
+	}
+	for i, m := range machines {
+		if m.Platform.AWS != nil {
+			poolPath := field.NewPath("machines").Index(i).Child("platform", "aws")
+			allErrs = append(allErrs, validateAWSMachinePool(m.Platform.AWS, m.Name == "master", poolPath)...)
+			if t := m.Platform.AWS.InstanceType; t != "" && aws.ARM64InstanceType(t) != (m.Architecture == types.ArchitectureARM64) {
+				arch := m.Architecture
+				if arch == "" {
+					arch = types.ArchitectureAMD64
+				}
+				allErrs = append(allErrs, field.Invalid(poolPath.Child("type"), t, fmt.Sprintf("must be an instance type of the pool's architecture, %s", arch)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// reservedTagNamespaces are the tag namespaces of Kubernetes itself.
+var reservedTagNamespaces = []string{"kubernetes.io", "k8s.io"}
+
+func validateTagNamespace(namespace string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, msg := range validation.IsDNS1123Subdomain(namespace) {
+		allErrs = append(allErrs, field.Invalid(fldPath, namespace, msg))
+	}
+	for _, reserved := range reservedTagNamespaces {
+		if namespace == reserved || strings.HasSuffix(namespace, "."+reserved) {
+			allErrs = append(allErrs, field.Invalid(fldPath, namespace, fmt.Sprintf("%s is reserved for Kubernetes", reserved)))
+		}
+	}
+	return allErrs
+}
+
+func validateBastion(b *aws.Bastion, sshKeys types.SSHKeys, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(sshKeys) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("sshKey"), "an SSH key is required to log in to the bastion"))
+	}
+	for i, cidr := range b.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allowedCIDRs").Index(i), cidr, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// validateAWSMachinePool checks the AWS configuration of a machine pool.
+// Only the masters, which are created by Terraform, support a tenancy and
+// placement group; the machine API provider config has no fields for them.
+func validateAWSMachinePool(p *aws.MachinePool, master bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	securityGroupIDs := map[string]bool{}
+	for i, id := range p.AdditionalSecurityGroupIDs {
+		idPath := fldPath.Child("additionalSecurityGroupIDs").Index(i)
+		if !securityGroupIDPattern.MatchString(id) {
+			allErrs = append(allErrs, field.Invalid(idPath, id, "must be a security group ID (e.g. sg-51530134)"))
+		} else if securityGroupIDs[id] {
+			allErrs = append(allErrs, field.Duplicate(idPath, id))
+		}
+		securityGroupIDs[id] = true
+	}
+	switch p.Tenancy {
+	case "", aws.DefaultTenancy, aws.DedicatedTenancy, aws.HostTenancy:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("tenancy"), p.Tenancy, []string{string(aws.DefaultTenancy), string(aws.DedicatedTenancy), string(aws.HostTenancy)}))
+	}
+	if master {
+		return allErrs
+	}
+	if p.Tenancy != "" && p.Tenancy != aws.DefaultTenancy {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("tenancy"), "only supported for the master pool"))
+	}
+	if p.PlacementGroup != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("placementGroup"), "only supported for the master pool"))
+	}
+	return allErrs
+}
+
+// validateAPILoadBalancer checks the API load balancer configuration
+// against the limits of its type.
+func validateAPILoadBalancer(lb *aws.APILoadBalancer, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	classic := lb.Type == aws.ClassicLoadBalancer
+	switch lb.Type {
+	case "", aws.NetworkLoadBalancer, aws.ClassicLoadBalancer:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), lb.Type, []string{string(aws.NetworkLoadBalancer), string(aws.ClassicLoadBalancer)}))
+	}
+	if lb.IdleTimeout != 0 {
+		if !classic {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("idleTimeout"), "only supported for Classic load balancers"))
+		} else if lb.IdleTimeout < 1 || lb.IdleTimeout > 4000 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("idleTimeout"), lb.IdleTimeout, "must be from 1 to 4000 seconds"))
+		}
+	}
+	names := map[aws.APIListenerName]bool{}
+	for i, listener := range lb.Listeners {
+		listenerPath := fldPath.Child("listeners").Index(i)
+		switch listener.Name {
+		case aws.APIServerListener:
+		case aws.MachineConfigServerListener:
+			if classic {
+				allErrs = append(allErrs, field.Forbidden(listenerPath.Child("name"), "Classic load balancers only have the health check of the api listener"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(listenerPath.Child("name"), listener.Name, []string{string(aws.APIServerListener), string(aws.MachineConfigServerListener)}))
+		}
+		if names[listener.Name] {
+			allErrs = append(allErrs, field.Duplicate(listenerPath.Child("name"), listener.Name))
+		}
+		names[listener.Name] = true
+
+		healthCheck := listener.HealthCheck
+		healthCheckPath := listenerPath.Child("healthCheck")
+		if interval := healthCheck.IntervalSeconds; interval != 0 {
+			if classic && (interval < 5 || interval > 300) {
+				allErrs = append(allErrs, field.Invalid(healthCheckPath.Child("intervalSeconds"), interval, "must be from 5 to 300 for Classic load balancers"))
+			} else if !classic && interval != 10 && interval != 30 {
+				allErrs = append(allErrs, field.NotSupported(healthCheckPath.Child("intervalSeconds"), interval, []string{"10", "30"}))
+			}
+		}
+		healthy, unhealthy := healthCheck.HealthyThreshold, healthCheck.UnhealthyThreshold
+		if healthy != 0 && (healthy < 2 || healthy > 10) {
+			allErrs = append(allErrs, field.Invalid(healthCheckPath.Child("healthyThreshold"), healthy, "must be from 2 to 10"))
+		}
+		if unhealthy != 0 && (unhealthy < 2 || unhealthy > 10) {
+			allErrs = append(allErrs, field.Invalid(healthCheckPath.Child("unhealthyThreshold"), unhealthy, "must be from 2 to 10"))
+		}
+		// Zero thresholds default to 3.
+		if healthy == 0 {
+			healthy = 3
+		}
+		if unhealthy == 0 {
+			unhealthy = 3
+		}
+		if !classic && healthy != unhealthy {
+			allErrs = append(allErrs, field.Invalid(healthCheckPath.Child("unhealthyThreshold"), healthCheck.UnhealthyThreshold, "must equal healthyThreshold for network load balancers"))
+		}
+	}
+	return allErrs
+}
+
+// validateAWSAMIs checks that every machine pool has an AMI, for regions
+// in which no RHCOS AMIs are published.
+func validateAWSAMIs(p *aws.Platform, machines []types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	defaultAMI := p.DefaultMachinePlatform != nil && p.DefaultMachinePlatform.AMIID != ""
+	for i, m := range machines {
+		// Windows AMIs are looked up in the region.
+		if m.OperatingSystem == types.OperatingSystemWindows {
+			continue
+		}
+		// arm64 pools do not use the default machine platform's AMI.
+		if defaultAMI && m.Architecture != types.ArchitectureARM64 {
+			continue
+		}
+		if m.Platform.AWS != nil && m.Platform.AWS.AMIID != "" {
+			continue
+		}
+		amiPath := field.NewPath("machines").Index(i).Child("platform", "aws", "amiID")
+		if m.Architecture == types.ArchitectureARM64 {
+			allErrs = append(allErrs, field.Required(amiPath, fmt.Sprintf("RHCOS AMIs are not published in %s; set this to an arm64 AMI", p.Region)))
+		} else {
+			allErrs = append(allErrs, field.Required(amiPath, fmt.Sprintf("RHCOS AMIs are not published in %s; set this or %s", p.Region, fldPath.Child("defaultMachinePlatform", "amiID"))))
+		}
+	}
+	return allErrs
+}
+
+func validateServiceEndpoints(endpoints []aws.ServiceEndpoint, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := map[string]bool{}
+	for i, endpoint := range endpoints {
+		supported := false
+		for _, name := range aws.ServiceEndpointNames {
+			supported = supported || endpoint.Name == name
+		}
+		if !supported {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i).Child("name"), endpoint.Name, aws.ServiceEndpointNames))
+		} else if seen[endpoint.Name] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), endpoint.Name))
+		}
+		seen[endpoint.Name] = true
+
+		u, err := url.Parse(endpoint.URL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("url"), endpoint.URL, "must be an absolute https URL"))
+		}
+	}
+	return allErrs
+}
+
+func validateComponentRoles(roles map[string]string, partition string, credentialsMode types.CredentialsMode, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if credentialsMode != types.ManualCredentialsMode {
+		// Outside Manual mode the cloud credential operator mints the
+		// component credentials itself.
+		allErrs = append(allErrs, field.Invalid(fldPath, roles, fmt.Sprintf("requires credentialsMode %s", types.ManualCredentialsMode)))
+	}
+	for key, arn := range roles {
+		parts := strings.Split(key, "/")
+		switch {
+		case len(parts) != 2:
+			allErrs = append(allErrs, field.Invalid(fldPath, key, "keys must be of the form <namespace>/<secret>"))
+		case len(validation.IsDNS1123Label(parts[0])) != 0 || len(validation.IsDNS1123Subdomain(parts[1])) != 0:
+			allErrs = append(allErrs, field.Invalid(fldPath, key, "keys must be a valid namespace and secret name"))
+		}
+		switch {
+		case !iamRoleARNPattern.MatchString(arn):
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), arn, "must be an IAM role ARN (e.g. arn:aws:iam::123456789012:role/openshift-machine-api)"))
+		case !strings.HasPrefix(arn, fmt.Sprintf("arn:%s:", partition)):
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), arn, fmt.Sprintf("must be in the %s partition of the cluster's region", partition)))
+		}
+	}
+	return allErrs
+}
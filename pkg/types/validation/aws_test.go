@@ -0,0 +1,296 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+)
+
+func TestValidateAWSPlatform(t *testing.T) {
+	testInstallConfigCases(t, []installConfigCase{
+		{
+			name:   "aws hosted zone",
+			modify: func(c *types.InstallConfig) { c.Platform.AWS = &aws.Platform{HostedZone: "Z1ILINNUJGTAO1"} },
+		},
+		{
+			name:   "aws hosted zone with prefix",
+			modify: func(c *types.InstallConfig) { c.Platform.AWS = &aws.Platform{HostedZone: "/hostedzone/Z1ILINNUJGTAO1"} },
+		},
+		{
+			name:          "invalid aws hosted zone",
+			modify:        func(c *types.InstallConfig) { c.Platform.AWS = &aws.Platform{HostedZone: "example.com"} },
+			expectedError: `^platform\.aws\.hostedZone: Invalid value: "example\.com": must be a Route53 hosted zone ID`,
+		},
+		{
+			name: "aws classic api load balancer",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{APILoadBalancer: &aws.APILoadBalancer{
+					Type:        aws.ClassicLoadBalancer,
+					Internal:    true,
+					IdleTimeout: 600,
+					Listeners: []aws.APIListener{{
+						Name:        aws.APIServerListener,
+						HealthCheck: aws.APIHealthCheck{IntervalSeconds: 5, HealthyThreshold: 2, UnhealthyThreshold: 4},
+					}},
+				}}
+			},
+		},
+		{
+			name: "invalid classic api load balancer",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{APILoadBalancer: &aws.APILoadBalancer{
+					Type:        aws.ClassicLoadBalancer,
+					IdleTimeout: 5000,
+					Listeners: []aws.APIListener{{
+						Name:        aws.MachineConfigServerListener,
+						HealthCheck: aws.APIHealthCheck{IntervalSeconds: 1},
+					}},
+				}}
+			},
+			expectedError: `^\[platform\.aws\.apiLoadBalancer\.idleTimeout: Invalid value: 5000: must be from 1 to 4000 seconds, platform\.aws\.apiLoadBalancer\.listeners\[0\]\.name: Forbidden: Classic load balancers only have the health check of the api listener, platform\.aws\.apiLoadBalancer\.listeners\[0\]\.healthCheck\.intervalSeconds: Invalid value: 1: must be from 5 to 300 for Classic load balancers\]$`,
+		},
+		{
+			name: "invalid network api load balancer",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{APILoadBalancer: &aws.APILoadBalancer{
+					IdleTimeout: 600,
+					Listeners: []aws.APIListener{{
+						Name:        aws.MachineConfigServerListener,
+						HealthCheck: aws.APIHealthCheck{IntervalSeconds: 30, HealthyThreshold: 3},
+					}, {
+						Name:        aws.MachineConfigServerListener,
+						HealthCheck: aws.APIHealthCheck{IntervalSeconds: 5, HealthyThreshold: 2, UnhealthyThreshold: 11},
+					}},
+				}}
+			},
+			expectedError: `^\[platform\.aws\.apiLoadBalancer\.idleTimeout: Forbidden: only supported for Classic load balancers, platform\.aws\.apiLoadBalancer\.listeners\[1\]\.name: Duplicate value: "machineConfigServer", platform\.aws\.apiLoadBalancer\.listeners\[1\]\.healthCheck\.intervalSeconds: Unsupported value: 5: supported values: "10", "30", platform\.aws\.apiLoadBalancer\.listeners\[1\]\.healthCheck\.unhealthyThreshold: Invalid value: 11: must be from 2 to 10, platform\.aws\.apiLoadBalancer\.listeners\[1\]\.healthCheck\.unhealthyThreshold: Invalid value: 11: must equal healthyThreshold for network load balancers\]$`,
+		},
+		{
+			name: "aws master placement",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{}
+				c.Machines = []types.MachinePool{{
+					Name:     "master",
+					Platform: types.MachinePoolPlatform{AWS: &aws.MachinePool{Tenancy: aws.DedicatedTenancy, PlacementGroup: "hpc"}},
+				}}
+			},
+		},
+		{
+			name: "invalid aws tenancy",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{}
+				c.Machines = []types.MachinePool{{
+					Name:     "master",
+					Platform: types.MachinePoolPlatform{AWS: &aws.MachinePool{Tenancy: "shared"}},
+				}}
+			},
+			expectedError: `^machines\[0\]\.platform\.aws\.tenancy: Unsupported value: "shared": supported values: "default", "dedicated", "host"$`,
+		},
+		{
+			name: "aws additional security groups",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{DefaultMachinePlatform: &aws.MachinePool{AdditionalSecurityGroupIDs: []string{"sg-51530134"}}}
+				c.Machines = []types.MachinePool{{
+					Name:     "worker",
+					Platform: types.MachinePoolPlatform{AWS: &aws.MachinePool{AdditionalSecurityGroupIDs: []string{"sg-51530134", "sg-b253d7cc"}}},
+				}}
+			},
+		},
+		{
+			name: "invalid aws additional security groups",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{}
+				c.Machines = []types.MachinePool{{
+					Name:     "master",
+					Platform: types.MachinePoolPlatform{AWS: &aws.MachinePool{AdditionalSecurityGroupIDs: []string{"sg-51530134", "sg-51530134", "management"}}},
+				}}
+			},
+			expectedError: `^\[machines\[0\]\.platform\.aws\.additionalSecurityGroupIDs\[1\]: Duplicate value: "sg-51530134", machines\[0\]\.platform\.aws\.additionalSecurityGroupIDs\[2\]: Invalid value: "management": must be a security group ID \(e\.g\. sg-51530134\)\]$`,
+		},
+		{
+			name: "aws worker placement",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{DefaultMachinePlatform: &aws.MachinePool{PlacementGroup: "hpc"}}
+				c.Machines = []types.MachinePool{{
+					Name:     "worker",
+					Platform: types.MachinePoolPlatform{AWS: &aws.MachinePool{Tenancy: aws.HostTenancy}},
+				}}
+			},
+			expectedError: `^\[platform\.aws\.defaultMachinePlatform\.placementGroup: Forbidden: only supported for the master pool, machines\[0\]\.platform\.aws\.tenancy: Forbidden: only supported for the master pool\]$`,
+		},
+		{
+			name: "aws component roles",
+			modify: func(c *types.InstallConfig) {
+				c.CredentialsMode = types.ManualCredentialsMode
+				c.Platform.AWS = &aws.Platform{ComponentRoles: map[string]string{
+					"openshift-machine-api/aws-cloud-credentials": "arn:aws:iam::123456789012:role/openshift-machine-api",
+				}}
+			},
+		},
+		{
+			name: "aws component roles without manual credentials",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{ComponentRoles: map[string]string{
+					"openshift-machine-api/aws-cloud-credentials": "arn:aws:iam::123456789012:role/openshift-machine-api",
+				}}
+			},
+			expectedError: `^platform\.aws\.componentRoles: Invalid value: .*: requires credentialsMode Manual$`,
+		},
+		{
+			name: "invalid aws component role key",
+			modify: func(c *types.InstallConfig) {
+				c.CredentialsMode = types.ManualCredentialsMode
+				c.Platform.AWS = &aws.Platform{ComponentRoles: map[string]string{
+					"aws-cloud-credentials": "arn:aws:iam::123456789012:role/openshift-machine-api",
+				}}
+			},
+			expectedError: `^platform\.aws\.componentRoles: Invalid value: "aws-cloud-credentials": keys must be of the form <namespace>/<secret>$`,
+		},
+		{
+			name: "invalid aws component role arn",
+			modify: func(c *types.InstallConfig) {
+				c.CredentialsMode = types.ManualCredentialsMode
+				c.Platform.AWS = &aws.Platform{ComponentRoles: map[string]string{
+					"openshift-machine-api/aws-cloud-credentials": "openshift-machine-api",
+				}}
+			},
+			expectedError: `^platform\.aws\.componentRoles\[openshift-machine-api/aws-cloud-credentials\]: Invalid value: "openshift-machine-api": must be an IAM role ARN`,
+		},
+		{
+			name: "aws component role arn in another partition",
+			modify: func(c *types.InstallConfig) {
+				c.CredentialsMode = types.ManualCredentialsMode
+				c.Platform.AWS = &aws.Platform{
+					Region:                 "cn-north-1",
+					DefaultMachinePlatform: &aws.MachinePool{AMIID: "ami-0123456789abcdef0"},
+					ComponentRoles: map[string]string{
+						"openshift-machine-api/aws-cloud-credentials": "arn:aws:iam::123456789012:role/openshift-machine-api",
+					},
+				}
+			},
+			expectedError: `^platform\.aws\.componentRoles\[openshift-machine-api/aws-cloud-credentials\]: Invalid value: "arn:aws:iam::123456789012:role/openshift-machine-api": must be in the aws-cn partition of the cluster's region$`,
+		},
+		{
+			name: "aws china",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "master"}, {Name: "worker"}}
+				c.Platform.AWS = &aws.Platform{
+					Region:                 "cn-northwest-1",
+					DefaultMachinePlatform: &aws.MachinePool{AMIID: "ami-0123456789abcdef0"},
+				}
+			},
+		},
+		{
+			name: "aws china without an ami",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{
+					{Name: "master", Platform: types.MachinePoolPlatform{AWS: &aws.MachinePool{AMIID: "ami-0123456789abcdef0"}}},
+					{Name: "worker"},
+				}
+				c.Platform.AWS = &aws.Platform{Region: "cn-north-1"}
+			},
+			expectedError: `^machines\[1\]\.platform\.aws\.amiID: Required value: RHCOS AMIs are not published in cn-north-1; set this or platform\.aws\.defaultMachinePlatform\.amiID$`,
+		},
+		{
+			name: "aws china arm64 pool with only a default ami",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "master"}, {Name: "arm", Architecture: types.ArchitectureARM64}}
+				c.Platform.AWS = &aws.Platform{
+					Region:                 "cn-north-1",
+					DefaultMachinePlatform: &aws.MachinePool{AMIID: "ami-0123456789abcdef0"},
+				}
+			},
+			expectedError: `^machines\[1\]\.platform\.aws\.amiID: Required value: RHCOS AMIs are not published in cn-north-1; set this to an arm64 AMI$`,
+		},
+		{
+			name: "aws bastion",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1", Bastion: &aws.Bastion{AllowedCIDRs: []string{"192.0.2.0/24"}}}
+			},
+		},
+		{
+			name: "aws bastion without an ssh key",
+			modify: func(c *types.InstallConfig) {
+				c.SSHKey = nil
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1", Bastion: &aws.Bastion{}}
+			},
+			expectedError: `^sshKey: Required value: an SSH key is required to log in to the bastion$`,
+		},
+		{
+			name: "aws bastion with an invalid allowed CIDR",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1", Bastion: &aws.Bastion{AllowedCIDRs: []string{"192.0.2.1"}}}
+			},
+			expectedError: `^platform\.aws\.bastion\.allowedCIDRs\[0\]: Invalid value: "192\.0\.2\.1": invalid CIDR address: 192\.0\.2\.1$`,
+		},
+		{
+			name: "aws tag namespace",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1", TagNamespace: "example.com"}
+			},
+		},
+		{
+			name: "invalid aws tag namespace",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1", TagNamespace: "Example.com/"}
+			},
+			expectedError: `^platform\.aws\.tagNamespace: Invalid value: "Example\.com/": a DNS-1123 subdomain must consist of lower case alphanumeric characters`,
+		},
+		{
+			name: "reserved aws tag namespace",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1", TagNamespace: "node.kubernetes.io"}
+			},
+			expectedError: `^platform\.aws\.tagNamespace: Invalid value: "node\.kubernetes\.io": kubernetes\.io is reserved for Kubernetes$`,
+		},
+		{
+			name: "aws service endpoints",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{Region: "us-gov-west-1", ServiceEndpoints: []aws.ServiceEndpoint{
+					{Name: "ec2", URL: "https://ec2.us-gov-west-1.amazonaws.com"},
+					{Name: "elasticloadbalancing", URL: "https://elasticloadbalancing.us-gov-west-1.amazonaws.com"},
+				}}
+			},
+		},
+		{
+			name: "unsupported aws service endpoint",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{ServiceEndpoints: []aws.ServiceEndpoint{
+					{Name: "lambda", URL: "https://lambda.example.com"},
+				}}
+			},
+			expectedError: `^platform\.aws\.serviceEndpoints\[0\]\.name: Unsupported value: "lambda": supported values: "ec2", `,
+		},
+		{
+			name: "duplicate aws service endpoint",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{ServiceEndpoints: []aws.ServiceEndpoint{
+					{Name: "ec2", URL: "https://ec2.example.com"},
+					{Name: "ec2", URL: "https://ec2.example.org"},
+				}}
+			},
+			expectedError: `^platform\.aws\.serviceEndpoints\[1\]\.name: Duplicate value: "ec2"$`,
+		},
+		{
+			name: "insecure aws service endpoint",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{ServiceEndpoints: []aws.ServiceEndpoint{
+					{Name: "ec2", URL: "http://ec2.example.com"},
+				}}
+			},
+			expectedError: `^platform\.aws\.serviceEndpoints\[0\]\.url: Invalid value: "http://ec2.example.com": must be an absolute https URL$`,
+		},
+		{
+			name: "instance type of another architecture",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{}
+				c.Machines = []types.MachinePool{
+					{Name: "worker", Platform: types.MachinePoolPlatform{AWS: &aws.MachinePool{InstanceType: "c6gn.xlarge"}}},
+					{Name: "arm", Architecture: types.ArchitectureARM64, Platform: types.MachinePoolPlatform{AWS: &aws.MachinePool{InstanceType: "m5.large"}}},
+				}
+			},
+			expectedError: `^\[machines\[0\]\.platform\.aws\.type: Invalid value: "c6gn\.xlarge": must be an instance type of the pool's architecture, amd64, machines\[1\]\.platform\.aws\.type: Invalid value: "m5\.large": must be an instance type of the pool's architecture, arm64\]$`,
+		},
+	})
+}
@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func validateConfigSecrets(secrets []types.ConfigSecret, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	for i, s := range secrets {
+		secretPath := fldPath.Index(i)
+		allErrs = append(allErrs, validateConfigResourceName(s.Name, names, secretPath.Child("name"))...)
+		allErrs = append(allErrs, validateConfigResourceData(s.Data, secretPath.Child("data"))...)
+		if s.Type == string(corev1.SecretTypeTLS) {
+			if _, err := tls.X509KeyPair([]byte(s.Data[corev1.TLSCertKey]), []byte(s.Data[corev1.TLSPrivateKeyKey])); err != nil {
+				// Do not echo the private key.
+				allErrs = append(allErrs, field.Invalid(secretPath.Child("data"), "<redacted>", fmt.Sprintf("%s and %s must be a PEM-encoded certificate and key pair: %v", corev1.TLSCertKey, corev1.TLSPrivateKeyKey, err)))
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateConfigMaps(configMaps []types.ConfigMap, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	for i, cm := range configMaps {
+		cmPath := fldPath.Index(i)
+		allErrs = append(allErrs, validateConfigResourceName(cm.Name, names, cmPath.Child("name"))...)
+		allErrs = append(allErrs, validateConfigResourceData(cm.Data, cmPath.Child("data"))...)
+	}
+	return allErrs
+}
+
+func validateConfigResourceName(name string, names map[string]bool, fldPath *field.Path) field.ErrorList {
+	if name == "" {
+		return field.ErrorList{field.Required(fldPath, "name is required")}
+	}
+	allErrs := field.ErrorList{}
+	for _, msg := range validation.IsDNS1123Subdomain(name) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, msg))
+	}
+	if names[name] {
+		allErrs = append(allErrs, field.Duplicate(fldPath, name))
+	}
+	names[name] = true
+	return allErrs
+}
+
+func validateConfigResourceData(data map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, key := range sets.StringKeySet(data).List() {
+		for _, msg := range validation.IsConfigMapKey(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), key, msg))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,45 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestValidateConfigResources(t *testing.T) {
+	testInstallConfigCases(t, []installConfigCase{
+		{
+			name:          "duplicate config maps",
+			modify:        func(c *types.InstallConfig) { c.ConfigMaps = []types.ConfigMap{{Name: "ca"}, {Name: "ca"}} },
+			expectedError: `^configMaps\[1\]\.name: Duplicate value: "ca"$`,
+		},
+		{
+			name: "invalid config map key",
+			modify: func(c *types.InstallConfig) {
+				c.ConfigMaps = []types.ConfigMap{{Name: "ca", Data: map[string]string{"ca/crt": "x"}}}
+			},
+			expectedError: `^configMaps\[0\]\.data\[ca/crt\]: Invalid value: "ca/crt": .*$`,
+		},
+		{
+			name:          "invalid config secret name",
+			modify:        func(c *types.InstallConfig) { c.ConfigSecrets = []types.ConfigSecret{{Name: "API_Cert"}} },
+			expectedError: `^configSecrets\[0\]\.name: Invalid value: "API_Cert": .*$`,
+		},
+		{
+			name: "tls config secret with a mismatched key",
+			modify: func(c *types.InstallConfig) {
+				cert, _ := certificateKeyPair("api.example.com")
+				_, otherKey := certificateKeyPair("api.example.com")
+				c.ConfigSecrets = []types.ConfigSecret{{
+					Name: "api-cert",
+					Type: "kubernetes.io/tls",
+					Data: map[string]string{
+						"tls.crt": cert,
+						"tls.key": otherKey,
+					},
+				}}
+			},
+			expectedError: `^configSecrets\[0\]\.data: Invalid value: "<redacted>": tls\.crt and tls\.key must be a PEM-encoded certificate and key pair: tls: private key does not match public key$`,
+		},
+	})
+}
@@ -0,0 +1,116 @@
+package validation
+
+import (
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func validateIdentityProviders(idps []types.IdentityProvider, configMaps []types.ConfigMap, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	for i, idp := range idps {
+		idpPath := fldPath.Index(i)
+		if idp.Name == "" {
+			allErrs = append(allErrs, field.Required(idpPath.Child("name"), "identity provider name is required"))
+		} else {
+			for _, msg := range validation.IsDNS1123Subdomain(idp.Name) {
+				allErrs = append(allErrs, field.Invalid(idpPath.Child("name"), idp.Name, msg))
+			}
+			if names[idp.Name] {
+				allErrs = append(allErrs, field.Duplicate(idpPath.Child("name"), idp.Name))
+			}
+			names[idp.Name] = true
+		}
+
+		switch idp.MappingMethod {
+		case "", types.ClaimMappingMethod, types.LookupMappingMethod, types.AddMappingMethod:
+		default:
+			allErrs = append(allErrs, field.NotSupported(idpPath.Child("mappingMethod"), idp.MappingMethod, []string{
+				string(types.ClaimMappingMethod),
+				string(types.LookupMappingMethod),
+				string(types.AddMappingMethod),
+			}))
+		}
+
+		configured := 0
+		if idp.HTPasswd != nil {
+			configured++
+			if idp.HTPasswd.FileData == "" {
+				allErrs = append(allErrs, field.Required(idpPath.Child("htpasswd", "fileData"), "htpasswd file content is required"))
+			}
+		}
+		if idp.LDAP != nil {
+			configured++
+			allErrs = append(allErrs, validateLDAPIdentityProvider(idp.LDAP, configMaps, idpPath.Child("ldap"))...)
+		}
+		if idp.OpenID != nil {
+			configured++
+			allErrs = append(allErrs, validateOpenIDIdentityProvider(idp.OpenID, configMaps, idpPath.Child("openID"))...)
+		}
+		if configured != 1 {
+			allErrs = append(allErrs, field.Invalid(idpPath, idp.Name, "exactly one of htpasswd, ldap, and openID must be set"))
+		}
+	}
+	return allErrs
+}
+
+func validateLDAPIdentityProvider(p *types.LDAPIdentityProvider, configMaps []types.ConfigMap, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	u, err := url.Parse(p.URL)
+	switch {
+	case p.URL == "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("url"), "LDAP URL is required"))
+	case err != nil:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), p.URL, err.Error()))
+	case u.Scheme != "ldap" && u.Scheme != "ldaps":
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), p.URL, "must be an ldap:// or ldaps:// URL"))
+	case u.Scheme == "ldaps" && p.Insecure:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("insecure"), p.Insecure, "may not be set for ldaps:// URLs"))
+	}
+	if len(p.Attributes.ID) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("attributes", "id"), "at least one ID attribute is required"))
+	}
+	allErrs = append(allErrs, validateCAReference(p.CA, p.CAConfigMap, configMaps, fldPath)...)
+	return allErrs
+}
+
+func validateOpenIDIdentityProvider(p *types.OpenIDIdentityProvider, configMaps []types.ConfigMap, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p.ClientID == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("clientID"), "OAuth client ID is required"))
+	}
+	if p.ClientSecret == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("clientSecret"), "OAuth client secret is required"))
+	}
+	if u, err := url.Parse(p.Issuer); err != nil || u.Scheme != "https" || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("issuer"), p.Issuer, "must be an https URL"))
+	}
+	allErrs = append(allErrs, validateCAReference(p.CA, p.CAConfigMap, configMaps, fldPath)...)
+	return allErrs
+}
+
+// validateCAReference checks the CA bundle of an identity provider, given
+// either inline or as the ca.crt key of a config map in configMaps.
+func validateCAReference(ca, caConfigMap string, configMaps []types.ConfigMap, fldPath *field.Path) field.ErrorList {
+	if caConfigMap == "" {
+		return validateCABundle(ca, fldPath.Child("ca"))
+	}
+	if ca != "" {
+		return field.ErrorList{field.Forbidden(fldPath.Child("caConfigMap"), "may not be set with ca")}
+	}
+	for _, cm := range configMaps {
+		if cm.Name != caConfigMap {
+			continue
+		}
+		bundle := cm.Data["ca.crt"]
+		if bundle == "" {
+			return field.ErrorList{field.Invalid(fldPath.Child("caConfigMap"), caConfigMap, "config map has no ca.crt key")}
+		}
+		return validateCABundle(bundle, fldPath.Child("caConfigMap"))
+	}
+	return field.ErrorList{field.NotFound(fldPath.Child("caConfigMap"), caConfigMap)}
+}
@@ -0,0 +1,146 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestValidateIdentityProviders(t *testing.T) {
+	testInstallConfigCases(t, []installConfigCase{
+		{
+			name: "identity providers",
+			modify: func(c *types.InstallConfig) {
+				c.IdentityProviders = []types.IdentityProvider{
+					{
+						Name:     "local",
+						HTPasswd: &types.HTPasswdIdentityProvider{FileData: "admin:$apr1$N4Ks5Xg5$HZyBtv4ky2mWyzYSmQfGB/\n"},
+					},
+					{
+						Name:          "corp-ldap",
+						MappingMethod: types.LookupMappingMethod,
+						LDAP: &types.LDAPIdentityProvider{
+							URL:        "ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid",
+							Attributes: types.LDAPAttributes{ID: []string{"dn"}},
+						},
+					},
+					{
+						Name: "sso",
+						OpenID: &types.OpenIDIdentityProvider{
+							ClientID:     "openshift",
+							ClientSecret: "secret",
+							Issuer:       "https://sso.example.com",
+						},
+					},
+				}
+			},
+		},
+		{
+			name:          "identity provider without a type",
+			modify:        func(c *types.InstallConfig) { c.IdentityProviders = []types.IdentityProvider{{Name: "local"}} },
+			expectedError: `^identityProviders\[0\]: Invalid value: "local": exactly one of htpasswd, ldap, and openID must be set$`,
+		},
+		{
+			name: "duplicate identity provider names",
+			modify: func(c *types.InstallConfig) {
+				c.IdentityProviders = []types.IdentityProvider{
+					{Name: "local", HTPasswd: &types.HTPasswdIdentityProvider{FileData: "a:b"}},
+					{Name: "local", HTPasswd: &types.HTPasswdIdentityProvider{FileData: "c:d"}},
+				}
+			},
+			expectedError: `^identityProviders\[1\]\.name: Duplicate value: "local"$`,
+		},
+		{
+			name: "insecure ldaps identity provider",
+			modify: func(c *types.InstallConfig) {
+				c.IdentityProviders = []types.IdentityProvider{{
+					Name: "corp-ldap",
+					LDAP: &types.LDAPIdentityProvider{
+						URL:        "ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid",
+						Insecure:   true,
+						Attributes: types.LDAPAttributes{ID: []string{"dn"}},
+					},
+				}}
+			},
+			expectedError: `^identityProviders\[0\]\.ldap\.insecure: Invalid value: true: may not be set for ldaps:// URLs$`,
+		},
+		{
+			name: "openid identity provider with http issuer",
+			modify: func(c *types.InstallConfig) {
+				c.IdentityProviders = []types.IdentityProvider{{
+					Name: "sso",
+					OpenID: &types.OpenIDIdentityProvider{
+						ClientID:     "openshift",
+						ClientSecret: "secret",
+						Issuer:       "http://sso.example.com",
+					},
+				}}
+			},
+			expectedError: `^identityProviders\[0\]\.openID\.issuer: Invalid value: "http://sso\.example\.com": must be an https URL$`,
+		},
+		{
+			name: "openid identity provider with a CA config map",
+			modify: func(c *types.InstallConfig) {
+				c.ConfigMaps = []types.ConfigMap{{
+					Name: "sso-ca",
+					Data: map[string]string{"ca.crt": ingressCertificate("sso.example.com").Certificate},
+				}}
+				c.IdentityProviders = []types.IdentityProvider{{
+					Name: "sso",
+					OpenID: &types.OpenIDIdentityProvider{
+						ClientID:     "openshift",
+						ClientSecret: "secret",
+						Issuer:       "https://sso.example.com",
+						CAConfigMap:  "sso-ca",
+					},
+				}}
+			},
+		},
+		{
+			name: "openid identity provider with a missing CA config map",
+			modify: func(c *types.InstallConfig) {
+				c.IdentityProviders = []types.IdentityProvider{{
+					Name: "sso",
+					OpenID: &types.OpenIDIdentityProvider{
+						ClientID:     "openshift",
+						ClientSecret: "secret",
+						Issuer:       "https://sso.example.com",
+						CAConfigMap:  "sso-ca",
+					},
+				}}
+			},
+			expectedError: `^identityProviders\[0\]\.openID\.caConfigMap: Not found: "sso-ca"$`,
+		},
+		{
+			name: "ldap identity provider with a CA config map without ca.crt",
+			modify: func(c *types.InstallConfig) {
+				c.ConfigMaps = []types.ConfigMap{{Name: "ldap-ca", Data: map[string]string{"ca.pem": "x"}}}
+				c.IdentityProviders = []types.IdentityProvider{{
+					Name: "corp-ldap",
+					LDAP: &types.LDAPIdentityProvider{
+						URL:         "ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid",
+						CAConfigMap: "ldap-ca",
+						Attributes:  types.LDAPAttributes{ID: []string{"dn"}},
+					},
+				}}
+			},
+			expectedError: `^identityProviders\[0\]\.ldap\.caConfigMap: Invalid value: "ldap-ca": config map has no ca\.crt key$`,
+		},
+		{
+			name: "ldap identity provider with both a CA and a CA config map",
+			modify: func(c *types.InstallConfig) {
+				c.ConfigMaps = []types.ConfigMap{{Name: "ldap-ca", Data: map[string]string{"ca.crt": "x"}}}
+				c.IdentityProviders = []types.IdentityProvider{{
+					Name: "corp-ldap",
+					LDAP: &types.LDAPIdentityProvider{
+						URL:         "ldaps://ldap.example.com/ou=users,dc=example,dc=com?uid",
+						CA:          ingressCertificate("ldap.example.com").Certificate,
+						CAConfigMap: "ldap-ca",
+						Attributes:  types.LDAPAttributes{ID: []string{"dn"}},
+					},
+				}}
+			},
+			expectedError: `^identityProviders\[0\]\.ldap\.caConfigMap: Forbidden: may not be set with ca$`,
+		},
+	})
+}
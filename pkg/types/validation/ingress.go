@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/validate"
+)
+
+// validateIngressCertificate checks that the certificate is valid for every
+// host under domain, and that its key file is given. The key is only read,
+// and matched with the certificate, when the manifests are generated.
+func validateIngressCertificate(c *types.IngressCertificate, domain string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if c.KeyFile == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("keyFile"), "the path of the certificate's private key is required"))
+	}
+	block, _ := pem.Decode([]byte(c.Certificate))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return append(allErrs, field.Invalid(fldPath.Child("certificate"), "", "must be a PEM-encoded certificate"))
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath.Child("certificate"), "", err.Error()))
+	}
+	// A wildcard certificate for the domain covers any single label under
+	// it.
+	if err := cert.VerifyHostname("wildcard-check." + domain); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("certificate"), cert.DNSNames, fmt.Sprintf("must be valid for *.%s", domain)))
+	}
+	return allErrs
+}
+
+func validateIngressControllers(controllers []types.IngressController, defaultDomain string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	domains := map[string]bool{defaultDomain: true}
+	for i, controller := range controllers {
+		controllerPath := fldPath.Index(i)
+		switch {
+		case controller.Name == "default":
+			allErrs = append(allErrs, field.Invalid(controllerPath.Child("name"), controller.Name, "the default ingress controller is always created"))
+		case names[controller.Name]:
+			allErrs = append(allErrs, field.Duplicate(controllerPath.Child("name"), controller.Name))
+		default:
+			for _, msg := range validation.IsDNS1123Label(controller.Name) {
+				allErrs = append(allErrs, field.Invalid(controllerPath.Child("name"), controller.Name, msg))
+			}
+		}
+		names[controller.Name] = true
+
+		if err := validate.DomainName(controller.Domain); err != nil {
+			allErrs = append(allErrs, field.Invalid(controllerPath.Child("domain"), controller.Domain, err.Error()))
+		} else if domains[controller.Domain] {
+			allErrs = append(allErrs, field.Duplicate(controllerPath.Child("domain"), controller.Domain))
+		}
+		domains[controller.Domain] = true
+
+		if controller.RouteSelector != nil {
+			if _, err := metav1.LabelSelectorAsSelector(controller.RouteSelector); err != nil {
+				allErrs = append(allErrs, field.Invalid(controllerPath.Child("routeSelector"), controller.RouteSelector, err.Error()))
+			}
+		}
+		if controller.Replicas != nil && *controller.Replicas < 0 {
+			allErrs = append(allErrs, field.Invalid(controllerPath.Child("replicas"), *controller.Replicas, "must not be negative"))
+		}
+		switch controller.EndpointPublishingStrategy {
+		case "", types.LoadBalancerServiceStrategy, types.HostNetworkStrategy, types.PrivateStrategy:
+		default:
+			allErrs = append(allErrs, field.NotSupported(controllerPath.Child("endpointPublishingStrategy"), controller.EndpointPublishingStrategy, []string{
+				string(types.LoadBalancerServiceStrategy),
+				string(types.HostNetworkStrategy),
+				string(types.PrivateStrategy),
+			}))
+		}
+	}
+	return allErrs
+}
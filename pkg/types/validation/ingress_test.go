@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestValidateIngress(t *testing.T) {
+	testInstallConfigCases(t, []installConfigCase{
+		{
+			name: "ingress default certificate",
+			modify: func(c *types.InstallConfig) {
+				c.ObjectMeta.Name = "test"
+				c.BaseDomain = "example.com"
+				c.Ingress = &types.Ingress{DefaultCertificate: ingressCertificate("*.apps.test.example.com")}
+			},
+		},
+		{
+			name: "ingress default certificate for another domain",
+			modify: func(c *types.InstallConfig) {
+				c.ObjectMeta.Name = "test"
+				c.BaseDomain = "example.com"
+				c.Ingress = &types.Ingress{DefaultCertificate: ingressCertificate("*.apps.other.example.com")}
+			},
+			expectedError: `^ingress\.defaultCertificate\.certificate: Invalid value: \[\]string{"\*\.apps\.other\.example\.com"}: must be valid for \*\.apps\.test\.example\.com$`,
+		},
+		{
+			name: "ingress default certificate without key file",
+			modify: func(c *types.InstallConfig) {
+				c.ObjectMeta.Name = "test"
+				c.BaseDomain = "example.com"
+				c.Ingress = &types.Ingress{DefaultCertificate: ingressCertificate("*.apps.test.example.com")}
+				c.Ingress.DefaultCertificate.KeyFile = ""
+			},
+			expectedError: `^ingress\.defaultCertificate\.keyFile: Required value: the path of the certificate's private key is required$`,
+		},
+		{
+			name: "ingress default certificate which is not PEM-encoded",
+			modify: func(c *types.InstallConfig) {
+				c.Ingress = &types.Ingress{DefaultCertificate: &types.IngressCertificate{Certificate: "certificate", KeyFile: "/etc/pki/tls/private/apps.key"}}
+			},
+			expectedError: `^ingress\.defaultCertificate\.certificate: Invalid value: "": must be a PEM-encoded certificate$`,
+		},
+		{
+			name: "ingress controllers",
+			modify: func(c *types.InstallConfig) {
+				replicas := int32(2)
+				c.Ingress = &types.Ingress{Controllers: []types.IngressController{{
+					Name:                       "internal",
+					Domain:                     "internal.example.com",
+					RouteSelector:              &metav1.LabelSelector{MatchLabels: map[string]string{"shard": "internal"}},
+					Replicas:                   &replicas,
+					EndpointPublishingStrategy: types.PrivateStrategy,
+				}}}
+			},
+		},
+		{
+			name: "ingress controller for the apps domain",
+			modify: func(c *types.InstallConfig) {
+				c.ObjectMeta.Name = "test"
+				c.BaseDomain = "example.com"
+				c.Ingress = &types.Ingress{Controllers: []types.IngressController{{Name: "public", Domain: "apps.test.example.com"}}}
+			},
+			expectedError: `^ingress\.controllers\[0\]\.domain: Duplicate value: "apps\.test\.example\.com"$`,
+		},
+		{
+			name: "ingress controller named default",
+			modify: func(c *types.InstallConfig) {
+				c.Ingress = &types.Ingress{Controllers: []types.IngressController{{Name: "default", Domain: "internal.example.com"}}}
+			},
+			expectedError: `^ingress\.controllers\[0\]\.name: Invalid value: "default": the default ingress controller is always created$`,
+		},
+		{
+			name: "unsupported ingress controller publishing strategy",
+			modify: func(c *types.InstallConfig) {
+				c.Ingress = &types.Ingress{Controllers: []types.IngressController{{Name: "internal", Domain: "internal.example.com", EndpointPublishingStrategy: "NodePort"}}}
+			},
+			expectedError: `^ingress\.controllers\[0\]\.endpointPublishingStrategy: Unsupported value: "NodePort": supported values: "LoadBalancerService", "HostNetwork", "Private"$`,
+		},
+	})
+}
@@ -0,0 +1,295 @@
+// Package validation contains validation of the installer configuration
+// types.
+package validation
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/validate"
+)
+
+var (
+	// digestPullSpecPattern matches image pull specs pinned to a sha256
+	// digest, e.g. quay.io/openshift/origin-release@sha256:<64 hex digits>.
+	digestPullSpecPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.:-]*(/[a-z0-9]+([._-]+[a-z0-9]+)*)+@sha256:[a-f0-9]{64}$`)
+
+	// channelPattern matches update channel names such as stable-4.1.
+	channelPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?$`)
+)
+
+// ValidateInstallConfig checks that the specified install config is valid.
+func ValidateInstallConfig(c *types.InstallConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+	allErrs = append(allErrs, validateClusterName(c, field.NewPath("metadata", "name"))...)
+	allErrs = append(allErrs, validateSSHKeys(c.SSHKey, field.NewPath("sshKey"))...)
+	if c.InfraID != "" {
+		allErrs = append(allErrs, validateInfraID(c.InfraID, c.ObjectMeta.Name, &c.Platform, field.NewPath("infraID"))...)
+	}
+	if c.MachineConfigServer != nil {
+		allErrs = append(allErrs, validateMachineConfigServer(c.MachineConfigServer, &c.Platform, field.NewPath("machineConfigServer"))...)
+	}
+	if c.ReleaseImage != "" && !digestPullSpecPattern.MatchString(c.ReleaseImage) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("releaseImage"), c.ReleaseImage, "must be a pull spec pinned to a sha256 digest (e.g. quay.io/openshift-release-dev/ocp-release@sha256:...)"))
+	}
+	allErrs = append(allErrs, validateNetworking(c, field.NewPath("networking"))...)
+	allErrs = append(allErrs, validateFeatureGates(c.FeatureSet, c.FeatureGates, field.NewPath("featureSet"), field.NewPath("featureGates"))...)
+	if c.APIServer != nil {
+		allErrs = append(allErrs, validateAudit(&c.APIServer.Audit, field.NewPath("apiServer", "audit"))...)
+		allErrs = append(allErrs, validateNamedCertificates(c.APIServer.NamedCertificates, c.ConfigSecrets, field.NewPath("apiServer", "namedCertificates"))...)
+	}
+	if c.Scheduler != nil {
+		allErrs = append(allErrs, validateScheduler(c.Scheduler, field.NewPath("scheduler"))...)
+	}
+	if c.Project != nil {
+		allErrs = append(allErrs, validateProject(c.Project, field.NewPath("project"))...)
+	}
+	allErrs = append(allErrs, validateIdentityProviders(c.IdentityProviders, c.ConfigMaps, field.NewPath("identityProviders"))...)
+	allErrs = append(allErrs, validateConfigSecrets(c.ConfigSecrets, field.NewPath("configSecrets"))...)
+	allErrs = append(allErrs, validateConfigMaps(c.ConfigMaps, field.NewPath("configMaps"))...)
+	if c.ImageRegistry != nil {
+		allErrs = append(allErrs, validateImageRegistry(c.ImageRegistry, &c.Platform, field.NewPath("imageRegistry"))...)
+	}
+	if c.Proxy != nil {
+		allErrs = append(allErrs, validateProxy(c.Proxy, field.NewPath("proxy"))...)
+	}
+	switch c.Telemetry {
+	case "", types.TelemetryEnabled, types.TelemetryDisabled:
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("telemetry"), c.Telemetry, []string{string(types.TelemetryEnabled), string(types.TelemetryDisabled)}))
+	}
+	if c.Monitoring != nil {
+		allErrs = append(allErrs, validateMonitoring(c.Monitoring, field.NewPath("monitoring"))...)
+	}
+	if c.Ingress != nil {
+		domain := fmt.Sprintf("apps.%s.%s", c.ObjectMeta.Name, c.BaseDomain)
+		if c.Ingress.DefaultCertificate != nil {
+			allErrs = append(allErrs, validateIngressCertificate(c.Ingress.DefaultCertificate, domain, field.NewPath("ingress", "defaultCertificate"))...)
+		}
+		allErrs = append(allErrs, validateIngressControllers(c.Ingress.Controllers, domain, field.NewPath("ingress", "controllers"))...)
+	}
+	allErrs = append(allErrs, validateManifestHooks(c.ManifestHooks, field.NewPath("manifestHooks"))...)
+	allErrs = append(allErrs, validateMachinePools(c, field.NewPath("machines"))...)
+	allErrs = append(allErrs, validateCredentialsMode(c.CredentialsMode, &c.Platform, field.NewPath("credentialsMode"))...)
+	if c.Channel != "" && !channelPattern.MatchString(c.Channel) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("channel"), c.Channel, "must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character"))
+	}
+	if c.Upstream != "" {
+		allErrs = append(allErrs, validateUpstream(c.Upstream, field.NewPath("upstream"))...)
+	}
+	allErrs = append(allErrs, validateCVOOverrides(c.CVOOverrides, field.NewPath("cvoOverrides"))...)
+	if c.Platform.AWS != nil {
+		allErrs = append(allErrs, validateAWSPlatform(c.Platform.AWS, c.Machines, c.CredentialsMode, field.NewPath("platform", "aws"))...)
+		if c.Platform.AWS.Bastion != nil {
+			allErrs = append(allErrs, validateBastion(c.Platform.AWS.Bastion, c.SSHKey, field.NewPath("platform", "aws", "bastion"))...)
+		}
+	}
+	if c.Platform.Libvirt != nil {
+		allErrs = append(allErrs, validateLibvirtPlatform(c.Platform.Libvirt, field.NewPath("platform", "libvirt"))...)
+	}
+	if c.Platform.OpenStack != nil {
+		allErrs = append(allErrs, validateOpenStackPlatform(c.Platform.OpenStack, c.Machines, field.NewPath("platform", "openstack"))...)
+	}
+	return allErrs
+}
+
+// maxDNSNameLength is the longest DNS name, and maxDNSLabelLength the
+// longest label within one.
+const (
+	maxDNSNameLength  = 253
+	maxDNSLabelLength = 63
+)
+
+// validateClusterName checks that the cluster name fits in the DNS names
+// derived from it and in the names of the platform's resources.  Its
+// segments between dots may not be longer than the <name>-etcd-<index>
+// labels allow, nor may *.apps.<name>.<baseDomain> be longer than a DNS
+// name.  On AWS, the name prefixes the names of load balancers unless an
+// infraID is set, and elsewhere it prefixes host names, so it must then be
+// a single label.
+func validateClusterName(c *types.InstallConfig, fldPath *field.Path) field.ErrorList {
+	name := c.ObjectMeta.Name
+	if err := validate.ClusterName(name); err != nil {
+		return field.ErrorList{field.Invalid(fldPath, name, err.Error())}
+	}
+
+	allErrs := field.ErrorList{}
+	segments := strings.Split(name, ".")
+	etcdSuffix := fmt.Sprintf("-etcd-%d", c.MasterCount()-1)
+	if last := segments[len(segments)-1]; len(last)+len(etcdSuffix) > maxDNSLabelLength {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, fmt.Sprintf("the part after the last dot must be no more than %d characters, so the etcd DNS labels (%s%s) fit in %d", maxDNSLabelLength-len(etcdSuffix), last, etcdSuffix, maxDNSLabelLength)))
+	}
+	if c.BaseDomain != "" {
+		if wildcard := fmt.Sprintf("*.apps.%s.%s", name, c.BaseDomain); len(wildcard) > maxDNSNameLength {
+			allErrs = append(allErrs, field.Invalid(fldPath, name, fmt.Sprintf("must be no more than %d characters with base domain %s, so the ingress wildcard (*.apps.<name>.<baseDomain>) fits in %d", maxDNSNameLength-len(wildcard)+len(name), c.BaseDomain, maxDNSNameLength)))
+		}
+	}
+
+	switch {
+	case c.Platform.AWS != nil && c.InfraID != "":
+	case c.Platform.AWS != nil:
+		if len(segments) > 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath, name, "must not contain dots on AWS, where it prefixes the load balancer names; set infraID to use a dotted name"))
+		} else if len(name) > maxAWSInfraIDLength {
+			allErrs = append(allErrs, field.Invalid(fldPath, name, fmt.Sprintf("must be no more than %d characters on AWS, where it prefixes the load balancer names; set infraID to use a longer name", maxAWSInfraIDLength)))
+		}
+	case c.Platform.Libvirt != nil, c.Platform.OpenStack != nil:
+		if len(segments) > 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath, name, fmt.Sprintf("must not contain dots on %s, where it prefixes the host names", c.Platform.Name())))
+		}
+	}
+	return allErrs
+}
+
+// maxAWSInfraIDLength keeps the names of the AWS load balancer target
+// groups, the longest of which is <infraID>-services, within 32 characters.
+const maxAWSInfraIDLength = 23
+
+// validateInfraID checks that the infra ID can prefix the names of the
+// cluster's resources.  Only the AWS resources are named after it.
+func validateInfraID(infraID, clusterName string, platform *types.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, msg := range validation.IsDNS1123Label(infraID) {
+		allErrs = append(allErrs, field.Invalid(fldPath, infraID, msg))
+	}
+	switch {
+	case platform.AWS != nil:
+		if len(infraID) > maxAWSInfraIDLength {
+			allErrs = append(allErrs, field.Invalid(fldPath, infraID, fmt.Sprintf("must be no more than %d characters on AWS", maxAWSInfraIDLength)))
+		}
+	case infraID != clusterName:
+		allErrs = append(allErrs, field.Forbidden(fldPath, "custom infra IDs are only supported on AWS"))
+	}
+	return allErrs
+}
+
+func validateMachineConfigServer(m *types.MachineConfigServer, platform *types.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch {
+	case m.Port < 0 || m.Port > 65535:
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("port"), m.Port, "must be between 1 and 65535"))
+	case m.Port != 0 && m.Port != types.DefaultMachineConfigServerPort && platform.Libvirt != nil:
+		// libvirt machines talk to the masters directly, without a load
+		// balancer that could map a different port.
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("port"), m.Port, "custom ports are not supported on libvirt"))
+	case m.Port != 0 && m.Port != types.DefaultMachineConfigServerPort && platform.OpenStack != nil:
+		// Nor do OpenStack machines, whose security groups only open the
+		// default port on the masters.
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("port"), m.Port, "custom ports are not supported on OpenStack"))
+	}
+	allErrs = append(allErrs, validateCABundle(m.CertificateAuthority, fldPath.Child("certificateAuthority"))...)
+	return allErrs
+}
+
+func validateFeatureGates(featureSet types.FeatureSet, gates []string, setPath, gatesPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch featureSet {
+	case types.DefaultFeatureSet, types.TechPreviewNoUpgrade:
+		if len(gates) > 0 {
+			allErrs = append(allErrs, field.Forbidden(gatesPath, fmt.Sprintf("may only be set when featureSet is %s", types.CustomNoUpgrade)))
+		}
+	case types.CustomNoUpgrade:
+		for i, gate := range gates {
+			if _, _, err := types.ParseFeatureGates([]string{gate}); err != nil {
+				allErrs = append(allErrs, field.Invalid(gatesPath.Index(i), gate, "must be Name=true or Name=false"))
+			}
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(setPath, featureSet, []string{string(types.TechPreviewNoUpgrade), string(types.CustomNoUpgrade)}))
+	}
+	return allErrs
+}
+
+func validateManifestHooks(hooks []types.ManifestHook, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := map[string]bool{}
+	for i, h := range hooks {
+		hookPath := fldPath.Index(i)
+		for _, msg := range validation.IsDNS1123Label(h.Name) {
+			allErrs = append(allErrs, field.Invalid(hookPath.Child("name"), h.Name, msg))
+		}
+		if names[h.Name] {
+			allErrs = append(allErrs, field.Duplicate(hookPath.Child("name"), h.Name))
+		}
+		names[h.Name] = true
+		if !filepath.IsAbs(h.Command) {
+			allErrs = append(allErrs, field.Invalid(hookPath.Child("command"), h.Command, "must be an absolute path"))
+		}
+	}
+	return allErrs
+}
+
+func validateCABundle(ca string, fldPath *field.Path) field.ErrorList {
+	if ca != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(ca)) {
+		return field.ErrorList{field.Invalid(fldPath, ca, "must contain at least one PEM-encoded certificate")}
+	}
+	return nil
+}
+
+func validateCredentialsMode(mode types.CredentialsMode, platform *types.Platform, fldPath *field.Path) field.ErrorList {
+	switch mode {
+	case "":
+		return nil
+	case types.MintCredentialsMode, types.PassthroughCredentialsMode, types.ManualCredentialsMode:
+		if platform.Libvirt != nil {
+			return field.ErrorList{field.Invalid(fldPath, mode, "cloud credentials are not used on libvirt")}
+		}
+		return nil
+	default:
+		return field.ErrorList{field.NotSupported(fldPath, mode, []string{
+			string(types.MintCredentialsMode),
+			string(types.PassthroughCredentialsMode),
+			string(types.ManualCredentialsMode),
+		})}
+	}
+}
+
+func validateUpstream(upstream string, fldPath *field.Path) field.ErrorList {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return field.ErrorList{field.Invalid(fldPath, upstream, err.Error())}
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return field.ErrorList{field.Invalid(fldPath, upstream, "must be an absolute http or https URL")}
+	}
+	return nil
+}
+
+func validateCVOOverrides(overrides []configv1.ComponentOverride, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := map[configv1.ComponentOverride]bool{}
+	for i, o := range overrides {
+		if o.Kind == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("kind"), "kind of the overridden object is required"))
+		}
+		if o.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("name"), "name of the overridden object is required"))
+		}
+		key := o
+		key.Unmanaged = false
+		if seen[key] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i), o))
+		}
+		seen[key] = true
+	}
+	return allErrs
+}
+
+func validateSSHKeys(keys types.SSHKeys, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, key := range keys {
+		if err := validate.SSHAuthorizedKey(key); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), key, err.Error()))
+		}
+	}
+	return allErrs
+}
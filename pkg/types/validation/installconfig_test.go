@@ -0,0 +1,388 @@
+package validation
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/libvirt"
+	"github.com/openshift/installer/pkg/types/openstack"
+)
+
+const (
+	validSSHKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAQa4Iv4kujekvpBNjtL6RaiTHaOyPN0kjDN0mEgRQeX you@example.com"
+)
+
+func validInstallConfig() *types.InstallConfig {
+	return &types.InstallConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		SSHKey:     types.SSHKeys{validSSHKey},
+	}
+}
+
+// certificateKeyPair returns a PEM-encoded self-signed certificate for
+// dnsName and its key.
+func certificateKeyPair(dnsName string) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		panic(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		panic(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+// ingressCertificate returns a self-signed certificate for dnsName.
+func ingressCertificate(dnsName string) *types.IngressCertificate {
+	cert, _ := certificateKeyPair(dnsName)
+	return &types.IngressCertificate{
+		Certificate: cert,
+		KeyFile:     "/etc/pki/tls/private/apps.key",
+	}
+}
+
+func ipNet(cidr string) ipnet.IPNet {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return ipnet.IPNet{IPNet: *n}
+}
+
+func machineNetwork(cidrs ...string) []types.MachineNetworkEntry {
+	entries := make([]types.MachineNetworkEntry, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		entries = append(entries, types.MachineNetworkEntry{CIDR: ipNet(cidr)})
+	}
+	return entries
+}
+
+// installConfigCase is a change to validInstallConfig and the error
+// expected from validating the result.
+type installConfigCase struct {
+	name          string
+	modify        func(c *types.InstallConfig)
+	expectedError string
+}
+
+// testInstallConfigCases validates the install config of each case.
+func testInstallConfigCases(t *testing.T, cases []installConfigCase) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := validInstallConfig()
+			if tc.modify != nil {
+				tc.modify(c)
+			}
+			err := ValidateInstallConfig(c).ToAggregate()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidateInstallConfig(t *testing.T) {
+	testInstallConfigCases(t, []installConfigCase{
+		{
+			name: "valid",
+		},
+		{
+			name:   "no ssh keys",
+			modify: func(c *types.InstallConfig) { c.SSHKey = nil },
+		},
+		{
+			name:   "multiple ssh keys",
+			modify: func(c *types.InstallConfig) { c.SSHKey = append(c.SSHKey, validSSHKey) },
+		},
+		{
+			name:          "invalid ssh key",
+			modify:        func(c *types.InstallConfig) { c.SSHKey = append(c.SSHKey, "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDxL") },
+			expectedError: `^sshKey\[1\]: Invalid value: "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAACAQDxL": invalid SSH public key \(key is not valid base64\)$`,
+		},
+		{
+			name: "aws infra id",
+			modify: func(c *types.InstallConfig) {
+				c.InfraID = "prod-east-7f3k2"
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+			},
+		},
+		{
+			name: "invalid infra id",
+			modify: func(c *types.InstallConfig) {
+				c.InfraID = "Prod_East"
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+			},
+			expectedError: `^infraID: Invalid value: "Prod_East": a DNS-1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character \(e\.g\. 'my-name',  or '123-abc', regex used for validation is '\[a-z0-9\]\(\[-a-z0-9\]\*\[a-z0-9\]\)\?'\)$`,
+		},
+		{
+			name: "too long aws infra id",
+			modify: func(c *types.InstallConfig) {
+				c.InfraID = "production-us-east-1-cluster"
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+			},
+			expectedError: `^infraID: Invalid value: "production-us-east-1-cluster": must be no more than 23 characters on AWS$`,
+		},
+		{
+			name:          "custom infra id off aws",
+			modify:        func(c *types.InstallConfig) { c.InfraID = "prod-7f3k2" },
+			expectedError: `^infraID: Forbidden: custom infra IDs are only supported on AWS$`,
+		},
+		{
+			name:          "missing cluster name",
+			modify:        func(c *types.InstallConfig) { c.ObjectMeta.Name = "" },
+			expectedError: `^metadata\.name: Invalid value: "": cannot be empty$`,
+		},
+		{
+			name:          "cluster name too long for the etcd dns labels",
+			modify:        func(c *types.InstallConfig) { c.ObjectMeta.Name = strings.Repeat("a", 58) },
+			expectedError: `^metadata\.name: Invalid value: "a{58}": the part after the last dot must be no more than 56 characters, so the etcd DNS labels \(a{58}-etcd-0\) fit in 63$`,
+		},
+		{
+			name: "cluster name too long for the ingress wildcard",
+			modify: func(c *types.InstallConfig) {
+				c.ObjectMeta.Name = strings.Repeat("a", 50) + "." + strings.Repeat("b", 50)
+				c.BaseDomain = strings.Repeat("c", 50) + "." + strings.Repeat("d", 50) + "." + strings.Repeat("e", 50) + ".example.com"
+			},
+			expectedError: `^metadata\.name: Invalid value: "a{50}\.b{50}": must be no more than 81 characters with base domain c{50}\.d{50}\.e{50}\.example\.com, so the ingress wildcard \(\*\.apps\.<name>\.<baseDomain>\) fits in 253$`,
+		},
+		{
+			name:   "dotted cluster name",
+			modify: func(c *types.InstallConfig) { c.ObjectMeta.Name = "prod.east" },
+		},
+		{
+			name: "dotted cluster name on aws",
+			modify: func(c *types.InstallConfig) {
+				c.ObjectMeta.Name = "prod.east"
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+			},
+			expectedError: `^metadata\.name: Invalid value: "prod\.east": must not contain dots on AWS, where it prefixes the load balancer names; set infraID to use a dotted name$`,
+		},
+		{
+			name: "long cluster name on aws",
+			modify: func(c *types.InstallConfig) {
+				c.ObjectMeta.Name = "production-us-east-1-cluster"
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+			},
+			expectedError: `^metadata\.name: Invalid value: "production-us-east-1-cluster": must be no more than 23 characters on AWS, where it prefixes the load balancer names; set infraID to use a longer name$`,
+		},
+		{
+			name: "long dotted cluster name on aws with an infra id",
+			modify: func(c *types.InstallConfig) {
+				c.ObjectMeta.Name = "production.us-east-1-cluster"
+				c.InfraID = "prod-east"
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+			},
+		},
+		{
+			name:   "machine config server port",
+			modify: func(c *types.InstallConfig) { c.MachineConfigServer = &types.MachineConfigServer{Port: 443} },
+		},
+		{
+			name:          "invalid machine config server port",
+			modify:        func(c *types.InstallConfig) { c.MachineConfigServer = &types.MachineConfigServer{Port: 70000} },
+			expectedError: `^machineConfigServer\.port: Invalid value: 70000: must be between 1 and 65535$`,
+		},
+		{
+			name: "machine config server port on libvirt",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.Libvirt = &libvirt.Platform{}
+				c.MachineConfigServer = &types.MachineConfigServer{Port: 443}
+			},
+			expectedError: `^machineConfigServer\.port: Invalid value: 443: custom ports are not supported on libvirt$`,
+		},
+		{
+			name: "machine config server port on openstack",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.OpenStack = &openstack.Platform{}
+				c.MachineConfigServer = &types.MachineConfigServer{Port: 443}
+			},
+			expectedError: `^machineConfigServer\.port: Invalid value: 443: custom ports are not supported on OpenStack$`,
+		},
+		{
+			name: "invalid machine config server certificate authority",
+			modify: func(c *types.InstallConfig) {
+				c.MachineConfigServer = &types.MachineConfigServer{CertificateAuthority: "not a certificate"}
+			},
+			expectedError: `^machineConfigServer\.certificateAuthority: Invalid value: "not a certificate": must contain at least one PEM-encoded certificate$`,
+		},
+		{
+			name: "cvo overrides",
+			modify: func(c *types.InstallConfig) {
+				c.CVOOverrides = []configv1.ComponentOverride{
+					{Kind: "Deployment", Group: "apps", Namespace: "openshift-cluster-samples-operator", Name: "cluster-samples-operator", Unmanaged: true},
+					{Kind: "Deployment", Group: "apps", Namespace: "openshift-marketplace", Name: "marketplace-operator", Unmanaged: true},
+				}
+			},
+		},
+		{
+			name: "cvo override without name",
+			modify: func(c *types.InstallConfig) {
+				c.CVOOverrides = []configv1.ComponentOverride{{Kind: "Deployment", Unmanaged: true}}
+			},
+			expectedError: `^cvoOverrides\[0\]\.name: Required value: name of the overridden object is required$`,
+		},
+		{
+			name: "duplicate cvo overrides",
+			modify: func(c *types.InstallConfig) {
+				c.CVOOverrides = []configv1.ComponentOverride{
+					{Kind: "Deployment", Group: "apps", Namespace: "openshift-marketplace", Name: "marketplace-operator", Unmanaged: true},
+					{Kind: "Deployment", Group: "apps", Namespace: "openshift-marketplace", Name: "marketplace-operator"},
+				}
+			},
+			expectedError: `^cvoOverrides\[1\]: Duplicate value: `,
+		},
+		{
+			name: "update channel and upstream",
+			modify: func(c *types.InstallConfig) {
+				c.Channel = "stable-4.1"
+				c.Upstream = "https://cincinnati.example.com/api/upgrades_info/v1/graph"
+			},
+		},
+		{
+			name:          "invalid channel",
+			modify:        func(c *types.InstallConfig) { c.Channel = "stable\nfoo: bar" },
+			expectedError: `^channel: Invalid value: "stable\\nfoo: bar": must consist of lower case alphanumeric characters, '-' or '\.', and must start and end with an alphanumeric character$`,
+		},
+		{
+			name:          "relative upstream",
+			modify:        func(c *types.InstallConfig) { c.Upstream = "cincinnati.example.com/graph" },
+			expectedError: `^upstream: Invalid value: "cincinnati\.example\.com/graph": must be an absolute http or https URL$`,
+		},
+		{
+			name: "digest-pinned release image",
+			modify: func(c *types.InstallConfig) {
+				c.ReleaseImage = "quay.io/openshift-release-dev/ocp-release@sha256:" + strings.Repeat("a", 64)
+			},
+		},
+		{
+			name: "release image with registry port",
+			modify: func(c *types.InstallConfig) {
+				c.ReleaseImage = "registry.example.com:5000/ocp/release@sha256:" + strings.Repeat("0", 64)
+			},
+		},
+		{
+			name: "tagged release image",
+			modify: func(c *types.InstallConfig) {
+				c.ReleaseImage = "registry.svc.ci.openshift.org/openshift/origin-release:v4.0"
+			},
+			expectedError: `^releaseImage: Invalid value: "registry\.svc\.ci\.openshift\.org/openshift/origin-release:v4\.0": must be a pull spec pinned to a sha256 digest`,
+		},
+		{
+			name:   "tech preview feature set",
+			modify: func(c *types.InstallConfig) { c.FeatureSet = types.TechPreviewNoUpgrade },
+		},
+		{
+			name: "custom feature gates",
+			modify: func(c *types.InstallConfig) {
+				c.FeatureSet = types.CustomNoUpgrade
+				c.FeatureGates = []string{"ExperimentalCriticalPodAnnotation=true", "RotateKubeletServerCertificate=false"}
+			},
+		},
+		{
+			name:          "feature gates without custom feature set",
+			modify:        func(c *types.InstallConfig) { c.FeatureGates = []string{"ExperimentalCriticalPodAnnotation=true"} },
+			expectedError: `^featureGates: Forbidden: may only be set when featureSet is CustomNoUpgrade$`,
+		},
+		{
+			name: "invalid feature gate",
+			modify: func(c *types.InstallConfig) {
+				c.FeatureSet = types.CustomNoUpgrade
+				c.FeatureGates = []string{"ExperimentalCriticalPodAnnotation"}
+			},
+			expectedError: `^featureGates\[0\]: Invalid value: "ExperimentalCriticalPodAnnotation": must be Name=true or Name=false$`,
+		},
+		{
+			name:          "unknown feature set",
+			modify:        func(c *types.InstallConfig) { c.FeatureSet = "AllTheThings" },
+			expectedError: `^featureSet: Unsupported value: "AllTheThings": supported values: "TechPreviewNoUpgrade", "CustomNoUpgrade"$`,
+		},
+		{
+			name: "valid manifest hooks",
+			modify: func(c *types.InstallConfig) {
+				c.ManifestHooks = []types.ManifestHook{
+					{Name: "monitoring", Command: "/usr/libexec/monitoring-manifests"},
+					{Name: "logging", Command: "/usr/libexec/logging-manifests", Args: []string{"--stable"}},
+				}
+			},
+		},
+		{
+			name: "invalid manifest hook name",
+			modify: func(c *types.InstallConfig) {
+				c.ManifestHooks = []types.ManifestHook{{Name: "Monitoring.Hook", Command: "/usr/libexec/monitoring-manifests"}}
+			},
+			expectedError: `^manifestHooks\[0\]\.name: Invalid value: "Monitoring.Hook": .*$`,
+		},
+		{
+			name: "duplicate manifest hooks",
+			modify: func(c *types.InstallConfig) {
+				c.ManifestHooks = []types.ManifestHook{
+					{Name: "monitoring", Command: "/usr/libexec/monitoring-manifests"},
+					{Name: "monitoring", Command: "/usr/libexec/logging-manifests"},
+				}
+			},
+			expectedError: `^manifestHooks\[1\]\.name: Duplicate value: "monitoring"$`,
+		},
+		{
+			name: "relative manifest hook command",
+			modify: func(c *types.InstallConfig) {
+				c.ManifestHooks = []types.ManifestHook{{Name: "monitoring", Command: "monitoring-manifests"}}
+			},
+			expectedError: `^manifestHooks\[0\]\.command: Invalid value: "monitoring-manifests": must be an absolute path$`,
+		},
+		{
+			name:   "telemetry disabled",
+			modify: func(c *types.InstallConfig) { c.Telemetry = types.TelemetryDisabled },
+		},
+		{
+			name:          "unsupported telemetry",
+			modify:        func(c *types.InstallConfig) { c.Telemetry = "off" },
+			expectedError: `^telemetry: Unsupported value: "off": supported values: "enabled", "disabled"$`,
+		},
+		{
+			name: "manual credentials mode",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+				c.CredentialsMode = types.ManualCredentialsMode
+			},
+		},
+		{
+			name: "credentials mode on libvirt",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.Libvirt = &libvirt.Platform{}
+				c.CredentialsMode = types.PassthroughCredentialsMode
+			},
+			expectedError: `^credentialsMode: Invalid value: "Passthrough": cloud credentials are not used on libvirt$`,
+		},
+		{
+			name:          "unknown credentials mode",
+			modify:        func(c *types.InstallConfig) { c.CredentialsMode = "Borrow" },
+			expectedError: `^credentialsMode: Unsupported value: "Borrow": supported values: "Mint", "Passthrough", "Manual"$`,
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types/libvirt"
+)
+
+// minBootstrapMemoryMiB is the least memory the bootstrap machine can
+// run the temporary control plane with.
+const minBootstrapMemoryMiB = 2048
+
+func validateLibvirtPlatform(p *libvirt.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p.BootstrapMemoryMiB != 0 && p.BootstrapMemoryMiB < minBootstrapMemoryMiB {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("bootstrapMemoryMiB"), p.BootstrapMemoryMiB, fmt.Sprintf("must be at least %d", minBootstrapMemoryMiB)))
+	}
+	if p.SSH != nil {
+		allErrs = append(allErrs, validateLibvirtSSH(p.URI, p.SSH, fldPath)...)
+	}
+	return allErrs
+}
+
+func validateLibvirtSSH(uri string, ssh *libvirt.SSH, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return append(allErrs, field.Invalid(fldPath.Child("URI"), uri, err.Error()))
+	}
+	transport := ""
+	if i := strings.Index(u.Scheme, "+"); i >= 0 {
+		transport = u.Scheme[i+1:]
+	}
+	switch transport {
+	case "ssh", "libssh2", "libssh":
+	default:
+		return append(allErrs, field.Invalid(fldPath.Child("URI"), uri, "must use an SSH transport (e.g. qemu+ssh://user@host/system) when ssh is set"))
+	}
+	if ssh.Port < 0 || ssh.Port > 65535 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ssh", "port"), ssh.Port, "must be between 1 and 65535"))
+	}
+	if ssh.KnownHosts != "" && transport == "ssh" {
+		// The ssh transport runs the ssh binary, which takes its known
+		// hosts from the user's SSH configuration.
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ssh", "knownHosts"), ssh.KnownHosts, "requires the libssh2 or libssh transport (e.g. qemu+libssh2://user@host/system)"))
+	}
+	return allErrs
+}
@@ -0,0 +1,51 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/libvirt"
+)
+
+func TestValidateLibvirtPlatform(t *testing.T) {
+	testInstallConfigCases(t, []installConfigCase{
+		{
+			name:   "libvirt bootstrap memory",
+			modify: func(c *types.InstallConfig) { c.Platform.Libvirt = &libvirt.Platform{BootstrapMemoryMiB: 4096} },
+		},
+		{
+			name:          "too little libvirt bootstrap memory",
+			modify:        func(c *types.InstallConfig) { c.Platform.Libvirt = &libvirt.Platform{BootstrapMemoryMiB: 1024} },
+			expectedError: `^platform\.libvirt\.bootstrapMemoryMiB: Invalid value: 1024: must be at least 2048$`,
+		},
+		{
+			name: "libvirt ssh",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.Libvirt = &libvirt.Platform{
+					URI: "qemu+libssh2://root@hypervisor.example.com/system",
+					SSH: &libvirt.SSH{Port: 2222, KeyFile: "/home/user/.ssh/libvirt", KnownHosts: "/home/user/.ssh/libvirt_known_hosts"},
+				}
+			},
+		},
+		{
+			name: "libvirt ssh without an ssh uri",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.Libvirt = &libvirt.Platform{
+					URI: "qemu+tcp://192.168.122.1/system",
+					SSH: &libvirt.SSH{KeyFile: "/home/user/.ssh/libvirt"},
+				}
+			},
+			expectedError: `^platform\.libvirt\.URI: Invalid value: "qemu\+tcp://192\.168\.122\.1/system": must use an SSH transport`,
+		},
+		{
+			name: "libvirt known hosts with the ssh transport",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.Libvirt = &libvirt.Platform{
+					URI: "qemu+ssh://root@hypervisor.example.com/system",
+					SSH: &libvirt.SSH{KnownHosts: "/home/user/.ssh/libvirt_known_hosts"},
+				}
+			},
+			expectedError: `^platform\.libvirt\.ssh\.knownHosts: Invalid value: "/home/user/\.ssh/libvirt_known_hosts": requires the libssh2 or libssh transport`,
+		},
+	})
+}
@@ -0,0 +1,288 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+var (
+	// percentPattern matches percentages from 0% to 100%.
+	percentPattern = regexp.MustCompile(`^(100|[1-9]?[0-9])%$`)
+
+	// reservableResources are the resources the kubelet can reserve.
+	reservableResources = sets.NewString("cpu", "memory", "ephemeral-storage", "pid")
+
+	// evictionSignals are the kubelet's hard eviction signals.
+	evictionSignals = sets.NewString("memory.available", "nodefs.available", "nodefs.inodesFree", "imagefs.available", "imagefs.inodesFree", "pid.available")
+
+	// devicePattern matches block device paths.
+	devicePattern = regexp.MustCompile(`^/dev/[A-Za-z0-9/_.:-]+$`)
+
+	// partitionMountPathPattern matches /var and the directories beneath
+	// it, whose names need no escaping in systemd mount unit names.
+	partitionMountPathPattern = regexp.MustCompile(`^/var(/[a-z0-9_]+)*$`)
+
+	// tangThumbprintPattern matches base64url-encoded JWK thumbprints.
+	tangThumbprintPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{20,}$`)
+)
+
+// validateMachinePools checks the machine pools and the settings applied to
+// their machines.
+func validateMachinePools(c *types.InstallConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	poolNames := map[string]bool{}
+	for i, m := range c.Machines {
+		namePath := fldPath.Index(i).Child("name")
+		if poolNames[m.Name] {
+			allErrs = append(allErrs, field.Duplicate(namePath, m.Name))
+		}
+		poolNames[m.Name] = true
+		// Named pools become MachineConfigPools and node roles.
+		for _, msg := range validation.IsDNS1123Label(m.Name) {
+			allErrs = append(allErrs, field.Invalid(namePath, m.Name, msg))
+		}
+		if m.HealthCheck != nil {
+			allErrs = append(allErrs, validateMachineHealthCheck(m.Name, m.HealthCheck, fldPath.Index(i).Child("healthCheck"))...)
+		}
+		if m.KubeletConfig != nil {
+			allErrs = append(allErrs, validateKubeletConfig(m.KubeletConfig, fldPath.Index(i).Child("kubeletConfig"))...)
+		}
+		allErrs = append(allErrs, validateDiskPartitions(m.DiskPartitions, fldPath.Index(i).Child("diskPartitions"))...)
+		switch m.KernelType {
+		case "", types.DefaultKernel, types.RealtimeKernel:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i).Child("kernelType"), m.KernelType, []string{string(types.DefaultKernel), string(types.RealtimeKernel)}))
+		}
+		switch m.Hyperthreading {
+		case "", types.HyperthreadingEnabled, types.HyperthreadingDisabled:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i).Child("hyperthreading"), m.Hyperthreading, []string{string(types.HyperthreadingEnabled), string(types.HyperthreadingDisabled)}))
+		}
+		switch m.Architecture {
+		case "", types.ArchitectureAMD64:
+		case types.ArchitectureARM64:
+			architecturePath := fldPath.Index(i).Child("architecture")
+			if m.Name == "master" {
+				allErrs = append(allErrs, field.Forbidden(architecturePath, "the master pool must be amd64"))
+			} else if c.Platform.AWS == nil {
+				allErrs = append(allErrs, field.Forbidden(architecturePath, "arm64 pools are only supported on AWS"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i).Child("architecture"), m.Architecture, []string{string(types.ArchitectureAMD64), string(types.ArchitectureARM64)}))
+		}
+		switch m.OperatingSystem {
+		case "", types.OperatingSystemLinux:
+		case types.OperatingSystemWindows:
+			allErrs = append(allErrs, validateWindowsPool(c, &m, fldPath.Index(i))...)
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i).Child("operatingSystem"), m.OperatingSystem, []string{string(types.OperatingSystemLinux), string(types.OperatingSystemWindows)}))
+		}
+		if m.Accelerator != nil {
+			acceleratorPath := fldPath.Index(i).Child("accelerator")
+			if m.Name == "master" {
+				allErrs = append(allErrs, field.Forbidden(acceleratorPath, "accelerators are only supported on compute pools"))
+			} else if m.Accelerator.Type != types.NvidiaGPUAccelerator {
+				allErrs = append(allErrs, field.NotSupported(acceleratorPath.Child("type"), m.Accelerator.Type, []string{string(types.NvidiaGPUAccelerator)}))
+			}
+		}
+		if m.DiskEncryption != nil {
+			allErrs = append(allErrs, validateDiskEncryption(m.DiskEncryption, fldPath.Index(i).Child("diskEncryption"))...)
+		}
+		if m.ContainerRuntimeConfig != nil {
+			allErrs = append(allErrs, validateContainerRuntimeConfig(m.ContainerRuntimeConfig, fldPath.Index(i).Child("containerRuntimeConfig"))...)
+		}
+	}
+	return allErrs
+}
+
+// validateWindowsPool checks a Windows pool is a named AWS compute pool
+// without the settings which are applied by machine configs, which Windows
+// machines do not run.
+func validateWindowsPool(c *types.InstallConfig, m *types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	osPath := fldPath.Child("operatingSystem")
+	if m.Name == "master" || m.Name == "worker" {
+		allErrs = append(allErrs, field.Forbidden(osPath, "the master and worker pools must be linux; use a named compute pool"))
+	}
+	if c.Platform.AWS == nil {
+		allErrs = append(allErrs, field.Forbidden(osPath, "windows pools are only supported on AWS"))
+	}
+	if len(c.SSHKey) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("sshKey"), "windows pools are configured over SSH"))
+	}
+	for _, setting := range []struct {
+		name string
+		set  bool
+	}{
+		{name: "architecture", set: m.Architecture != "" && m.Architecture != types.ArchitectureAMD64},
+		{name: "kernelType", set: m.KernelType != "" && m.KernelType != types.DefaultKernel},
+		{name: "hyperthreading", set: m.Hyperthreading != "" && m.Hyperthreading != types.HyperthreadingEnabled},
+		{name: "diskPartitions", set: len(m.DiskPartitions) > 0},
+		{name: "diskEncryption", set: m.DiskEncryption != nil},
+		{name: "kubeletConfig", set: m.KubeletConfig != nil},
+		{name: "containerRuntimeConfig", set: m.ContainerRuntimeConfig != nil},
+		{name: "accelerator", set: m.Accelerator != nil},
+	} {
+		if setting.set {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child(setting.name), "not supported for windows pools"))
+		}
+	}
+	return allErrs
+}
+
+func validateMachineHealthCheck(pool string, check *types.MachineHealthCheck, fldPath *field.Path) field.ErrorList {
+	if pool == "master" {
+		return field.ErrorList{field.Forbidden(fldPath, "master machines are not remediated")}
+	}
+	allErrs := field.ErrorList{}
+	for i, condition := range check.UnhealthyConditions {
+		conditionPath := fldPath.Child("unhealthyConditions").Index(i)
+		if condition.Type == "" {
+			allErrs = append(allErrs, field.Required(conditionPath.Child("type"), "condition type is required"))
+		}
+		switch condition.Status {
+		case corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown:
+		default:
+			allErrs = append(allErrs, field.NotSupported(conditionPath.Child("status"), condition.Status, []string{
+				string(corev1.ConditionTrue),
+				string(corev1.ConditionFalse),
+				string(corev1.ConditionUnknown),
+			}))
+		}
+		if timeout, err := time.ParseDuration(condition.Timeout); err != nil || timeout <= 0 {
+			allErrs = append(allErrs, field.Invalid(conditionPath.Child("timeout"), condition.Timeout, "must be a positive duration such as 300s"))
+		}
+	}
+	if maxUnhealthy := check.MaxUnhealthy; maxUnhealthy != nil {
+		switch {
+		case maxUnhealthy.Type == intstr.Int && maxUnhealthy.IntVal < 0:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnhealthy"), maxUnhealthy.IntVal, "must not be negative"))
+		case maxUnhealthy.Type == intstr.String && !percentPattern.MatchString(maxUnhealthy.StrVal):
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnhealthy"), maxUnhealthy.StrVal, "must be a percentage between 0% and 100%"))
+		}
+	}
+	return allErrs
+}
+
+func validateKubeletConfig(c *types.KubeletConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if c.MaxPods != nil && *c.MaxPods <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxPods"), *c.MaxPods, "must be a positive number"))
+	}
+	allErrs = append(allErrs, validateReservedResources(c.SystemReserved, fldPath.Child("systemReserved"))...)
+	allErrs = append(allErrs, validateReservedResources(c.KubeReserved, fldPath.Child("kubeReserved"))...)
+	for signal, threshold := range c.EvictionHard {
+		if !evictionSignals.Has(signal) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("evictionHard"), signal, evictionSignals.List()))
+			continue
+		}
+		if percentPattern.MatchString(threshold) {
+			continue
+		}
+		if _, err := resource.ParseQuantity(threshold); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("evictionHard").Key(signal), threshold, "must be a quantity such as 500Mi or a percentage such as 10%"))
+		}
+	}
+	return allErrs
+}
+
+func validateReservedResources(reserved map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for name, quantity := range reserved {
+		if !reservableResources.Has(name) {
+			allErrs = append(allErrs, field.NotSupported(fldPath, name, reservableResources.List()))
+			continue
+		}
+		if _, err := resource.ParseQuantity(quantity); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(name), quantity, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateContainerRuntimeConfig(c *types.ContainerRuntimeConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if c.PidsLimit != nil && *c.PidsLimit <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pidsLimit"), *c.PidsLimit, "must be a positive number"))
+	}
+	if c.LogSizeMax != "" {
+		// CRI-O rejects log size limits below 8 KiB.
+		if size, err := resource.ParseQuantity(c.LogSizeMax); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("logSizeMax"), c.LogSizeMax, err.Error()))
+		} else if size.Value() < 8*1024 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("logSizeMax"), c.LogSizeMax, "must be at least 8Ki"))
+		}
+	}
+	if c.OverlaySize != "" {
+		if size, err := resource.ParseQuantity(c.OverlaySize); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("overlaySize"), c.OverlaySize, err.Error()))
+		} else if size.Sign() <= 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("overlaySize"), c.OverlaySize, "must be positive"))
+		}
+	}
+	return allErrs
+}
+
+func validateDiskPartitions(partitions []types.DiskPartition, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	mountPaths := map[string]bool{}
+	for i, p := range partitions {
+		partitionPath := fldPath.Index(i)
+		if !devicePattern.MatchString(p.Device) {
+			allErrs = append(allErrs, field.Invalid(partitionPath.Child("device"), p.Device, "must be a device path such as /dev/vda"))
+		}
+		if !partitionMountPathPattern.MatchString(p.MountPath) {
+			allErrs = append(allErrs, field.Invalid(partitionPath.Child("mountPath"), p.MountPath, "must be /var or a directory beneath it, of lowercase letters, digits and underscores"))
+		} else if mountPaths[p.MountPath] {
+			allErrs = append(allErrs, field.Duplicate(partitionPath.Child("mountPath"), p.MountPath))
+		}
+		mountPaths[p.MountPath] = true
+		if p.StartMiB < 0 {
+			allErrs = append(allErrs, field.Invalid(partitionPath.Child("startMiB"), p.StartMiB, "must not be negative"))
+		}
+		if p.SizeMiB < 0 {
+			allErrs = append(allErrs, field.Invalid(partitionPath.Child("sizeMiB"), p.SizeMiB, "must not be negative"))
+		}
+		switch p.Format {
+		case "", "xfs", "ext4":
+		default:
+			allErrs = append(allErrs, field.NotSupported(partitionPath.Child("format"), p.Format, []string{"xfs", "ext4"}))
+		}
+	}
+	return allErrs
+}
+
+func validateDiskEncryption(e *types.DiskEncryption, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	pins := len(e.Tang)
+	if e.TPM2 {
+		pins++
+	}
+	if pins == 0 {
+		return field.ErrorList{field.Required(fldPath, "tpm2 or at least one tang server is required")}
+	}
+	for i, server := range e.Tang {
+		serverPath := fldPath.Child("tang").Index(i)
+		if u, err := url.Parse(server.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			allErrs = append(allErrs, field.Invalid(serverPath.Child("url"), server.URL, "must be an http or https URL"))
+		}
+		if !tangThumbprintPattern.MatchString(server.Thumbprint) {
+			allErrs = append(allErrs, field.Invalid(serverPath.Child("thumbprint"), server.Thumbprint, "must be a base64url-encoded key thumbprint"))
+		}
+	}
+	if e.Threshold < 0 || e.Threshold > pins {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("threshold"), e.Threshold, fmt.Sprintf("must be between 1 and the number of TPM and Tang pins (%d)", pins)))
+	}
+	return allErrs
+}
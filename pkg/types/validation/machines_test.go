@@ -0,0 +1,282 @@
+package validation
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+)
+
+func TestValidateMachinePools(t *testing.T) {
+	testInstallConfigCases(t, []installConfigCase{
+		{
+			name: "named compute pool",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "master"}, {Name: "worker"}, {Name: "infra"}}
+			},
+		},
+		{
+			name:          "duplicate machine pool",
+			modify:        func(c *types.InstallConfig) { c.Machines = []types.MachinePool{{Name: "worker"}, {Name: "worker"}} },
+			expectedError: `^machines\[1\]\.name: Duplicate value: "worker"$`,
+		},
+		{
+			name:          "invalid machine pool name",
+			modify:        func(c *types.InstallConfig) { c.Machines = []types.MachinePool{{Name: "Infra_Nodes"}} },
+			expectedError: `^machines\[0\]\.name: Invalid value: "Infra_Nodes": a DNS-1123 label must consist of`,
+		},
+		{
+			name: "kubelet config",
+			modify: func(c *types.InstallConfig) {
+				maxPods := int32(500)
+				c.Machines = []types.MachinePool{{
+					Name: "worker",
+					KubeletConfig: &types.KubeletConfig{
+						MaxPods:        &maxPods,
+						SystemReserved: map[string]string{"cpu": "500m", "memory": "1Gi"},
+						KubeReserved:   map[string]string{"memory": "512Mi"},
+						EvictionHard:   map[string]string{"memory.available": "500Mi", "nodefs.available": "10%"},
+					},
+				}}
+			},
+		},
+		{
+			name: "unsupported kubelet reserved resource",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", KubeletConfig: &types.KubeletConfig{SystemReserved: map[string]string{"gpu": "1"}}}}
+			},
+			expectedError: `^machines\[0\]\.kubeletConfig\.systemReserved: Unsupported value: "gpu": supported values: "cpu", "ephemeral-storage", "memory", "pid"$`,
+		},
+		{
+			name: "invalid kubelet eviction threshold",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", KubeletConfig: &types.KubeletConfig{EvictionHard: map[string]string{"memory.available": "half"}}}}
+			},
+			expectedError: `^machines\[0\]\.kubeletConfig\.evictionHard\[memory\.available\]: Invalid value: "half": must be a quantity such as 500Mi or a percentage such as 10%$`,
+		},
+		{
+			name: "container runtime config",
+			modify: func(c *types.InstallConfig) {
+				pidsLimit := int64(2048)
+				c.Machines = []types.MachinePool{{
+					Name:                   "worker",
+					ContainerRuntimeConfig: &types.ContainerRuntimeConfig{PidsLimit: &pidsLimit, LogSizeMax: "50Mi", OverlaySize: "10Gi"},
+				}}
+			},
+		},
+		{
+			name: "small container log size",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", ContainerRuntimeConfig: &types.ContainerRuntimeConfig{LogSizeMax: "1Ki"}}}
+			},
+			expectedError: `^machines\[0\]\.containerRuntimeConfig\.logSizeMax: Invalid value: "1Ki": must be at least 8Ki$`,
+		},
+		{
+			name: "disk partitions",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{
+					Name: "worker",
+					DiskPartitions: []types.DiskPartition{
+						{Device: "/dev/nvme0n1", MountPath: "/var", StartMiB: 25000, SizeMiB: 50000},
+						{Device: "/dev/nvme1n1", MountPath: "/var/lib/containers", Format: "ext4"},
+					},
+				}}
+			},
+		},
+		{
+			name: "disk partition outside /var",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", DiskPartitions: []types.DiskPartition{{Device: "/dev/vda", MountPath: "/home"}}}}
+			},
+			expectedError: `^machines\[0\]\.diskPartitions\[0\]\.mountPath: Invalid value: "/home": must be /var or a directory beneath it`,
+		},
+		{
+			name: "duplicate disk partition mount path",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", DiskPartitions: []types.DiskPartition{
+					{Device: "/dev/vda", MountPath: "/var"},
+					{Device: "/dev/vdb", MountPath: "/var"},
+				}}}
+			},
+			expectedError: `^machines\[0\]\.diskPartitions\[1\]\.mountPath: Duplicate value: "/var"$`,
+		},
+		{
+			name: "disk encryption",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{
+					Name: "master",
+					DiskEncryption: &types.DiskEncryption{
+						TPM2:      true,
+						Tang:      []types.TangServer{{URL: "http://tang.example.com:7500", Thumbprint: "PLjNyRdGw03zlRoGjQYMahSZGu9"}},
+						Threshold: 2,
+					},
+				}}
+			},
+		},
+		{
+			name: "disk encryption without pins",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", DiskEncryption: &types.DiskEncryption{}}}
+			},
+			expectedError: `^machines\[0\]\.diskEncryption: Required value: tpm2 or at least one tang server is required$`,
+		},
+		{
+			name: "disk encryption threshold above pins",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", DiskEncryption: &types.DiskEncryption{TPM2: true, Threshold: 2}}}
+			},
+			expectedError: `^machines\[0\]\.diskEncryption\.threshold: Invalid value: 2: must be between 1 and the number of TPM and Tang pins \(1\)$`,
+		},
+		{
+			name: "realtime kernel",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", KernelType: types.RealtimeKernel}}
+			},
+		},
+		{
+			name: "unsupported kernel type",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", KernelType: "lowlatency"}}
+			},
+			expectedError: `^machines\[0\]\.kernelType: Unsupported value: "lowlatency": supported values: "default", "realtime"$`,
+		},
+		{
+			name: "hyperthreading disabled",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "master", Hyperthreading: types.HyperthreadingDisabled}, {Name: "worker", Hyperthreading: types.HyperthreadingEnabled}}
+			},
+		},
+		{
+			name: "unsupported hyperthreading",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", Hyperthreading: "Off"}}
+			},
+			expectedError: `^machines\[0\]\.hyperthreading: Unsupported value: "Off": supported values: "Enabled", "Disabled"$`,
+		},
+		{
+			name: "gpu pool",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker"}, {Name: "gpu", Accelerator: &types.Accelerator{Type: types.NvidiaGPUAccelerator, Operators: true}}}
+			},
+		},
+		{
+			name: "master accelerator",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "master", Accelerator: &types.Accelerator{Type: types.NvidiaGPUAccelerator}}}
+			},
+			expectedError: `^machines\[0\]\.accelerator: Forbidden: accelerators are only supported on compute pools$`,
+		},
+		{
+			name: "arm64 compute pool",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{}
+				c.Machines = []types.MachinePool{
+					{Name: "worker", Platform: types.MachinePoolPlatform{AWS: &aws.MachinePool{InstanceType: "m5.large"}}},
+					{Name: "arm", Architecture: types.ArchitectureARM64, Platform: types.MachinePoolPlatform{AWS: &aws.MachinePool{InstanceType: "m6g.large"}}},
+				}
+			},
+		},
+		{
+			name: "windows pool",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{}
+				c.Machines = []types.MachinePool{{Name: "worker"}, {Name: "windows", OperatingSystem: types.OperatingSystemWindows}}
+			},
+		},
+		{
+			name: "windows worker pool",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{}
+				c.Machines = []types.MachinePool{{Name: "worker", OperatingSystem: types.OperatingSystemWindows}}
+			},
+			expectedError: `^machines\[0\]\.operatingSystem: Forbidden: the master and worker pools must be linux; use a named compute pool$`,
+		},
+		{
+			name: "windows pool with machine configs",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{}
+				c.Machines = []types.MachinePool{{Name: "windows", OperatingSystem: types.OperatingSystemWindows, KernelType: types.RealtimeKernel, Hyperthreading: types.HyperthreadingDisabled}}
+			},
+			expectedError: `^\[machines\[0\]\.kernelType: Forbidden: not supported for windows pools, machines\[0\]\.hyperthreading: Forbidden: not supported for windows pools\]$`,
+		},
+		{
+			name: "windows pool off AWS without ssh keys",
+			modify: func(c *types.InstallConfig) {
+				c.SSHKey = nil
+				c.Machines = []types.MachinePool{{Name: "windows", OperatingSystem: types.OperatingSystemWindows}}
+			},
+			expectedError: `^\[machines\[0\]\.operatingSystem: Forbidden: windows pools are only supported on AWS, sshKey: Required value: windows pools are configured over SSH\]$`,
+		},
+		{
+			name: "unsupported operating system",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", OperatingSystem: "plan9"}}
+			},
+			expectedError: `^machines\[0\]\.operatingSystem: Unsupported value: "plan9": supported values: "linux", "windows"$`,
+		},
+		{
+			name: "arm64 master pool",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{}
+				c.Machines = []types.MachinePool{{Name: "master", Architecture: types.ArchitectureARM64}}
+			},
+			expectedError: `^machines\[0\]\.architecture: Forbidden: the master pool must be amd64$`,
+		},
+		{
+			name: "arm64 pool off AWS",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", Architecture: types.ArchitectureARM64}}
+			},
+			expectedError: `^machines\[0\]\.architecture: Forbidden: arm64 pools are only supported on AWS$`,
+		},
+		{
+			name: "unsupported architecture",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", Architecture: "s390x"}}
+			},
+			expectedError: `^machines\[0\]\.architecture: Unsupported value: "s390x": supported values: "amd64", "arm64"$`,
+		},
+		{
+			name: "worker health check",
+			modify: func(c *types.InstallConfig) {
+				maxUnhealthy := intstr.FromString("40%")
+				c.Machines = []types.MachinePool{{
+					Name: "worker",
+					HealthCheck: &types.MachineHealthCheck{
+						UnhealthyConditions: []types.UnhealthyCondition{{Type: "Ready", Status: "Unknown", Timeout: "5m"}},
+						MaxUnhealthy:        &maxUnhealthy,
+					},
+				}}
+			},
+		},
+		{
+			name: "master health check",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "master", HealthCheck: &types.MachineHealthCheck{}}}
+			},
+			expectedError: `^machines\[0\]\.healthCheck: Forbidden: master machines are not remediated$`,
+		},
+		{
+			name: "invalid health check timeout",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{
+					Name: "worker",
+					HealthCheck: &types.MachineHealthCheck{
+						UnhealthyConditions: []types.UnhealthyCondition{{Type: "Ready", Status: "False", Timeout: "five minutes"}},
+					},
+				}}
+			},
+			expectedError: `^machines\[0\]\.healthCheck\.unhealthyConditions\[0\]\.timeout: Invalid value: "five minutes": must be a positive duration such as 300s$`,
+		},
+		{
+			name: "invalid health check max unhealthy",
+			modify: func(c *types.InstallConfig) {
+				maxUnhealthy := intstr.FromString("150%")
+				c.Machines = []types.MachinePool{{Name: "worker", HealthCheck: &types.MachineHealthCheck{MaxUnhealthy: &maxUnhealthy}}}
+			},
+			expectedError: `^machines\[0\]\.healthCheck\.maxUnhealthy: Invalid value: "150%": must be a percentage between 0% and 100%$`,
+		},
+	})
+}
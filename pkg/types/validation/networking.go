@@ -0,0 +1,279 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/networkoperator/v1"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/validate"
+)
+
+// portRangePattern matches port ranges such as 30000-32767.
+var portRangePattern = regexp.MustCompile(`^([0-9]{1,5})-([0-9]{1,5})$`)
+
+// validateNetworking checks the cluster's networks and the network
+// operator's settings.
+func validateNetworking(c *types.InstallConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(c.Networking.MachineNetwork) > 0 {
+		allErrs = append(allErrs, validateMachineNetworks(&c.Networking, &c.Platform, fldPath.Child("machineNetwork"))...)
+	}
+	warnReservedRanges(&c.Networking, &c.Platform, fldPath)
+	if len(c.Networking.ServiceCIDR.IP) > 0 {
+		if _, err := c.Networking.ClusterDNSIP(); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("serviceCIDR"), c.Networking.ServiceCIDR.String(), err.Error()))
+		}
+	}
+	if c.Networking.OVNKubernetesConfig != nil && c.Networking.Type != netopv1.NetworkTypeOVNKubernetes {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("ovnKubernetesConfig"), fmt.Sprintf("may only be set when type is %s", netopv1.NetworkTypeOVNKubernetes)))
+	}
+	if c.Networking.Egress != nil {
+		allErrs = append(allErrs, validateEgress(c.Networking.Egress, c.Networking.Type, c.MachineCIDRs(), fldPath.Child("egress"))...)
+	}
+	if c.Networking.ExternalIP != nil {
+		allErrs = append(allErrs, validateExternalIP(c.Networking.ExternalIP, fldPath.Child("externalIP"))...)
+	}
+	if c.Networking.ServiceNodePortRange != "" {
+		allErrs = append(allErrs, validateServiceNodePortRange(c.Networking.ServiceNodePortRange, c.MachineConfigServerPort(), fldPath.Child("serviceNodePortRange"))...)
+	}
+	return allErrs
+}
+
+// validateMachineNetworks checks that the machine networks are IPv4 and
+// overlap neither each other nor the service and cluster networks, and that
+// they agree with the platform's network.  Only AWS, which associates the
+// additional networks with the VPC, supports more than one.
+func validateMachineNetworks(n *types.Networking, platform *types.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	var others []string
+	if len(n.ServiceCIDR.IP) > 0 {
+		others = append(others, n.ServiceCIDR.String())
+	}
+	for _, cn := range n.ClusterNetworks {
+		if _, _, err := net.ParseCIDR(cn.CIDR); err == nil {
+			others = append(others, cn.CIDR)
+		}
+	}
+	if n.PodCIDR != nil && len(n.PodCIDR.IP) > 0 {
+		others = append(others, n.PodCIDR.String())
+	}
+	for i, entry := range n.MachineNetwork {
+		cidrPath := fldPath.Index(i).Child("cidr")
+		cidr := entry.CIDR.String()
+		if entry.CIDR.IP.To4() == nil {
+			allErrs = append(allErrs, field.Invalid(cidrPath, cidr, "must be an IPv4 network"))
+			continue
+		}
+		for _, prev := range n.MachineNetwork[:i] {
+			if err := validate.CIDRsDontOverlap(cidr, prev.CIDR.String()); err != nil {
+				allErrs = append(allErrs, field.Invalid(cidrPath, cidr, err.Error()))
+			}
+		}
+		for _, other := range others {
+			if err := validate.CIDRsDontOverlap(cidr, other); err != nil {
+				allErrs = append(allErrs, field.Invalid(cidrPath, cidr, err.Error()))
+			}
+		}
+	}
+
+	primary := n.MachineNetwork[0].CIDR.String()
+	var platformPath *field.Path
+	var platformCIDR string
+	switch {
+	case platform.AWS != nil:
+		platformPath, platformCIDR = field.NewPath("platform", "aws", "vpcCIDRBlock"), platform.AWS.VPCCIDRBlock
+	case platform.OpenStack != nil:
+		platformPath, platformCIDR = field.NewPath("platform", "openstack", "NetworkCIDRBlock"), platform.OpenStack.NetworkCIDRBlock
+	case platform.Libvirt != nil:
+		platformPath, platformCIDR = field.NewPath("platform", "libvirt", "network", "ipRange"), platform.Libvirt.Network.IPRange
+	}
+	if platformCIDR != "" && platformCIDR != primary {
+		allErrs = append(allErrs, field.Invalid(platformPath, platformCIDR, fmt.Sprintf("must match the first machine network (%s)", primary)))
+	}
+	if platform.AWS == nil && len(n.MachineNetwork) > 1 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Index(1), "multiple machine networks are only supported on AWS"))
+	}
+	return allErrs
+}
+
+// reservedRange is a block the service and cluster networks must avoid.
+type reservedRange struct {
+	cidr string
+	name string
+}
+
+// linkLocalRange holds the instance metadata services of AWS and OpenStack
+// (169.254.169.254), and the AWS DNS server (169.254.169.253).
+var linkLocalRange = reservedRange{cidr: "169.254.0.0/16", name: "the link-local range"}
+
+// warnReservedRanges warns about service and cluster networks which overlap
+// the link-local range or the platform's reserved addresses, which breaks
+// metadata and DNS lookups in ways that are hard to diagnose.
+func warnReservedRanges(n *types.Networking, platform *types.Platform, fldPath *field.Path) {
+	for _, overlap := range reservedRangeOverlaps(n, platform, fldPath) {
+		logrus.Warn(overlap)
+	}
+}
+
+// reservedRangeOverlaps describes the overlaps of the service and cluster
+// networks with the reserved ranges.  The platform network itself is only
+// reserved here if no machine networks were set; validateMachineNetworks
+// checks those.
+func reservedRangeOverlaps(n *types.Networking, platform *types.Platform, fldPath *field.Path) []string {
+	reserved := []reservedRange{linkLocalRange}
+	if len(n.MachineNetwork) == 0 {
+		switch {
+		case platform.AWS != nil && platform.AWS.VPCCIDRBlock != "":
+			if _, vpc, err := net.ParseCIDR(platform.AWS.VPCCIDRBlock); err == nil && vpc.IP.To4() != nil {
+				// AWS serves DNS on the VPC's base address plus two.
+				dns := make(net.IP, net.IPv4len)
+				copy(dns, vpc.IP.To4())
+				dns[3] += 2
+				reserved = append(reserved, reservedRange{cidr: dns.String() + "/32", name: "the AWS VPC DNS server"})
+			}
+		case platform.OpenStack != nil && platform.OpenStack.NetworkCIDRBlock != "":
+			reserved = append(reserved, reservedRange{cidr: platform.OpenStack.NetworkCIDRBlock, name: "the OpenStack network"})
+		}
+	}
+
+	type network struct {
+		path *field.Path
+		cidr string
+	}
+	var networks []network
+	if len(n.ServiceCIDR.IP) > 0 {
+		networks = append(networks, network{path: fldPath.Child("serviceCIDR"), cidr: n.ServiceCIDR.String()})
+	}
+	for i, cn := range n.ClusterNetworks {
+		networks = append(networks, network{path: fldPath.Child("clusterNetworks").Index(i).Child("cidr"), cidr: cn.CIDR})
+	}
+	if n.PodCIDR != nil && len(n.PodCIDR.IP) > 0 {
+		networks = append(networks, network{path: fldPath.Child("podCIDR"), cidr: n.PodCIDR.String()})
+	}
+
+	var overlaps []string
+	for _, nw := range networks {
+		if _, _, err := net.ParseCIDR(nw.cidr); err != nil {
+			continue
+		}
+		for _, r := range reserved {
+			if _, _, err := net.ParseCIDR(r.cidr); err != nil {
+				continue
+			}
+			if err := validate.CIDRsDontOverlap(nw.cidr, r.cidr); err != nil {
+				overlaps = append(overlaps, fmt.Sprintf("%s: %s overlaps %s (%s)", nw.path, nw.cidr, r.name, r.cidr))
+			}
+		}
+	}
+	return overlaps
+}
+
+// validateServiceNodePortRange checks that the node port range is a valid
+// port range which does not include the ports the cluster's hosts serve.
+func validateServiceNodePortRange(r string, mcsPort int, fldPath *field.Path) field.ErrorList {
+	m := portRangePattern.FindStringSubmatch(r)
+	if m == nil {
+		return field.ErrorList{field.Invalid(fldPath, r, "must be a port range (e.g. 30000-32767)")}
+	}
+	first, _ := strconv.Atoi(m[1])
+	last, _ := strconv.Atoi(m[2])
+	if first < 1 || last > 65535 || first > last {
+		return field.ErrorList{field.Invalid(fldPath, r, "must be an ascending range of ports between 1 and 65535")}
+	}
+	allErrs := field.ErrorList{}
+	for _, reserved := range []struct {
+		port int
+		name string
+	}{
+		{port: 2379, name: "etcd"},
+		{port: 2380, name: "etcd peers"},
+		{port: 6443, name: "the API server"},
+		{port: 10250, name: "the kubelet"},
+		{port: mcsPort, name: "the machine-config server"},
+	} {
+		if first <= reserved.port && reserved.port <= last {
+			allErrs = append(allErrs, field.Invalid(fldPath, r, fmt.Sprintf("must not include port %d, used by %s", reserved.port, reserved.name)))
+		}
+	}
+	return allErrs
+}
+
+// validateExternalIP checks that the external IP blocks are CIDRs and that
+// the automatically assigned blocks are not rejected by the policy.
+func validateExternalIP(c *types.ExternalIPConfig, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	var rejected []string
+	if c.Policy != nil {
+		allErrs = append(allErrs, validateCIDRs(c.Policy.AllowedCIDRs, fldPath.Child("policy", "allowedCIDRs"))...)
+		allErrs = append(allErrs, validateCIDRs(c.Policy.RejectedCIDRs, fldPath.Child("policy", "rejectedCIDRs"))...)
+		rejected = c.Policy.RejectedCIDRs
+	}
+	allErrs = append(allErrs, validateCIDRs(c.AutoAssignCIDRs, fldPath.Child("autoAssignCIDRs"))...)
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+	for i, cidr := range c.AutoAssignCIDRs {
+		for _, r := range rejected {
+			if err := validate.CIDRsDontOverlap(cidr, r); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("autoAssignCIDRs").Index(i), cidr, fmt.Sprintf("overlaps the rejected %s", r)))
+			}
+		}
+	}
+	return allErrs
+}
+
+// validateEgress checks the egress policy and that the egress IP blocks are
+// within the machine networks, whose addresses the nodes' interfaces
+// carry.
+func validateEgress(c *types.EgressConfig, networkType netopv1.NetworkType, machineCIDRs []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if networkType != netopv1.NetworkTypeOpenshiftSDN && networkType != netopv1.NetworkTypeOVNKubernetes {
+		allErrs = append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("may only be set when type is %s or %s", netopv1.NetworkTypeOpenshiftSDN, netopv1.NetworkTypeOVNKubernetes)))
+	}
+	switch c.DefaultPolicy {
+	case "", types.EgressPolicyAllow, types.EgressPolicyDeny:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("defaultPolicy"), c.DefaultPolicy, []string{string(types.EgressPolicyAllow), string(types.EgressPolicyDeny)}))
+	}
+	allErrs = append(allErrs, validateCIDRs(c.AllowedCIDRs, fldPath.Child("allowedCIDRs"))...)
+
+	cidrErrs := validateCIDRs(c.EgressIPCIDRs, fldPath.Child("egressIPCIDRs"))
+	if len(cidrErrs) > 0 || len(machineCIDRs) == 0 {
+		return append(allErrs, cidrErrs...)
+	}
+	for i, cidr := range c.EgressIPCIDRs {
+		_, egress, _ := net.ParseCIDR(cidr)
+		within := false
+		for _, machineCIDR := range machineCIDRs {
+			_, machine, err := net.ParseCIDR(machineCIDR)
+			if err != nil {
+				continue
+			}
+			egressOnes, _ := egress.Mask.Size()
+			machineOnes, _ := machine.Mask.Size()
+			within = within || (machine.Contains(egress.IP) && egressOnes >= machineOnes)
+		}
+		if !within {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("egressIPCIDRs").Index(i), cidr, fmt.Sprintf("must be within a machine network (%s)", strings.Join(machineCIDRs, ", "))))
+		}
+	}
+	return allErrs
+}
+
+// validateCIDRs checks that each of a list of blocks is a CIDR.
+func validateCIDRs(cidrs []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i), cidr, err.Error()))
+		}
+	}
+	return allErrs
+}
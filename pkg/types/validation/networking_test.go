@@ -0,0 +1,205 @@
+package validation
+
+import (
+	"testing"
+
+	netopv1 "github.com/openshift/cluster-network-operator/pkg/apis/networkoperator/v1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+	"github.com/openshift/installer/pkg/types/openstack"
+)
+
+func TestValidateNetworking(t *testing.T) {
+	testInstallConfigCases(t, []installConfigCase{
+		{
+			name: "aws machine networks",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.ServiceCIDR = ipNet("172.30.0.0/16")
+				c.Networking.MachineNetwork = machineNetwork("10.0.0.0/16", "10.1.0.0/16")
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1", VPCCIDRBlock: "10.0.0.0/16"}
+			},
+		},
+		{
+			name: "aws machine network not matching the vpc",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.MachineNetwork = machineNetwork("10.1.0.0/16")
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1", VPCCIDRBlock: "10.0.0.0/16"}
+			},
+			expectedError: `^platform\.aws\.vpcCIDRBlock: Invalid value: "10\.0\.0\.0/16": must match the first machine network \(10\.1\.0\.0/16\)$`,
+		},
+		{
+			name: "overlapping machine networks",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.ServiceCIDR = ipNet("10.1.128.0/20")
+				c.Networking.MachineNetwork = machineNetwork("10.0.0.0/16", "10.0.128.0/17", "10.1.0.0/16")
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+			},
+			expectedError: `^\[networking\.machineNetwork\[1\]\.cidr: Invalid value: "10\.0\.128\.0/17": "10\.0\.128\.0/17" and "10\.0\.0\.0/16" overlap, networking\.machineNetwork\[2\]\.cidr: Invalid value: "10\.1\.0\.0/16": "10\.1\.0\.0/16" and "10\.1\.128\.0/20" overlap\]$`,
+		},
+		{
+			name:          "ipv6 machine network",
+			modify:        func(c *types.InstallConfig) { c.Networking.MachineNetwork = machineNetwork("fd00::/48") },
+			expectedError: `^networking\.machineNetwork\[0\]\.cidr: Invalid value: "fd00::/48": must be an IPv4 network$`,
+		},
+		{
+			name: "multiple machine networks off aws",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.MachineNetwork = machineNetwork("10.0.0.0/16", "10.1.0.0/16")
+			},
+			expectedError: `^networking\.machineNetwork\[1\]: Forbidden: multiple machine networks are only supported on AWS$`,
+		},
+		{
+			name:   "service node port range",
+			modify: func(c *types.InstallConfig) { c.Networking.ServiceNodePortRange = "30000-39999" },
+		},
+		{
+			name:          "invalid service node port range",
+			modify:        func(c *types.InstallConfig) { c.Networking.ServiceNodePortRange = "30000" },
+			expectedError: `^networking\.serviceNodePortRange: Invalid value: "30000": must be a port range \(e\.g\. 30000-32767\)$`,
+		},
+		{
+			name:          "descending service node port range",
+			modify:        func(c *types.InstallConfig) { c.Networking.ServiceNodePortRange = "32767-30000" },
+			expectedError: `^networking\.serviceNodePortRange: Invalid value: "32767-30000": must be an ascending range of ports between 1 and 65535$`,
+		},
+		{
+			name:          "service node port range including the machine-config server",
+			modify:        func(c *types.InstallConfig) { c.Networking.ServiceNodePortRange = "40000-50000" },
+			expectedError: `^networking\.serviceNodePortRange: Invalid value: "40000-50000": must not include port 49500, used by the machine-config server$`,
+		},
+		{
+			name: "ovn-kubernetes ipsec",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.Type = netopv1.NetworkTypeOVNKubernetes
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{IPsecConfig: &types.IPsecConfig{}}
+			},
+		},
+		{
+			name: "ipsec without ovn-kubernetes",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.Type = netopv1.NetworkTypeOpenshiftSDN
+				c.Networking.OVNKubernetesConfig = &types.OVNKubernetesConfig{IPsecConfig: &types.IPsecConfig{}}
+			},
+			expectedError: `^networking\.ovnKubernetesConfig: Forbidden: may only be set when type is OVNKubernetes$`,
+		},
+		{
+			name: "egress",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.Type = netopv1.NetworkTypeOpenshiftSDN
+				c.Networking.MachineNetwork = machineNetwork("10.0.0.0/16")
+				c.Networking.Egress = &types.EgressConfig{
+					DefaultPolicy: types.EgressPolicyDeny,
+					AllowedCIDRs:  []string{"192.0.2.0/24"},
+					EgressIPCIDRs: []string{"10.0.200.0/24"},
+				}
+			},
+		},
+		{
+			name: "egress without a supported network type",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.Type = netopv1.NetworkTypeCalico
+				c.Networking.Egress = &types.EgressConfig{DefaultPolicy: "Block"}
+			},
+			expectedError: `^\[networking\.egress: Forbidden: may only be set when type is OpenshiftSDN or OVNKubernetes, networking\.egress\.defaultPolicy: Unsupported value: "Block": supported values: "Allow", "Deny"\]$`,
+		},
+		{
+			name: "egress ips outside the machine networks",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.Type = netopv1.NetworkTypeOVNKubernetes
+				c.Networking.MachineNetwork = machineNetwork("10.0.0.0/16")
+				c.Networking.Egress = &types.EgressConfig{EgressIPCIDRs: []string{"10.0.0.0/8"}}
+			},
+			expectedError: `^networking\.egress\.egressIPCIDRs\[0\]: Invalid value: "10\.0\.0\.0/8": must be within a machine network \(10\.0\.0\.0/16\)$`,
+		},
+		{
+			name: "external ip",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.ExternalIP = &types.ExternalIPConfig{
+					Policy: &types.ExternalIPPolicy{
+						AllowedCIDRs:  []string{"192.0.2.0/24"},
+						RejectedCIDRs: []string{"192.0.2.128/25"},
+					},
+					AutoAssignCIDRs: []string{"192.0.2.0/26"},
+				}
+			},
+		},
+		{
+			name: "invalid external ip cidr",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.ExternalIP = &types.ExternalIPConfig{
+					Policy: &types.ExternalIPPolicy{AllowedCIDRs: []string{"192.0.2.1"}},
+				}
+			},
+			expectedError: `^networking\.externalIP\.policy\.allowedCIDRs\[0\]: Invalid value: "192\.0\.2\.1": invalid CIDR address: 192\.0\.2\.1$`,
+		},
+		{
+			name: "rejected external ip auto-assign cidr",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.ExternalIP = &types.ExternalIPConfig{
+					Policy:          &types.ExternalIPPolicy{RejectedCIDRs: []string{"192.0.2.128/25"}},
+					AutoAssignCIDRs: []string{"192.0.2.0/24"},
+				}
+			},
+			expectedError: `^networking\.externalIP\.autoAssignCIDRs\[0\]: Invalid value: "192\.0\.2\.0/24": overlaps the rejected 192\.0\.2\.128/25$`,
+		},
+		{
+			name:          "service network too small for the cluster dns ip",
+			modify:        func(c *types.InstallConfig) { c.Networking.ServiceCIDR = ipNet("172.30.0.0/30") },
+			expectedError: `^networking\.serviceCIDR: Invalid value: "172\.30\.0\.0/30": too small for the cluster DNS service, whose IP is address 10 of the block; it must have at least 16 addresses \(e\.g\. a /28\)$`,
+		},
+	})
+}
+
+func TestReservedRangeOverlaps(t *testing.T) {
+	cases := []struct {
+		name     string
+		modify   func(c *types.InstallConfig)
+		expected []string
+	}{
+		{
+			name: "no overlaps",
+		},
+		{
+			name:     "service network overlapping the link-local range",
+			modify:   func(c *types.InstallConfig) { c.Networking.ServiceCIDR = ipNet("169.254.0.0/20") },
+			expected: []string{"networking.serviceCIDR: 169.254.0.0/20 overlaps the link-local range (169.254.0.0/16)"},
+		},
+		{
+			name: "cluster network overlapping the aws vpc dns server",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.ClusterNetworks = []netopv1.ClusterNetwork{{CIDR: "10.0.0.0/14", HostSubnetLength: 9}}
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1", VPCCIDRBlock: "10.0.0.0/16"}
+			},
+			expected: []string{"networking.clusterNetworks[0].cidr: 10.0.0.0/14 overlaps the AWS VPC DNS server (10.0.0.2/32)"},
+		},
+		{
+			name: "service network overlapping the openstack network",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.ServiceCIDR = ipNet("10.0.128.0/20")
+				c.Platform.OpenStack = &openstack.Platform{NetworkCIDRBlock: "10.0.0.0/16"}
+			},
+			expected: []string{"networking.serviceCIDR: 10.0.128.0/20 overlaps the OpenStack network (10.0.0.0/16)"},
+		},
+		{
+			name: "openstack network checked with the machine networks",
+			modify: func(c *types.InstallConfig) {
+				c.Networking.ServiceCIDR = ipNet("10.0.128.0/20")
+				c.Networking.MachineNetwork = machineNetwork("10.0.0.0/16")
+				c.Platform.OpenStack = &openstack.Platform{NetworkCIDRBlock: "10.0.0.0/16"}
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := validInstallConfig()
+			if tc.modify != nil {
+				tc.modify(c)
+			}
+			overlaps := reservedRangeOverlaps(&c.Networking, &c.Platform, field.NewPath("networking"))
+			assert.Equal(t, tc.expected, overlaps)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"net"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/openstack"
+)
+
+func validateOpenStackPlatform(p *openstack.Platform, machines []types.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p.DefaultMachinePlatform != nil {
+		allErrs = append(allErrs, validateOpenStackMachinePool(p.DefaultMachinePlatform, fldPath.Child("defaultMachinePlatform"))...)
+	}
+	for i, m := range machines {
+		if m.Platform.OpenStack != nil {
+			allErrs = append(allErrs, validateOpenStackMachinePool(m.Platform.OpenStack, field.NewPath("machines").Index(i).Child("platform", "openstack"))...)
+		}
+	}
+	if p.APIFloatingIP != "" && !isIPv4(p.APIFloatingIP) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("apiFloatingIP"), p.APIFloatingIP, "must be an IPv4 address"))
+	}
+	if p.IngressFloatingIP != "" && !isIPv4(p.IngressFloatingIP) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ingressFloatingIP"), p.IngressFloatingIP, "must be an IPv4 address"))
+	}
+	if p.APIFloatingIP != "" && p.APIFloatingIP == p.IngressFloatingIP {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ingressFloatingIP"), p.IngressFloatingIP, "must differ from apiFloatingIP"))
+	}
+	if p.ExternalNetwork == "" {
+		if p.AllocateFloatingIPs {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("allocateFloatingIPs"), p.AllocateFloatingIPs, "requires externalNetwork"))
+		}
+		if p.APIFloatingIP != "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("apiFloatingIP"), p.APIFloatingIP, "requires externalNetwork"))
+		}
+	}
+	return allErrs
+}
+
+func validateOpenStackMachinePool(p *openstack.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p.RootVolume != nil && p.RootVolume.Size <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("rootVolume", "size"), p.RootVolume.Size, "must be a positive number of GiB"))
+	}
+	for i, zone := range p.Zones {
+		if zone == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("zones").Index(i), "zones must not be empty"))
+		}
+	}
+	return allErrs
+}
+
+func isIPv4(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && ip.To4() != nil
+}
@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/openstack"
+)
+
+func TestValidateOpenStackPlatform(t *testing.T) {
+	testInstallConfigCases(t, []installConfigCase{
+		{
+			name: "openstack floating ips",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.OpenStack = &openstack.Platform{
+					ExternalNetwork:     "public",
+					APIFloatingIP:       "203.0.113.10",
+					AllocateFloatingIPs: true,
+				}
+			},
+		},
+		{
+			name: "invalid openstack floating ip",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.OpenStack = &openstack.Platform{
+					ExternalNetwork:   "public",
+					IngressFloatingIP: "apps.example.com",
+				}
+			},
+			expectedError: `^platform\.openstack\.ingressFloatingIP: Invalid value: "apps\.example\.com": must be an IPv4 address$`,
+		},
+		{
+			name:          "openstack floating ip allocation without an external network",
+			modify:        func(c *types.InstallConfig) { c.Platform.OpenStack = &openstack.Platform{AllocateFloatingIPs: true} },
+			expectedError: `^platform\.openstack\.allocateFloatingIPs: Invalid value: true: requires externalNetwork$`,
+		},
+		{
+			name: "openstack machine pools",
+			modify: func(c *types.InstallConfig) {
+				c.Machines = []types.MachinePool{{Name: "worker", Platform: types.MachinePoolPlatform{OpenStack: &openstack.MachinePool{
+					FlavorName: "m1.xlarge",
+					RootVolume: &openstack.RootVolume{Size: 120, Type: "performance"},
+					Zones:      []string{"az0", "az1"},
+				}}}}
+				c.Platform.OpenStack = &openstack.Platform{}
+			},
+		},
+		{
+			name: "invalid openstack root volume",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.OpenStack = &openstack.Platform{DefaultMachinePlatform: &openstack.MachinePool{RootVolume: &openstack.RootVolume{}}}
+			},
+			expectedError: `^platform\.openstack\.defaultMachinePlatform\.rootVolume\.size: Invalid value: 0: must be a positive number of GiB$`,
+		},
+	})
+}
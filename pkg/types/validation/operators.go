@@ -0,0 +1,153 @@
+package validation
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+var (
+	// s3BucketPattern matches DNS-compatible S3 bucket names.
+	s3BucketPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+	// prometheusDurationPattern matches Prometheus durations such as 15d.
+	prometheusDurationPattern = regexp.MustCompile(`^[0-9]+(ms|s|m|h|d|w|y)$`)
+)
+
+func validateScheduler(s *types.Scheduler, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch s.Profile {
+	case "", types.LowNodeUtilizationSchedulerProfile, types.HighNodeUtilizationSchedulerProfile:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("profile"), s.Profile, []string{
+			string(types.LowNodeUtilizationSchedulerProfile),
+			string(types.HighNodeUtilizationSchedulerProfile),
+		}))
+	}
+	if s.DefaultNodeSelector != "" {
+		if _, err := labels.Parse(s.DefaultNodeSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("defaultNodeSelector"), s.DefaultNodeSelector, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+func validateProject(p *types.Project, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p.RequestTemplate != "" {
+		var template struct {
+			APIVersion string        `json:"apiVersion"`
+			Kind       string        `json:"kind"`
+			Objects    []interface{} `json:"objects"`
+		}
+		if err := yaml.Unmarshal([]byte(p.RequestTemplate), &template); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requestTemplate"), p.RequestTemplate, err.Error()))
+		} else if template.Kind != "Template" || (template.APIVersion != "template.openshift.io/v1" && template.APIVersion != "v1") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requestTemplate"), p.RequestTemplate, "must be a template.openshift.io/v1 Template"))
+		} else if len(template.Objects) == 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("requestTemplate"), p.RequestTemplate, "must create at least the Project"))
+		}
+	}
+	return allErrs
+}
+
+func validateImageRegistry(r *types.ImageRegistry, platform *types.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	storagePath := fldPath.Child("storage")
+	configured := 0
+	if s3 := r.Storage.S3; s3 != nil {
+		configured++
+		if !s3BucketPattern.MatchString(s3.Bucket) {
+			allErrs = append(allErrs, field.Invalid(storagePath.Child("s3", "bucket"), s3.Bucket, "must be 3 to 63 lowercase letters, digits, dots, or hyphens, beginning and ending with a letter or digit"))
+		}
+		if s3.Region == "" && platform.AWS == nil {
+			allErrs = append(allErrs, field.Required(storagePath.Child("s3", "region"), "region is required when not installing on AWS"))
+		}
+	}
+	if pvc := r.Storage.PVC; pvc != nil {
+		configured++
+		if pvc.Claim != "" {
+			for _, msg := range validation.IsDNS1123Subdomain(pvc.Claim) {
+				allErrs = append(allErrs, field.Invalid(storagePath.Child("pvc", "claim"), pvc.Claim, msg))
+			}
+		}
+	}
+	if r.Storage.EmptyDir != nil {
+		configured++
+	}
+	if configured != 1 {
+		allErrs = append(allErrs, field.Invalid(storagePath, r.Storage, "exactly one of s3, pvc, and emptyDir must be set"))
+	}
+	return allErrs
+}
+
+func validateProxy(p *types.Proxy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p.HTTPProxy == "" && p.HTTPSProxy == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of httpProxy and httpsProxy is required"))
+	}
+	allErrs = append(allErrs, validateProxyURL(p.HTTPProxy, fldPath.Child("httpProxy"))...)
+	allErrs = append(allErrs, validateProxyURL(p.HTTPSProxy, fldPath.Child("httpsProxy"))...)
+	if p.NoProxy != "" {
+		for _, entry := range strings.Split(p.NoProxy, ",") {
+			if entry == "" || strings.TrimSpace(entry) != entry {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("noProxy"), p.NoProxy, "must be a comma-separated list of hosts, domains and CIDRs without spaces"))
+				break
+			}
+		}
+	}
+	allErrs = append(allErrs, validateCABundle(p.TrustBundle, fldPath.Child("trustBundle"))...)
+	return allErrs
+}
+
+func validateProxyURL(proxyURL string, fldPath *field.Path) field.ErrorList {
+	if proxyURL == "" {
+		return nil
+	}
+	if u, err := url.Parse(proxyURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return field.ErrorList{field.Invalid(fldPath, proxyURL, "must be an http or https URL")}
+	}
+	return nil
+}
+
+func validateMonitoring(m *types.Monitoring, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if m.Retention != "" && !prometheusDurationPattern.MatchString(m.Retention) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("retention"), m.Retention, "must be a duration such as 15d or 12h"))
+	}
+	if m.StorageSize != "" {
+		if _, err := resource.ParseQuantity(m.StorageSize); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("storageSize"), m.StorageSize, err.Error()))
+		}
+	} else if m.StorageClass != "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("storageSize"), "storage size is required when a storage class is set"))
+	}
+	if m.StorageClass != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(m.StorageClass) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("storageClass"), m.StorageClass, msg))
+		}
+	}
+	allErrs = append(allErrs, validateNodeSelector(m.NodeSelector, fldPath.Child("nodeSelector"))...)
+	return allErrs
+}
+
+func validateNodeSelector(selector map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for key, value := range selector {
+		for _, msg := range validation.IsQualifiedName(key) {
+			allErrs = append(allErrs, field.Invalid(fldPath, key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(value) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Key(key), value, msg))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,160 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/openshift/installer/pkg/types"
+	"github.com/openshift/installer/pkg/types/aws"
+)
+
+func TestValidateOperatorConfig(t *testing.T) {
+	testInstallConfigCases(t, []installConfigCase{
+		{
+			name: "scheduler profile and default node selector",
+			modify: func(c *types.InstallConfig) {
+				c.Scheduler = &types.Scheduler{
+					Profile:             types.HighNodeUtilizationSchedulerProfile,
+					DefaultNodeSelector: "node-role.kubernetes.io/worker=,type=user",
+				}
+			},
+		},
+		{
+			name:          "unknown scheduler profile",
+			modify:        func(c *types.InstallConfig) { c.Scheduler = &types.Scheduler{Profile: "Random"} },
+			expectedError: `^scheduler\.profile: Unsupported value: "Random": supported values: "LowNodeUtilization", "HighNodeUtilization"$`,
+		},
+		{
+			name:          "invalid default node selector",
+			modify:        func(c *types.InstallConfig) { c.Scheduler = &types.Scheduler{DefaultNodeSelector: "=user"} },
+			expectedError: `^scheduler\.defaultNodeSelector: Invalid value: "=user": `,
+		},
+		{
+			name: "project request message and template",
+			modify: func(c *types.InstallConfig) {
+				c.Project = &types.Project{
+					RequestMessage: "Ask the platform team for a project.",
+					RequestTemplate: `apiVersion: template.openshift.io/v1
+kind: Template
+objects:
+- apiVersion: project.openshift.io/v1
+  kind: Project
+  metadata:
+    name: ${PROJECT_NAME}
+parameters:
+- name: PROJECT_NAME
+`,
+				}
+			},
+		},
+		{
+			name: "project request template of another kind",
+			modify: func(c *types.InstallConfig) {
+				c.Project = &types.Project{RequestTemplate: "apiVersion: v1\nkind: ConfigMap\n"}
+			},
+			expectedError: `^project\.requestTemplate: Invalid value: ".*": must be a template\.openshift\.io/v1 Template$`,
+		},
+		{
+			name: "proxy",
+			modify: func(c *types.InstallConfig) {
+				c.Proxy = &types.Proxy{
+					HTTPProxy:   "http://proxy.example.com:3128",
+					HTTPSProxy:  "http://proxy.example.com:3128",
+					NoProxy:     ".example.com,10.0.0.0/8",
+					TrustBundle: ingressCertificate("proxy.example.com").Certificate,
+				}
+			},
+		},
+		{
+			name:          "proxy without a URL",
+			modify:        func(c *types.InstallConfig) { c.Proxy = &types.Proxy{NoProxy: ".example.com"} },
+			expectedError: `^proxy: Required value: at least one of httpProxy and httpsProxy is required$`,
+		},
+		{
+			name: "proxy with an invalid URL and no proxy list",
+			modify: func(c *types.InstallConfig) {
+				c.Proxy = &types.Proxy{HTTPSProxy: "proxy.example.com:3128", NoProxy: ".example.com, 10.0.0.0/8"}
+			},
+			expectedError: `^\[proxy\.httpsProxy: Invalid value: "proxy\.example\.com:3128": must be an http or https URL, proxy\.noProxy: Invalid value: "\.example\.com, 10\.0\.0\.0/8": must be a comma-separated list of hosts, domains and CIDRs without spaces\]$`,
+		},
+		{
+			name: "proxy with an invalid trust bundle",
+			modify: func(c *types.InstallConfig) {
+				c.Proxy = &types.Proxy{HTTPProxy: "http://proxy.example.com:3128", TrustBundle: "not a certificate"}
+			},
+			expectedError: `^proxy\.trustBundle: Invalid value: "not a certificate": must contain at least one PEM-encoded certificate$`,
+		},
+		{
+			name: "image registry s3 storage",
+			modify: func(c *types.InstallConfig) {
+				c.Platform.AWS = &aws.Platform{Region: "us-east-1"}
+				c.ImageRegistry = &types.ImageRegistry{Storage: types.ImageRegistryStorage{
+					S3: &types.ImageRegistryStorageS3{Bucket: "my-cluster-registry", Encrypt: true},
+				}}
+			},
+		},
+		{
+			name: "image registry s3 storage without region off AWS",
+			modify: func(c *types.InstallConfig) {
+				c.ImageRegistry = &types.ImageRegistry{Storage: types.ImageRegistryStorage{
+					S3: &types.ImageRegistryStorageS3{Bucket: "my-cluster-registry"},
+				}}
+			},
+			expectedError: `^imageRegistry\.storage\.s3\.region: Required value: region is required when not installing on AWS$`,
+		},
+		{
+			name: "image registry emptyDir storage",
+			modify: func(c *types.InstallConfig) {
+				c.ImageRegistry = &types.ImageRegistry{Storage: types.ImageRegistryStorage{
+					EmptyDir: &types.ImageRegistryStorageEmptyDir{},
+				}}
+			},
+		},
+		{
+			name: "image registry with multiple storages",
+			modify: func(c *types.InstallConfig) {
+				c.ImageRegistry = &types.ImageRegistry{Storage: types.ImageRegistryStorage{
+					PVC:      &types.ImageRegistryStoragePVC{},
+					EmptyDir: &types.ImageRegistryStorageEmptyDir{},
+				}}
+			},
+			expectedError: `^imageRegistry\.storage: Invalid value: .*: exactly one of s3, pvc, and emptyDir must be set$`,
+		},
+		{
+			name: "invalid image registry bucket",
+			modify: func(c *types.InstallConfig) {
+				c.ImageRegistry = &types.ImageRegistry{Storage: types.ImageRegistryStorage{
+					S3: &types.ImageRegistryStorageS3{Bucket: "My_Bucket", Region: "us-east-1"},
+				}}
+			},
+			expectedError: `^imageRegistry\.storage\.s3\.bucket: Invalid value: "My_Bucket": must be 3 to 63`,
+		},
+		{
+			name: "monitoring",
+			modify: func(c *types.InstallConfig) {
+				c.Monitoring = &types.Monitoring{
+					Retention:    "15d",
+					StorageClass: "gp2",
+					StorageSize:  "40Gi",
+					NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+				}
+			},
+		},
+		{
+			name:          "invalid monitoring retention",
+			modify:        func(c *types.InstallConfig) { c.Monitoring = &types.Monitoring{Retention: "two weeks"} },
+			expectedError: `^monitoring\.retention: Invalid value: "two weeks": must be a duration such as 15d or 12h$`,
+		},
+		{
+			name:          "monitoring storage class without size",
+			modify:        func(c *types.InstallConfig) { c.Monitoring = &types.Monitoring{StorageClass: "gp2"} },
+			expectedError: `^monitoring\.storageSize: Required value: storage size is required when a storage class is set$`,
+		},
+		{
+			name: "invalid monitoring node selector",
+			modify: func(c *types.InstallConfig) {
+				c.Monitoring = &types.Monitoring{NodeSelector: map[string]string{"role": "infra nodes"}}
+			},
+			expectedError: `^monitoring\.nodeSelector\[role\]: Invalid value: "infra nodes": `,
+		},
+	})
+}
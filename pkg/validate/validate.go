@@ -2,11 +2,14 @@
 package validate
 
 import (
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"unicode/utf8"
@@ -64,6 +67,44 @@ func JSON(data []byte) error {
 	return json.Unmarshal(data, &dummy)
 }
 
+// PullSecret checks if the given string is a container registry pull secret
+// (e.g. {"auths": {"quay.io": {"auth": "..."}}}) with at least one registry,
+// each of whose auth is a base64-encoded user:password, and returns an error
+// if not.
+func PullSecret(v string) error {
+	var secret struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal([]byte(v), &secret); err != nil {
+		return fmt.Errorf("invalid pull secret (%v)", err)
+	}
+	if len(secret.Auths) == 0 {
+		return errors.New("invalid pull secret (no registry auths)")
+	}
+
+	registries := make([]string, 0, len(secret.Auths))
+	for registry := range secret.Auths {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+	for _, registry := range registries {
+		auth := secret.Auths[registry].Auth
+		if auth == "" {
+			return fmt.Errorf("invalid pull secret (no auth for %q)", registry)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth)
+		if err != nil {
+			return fmt.Errorf("invalid pull secret (auth for %q is not valid base64)", registry)
+		}
+		if !strings.Contains(string(decoded), ":") {
+			return fmt.Errorf("invalid pull secret (auth for %q is not of the form user:password)", registry)
+		}
+	}
+	return nil
+}
+
 func isMatch(re string, v string) bool {
 	return regexp.MustCompile(re).MatchString(v)
 }
@@ -253,3 +294,87 @@ func SSHPublicKey(v string) error {
 
 	return nil
 }
+
+// SSHAuthorizedKey checks if the given string is a single valid
+// authorized_keys entry (optional options, key type, base64-encoded key and
+// optional comment) whose encoded key type matches the declared one, and
+// returns an error if not.
+func SSHAuthorizedKey(v string) error {
+	trimmed := strings.TrimSpace(v)
+
+	if isMatch(`-BEGIN [\w-]+ PRIVATE KEY-`, trimmed) {
+		return errors.New("invalid SSH public key (appears to be a private key)")
+	}
+
+	if strings.Contains(trimmed, "\n") {
+		return errors.New("invalid SSH public key (should not contain any newline characters)")
+	}
+
+	err := sshAuthorizedKey(trimmed)
+	if err == nil {
+		return nil
+	}
+
+	// The entry may be prefixed by options (e.g. 'no-pty,from="10.0.0.1"').
+	if options, rest := splitSSHKeyOptions(trimmed); options != "" && rest != "" {
+		if optErr := sshAuthorizedKey(rest); optErr == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// sshAuthorizedKey checks an authorized_keys entry without options.
+func sshAuthorizedKey(v string) error {
+	invalidError := errors.New("invalid SSH public key")
+
+	keyParts := strings.Fields(v)
+	if len(keyParts) < 2 {
+		return invalidError
+	}
+
+	keyType := keyParts[0]
+	if !isMatch(`^[\w@.-]+$`, keyType) {
+		return invalidError
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(keyParts[1])
+	if err != nil {
+		return errors.New("invalid SSH public key (key is not valid base64)")
+	}
+
+	// The key blob starts with a length-prefixed copy of the key type.
+	if len(blob) < 4 {
+		return invalidError
+	}
+	length := uint64(binary.BigEndian.Uint32(blob[:4]))
+	if length > uint64(len(blob)-4) {
+		return invalidError
+	}
+	if encodedType := string(blob[4 : 4+length]); encodedType != keyType {
+		return fmt.Errorf("invalid SSH public key (declared type %q does not match encoded type %q)", keyType, encodedType)
+	}
+
+	return nil
+}
+
+// splitSSHKeyOptions splits the leading options field from an
+// authorized_keys entry, honoring double-quoted option values.
+func splitSSHKeyOptions(v string) (options string, rest string) {
+	inQuote := false
+	for i := 0; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			if inQuote && i+1 < len(v) {
+				i++
+			}
+		case '"':
+			inQuote = !inQuote
+		case ' ', '\t':
+			if !inQuote {
+				return v[:i], strings.TrimSpace(v[i:])
+			}
+		}
+	}
+	return v, ""
+}